@@ -0,0 +1,86 @@
+// Command encrypt_passports encrypts the passport data of the users stored before the encryption was enabled
+// and re-encrypts the data encrypted with the old keys after the current key is changed.
+package main
+
+import (
+	"context"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/config"
+	internalDB "git.epam.com/epm-lstr/epm-lstr-lc/be/internal/db"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/encryption"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/logger"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/repositories"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+const batchSize = 100
+
+func main() {
+	ctx, cancelFunc := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer cancelFunc()
+
+	lg, err := logger.Get()
+	if err != nil {
+		lg.Fatal("load config error", zap.Error(err))
+	}
+
+	conf, err := config.GetAppConfig()
+	if err != nil {
+		lg.Fatal("fail to setup app config", zap.Error(err))
+	}
+
+	keyring, err := conf.PassportEncryption.Keyring()
+	if err != nil {
+		lg.Fatal("failed to load passport encryption keys", zap.Error(err))
+	}
+	encryption.SetDefault(keyring)
+
+	entClient, db, err := internalDB.GetDB(conf.DB)
+	if err != nil {
+		lg.Fatal("failed to db connection", zap.Error(err))
+	}
+	defer entClient.Close()
+
+	if err = internalDB.ApplyMigrations(db); err != nil {
+		lg.Fatal("failed to apply migrations", zap.Error(err))
+	}
+
+	userRepository := repositories.NewUserRepository()
+	total := 0
+	for {
+		encrypted, errBatch := encryptBatch(ctx, entClient, userRepository)
+		if errBatch != nil {
+			lg.Fatal("failed to encrypt passport data", zap.Error(errBatch), zap.Int("users", total))
+		}
+		total += encrypted
+		if encrypted < batchSize {
+			break
+		}
+	}
+	lg.Info("passport data encrypted", zap.Int("users", total))
+}
+
+func encryptBatch(ctx context.Context, cln *ent.Client, userRepository domain.UserRepository) (n int, err error) {
+	tx, err := cln.Tx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+
+	n, err = userRepository.EncryptPassportData(ctx, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	return n, tx.Commit()
+}
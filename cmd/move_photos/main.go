@@ -0,0 +1,86 @@
+// Command move_photos moves the photos and their renditions stored in the database to the configured photo storage.
+package main
+
+import (
+	"context"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/blobstore"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/config"
+	internalDB "git.epam.com/epm-lstr/epm-lstr-lc/be/internal/db"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/logger"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+const batchSize = 50
+
+func main() {
+	ctx, cancelFunc := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer cancelFunc()
+
+	lg, err := logger.Get()
+	if err != nil {
+		lg.Fatal("load config error", zap.Error(err))
+	}
+
+	conf, err := config.GetAppConfig()
+	if err != nil {
+		lg.Fatal("fail to setup app config", zap.Error(err))
+	}
+	if conf.PhotoStorage.Backend == config.StorageDB {
+		lg.Info("photos are stored in the database, nothing to move")
+		return
+	}
+
+	store, err := blobstore.New(conf.PhotoStorage)
+	if err != nil {
+		lg.Fatal("failed to create photo storage", zap.Error(err))
+	}
+
+	entClient, db, err := internalDB.GetDB(conf.DB)
+	if err != nil {
+		lg.Fatal("failed to db connection", zap.Error(err))
+	}
+	defer entClient.Close()
+
+	if err = internalDB.ApplyMigrations(db); err != nil {
+		lg.Fatal("failed to apply migrations", zap.Error(err))
+	}
+
+	total := 0
+	for {
+		moved, errBatch := moveBatch(ctx, entClient, store)
+		if errBatch != nil {
+			lg.Fatal("failed to move photos", zap.Error(errBatch), zap.Int("photos", total))
+		}
+		total += moved
+		if moved < batchSize {
+			break
+		}
+	}
+	lg.Info("photos moved", zap.String("backend", conf.PhotoStorage.Backend), zap.Int("photos", total))
+}
+
+func moveBatch(ctx context.Context, cln *ent.Client, store domain.BlobStore) (n int, err error) {
+	tx, err := cln.Tx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+
+	n, err = blobstore.MoveFromDB(ctx, store, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	return n, tx.Commit()
+}
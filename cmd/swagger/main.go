@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/blobstore"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/config"
 	internalDB "git.epam.com/epm-lstr/epm-lstr-lc/be/internal/db"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/encryption"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/logger"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	_ "github.com/jackc/pgx/v4/stdlib"
@@ -26,6 +28,12 @@ func main() {
 		lg.Fatal("fail to setup app config", zap.Error(err))
 	}
 
+	keyring, err := conf.PassportEncryption.Keyring()
+	if err != nil {
+		lg.Fatal("failed to load passport encryption keys", zap.Error(err))
+	}
+	encryption.SetDefault(keyring)
+
 	entClient, db, err := internalDB.GetDB(conf.DB)
 	if err != nil {
 		lg.Fatal("failed to db connection", zap.Error(err))
@@ -36,7 +44,7 @@ func main() {
 	}
 
 	// setup swagger api
-	server, checker, err := SetupAPI(entClient, lg, conf)
+	server, checker, err := SetupAPI(ctx, entClient, lg, conf)
 	if err != nil {
 		lg.Fatal("error setup swagger api", zap.Error(err))
 	}
@@ -44,6 +52,15 @@ func main() {
 	go checker.PeriodicalCheckup(ctx, conf.PeriodicCheckDuration, entClient, lg)
 
 	runUnblockPeriodically(ctx, entClient, conf.PeriodicCheckDuration, lg)
+	runMaintenanceSchedulePeriodically(ctx, entClient, conf.PeriodicCheckDuration, lg)
+	runExpiredTokensCleanupPeriodically(ctx, entClient, conf.PeriodicCheckDuration, lg)
+	runDeletedUsersPurgePeriodically(ctx, entClient, conf.PeriodicCheckDuration, conf.UserDeletion.RetentionPeriod, lg)
+	photoStore, err := blobstore.New(conf.PhotoStorage)
+	if err != nil {
+		lg.Fatal("failed to create photo storage", zap.Error(err))
+	}
+	runOrphanedPhotosCleanupPeriodically(ctx, entClient, conf.PeriodicCheckDuration, conf.PhotoCleanup.GracePeriod,
+		photoStore, lg)
 
 	// Swagger servers handles signals and gracefully shuts down by itself
 	if err := server.Serve(); err != nil {
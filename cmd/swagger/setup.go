@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/go-openapi/loads"
@@ -11,6 +12,8 @@ import (
 	"github.com/rs/cors"
 	"go.uber.org/zap"
 
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/blobstore"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/cleanup"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/config"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/docs"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/email"
@@ -18,8 +21,12 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/handlers"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/images"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/maintenance"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/oidc"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/overdue"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/permissions"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/repositories"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/services"
@@ -28,11 +35,19 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/timer"
 )
 
-func SetupAPI(entClient *ent.Client, lg *zap.Logger, conf *config.AppConfig) (*restapi.Server, domain.OrderOverdueCheckup, error) {
-	passwordGenerator, err := utils.NewPasswordGenerator(conf.Password.Length)
-	if err != nil {
-		return nil, nil, err
-	}
+const oidcRequestTimeout = 10 * time.Second
+
+func SetupAPI(ctx context.Context, entClient *ent.Client, lg *zap.Logger,
+	conf *config.AppConfig) (*restapi.Server, domain.OrderOverdueCheckup, error) {
+	passwordPolicy := utils.NewPasswordPolicy(utils.PasswordPolicyRules{
+		MinLength:        conf.Password.Policy.MinLength,
+		RequireLowercase: conf.Password.Policy.RequireLowercase,
+		RequireUppercase: conf.Password.Policy.RequireUppercase,
+		RequireDigit:     conf.Password.Policy.RequireDigit,
+		RequireSpecial:   conf.Password.Policy.RequireSpecial,
+		ForbidUserData:   conf.Password.Policy.ForbidUserData,
+		ForbidCommon:     conf.Password.Policy.ForbidCommon,
+	})
 
 	swaggerSpec, err := loadSwaggerSpec()
 	if err != nil {
@@ -44,6 +59,8 @@ func SetupAPI(entClient *ent.Client, lg *zap.Logger, conf *config.AppConfig) (*r
 	userRepository := repositories.NewUserRepository()
 	tokenRepository := repositories.NewTokenRepository()
 	emailConfirmRepository := repositories.NewConfirmEmailRepository()
+	twoFactorRepository := repositories.NewTwoFactorRepository()
+	loginStateRepository := repositories.NewLoginStateRepository()
 
 	// conf
 	jwtSecret := conf.JWTSecretKey
@@ -56,45 +73,90 @@ func SetupAPI(entClient *ent.Client, lg *zap.Logger, conf *config.AppConfig) (*r
 	regConfirmService := services.NewRegistrationConfirmService(mailSendClient, userRepository, regConfirmRepo,
 		lg, conf.Email.ConfirmLinkExpiration)
 	passwordService := services.NewPasswordResetService(mailSendClient,
-		userRepository, passwordRepo, lg, conf.Password.ResetLinkExpiration, passwordGenerator)
+		userRepository, passwordRepo, tokenRepository, lg, conf.Password.ResetLinkExpiration, passwordPolicy)
 	tokenManager := services.NewTokenManager(userRepository, tokenRepository, jwtSecret, lg)
 	changeEmailService := services.NewEmailChangeService(
 		mailSendClient, userRepository,
 		emailConfirmRepository, lg,
 	)
+	var twoFactorRequiredRoles []string
+	if conf.TwoFactor.RequiredForFullAccessRoles {
+		twoFactorRequiredRoles = fullAccessRoleSlugs
+	}
+	twoFactorService := services.NewTwoFactorService(entClient, userRepository, twoFactorRepository, jwtSecret,
+		conf.TwoFactor.Issuer, conf.TwoFactor.ChallengeExpiration, conf.TwoFactor.MaxChallengeAttempts,
+		conf.TwoFactor.RecoveryCodesCount, twoFactorRequiredRoles, lg)
+	userExportService := services.NewUserExportService(userRepository, repositories.NewActiveAreaRepository(),
+		repositories.NewOrderRepository(), repositories.NewOrderStatusRepository(), tokenRepository)
+	photoStore, err := blobstore.New(conf.PhotoStorage)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create photo storage: %w", err)
+	}
 	// swagger api
 	api := operations.NewBeAPI(swaggerSpec)
 	api.UseSwaggerUI()
 
 	api.APIKeyAuthenticator = func(name string, in string, _ security.TokenAuthentication) runtime.Authenticator {
-		return security.APIKeyAuthCtx(name, in, middlewares.APIKeyAuthFunc(jwtSecret, userRepository))
+		return security.APIKeyAuthCtx(name, in, middlewares.APIKeyAuthFunc(jwtSecret, userRepository, tokenRepository))
 	}
 
 	handlers.SetActiveAreaHandler(lg, api)
 	handlers.SetEquipmentHandler(lg, api)
 	handlers.SetCategoryHandler(lg, api)
+	handlers.SetDamageClaimHandler(lg, api)
 	handlers.SetSubcategoryHandler(lg, api)
 	handlers.SetOrderHandler(lg, api)
 	orderStatusRepo, orderFilterRepo, equipmentStatusRepo := handlers.SetOrderStatusHandler(lg, api)
 	handlers.SetPasswordResetHandler(lg, api, passwordService)
 	handlers.SetPetSizeHandler(lg, api)
-	handlers.SetPhotoHandler(lg, api)
+	handlers.SetPhotoHandler(lg, api, photoStore, images.Limits{
+		MaxFileSize: conf.PhotoUpload.MaxFileSize,
+		MaxWidth:    conf.PhotoUpload.MaxWidth,
+		MaxHeight:   conf.PhotoUpload.MaxHeight,
+	})
 	handlers.SetRegistrationHandler(lg, api, regConfirmService)
 	handlers.SetEmailConfirmHandler(lg, api, changeEmailService)
 	handlers.SetRoleHandler(lg, api)
+	handlers.SetGroupHandler(lg, api)
+	handlers.SetOrganizationHandler(lg, api)
+	var accessManager middlewares.AccessManager
+	handlers.SetAccessPolicyHandler(lg, api, func() middlewares.AccessManager {
+		return accessManager
+	})
 	handlers.SetEquipmentStatusNameHandler(lg, api)
 	handlers.SetEquipmentStatusHandler(lg, api)
 	handlers.SetEquipmentPeriodsHandler(lg, api)
-	handlers.SetUserHandler(lg, api, tokenManager, regConfirmService, changeEmailService)
+	handlers.SetMaintenanceHandler(lg, api)
+	handlers.SetUserHandler(lg, api, tokenManager, regConfirmService, changeEmailService, twoFactorService,
+		passwordPolicy, userExportService, conf.UserDeletion.RetentionPeriod)
+	handlers.SetTwoFactorHandler(lg, api, tokenManager, twoFactorService)
+	if conf.OIDC.Enabled {
+		oidcProvider := oidc.NewProvider(oidc.Config{
+			Issuer:       conf.OIDC.Issuer,
+			ClientID:     conf.OIDC.ClientID,
+			ClientSecret: conf.OIDC.ClientSecret,
+			RedirectURL:  conf.OIDC.RedirectURL,
+			Scopes:       conf.OIDC.Scopes,
+		}, &http.Client{Timeout: oidcRequestTimeout})
+		oidcService := services.NewOIDCService(oidcProvider, userRepository, loginStateRepository,
+			conf.OIDC.StateExpiration, lg)
+		handlers.SetOIDCHandler(lg, api, oidcService, tokenManager, twoFactorService)
+	}
 	handlers.SetPetKindHandler(lg, api)
 	handlers.SetHealthHandler(lg, api)
 
 	api.Init()
-	accessManager, err := AccessManager(api, conf.AccessBindings)
+	accessManager, err = AccessManager(api, conf.AccessBindings, conf.TwoFactor.RequiredForFullAccessRoles)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create access manager: %w", err)
 	}
 	api.APIAuthorizer = accessManager
+	err = config.WatchAccessBindings(ctx, func(bindings []config.RoleEndpointBinding, err error) {
+		reloadAccessBindings(accessManager, bindings, err, lg)
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to watch access bindings: %w", err)
+	}
 	// run server
 	server := restapi.NewServer(api)
 	listeners := []string{"http"}
@@ -105,7 +167,7 @@ func SetupAPI(entClient *ent.Client, lg *zap.Logger, conf *config.AppConfig) (*r
 	server.Port = conf.Server.Port
 	server.SetHandler(
 		cors.AllowAll().Handler(
-			middlewares.Tx(entClient)(api.Serve(nil)),
+			middlewares.UploadLimit(conf.PhotoUpload.MaxFileSize)(middlewares.Tx(entClient)(api.Serve(nil))),
 		),
 	)
 
@@ -138,49 +200,122 @@ func loadSwaggerSpec() (*loads.Document, error) {
 	return swaggerSpec, nil
 }
 
-func AccessManager(api *operations.BeAPI, bindings []config.RoleEndpointBinding) (middlewares.AccessManager, error) {
-	acceptableRoles := []middlewares.Role{
-		{
-			Slug: roles.Admin,
+// fullAccessRoleSlugs are roles which have access to all endpoints regardless of access bindings.
+var fullAccessRoleSlugs = []string{roles.Admin, roles.Manager, roles.Operator}
+
+// twoFactorSetupEndpoints stay available for full access roles which must enable two-factor authentication.
+var twoFactorSetupEndpoints = middlewares.ExistingEndpoints{
+	http.MethodGet: {
+		"/v1/users/me",
+	},
+	http.MethodPost: {
+		"/v1/users/me/2fa",
+		"/v1/users/me/2fa/activate",
+	},
+}
+
+// orderViewEndpoints are available with any of the order permissions.
+var orderViewEndpoints = []string{
+	"/management/orders",
+	"/v1/order_statuses/{orderId}",
+	"/v1/orders/status/{status}",
+	"/v1/orders/from={fromDate}&to={toDate}&status={statusName}",
+}
+
+// permissionEndpoints are the endpoints granted by the permissions of the user groups in addition to the role
+// access bindings, handlers check which operations the permissions allow.
+var permissionEndpoints = map[string]middlewares.ExistingEndpoints{
+	permissions.EquipmentBlock: {
+		http.MethodPost: {
+			"/equipment/{equipmentId}/blocking",
+			"/equipment/{equipmentId}/unblocking",
 		},
+	},
+	permissions.OrdersView: {
+		http.MethodGet: orderViewEndpoints,
+	},
+	permissions.OrdersApprove: {
+		http.MethodGet:  orderViewEndpoints,
+		http.MethodPost: {"/v1/order_statuses"},
+	},
+	permissions.OrdersPrepare: {
+		http.MethodGet:  orderViewEndpoints,
+		http.MethodPost: {"/v1/order_statuses"},
+	},
+	permissions.OrdersIssue: {
+		http.MethodGet:  orderViewEndpoints,
+		http.MethodPost: {"/v1/order_statuses"},
+	},
+	permissions.OrdersClose: {
+		http.MethodGet:  orderViewEndpoints,
+		http.MethodPost: {"/v1/order_statuses"},
+	},
+}
+
+func AccessManager(api *operations.BeAPI, bindings []config.RoleEndpointBinding,
+	isTwoFactorRequired bool) (middlewares.AccessManager, error) {
+	acceptableRoles := []middlewares.Role{
 		{
 			Slug: roles.User,
 		},
-		{
-			Slug: roles.Operator,
-		},
-		{
-			Slug: roles.Manager,
-		},
 	}
-	fullAccessRoles := []middlewares.Role{
-		{
-			Slug: roles.Admin,
-		},
-		{
-			Slug: roles.Manager,
-		},
-		{
-			Slug: roles.Operator,
-		},
+	fullAccessRoles := make([]middlewares.Role, len(fullAccessRoleSlugs))
+	for i, slug := range fullAccessRoleSlugs {
+		fullAccessRoles[i] = middlewares.Role{Slug: slug}
 	}
+	acceptableRoles = append(acceptableRoles, fullAccessRoles...)
 
 	manager, err := middlewares.NewAccessManager(acceptableRoles, fullAccessRoles, api.GetExistingEndpoints())
 	if err != nil {
 		return nil, err
 	}
 
+	if isTwoFactorRequired {
+		if err = manager.RequireTwoFactor(twoFactorSetupEndpoints); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, _, err = manager.ReplaceAccess(bindingAccesses(bindings)); err != nil {
+		return nil, err
+	}
+	return middlewares.NewPermissionAccessManager(manager, api.GetExistingEndpoints(), permissionEndpoints)
+}
+
+func bindingAccesses(bindings []config.RoleEndpointBinding) []middlewares.Access {
+	var accesses []middlewares.Access
 	for _, binding := range bindings {
 		for verb, paths := range binding.AllowedEndpoints {
 			for _, path := range paths {
-				_, err = manager.AddNewAccess(binding.Role, verb, path)
-				if err != nil {
-					return nil, err
-				}
+				accesses = append(accesses, middlewares.Access{Role: binding.Role, Method: verb, Path: path})
 			}
 		}
 	}
-	return manager, nil
+	return accesses
+}
+
+// reloadAccessBindings swaps the access bindings of the running server,
+// the current ones stay in effect if the new bindings can't be read or are invalid.
+func reloadAccessBindings(manager middlewares.AccessManager, bindings []config.RoleEndpointBinding, err error,
+	lg *zap.Logger) {
+	if err != nil {
+		lg.Error("failed to reload access bindings, keeping the current ones", zap.Error(err))
+		return
+	}
+	added, removed, err := manager.ReplaceAccess(bindingAccesses(bindings))
+	if err != nil {
+		lg.Error("invalid access bindings, keeping the current ones", zap.Error(err))
+		return
+	}
+	for _, access := range added {
+		lg.Info("access granted", zap.Any("role", access.Role), zap.String("method", access.Method),
+			zap.String("path", access.Path))
+	}
+	for _, access := range removed {
+		lg.Info("access revoked", zap.Any("role", access.Role), zap.String("method", access.Method),
+			zap.String("path", access.Path))
+	}
+	lg.Info("access bindings reloaded", zap.Int("granted", len(added)), zap.Int("revoked", len(removed)))
 }
 
 func runUnblockPeriodically(ctx context.Context, client *ent.Client, checkPeriodDuration time.Duration, lg *zap.Logger) {
@@ -202,3 +337,59 @@ func runUnblockPeriodically(ctx context.Context, client *ent.Client, checkPeriod
 	pt.Start(checkPeriodDuration, f)
 	f()
 }
+
+func runExpiredTokensCleanupPeriodically(ctx context.Context, client *ent.Client, checkPeriodDuration time.Duration,
+	lg *zap.Logger) {
+	pt := timer.NewPeriodicTimer()
+	expiredTokensCleanup := cleanup.NewExpiredTokensCleanup(repositories.NewRegistrationConfirmRepository(),
+		repositories.NewPasswordResetRepository(), repositories.NewConfirmEmailRepository(),
+		repositories.NewLoginStateRepository(), lg)
+	f := func() {
+		if err := expiredTokensCleanup.Cleanup(ctx, client); err != nil {
+			lg.Error("error when deleting expired tokens", zap.Error(err))
+		}
+	}
+	pt.Start(checkPeriodDuration, f)
+	f()
+}
+
+func runOrphanedPhotosCleanupPeriodically(ctx context.Context, client *ent.Client, checkPeriodDuration,
+	gracePeriod time.Duration, blobStore domain.BlobStore, lg *zap.Logger) {
+	pt := timer.NewPeriodicTimer()
+	orphanedPhotosCleanup := cleanup.NewOrphanedPhotosCleanup(repositories.NewPhotoRepository(), blobStore,
+		gracePeriod, lg)
+	f := func() {
+		if err := orphanedPhotosCleanup.Cleanup(ctx, client); err != nil {
+			lg.Error("error when deleting orphaned photos", zap.Error(err))
+		}
+	}
+	pt.Start(checkPeriodDuration, f)
+	f()
+}
+
+func runMaintenanceSchedulePeriodically(ctx context.Context, client *ent.Client, checkPeriodDuration time.Duration,
+	lg *zap.Logger) {
+	pt := timer.NewPeriodicTimer()
+	maintenanceScheduler := maintenance.NewMaintenanceScheduler(repositories.NewMaintenancePlanRepository(),
+		repositories.NewEquipmentStatusRepository(), lg)
+	f := func() {
+		if err := maintenanceScheduler.Schedule(ctx, client); err != nil {
+			lg.Error("error when scheduling maintenance", zap.Error(err))
+		}
+	}
+	pt.Start(checkPeriodDuration, f)
+	f()
+}
+
+func runDeletedUsersPurgePeriodically(ctx context.Context, client *ent.Client, checkPeriodDuration,
+	retentionPeriod time.Duration, lg *zap.Logger) {
+	pt := timer.NewPeriodicTimer()
+	deletedUsersPurge := cleanup.NewDeletedUsersPurge(repositories.NewUserRepository(), retentionPeriod, lg)
+	f := func() {
+		if err := deletedUsersPurge.Purge(ctx, client); err != nil {
+			lg.Error("error when purging deleted users", zap.Error(err))
+		}
+	}
+	pt.Start(checkPeriodDuration, f)
+	f()
+}
@@ -0,0 +1,21 @@
+package blobstore
+
+import (
+	"fmt"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/config"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+// New creates the store of the configured backend.
+func New(cfg config.PhotoStorage) (domain.BlobStore, error) {
+	switch cfg.Backend {
+	case config.StorageDB:
+		return NewDBStore(), nil
+	case config.StorageLocal:
+		return NewLocalStore(cfg.LocalDir)
+	case config.StorageS3:
+		return NewS3Store(cfg.S3)
+	}
+	return nil, fmt.Errorf("unknown photo storage backend %q", cfg.Backend)
+}
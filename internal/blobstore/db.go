@@ -0,0 +1,70 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/blob"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type dbStore struct {
+}
+
+// NewDBStore keeps the content in the blobs table with the key as the id. It uses the transaction
+// from the context.
+func NewDBStore() domain.BlobStore {
+	return &dbStore{}
+}
+
+func (s *dbStore) Put(ctx context.Context, key string, content io.Reader, _ int64) error {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	updated, err := tx.Blob.Update().Where(blob.ID(key)).SetContent(data).Save(ctx)
+	if err != nil || updated > 0 {
+		return err
+	}
+	return tx.Blob.Create().SetID(key).SetContent(data).Exec(ctx)
+}
+
+func (s *dbStore) Get(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b, err := tx.Blob.Get(ctx, key)
+	if ent.IsNotFound(err) {
+		return nil, domain.ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nopCloser{bytes.NewReader(b.Content)}, nil
+}
+
+// nopCloser is the content read from the row, there is nothing to close.
+type nopCloser struct {
+	*bytes.Reader
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+func (s *dbStore) Delete(ctx context.Context, key string) error {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Blob.Delete().Where(blob.ID(key)).Exec(ctx)
+	return err
+}
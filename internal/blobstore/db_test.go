@@ -0,0 +1,92 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/enttest"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+func txContext(t *testing.T, client *ent.Client) (context.Context, *ent.Tx) {
+	t.Helper()
+	ctx := context.Background()
+	tx, err := client.Tx(ctx)
+	require.NoError(t, err)
+	return context.WithValue(ctx, middlewares.TxContextKey, tx), tx
+}
+
+func TestDBStore(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:blobstore_db?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+	ctx, tx := txContext(t, client)
+	store := NewDBStore()
+	_, err := store.Get(ctx, "photo")
+	require.ErrorIs(t, err, domain.ErrBlobNotFound)
+
+	require.NoError(t, store.Put(ctx, "photo", strings.NewReader("content"), 7))
+	content, err := store.Get(ctx, "photo")
+	require.NoError(t, err)
+	data, err := io.ReadAll(content)
+	require.NoError(t, err)
+	require.Equal(t, "content", string(data))
+	_, err = content.Seek(3, io.SeekStart)
+	require.NoError(t, err)
+	data, err = io.ReadAll(content)
+	require.NoError(t, err)
+	require.Equal(t, "tent", string(data))
+
+	require.NoError(t, store.Put(ctx, "photo", strings.NewReader("replaced"), 8))
+	content, err = store.Get(ctx, "photo")
+	require.NoError(t, err)
+	data, err = io.ReadAll(content)
+	require.NoError(t, err)
+	require.Equal(t, "replaced", string(data))
+
+	require.NoError(t, store.Delete(ctx, "photo"))
+	_, err = store.Get(ctx, "photo")
+	require.ErrorIs(t, err, domain.ErrBlobNotFound)
+	require.NoError(t, tx.Commit())
+}
+
+func TestMoveFromDB(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:blobstore_move?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+	for _, id := range []string{"first", "second"} {
+		_, err := client.Blob.Create().SetID(id).SetContent([]byte(id)).Save(context.Background())
+		require.NoError(t, err)
+	}
+	target, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	ctx, tx := txContext(t, client)
+	moved, err := MoveFromDB(ctx, target, 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, moved)
+	require.NoError(t, tx.Commit())
+
+	ctx, tx = txContext(t, client)
+	moved, err = MoveFromDB(ctx, target, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, moved)
+	moved, err = MoveFromDB(ctx, target, 10)
+	require.NoError(t, err)
+	require.Zero(t, moved)
+	require.NoError(t, tx.Commit())
+
+	for _, id := range []string{"first", "second"} {
+		content, err := target.Get(context.Background(), id)
+		require.NoError(t, err)
+		data, err := io.ReadAll(content)
+		require.NoError(t, err)
+		require.NoError(t, content.Close())
+		require.Equal(t, id, string(data))
+	}
+}
@@ -0,0 +1,57 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "photos")
+	store, err := NewLocalStore(dir)
+	require.NoError(t, err)
+
+	_, err = store.Get(ctx, "photo")
+	require.ErrorIs(t, err, domain.ErrBlobNotFound)
+
+	require.NoError(t, store.Put(ctx, "photo", strings.NewReader("content"), 7))
+	content, err := store.Get(ctx, "photo")
+	require.NoError(t, err)
+	_, err = content.Seek(3, io.SeekStart)
+	require.NoError(t, err)
+	data, err := io.ReadAll(content)
+	require.NoError(t, err)
+	require.NoError(t, content.Close())
+	require.Equal(t, "tent", string(data))
+
+	require.NoError(t, store.Put(ctx, "photo", strings.NewReader("replaced"), 8))
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	require.NoError(t, store.Delete(ctx, "photo"))
+	require.NoError(t, store.Delete(ctx, "photo"))
+	_, err = store.Get(ctx, "photo")
+	require.ErrorIs(t, err, domain.ErrBlobNotFound)
+}
+
+func TestLocalStore_InvalidKey(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "..", "../photo", "dir/photo", `dir\photo`} {
+		require.Error(t, store.Put(ctx, key, strings.NewReader("content"), 7), key)
+		_, err = store.Get(ctx, key)
+		require.Error(t, err, key)
+		require.Error(t, store.Delete(ctx, key), key)
+	}
+}
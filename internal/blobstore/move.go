@@ -0,0 +1,35 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/blob"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+// MoveFromDB moves up to limit blobs from the database to the store and returns the number of the blobs
+// moved. It uses the transaction from the context, the blobs are deleted only when it commits, so the
+// blobs put to the store before a failure are moved again on the next run.
+func MoveFromDB(ctx context.Context, target domain.BlobStore, limit int) (int, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	blobs, err := tx.Blob.Query().Order(ent.Asc(blob.FieldID)).Limit(limit).All(ctx)
+	if err != nil {
+		return 0, err
+	}
+	source := NewDBStore()
+	for _, b := range blobs {
+		if err = target.Put(ctx, b.ID, bytes.NewReader(b.Content), int64(len(b.Content))); err != nil {
+			return 0, err
+		}
+		if err = source.Delete(ctx, b.ID); err != nil {
+			return 0, err
+		}
+	}
+	return len(blobs), nil
+}
@@ -0,0 +1,157 @@
+package blobstore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/config"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+// fakeS3 is the in-memory stand-in for MinIO serving the objects of the path-style bucket.
+type fakeS3 struct {
+	mu      sync.Mutex
+	bucket  string
+	objects map[string][]byte
+	// ranges are the Range headers of the GET requests
+	ranges []string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	key, found := strings.CutPrefix(r.URL.Path, "/"+f.bucket+"/")
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+			body = decodeChunked(body)
+		}
+		f.objects[key] = body
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodHead, http.MethodGet:
+		content, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		if r.Method == http.MethodGet {
+			f.ranges = append(f.ranges, r.Header.Get("Range"))
+			http.ServeContent(w, r, key, time.Time{}, bytes.NewReader(content))
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// decodeChunked strips the signatures of the chunks the client sends over plain HTTP.
+func decodeChunked(body []byte) []byte {
+	var result []byte
+	reader := bufio.NewReader(bytes.NewReader(body))
+	for {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return result
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(header), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil || size == 0 {
+			return result
+		}
+		chunk := make([]byte, size+2)
+		if _, err = io.ReadFull(reader, chunk); err != nil {
+			return result
+		}
+		result = append(result, chunk[:size]...)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeS3{bucket: "photos", objects: make(map[string][]byte)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store, err := NewS3Store(config.S3Storage{
+		Endpoint:        strings.TrimPrefix(server.URL, "http://"),
+		Region:          "us-east-1",
+		Bucket:          "photos",
+		AccessKeyID:     "access",
+		SecretAccessKey: "secret",
+	})
+	require.NoError(t, err)
+
+	_, err = store.Get(ctx, "photo")
+	require.ErrorIs(t, err, domain.ErrBlobNotFound)
+
+	content := bytes.Repeat([]byte("content"), 1000)
+	require.NoError(t, store.Put(ctx, "photo", bytes.NewReader(content), int64(len(content))))
+	require.Equal(t, content, fake.objects["photo"])
+
+	reader, err := store.Get(ctx, "photo")
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.Equal(t, content, data)
+
+	// the seek requests the rest of the object only
+	reader, err = store.Get(ctx, "photo")
+	require.NoError(t, err)
+	_, err = reader.Seek(int64(len(content)-7), io.SeekStart)
+	require.NoError(t, err)
+	data, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.Equal(t, "content", string(data))
+	require.Equal(t, fmt.Sprintf("bytes=%d-", len(content)-7), fake.ranges[len(fake.ranges)-1])
+
+	require.NoError(t, store.Delete(ctx, "photo"))
+	require.Empty(t, fake.objects)
+}
+
+func TestS3Store_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	store, err := NewS3Store(config.S3Storage{
+		Endpoint: strings.TrimPrefix(server.URL, "http://"),
+		Region:   "us-east-1",
+		Bucket:   "photos",
+	})
+	require.NoError(t, err)
+	_, err = store.Get(context.Background(), "photo")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, domain.ErrBlobNotFound, fmt.Sprint(err))
+}
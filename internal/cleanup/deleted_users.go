@@ -0,0 +1,55 @@
+package cleanup
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type deletedUsersPurge struct {
+	userRepository  domain.UserRepository
+	retentionPeriod time.Duration
+	logger          *zap.Logger
+}
+
+func NewDeletedUsersPurge(userRepository domain.UserRepository, retentionPeriod time.Duration,
+	logger *zap.Logger) domain.DeletedUsersPurge {
+	return &deletedUsersPurge{
+		userRepository:  userRepository,
+		retentionPeriod: retentionPeriod,
+		logger:          logger,
+	}
+}
+
+// Purge anonymises the users deleted more than the retention period ago.
+// Until then administrators can restore them.
+func (p *deletedUsersPurge) Purge(ctx context.Context, cln *ent.Client) (err error) {
+	tx, err := cln.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+
+	purged, err := p.userRepository.PurgeDeletedUsers(ctx, time.Now().Add(-p.retentionPeriod))
+	if err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	if purged > 0 {
+		p.logger.Info("deleted users purged", zap.Int("users", purged))
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/enttest"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/mocks"
+)
+
+func TestDeletedUsersPurge_Purge(t *testing.T) {
+	ctx := context.Background()
+	client := enttest.Open(t, "sqlite3", "file:deletedusers?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+
+	retentionPeriod := 24 * time.Hour
+	userRepository := &mocks.UserRepository{}
+	userRepository.On("PurgeDeletedUsers", mock.Anything, mock.MatchedBy(func(deletedBefore time.Time) bool {
+		return time.Since(deletedBefore) >= retentionPeriod && time.Since(deletedBefore) < retentionPeriod+time.Minute
+	})).Return(2, nil)
+
+	err := NewDeletedUsersPurge(userRepository, retentionPeriod, zap.NewNop()).Purge(ctx, client)
+	require.NoError(t, err)
+	userRepository.AssertExpectations(t)
+}
+
+func TestDeletedUsersPurge_Purge_RepoErr(t *testing.T) {
+	ctx := context.Background()
+	client := enttest.Open(t, "sqlite3", "file:deletedusers?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+
+	err := errors.New("error")
+	userRepository := &mocks.UserRepository{}
+	userRepository.On("PurgeDeletedUsers", mock.Anything, mock.Anything).Return(0, err)
+
+	errReturn := NewDeletedUsersPurge(userRepository, time.Hour, zap.NewNop()).Purge(ctx, client)
+	require.ErrorIs(t, errReturn, err)
+	userRepository.AssertExpectations(t)
+}
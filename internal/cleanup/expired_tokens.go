@@ -0,0 +1,76 @@
+package cleanup
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type expiredTokensCleanup struct {
+	registrationConfirmRepository domain.RegistrationConfirmRepository
+	passwordResetRepository       domain.PasswordResetRepository
+	emailConfirmRepository        domain.EmailConfirmRepository
+	loginStateRepository          domain.LoginStateRepository
+	logger                        *zap.Logger
+}
+
+func NewExpiredTokensCleanup(registrationConfirmRepository domain.RegistrationConfirmRepository,
+	passwordResetRepository domain.PasswordResetRepository, emailConfirmRepository domain.EmailConfirmRepository,
+	loginStateRepository domain.LoginStateRepository, logger *zap.Logger) domain.ExpiredTokensCleanup {
+	return &expiredTokensCleanup{
+		registrationConfirmRepository: registrationConfirmRepository,
+		passwordResetRepository:       passwordResetRepository,
+		emailConfirmRepository:        emailConfirmRepository,
+		loginStateRepository:          loginStateRepository,
+		logger:                        logger,
+	}
+}
+
+// Cleanup deletes expired registration confirmation, password reset and email confirmation tokens
+// and abandoned OIDC login states.
+// Tokens are otherwise deleted only when somebody uses them.
+func (c *expiredTokensCleanup) Cleanup(ctx context.Context, cln *ent.Client) (err error) {
+	tx, err := cln.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+
+	registrationConfirms, err := c.registrationConfirmRepository.DeleteExpiredTokens(ctx)
+	if err != nil {
+		return err
+	}
+	passwordResets, err := c.passwordResetRepository.DeleteExpiredTokens(ctx)
+	if err != nil {
+		return err
+	}
+	emailConfirms, err := c.emailConfirmRepository.DeleteExpiredTokens(ctx)
+	if err != nil {
+		return err
+	}
+	loginStates, err := c.loginStateRepository.DeleteExpiredTokens(ctx)
+	if err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	if registrationConfirms+passwordResets+emailConfirms+loginStates > 0 {
+		c.logger.Info("expired tokens deleted",
+			zap.Int("registration confirmations", registrationConfirms),
+			zap.Int("password resets", passwordResets),
+			zap.Int("email confirmations", emailConfirms),
+			zap.Int("login states", loginStates))
+	}
+	return nil
+}
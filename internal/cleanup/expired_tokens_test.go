@@ -0,0 +1,75 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/enttest"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/mocks"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type ExpiredTokensCleanupTestSuite struct {
+	suite.Suite
+	registrationConfirmRepository *mocks.RegistrationConfirmRepository
+	passwordResetRepository       *mocks.PasswordResetRepository
+	emailConfirmRepository        *mocks.EmailConfirmRepository
+	loginStateRepository          *mocks.LoginStateRepository
+	cleanup                       domain.ExpiredTokensCleanup
+}
+
+func TestExpiredTokensCleanupSuite(t *testing.T) {
+	suite.Run(t, new(ExpiredTokensCleanupTestSuite))
+}
+
+func (s *ExpiredTokensCleanupTestSuite) SetupTest() {
+	s.registrationConfirmRepository = &mocks.RegistrationConfirmRepository{}
+	s.passwordResetRepository = &mocks.PasswordResetRepository{}
+	s.emailConfirmRepository = &mocks.EmailConfirmRepository{}
+	s.loginStateRepository = &mocks.LoginStateRepository{}
+	s.cleanup = NewExpiredTokensCleanup(s.registrationConfirmRepository, s.passwordResetRepository,
+		s.emailConfirmRepository, s.loginStateRepository, zap.NewNop())
+}
+
+func (s *ExpiredTokensCleanupTestSuite) TestExpiredTokensCleanup_Cleanup_OK() {
+	t := s.T()
+	ctx := context.Background()
+	client := enttest.Open(t, "sqlite3", "file:expiredtokens?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+
+	s.registrationConfirmRepository.On("DeleteExpiredTokens", mock.Anything).Return(1, nil)
+	s.passwordResetRepository.On("DeleteExpiredTokens", mock.Anything).Return(2, nil)
+	s.emailConfirmRepository.On("DeleteExpiredTokens", mock.Anything).Return(0, nil)
+	s.loginStateRepository.On("DeleteExpiredTokens", mock.Anything).Return(3, nil)
+
+	err := s.cleanup.Cleanup(ctx, client)
+	require.NoError(t, err)
+	s.registrationConfirmRepository.AssertExpectations(t)
+	s.passwordResetRepository.AssertExpectations(t)
+	s.emailConfirmRepository.AssertExpectations(t)
+	s.loginStateRepository.AssertExpectations(t)
+}
+
+func (s *ExpiredTokensCleanupTestSuite) TestExpiredTokensCleanup_Cleanup_RepoErr() {
+	t := s.T()
+	ctx := context.Background()
+	client := enttest.Open(t, "sqlite3", "file:expiredtokens?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+
+	err := errors.New("error")
+	s.registrationConfirmRepository.On("DeleteExpiredTokens", mock.Anything).Return(1, nil)
+	s.passwordResetRepository.On("DeleteExpiredTokens", mock.Anything).Return(0, err)
+
+	errReturn := s.cleanup.Cleanup(ctx, client)
+	require.ErrorIs(t, errReturn, err)
+	s.registrationConfirmRepository.AssertExpectations(t)
+	s.passwordResetRepository.AssertExpectations(t)
+	s.emailConfirmRepository.AssertExpectations(t)
+}
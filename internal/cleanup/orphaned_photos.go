@@ -0,0 +1,74 @@
+package cleanup
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/images"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type orphanedPhotosCleanup struct {
+	photoRepository domain.PhotoRepository
+	blobStore       domain.BlobStore
+	gracePeriod     time.Duration
+	logger          *zap.Logger
+}
+
+func NewOrphanedPhotosCleanup(photoRepository domain.PhotoRepository, blobStore domain.BlobStore,
+	gracePeriod time.Duration, logger *zap.Logger) domain.OrphanedPhotosCleanup {
+	return &orphanedPhotosCleanup{
+		photoRepository: photoRepository,
+		blobStore:       blobStore,
+		gracePeriod:     gracePeriod,
+		logger:          logger,
+	}
+}
+
+// Cleanup deletes the photos uploaded more than the grace period ago which are in no gallery and no order status
+// report, with the content of the original and the renditions. Such photos are left when the equipment form is
+// abandoned after the upload, the photo is removed from the gallery or the equipment is deleted.
+// The photos are deleted before their content, so a failure never leaves a photo without the content: the content
+// which fails to be deleted stays in the blob store as garbage and is only logged.
+func (c *orphanedPhotosCleanup) Cleanup(ctx context.Context, cln *ent.Client) (err error) {
+	tx, err := cln.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	txCtx := context.WithValue(ctx, middlewares.TxContextKey, tx)
+
+	ids, err := c.photoRepository.OrphanedPhotoIDs(txCtx, time.Now().Add(-c.gracePeriod))
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err = c.photoRepository.DeletePhotoByID(txCtx, id); err != nil {
+			return err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		for _, key := range images.Keys(id) {
+			if errDelete := c.blobStore.Delete(ctx, key); errDelete != nil {
+				c.logger.Error("failed to delete the content of the orphaned photo", zap.String("key", key),
+					zap.Error(errDelete))
+			}
+		}
+	}
+	if len(ids) > 0 {
+		c.logger.Info("orphaned photos deleted", zap.Int("photos", len(ids)))
+	}
+	return nil
+}
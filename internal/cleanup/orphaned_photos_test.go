@@ -0,0 +1,81 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/enttest"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/mocks"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/images"
+)
+
+func TestOrphanedPhotosCleanup_Cleanup(t *testing.T) {
+	ctx := context.Background()
+	client := enttest.Open(t, "sqlite3", "file:orphanedphotos?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+
+	gracePeriod := 24 * time.Hour
+	photoRepository := &mocks.PhotoRepository{}
+	photoRepository.On("OrphanedPhotoIDs", mock.Anything, mock.MatchedBy(func(createdBefore time.Time) bool {
+		return time.Since(createdBefore) >= gracePeriod && time.Since(createdBefore) < gracePeriod+time.Minute
+	})).Return([]string{"first", "second"}, nil)
+	blobStore := &mocks.BlobStore{}
+	for _, id := range []string{"first", "second"} {
+		photoRepository.On("DeletePhotoByID", mock.Anything, id).Return(nil)
+		for _, key := range images.Keys(id) {
+			blobStore.On("Delete", mock.Anything, key).Return(nil)
+		}
+	}
+
+	err := NewOrphanedPhotosCleanup(photoRepository, blobStore, gracePeriod, zap.NewNop()).Cleanup(ctx, client)
+	require.NoError(t, err)
+	photoRepository.AssertExpectations(t)
+	blobStore.AssertExpectations(t)
+}
+
+func TestOrphanedPhotosCleanup_Cleanup_BlobErr(t *testing.T) {
+	ctx := context.Background()
+	client := enttest.Open(t, "sqlite3", "file:orphanedphotos?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+
+	// the photos are deleted anyway, the content failing to be deleted is left as garbage
+	photoRepository := &mocks.PhotoRepository{}
+	photoRepository.On("OrphanedPhotoIDs", mock.Anything, mock.Anything).Return([]string{"first", "second"}, nil)
+	blobStore := &mocks.BlobStore{}
+	for _, id := range []string{"first", "second"} {
+		photoRepository.On("DeletePhotoByID", mock.Anything, id).Return(nil)
+		for _, key := range images.Keys(id) {
+			blobStore.On("Delete", mock.Anything, key).Return(errors.New("error")).Once()
+		}
+	}
+
+	err := NewOrphanedPhotosCleanup(photoRepository, blobStore, time.Hour, zap.NewNop()).Cleanup(ctx, client)
+	require.NoError(t, err)
+	photoRepository.AssertExpectations(t)
+	blobStore.AssertExpectations(t)
+}
+
+func TestOrphanedPhotosCleanup_Cleanup_RepoErr(t *testing.T) {
+	ctx := context.Background()
+	client := enttest.Open(t, "sqlite3", "file:orphanedphotos?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+
+	// the content is kept when the photos are not deleted
+	err := errors.New("error")
+	photoRepository := &mocks.PhotoRepository{}
+	photoRepository.On("OrphanedPhotoIDs", mock.Anything, mock.Anything).Return([]string{"first", "second"}, nil)
+	photoRepository.On("DeletePhotoByID", mock.Anything, "first").Return(nil)
+	photoRepository.On("DeletePhotoByID", mock.Anything, "second").Return(err)
+	blobStore := &mocks.BlobStore{}
+
+	errReturn := NewOrphanedPhotosCleanup(photoRepository, blobStore, time.Hour, zap.NewNop()).Cleanup(ctx, client)
+	require.ErrorIs(t, errReturn, err)
+	photoRepository.AssertExpectations(t)
+	blobStore.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
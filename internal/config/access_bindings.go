@@ -0,0 +1,72 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+const accessBindingsKey = "accessbindings"
+
+// AccessBindingsHandler receives the access bindings read from the config file or the error of reading them.
+type AccessBindingsHandler func(bindings []RoleEndpointBinding, err error)
+
+// WatchAccessBindings rereads the access bindings when the config file changes or the process receives SIGHUP
+// until ctx is done. GetAppConfig must be called before, so the config file is already known to viper.
+// viper is not safe for concurrent use, so the config is reread only by the single watching goroutine.
+func WatchAccessBindings(ctx context.Context, handler AccessBindingsHandler) error {
+	configFile := filepath.Clean(viper.ConfigFileUsed())
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config watcher: %w", err)
+	}
+	// the directory is watched as editors and config maps replace the file instead of writing to it
+	if err = watcher.Add(filepath.Dir(configFile)); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch config directory: %w", err)
+	}
+
+	reload := func() {
+		if err := viper.ReadInConfig(); err != nil {
+			handler(nil, fmt.Errorf("failed to read in config: %w", err))
+			return
+		}
+		handler(readAccessBindings())
+	}
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		defer watcher.Close()
+		defer signal.Stop(hangup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hangup:
+				reload()
+			case event := <-watcher.Events:
+				if filepath.Clean(event.Name) == configFile && event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+					reload()
+				}
+			case err := <-watcher.Errors:
+				handler(nil, fmt.Errorf("failed to watch config: %w", err))
+			}
+		}
+	}()
+	return nil
+}
+
+func readAccessBindings() ([]RoleEndpointBinding, error) {
+	var bindings []RoleEndpointBinding
+	if err := viper.UnmarshalKey(accessBindingsKey, &bindings); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal access bindings: %w", err)
+	}
+	return bindings, nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAccessBindingsConfig(t *testing.T, file, slug string) {
+	content := fmt.Sprintf(`{"accessBindings": [{"role": {"slug": %q}, "allowedEndpoints": {"get": ["/v1/x"]}}]}`,
+		slug)
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+}
+
+func TestWatchAccessBindings(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.json")
+	writeAccessBindingsConfig(t, file, "user")
+	viper.SetConfigFile(file)
+	require.NoError(t, viper.ReadInConfig())
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		viper.Reset()
+	})
+
+	reloaded := make(chan string, 10)
+	err := WatchAccessBindings(ctx, func(bindings []RoleEndpointBinding, err error) {
+		if err != nil {
+			reloaded <- err.Error()
+			return
+		}
+		reloaded <- bindings[0].Role.Slug
+	})
+	require.NoError(t, err)
+
+	waitFor := func(slug string) {
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case got := <-reloaded:
+				if got == slug {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("access bindings with %q were not reloaded", slug)
+			}
+		}
+	}
+
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	waitFor("user")
+
+	writeAccessBindingsConfig(t, file, "manager")
+	waitFor("manager")
+
+	// a replaced file is reread as well
+	replaced := file + ".new"
+	writeAccessBindingsConfig(t, replaced, "operator")
+	require.NoError(t, os.Rename(replaced, file))
+	waitFor("operator")
+}
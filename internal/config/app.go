@@ -1,20 +1,29 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/go-playground/validator"
 	"github.com/spf13/viper"
 
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/encryption"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
 )
 
 type AppConfig struct {
 	Password              Password
+	TwoFactor             TwoFactor
+	OIDC                  OIDC
 	JWTSecretKey          string `validate:"required"`
 	Email                 Email
 	PeriodicCheckDuration time.Duration `validate:"required"`
+	UserDeletion          UserDeletion
+	PassportEncryption    PassportEncryption
+	PhotoStorage          PhotoStorage
+	PhotoUpload           PhotoUpload
+	PhotoCleanup          PhotoCleanup
 	Server                Server
 	DB                    DB
 	AccessBindings        []RoleEndpointBinding
@@ -60,7 +69,123 @@ type Email struct {
 
 type Password struct {
 	ResetLinkExpiration time.Duration `validate:"required"`
-	Length              int           `validate:"required,gte=8"`
+	Policy              PasswordPolicy
+}
+
+type PasswordPolicy struct {
+	MinLength        int `validate:"required,gte=6"`
+	RequireLowercase bool
+	RequireUppercase bool
+	RequireDigit     bool
+	RequireSpecial   bool
+	// ForbidUserData rejects passwords equal to the login or email of the user.
+	ForbidUserData bool
+	// ForbidCommon rejects passwords from the bundled list of commonly used passwords.
+	ForbidCommon bool
+}
+
+type TwoFactor struct {
+	Issuer              string        `validate:"required"`
+	ChallengeExpiration time.Duration `validate:"required"`
+	RecoveryCodesCount  int           `validate:"required,gte=1"`
+	// MaxChallengeAttempts is the number of wrong codes after which the login challenge is invalidated.
+	MaxChallengeAttempts int `validate:"required,gte=1"`
+	// RequiredForFullAccessRoles makes administrators, managers and operators enable two-factor authentication
+	// before they can use any other endpoint.
+	RequiredForFullAccessRoles bool
+}
+
+// OIDC configures login through the OpenID Connect identity provider with the authorization code flow.
+type OIDC struct {
+	Enabled      bool
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the frontend page the identity provider redirects to, it posts the code and the state back.
+	RedirectURL     string
+	Scopes          []string
+	StateExpiration time.Duration
+}
+
+func (o OIDC) validate() error {
+	if !o.Enabled {
+		return nil
+	}
+	if o.Issuer == "" || o.ClientID == "" || o.RedirectURL == "" || o.StateExpiration <= 0 {
+		return errors.New("issuer, client id, redirect url and state expiration are required when oidc is enabled")
+	}
+	return nil
+}
+
+// UserDeletion configures how long the deleted users can be restored before their personal data is anonymised.
+type UserDeletion struct {
+	RetentionPeriod time.Duration `validate:"required"`
+}
+
+// PassportEncryption configures the keys the passport data of the users is encrypted with. Keys are comma
+// separated version:key pairs with 32 byte keys encoded in base64. To rotate the key add the new one, make it
+// current and run the encrypt_passports command, then the old key can be removed.
+// There are no default keys, they must be set with the PASSPORT_ENCRYPTION_KEYS environment variable.
+type PassportEncryption struct {
+	CurrentKey string `validate:"required"`
+	Keys       string `validate:"required"`
+}
+
+func (p PassportEncryption) Keyring() (*encryption.Keyring, error) {
+	keys, err := encryption.ParseKeys(p.Keys)
+	if err != nil {
+		return nil, err
+	}
+	return encryption.NewKeyring(p.CurrentKey, keys)
+}
+
+// Backends of the photo storage.
+const (
+	StorageDB    = "db"
+	StorageLocal = "local"
+	StorageS3    = "s3"
+)
+
+// PhotoStorage selects where the content of the photos is kept. Photos stored before the backend is changed
+// are moved from the database by the move_photos command.
+type PhotoStorage struct {
+	Backend  string `validate:"required,oneof=db local s3"`
+	LocalDir string
+	S3       S3Storage
+}
+
+type S3Storage struct {
+	// Endpoint is the host and the port of the S3 compatible storage like AWS S3 or MinIO.
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	UseSSL          bool
+}
+
+func (s PhotoStorage) validate() error {
+	switch {
+	case s.Backend == StorageLocal && s.LocalDir == "":
+		return errors.New("local directory is required for the local storage")
+	case s.Backend == StorageS3 && (s.S3.Endpoint == "" || s.S3.Bucket == ""):
+		return errors.New("endpoint and bucket are required for the s3 storage")
+	}
+	return nil
+}
+
+// PhotoUpload limits the uploaded photos. The dimensions are checked before the photo is decoded, so the small
+// files of the huge images can't take all the memory.
+type PhotoUpload struct {
+	MaxFileSize int64 `validate:"required,gt=0"`
+	MaxWidth    int   `validate:"required,gt=0"`
+	MaxHeight   int   `validate:"required,gt=0"`
+}
+
+// PhotoCleanup configures how long the photos which are in no gallery are kept. The photos are uploaded before
+// the equipment is created, so the grace period must be longer than it takes to fill in the equipment form.
+type PhotoCleanup struct {
+	GracePeriod time.Duration `validate:"required"`
 }
 
 type Server struct {
@@ -95,6 +220,15 @@ func GetAppConfig(additionalDirectories ...string) (*AppConfig, error) {
 	if err := validator.New().Struct(conf); err != nil {
 		return nil, fmt.Errorf("failed to validate config: %w", err)
 	}
+	if err := conf.OIDC.validate(); err != nil {
+		return nil, fmt.Errorf("failed to validate oidc config: %w", err)
+	}
+	if err := conf.PhotoStorage.validate(); err != nil {
+		return nil, fmt.Errorf("failed to validate photo storage config: %w", err)
+	}
+	if _, err := conf.PassportEncryption.Keyring(); err != nil {
+		return nil, fmt.Errorf("failed to validate passport encryption config: %w", err)
+	}
 
 	return conf, nil
 }
@@ -108,14 +242,45 @@ func getDefaultConfig() *AppConfig {
 			Database: "stage_csr",
 		},
 		Password: Password{
-			Length:              8,
 			ResetLinkExpiration: 15 * time.Minute,
+			Policy: PasswordPolicy{
+				MinLength:        8,
+				RequireLowercase: true,
+				RequireUppercase: true,
+				RequireDigit:     true,
+				ForbidUserData:   true,
+				ForbidCommon:     true,
+			},
+		},
+		TwoFactor: TwoFactor{
+			Issuer:               "CSR",
+			ChallengeExpiration:  5 * time.Minute,
+			RecoveryCodesCount:   10,
+			MaxChallengeAttempts: 5,
+		},
+		OIDC: OIDC{
+			Scopes:          []string{"openid", "email", "profile"},
+			StateExpiration: 10 * time.Minute,
 		},
 		Email: Email{
 			Password:              "default_value",
 			SenderWebsiteUrl:      "https://csr.golangforall.com/",
 			ConfirmLinkExpiration: 15 * time.Minute,
 		},
+		UserDeletion: UserDeletion{
+			RetentionPeriod: 30 * 24 * time.Hour,
+		},
+		PhotoStorage: PhotoStorage{
+			Backend: StorageDB,
+		},
+		PhotoUpload: PhotoUpload{
+			MaxFileSize: 10 << 20,
+			MaxWidth:    8000,
+			MaxHeight:   8000,
+		},
+		PhotoCleanup: PhotoCleanup{
+			GracePeriod: 24 * time.Hour,
+		},
 		Server: Server{
 			Host: "0.0.0.0",
 			Port: 8080,
@@ -127,6 +292,10 @@ func bindEnvVars() {
 	viper.BindEnv("jwtsecretkey", "JWT_SECRET_KEY")
 	viper.BindEnv("email.password", "EMAIL_PASSWORD")
 	viper.BindEnv("db.user", "DB_USER")
+	viper.BindEnv("oidc.clientsecret", "OIDC_CLIENT_SECRET")
+	viper.BindEnv("passportencryption.keys", "PASSPORT_ENCRYPTION_KEYS")
+	viper.BindEnv("photostorage.s3.accesskeyid", "PHOTO_STORAGE_S3_ACCESS_KEY_ID")
+	viper.BindEnv("photostorage.s3.secretaccesskey", "PHOTO_STORAGE_S3_SECRET_ACCESS_KEY")
 
 	viper.AutomaticEnv()
 }
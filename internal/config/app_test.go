@@ -2,14 +2,62 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
 
+const testPassportEncryptionKeys = "1:p93ArxMmooRtjkHbcAz6JkyNS7XmCVlZMD+SZ4MWKfc="
+
 func TestGetAppConfig(t *testing.T) {
+	t.Setenv("PASSPORT_ENCRYPTION_KEYS", testPassportEncryptionKeys)
 	cfg, err := GetAppConfig("../..")
 	require.NoError(t, err)
 
 	require.Equal(t, "0.0.0.0", cfg.Server.Host)
 	require.Equal(t, 8080, cfg.Server.Port)
+	require.Equal(t, 30*24*time.Hour, cfg.UserDeletion.RetentionPeriod)
+	require.Equal(t, "1", cfg.PassportEncryption.CurrentKey)
+	require.Equal(t, StorageDB, cfg.PhotoStorage.Backend)
+	require.Equal(t, int64(10<<20), cfg.PhotoUpload.MaxFileSize)
+	require.Equal(t, 8000, cfg.PhotoUpload.MaxWidth)
+	require.Equal(t, 24*time.Hour, cfg.PhotoCleanup.GracePeriod)
+}
+
+func TestGetAppConfig_NoPassportEncryptionKeys(t *testing.T) {
+	t.Setenv("PASSPORT_ENCRYPTION_KEYS", "")
+	_, err := GetAppConfig("../..")
+	require.ErrorContains(t, err, "Keys")
+}
+
+func TestOIDC_validate(t *testing.T) {
+	require.NoError(t, OIDC{}.validate())
+	require.Error(t, OIDC{Enabled: true, Issuer: "https://idp.example.com"}.validate())
+	require.NoError(t, OIDC{
+		Enabled:         true,
+		Issuer:          "https://idp.example.com",
+		ClientID:        "csr",
+		RedirectURL:     "https://csr.example.com/oidc/callback",
+		StateExpiration: time.Minute,
+	}.validate())
+}
+
+func TestReadAccessBindings(t *testing.T) {
+	t.Setenv("PASSPORT_ENCRYPTION_KEYS", testPassportEncryptionKeys)
+	_, err := GetAppConfig("../..")
+	require.NoError(t, err)
+
+	bindings, err := readAccessBindings()
+	require.NoError(t, err)
+	require.NotEmpty(t, bindings)
+	require.Equal(t, "user", bindings[0].Role.Slug)
+	require.NotEmpty(t, bindings[0].AllowedEndpoints["get"])
+}
+
+func TestPhotoStorage_validate(t *testing.T) {
+	require.NoError(t, PhotoStorage{Backend: StorageDB}.validate())
+	require.Error(t, PhotoStorage{Backend: StorageLocal}.validate())
+	require.NoError(t, PhotoStorage{Backend: StorageLocal, LocalDir: "photos"}.validate())
+	require.Error(t, PhotoStorage{Backend: StorageS3, S3: S3Storage{Endpoint: "localhost:9000"}}.validate())
+	require.NoError(t, PhotoStorage{Backend: StorageS3, S3: S3Storage{Endpoint: "localhost:9000", Bucket: "photos"}}.validate())
 }
@@ -0,0 +1,16 @@
+-- +migrate Up
+ALTER TABLE "users" ADD "totp_secret" varchar NULL;
+ALTER TABLE "users" ADD "is_totp_enabled" bool NOT NULL DEFAULT false;
+
+CREATE TABLE "recovery_codes"(
+    "id" SERIAL PRIMARY KEY NOT NULL,
+    "code_hash" varchar(255) NOT NULL,
+    "user_recovery_codes" integer NULL,
+    FOREIGN KEY("user_recovery_codes")
+    REFERENCES "users"("id") ON DELETE CASCADE
+    );
+
+-- +migrate Down
+DROP TABLE IF EXISTS "recovery_codes";
+ALTER TABLE "users" DROP COLUMN "is_totp_enabled";
+ALTER TABLE "users" DROP COLUMN "totp_secret";
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- password reset tokens are stored as SHA-256 hashes now, plaintext tokens can't be looked up anymore
+DELETE FROM "password_resets";
+
+-- +migrate Down
+DELETE FROM "password_resets";
@@ -0,0 +1,9 @@
+-- +migrate Up
+-- registration and email confirmation tokens are stored as SHA-256 hashes now,
+-- plaintext tokens can't be looked up anymore
+DELETE FROM "registration_confirms";
+DELETE FROM "email_confirms";
+
+-- +migrate Down
+DELETE FROM "registration_confirms";
+DELETE FROM "email_confirms";
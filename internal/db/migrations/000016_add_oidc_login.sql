@@ -0,0 +1,14 @@
+-- +migrate Up
+ALTER TABLE "users" ADD "oidc_subject" varchar NULL UNIQUE;
+
+CREATE TABLE "login_states"(
+    "id" SERIAL PRIMARY KEY NOT NULL,
+    "ttl" timestamptz NOT NULL,
+    "state" varchar NOT NULL UNIQUE,
+    "code_verifier" varchar NOT NULL,
+    "nonce" varchar NOT NULL
+    );
+
+-- +migrate Down
+DROP TABLE IF EXISTS "login_states";
+ALTER TABLE "users" DROP COLUMN "oidc_subject";
@@ -0,0 +1,28 @@
+-- +migrate Up
+-- groups and permissions were not used before, their rows have no meaning
+DELETE FROM "groups";
+DELETE FROM "permissions";
+
+ALTER TABLE "groups" ADD "name" varchar NOT NULL UNIQUE;
+ALTER TABLE "groups" ADD "description" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "permissions" ALTER COLUMN "name" DROP DEFAULT;
+ALTER TABLE "permissions" ADD CONSTRAINT "permissions_name_key" UNIQUE ("name");
+ALTER TABLE "permissions" ADD "description" varchar NOT NULL DEFAULT '';
+
+INSERT INTO "permissions" ("name", "description") VALUES
+    ('equipment:block', 'Block and unblock equipment'),
+    ('orders:view', 'View all orders and their status history'),
+    ('orders:approve', 'Approve or reject orders in review'),
+    ('orders:prepare', 'Mark approved orders as prepared'),
+    ('orders:issue', 'Hand prepared orders over to the renter'),
+    ('orders:close', 'Close orders');
+
+-- +migrate Down
+DELETE FROM "permissions";
+ALTER TABLE "permissions" DROP COLUMN "description";
+ALTER TABLE "permissions" DROP CONSTRAINT "permissions_name_key";
+ALTER TABLE "permissions" ALTER COLUMN "name" SET DEFAULT 'unknown';
+
+ALTER TABLE "groups" DROP COLUMN "description";
+ALTER TABLE "groups" DROP COLUMN "name";
@@ -0,0 +1,26 @@
+-- +migrate Up
+CREATE TABLE "organizations"(
+    "id" SERIAL PRIMARY KEY NOT NULL,
+    "name" varchar NOT NULL,
+    "website" varchar NULL
+    );
+
+CREATE TABLE "organization_members"(
+    "id" SERIAL PRIMARY KEY NOT NULL,
+    "role" varchar NOT NULL DEFAULT 'member',
+    "organization_members" integer NOT NULL,
+    "user_organization_memberships" integer NOT NULL,
+    FOREIGN KEY("organization_members") REFERENCES "organizations"("id") ON DELETE NO ACTION,
+    FOREIGN KEY("user_organization_memberships") REFERENCES "users"("id") ON DELETE NO ACTION
+    );
+CREATE UNIQUE INDEX "organizationmember_organization_members_user_organization_memberships"
+    ON "organization_members"("organization_members", "user_organization_memberships");
+
+ALTER TABLE "orders" ADD "organization_orders" integer NULL;
+ALTER TABLE "orders" ADD CONSTRAINT "orders_organizations_orders"
+    FOREIGN KEY("organization_orders") REFERENCES "organizations"("id") ON DELETE SET NULL;
+
+-- +migrate Down
+ALTER TABLE "orders" DROP COLUMN "organization_orders";
+DROP TABLE IF EXISTS "organization_members";
+DROP TABLE IF EXISTS "organizations";
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE "users" ADD "deleted_at" timestamptz NULL;
+ALTER TABLE "users" ADD "purged_at" timestamptz NULL;
+-- the retention period of the users deleted before starts now
+UPDATE "users" SET "deleted_at" = now() WHERE "is_deleted";
+
+-- +migrate Down
+ALTER TABLE "users" DROP COLUMN "purged_at";
+ALTER TABLE "users" DROP COLUMN "deleted_at";
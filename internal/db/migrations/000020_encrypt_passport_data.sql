@@ -0,0 +1,15 @@
+-- +migrate Up
+-- passport data is encrypted by the application, the values don't fit into varchar(255) and the issue date
+-- is stored as the encrypted RFC 3339 string. Existing rows stay readable until encrypt_passports is run.
+ALTER TABLE "users" ALTER COLUMN "passport_series" TYPE text;
+ALTER TABLE "users" ALTER COLUMN "passport_number" TYPE text;
+ALTER TABLE "users" ALTER COLUMN "passport_authority" TYPE text;
+ALTER TABLE "users" ALTER COLUMN "passport_issue_date" TYPE text
+    USING to_char("passport_issue_date", 'YYYY-MM-DD"T"HH24:MI:SS"Z"');
+
+-- +migrate Down
+-- works only for the rows which were not encrypted yet
+ALTER TABLE "users" ALTER COLUMN "passport_issue_date" TYPE timestamp USING "passport_issue_date"::timestamp;
+ALTER TABLE "users" ALTER COLUMN "passport_authority" TYPE varchar(255);
+ALTER TABLE "users" ALTER COLUMN "passport_number" TYPE varchar(255);
+ALTER TABLE "users" ALTER COLUMN "passport_series" TYPE varchar(255);
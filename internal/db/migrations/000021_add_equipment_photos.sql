@@ -0,0 +1,27 @@
+-- +migrate Up
+CREATE TABLE "equipment_photos"(
+    "id" SERIAL PRIMARY KEY NOT NULL,
+    "position" integer NOT NULL DEFAULT 0,
+    "cover" boolean NOT NULL DEFAULT false,
+    "equipment_photos" integer NOT NULL,
+    "photo_equipment_photos" varchar(255) NOT NULL,
+    FOREIGN KEY("equipment_photos") REFERENCES "equipment"("id") ON DELETE NO ACTION,
+    FOREIGN KEY("photo_equipment_photos") REFERENCES "photos"("id") ON DELETE NO ACTION
+    );
+CREATE UNIQUE INDEX "equipmentphoto_equipment_photos_photo_equipment_photos"
+    ON "equipment_photos"("equipment_photos", "photo_equipment_photos");
+
+-- the only photo of the equipment becomes the cover of its gallery
+INSERT INTO "equipment_photos"("position", "cover", "equipment_photos", "photo_equipment_photos")
+SELECT 0, true, "id", "photo_equipments" FROM "equipment" WHERE "photo_equipments" IS NOT NULL;
+
+ALTER TABLE "equipment" DROP COLUMN "photo_equipments";
+
+-- +migrate Down
+ALTER TABLE "equipment" ADD "photo_equipments" varchar(255) NULL;
+ALTER TABLE "equipment" ADD CONSTRAINT "equipment_photos_equipments"
+    FOREIGN KEY("photo_equipments") REFERENCES "photos"("id") ON DELETE SET NULL;
+UPDATE "equipment" SET "photo_equipments" = "equipment_photos"."photo_equipment_photos"
+FROM "equipment_photos" WHERE "equipment_photos"."equipment_photos" = "equipment"."id" AND "equipment_photos"."cover";
+
+DROP TABLE IF EXISTS "equipment_photos";
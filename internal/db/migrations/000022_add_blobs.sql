@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE "blobs"(
+    "id" varchar(255) PRIMARY KEY NOT NULL,
+    "content" bytea NOT NULL
+    );
+
+-- the content of the photo is kept by its id, the renditions get their own keys
+INSERT INTO "blobs"("id", "content")
+SELECT "id", "content" FROM "photos" WHERE "content" IS NOT NULL;
+
+ALTER TABLE "photos" DROP COLUMN "content";
+
+-- +migrate Down
+ALTER TABLE "photos" ADD "content" bytea NULL;
+UPDATE "photos" SET "content" = "blobs"."content" FROM "blobs" WHERE "blobs"."id" = "photos"."id";
+
+DROP TABLE IF EXISTS "blobs";
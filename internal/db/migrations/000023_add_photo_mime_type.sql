@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE "photos" ADD "mime_type" varchar NOT NULL DEFAULT 'image/jpeg';
+
+-- +migrate Down
+ALTER TABLE "photos" DROP COLUMN "mime_type";
@@ -0,0 +1,11 @@
+-- +migrate Up
+ALTER TABLE "photos" ADD "etag" varchar NOT NULL DEFAULT '';
+ALTER TABLE "photos" ADD "created_at" timestamptz NOT NULL DEFAULT now();
+
+-- the photos which content is still in the database get the hash, the others are tagged by their id
+UPDATE "photos" SET "etag" = encode(sha256("blobs"."content"), 'hex')
+FROM "blobs" WHERE "blobs"."id" = "photos"."id";
+
+-- +migrate Down
+ALTER TABLE "photos" DROP COLUMN "created_at";
+ALTER TABLE "photos" DROP COLUMN "etag";
@@ -0,0 +1,15 @@
+-- +migrate Up
+ALTER TABLE "order_status" ADD "condition_note" varchar NOT NULL DEFAULT '';
+
+CREATE TABLE "order_status_photos"(
+    "order_status_id" integer NOT NULL,
+    "photo_id" varchar(255) NOT NULL,
+    PRIMARY KEY("order_status_id", "photo_id"),
+    FOREIGN KEY("order_status_id") REFERENCES "order_status"("id") ON DELETE CASCADE,
+    FOREIGN KEY("photo_id") REFERENCES "photos"("id") ON DELETE NO ACTION
+    );
+
+-- +migrate Down
+DROP TABLE IF EXISTS "order_status_photos";
+
+ALTER TABLE "order_status" DROP COLUMN "condition_note";
@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE "damage_claims"(
+    "id" SERIAL PRIMARY KEY NOT NULL,
+    "amount" bigint NOT NULL,
+    "status" varchar NOT NULL DEFAULT 'open',
+    "notes" text NOT NULL DEFAULT '',
+    "created_at" timestamptz NOT NULL,
+    "updated_at" timestamptz NOT NULL,
+    "equipment_damage_claims" integer NOT NULL,
+    "order_damage_claims" integer NOT NULL,
+    FOREIGN KEY("equipment_damage_claims") REFERENCES "equipment"("id") ON DELETE NO ACTION,
+    FOREIGN KEY("order_damage_claims") REFERENCES "orders"("id") ON DELETE NO ACTION
+    );
+CREATE UNIQUE INDEX "damageclaim_order_damage_claims_equipment_damage_claims"
+    ON "damage_claims"("order_damage_claims", "equipment_damage_claims");
+
+-- +migrate Down
+DROP TABLE IF EXISTS "damage_claims";
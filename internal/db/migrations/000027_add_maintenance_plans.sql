@@ -0,0 +1,30 @@
+-- +migrate Up
+CREATE TABLE "maintenance_plans"(
+    "id" SERIAL PRIMARY KEY NOT NULL,
+    "name" varchar NOT NULL,
+    "every_rentals" integer NULL,
+    "every_days" integer NULL,
+    "duration_days" integer NOT NULL DEFAULT 1,
+    "created_at" timestamptz NOT NULL,
+    "category_maintenance_plans" integer NULL,
+    "equipment_maintenance_plans" integer NULL,
+    FOREIGN KEY("category_maintenance_plans") REFERENCES "categories"("id") ON DELETE SET NULL,
+    FOREIGN KEY("equipment_maintenance_plans") REFERENCES "equipment"("id") ON DELETE SET NULL
+    );
+
+CREATE TABLE "maintenance_windows"(
+    "id" SERIAL PRIMARY KEY NOT NULL,
+    "start_date" timestamptz NOT NULL,
+    "end_date" timestamptz NOT NULL,
+    "created_at" timestamptz NOT NULL,
+    "equipment_maintenance_windows" integer NOT NULL,
+    "equipment_status_maintenance_window" integer UNIQUE NULL,
+    "maintenance_plan_windows" integer NOT NULL,
+    FOREIGN KEY("equipment_maintenance_windows") REFERENCES "equipment"("id") ON DELETE NO ACTION,
+    FOREIGN KEY("equipment_status_maintenance_window") REFERENCES "equipment_status"("id") ON DELETE SET NULL,
+    FOREIGN KEY("maintenance_plan_windows") REFERENCES "maintenance_plans"("id") ON DELETE NO ACTION
+    );
+
+-- +migrate Down
+DROP TABLE IF EXISTS "maintenance_windows";
+DROP TABLE IF EXISTS "maintenance_plans";
@@ -0,0 +1,16 @@
+-- +migrate Up
+ALTER TABLE "users" ADD "totp_last_step" bigint NULL;
+
+CREATE TABLE "two_factor_challenges"(
+    "id" SERIAL PRIMARY KEY NOT NULL,
+    "jti" varchar(255) NOT NULL UNIQUE,
+    "failed_attempts" integer NOT NULL DEFAULT 0,
+    "expires_at" timestamptz NOT NULL,
+    "user_two_factor_challenges" integer NOT NULL,
+    FOREIGN KEY("user_two_factor_challenges")
+    REFERENCES "users"("id") ON DELETE CASCADE
+    );
+
+-- +migrate Down
+DROP TABLE IF EXISTS "two_factor_challenges";
+ALTER TABLE "users" DROP COLUMN "totp_last_step";
@@ -14,8 +14,8 @@ func GenerateSendLinkReset(userName, websiteUrl, token string) (string, error) {
 	return generateHtml(generateSendLinkReset(userName, websiteUrl, token))
 }
 
-func GenerateGetPasswordReset(userName, password string) (string, error) {
-	return generateHtml(generateGetPasswordReset(userName, password))
+func GeneratePasswordChanged(userName string) (string, error) {
+	return generateHtml(generatePasswordChanged(userName))
 }
 
 type RegistrationConfirmData struct {
@@ -68,7 +68,7 @@ func generateSendLinkReset(userName, websiteUrl, token string) hermes.Email {
 					Button: hermes.Button{
 						Color: "#DC4D2F",
 						Text:  "Сбросить пароль",
-						Link:  fmt.Sprintf("%sapi/password_reset/%s", websiteUrl, token),
+						Link:  fmt.Sprintf("%spassword_reset/%s", websiteUrl, token),
 					},
 				},
 			},
@@ -80,17 +80,16 @@ func generateSendLinkReset(userName, websiteUrl, token string) hermes.Email {
 	}
 }
 
-func generateGetPasswordReset(userName, password string) hermes.Email {
+func generatePasswordChanged(userName string) hermes.Email {
 	return hermes.Email{
 		Body: hermes.Body{
 			Name: userName,
 			Intros: []string{
-				"Вы получили это электронное письмо, потому что был получен запрос на сброс пароля для учетной записи в сервисе Лёнькин Кот.",
-				"Ваш новый пароль:",
-				password,
+				"Пароль для вашей учетной записи в сервисе Лёнькин Кот был успешно изменен.",
+				"Все активные сеансы были завершены, войдите в сервис с новым паролем.",
 			},
 			Outros: []string{
-				"Если вы не запрашивали сброс пароля, никаких дальнейших действий с вашей стороны не требуется.",
+				"Если вы не меняли пароль, немедленно свяжитесь с администрацией сервиса.",
 			},
 			Signature: "Спасибо",
 		},
@@ -61,19 +61,19 @@ func (c *sender) SendResetLink(email string, userName string, token string) erro
 	return err
 }
 
-func (c *sender) SendNewPassword(email string, userName string, password string) error {
+func (c *sender) SendPasswordChanged(email string, userName string) error {
 	if c.isRequiredToSend == false {
 		return nil
 	}
 
-	text, err := GenerateGetPasswordReset(userName, password)
+	text, err := GeneratePasswordChanged(userName)
 	if err != nil {
 		return fmt.Errorf("cant generate email %w", err)
 	}
 	sendData := &domain.SendData{
 		FromName: c.senderName,
 		FromAddr: c.senderEmail,
-		Subject:  "New Password",
+		Subject:  "Password Changed",
 		ToAddr:   email,
 		Text:     text,
 	}
@@ -36,7 +36,7 @@ func TestSenderImpl_SendResetLink(t *testing.T) {
 	cl.AssertExpectations(t)
 }
 
-func TestSenderImpl_SendNewPassword(t *testing.T) {
+func TestSenderImpl_SendPasswordChanged(t *testing.T) {
 	name := "1"
 	email := "2"
 
@@ -51,12 +51,12 @@ func TestSenderImpl_SendNewPassword(t *testing.T) {
 		require.Equal(t, conf.SenderFromName, d.FromName)
 		require.Equal(t, conf.SenderFromAddress, d.FromAddr)
 		require.Equal(t, email, d.ToAddr)
-		require.Equal(t, "New Password", d.Subject)
+		require.Equal(t, "Password Changed", d.Subject)
 		return true
 	})).Return(nil)
 
 	s := NewSenderSmtp(conf, cl)
-	require.NoError(t, s.SendNewPassword(email, name, "123"))
+	require.NoError(t, s.SendPasswordChanged(email, name))
 
 	cl.AssertExpectations(t)
 }
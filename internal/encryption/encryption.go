@@ -0,0 +1,182 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Values are encrypted with a random data key and the data key is encrypted with the key from config
+// (envelope encryption). The version of that key is stored with the value, so the keys can be rotated:
+// a new key becomes current and the old one is kept for decryption until the rows are re-encrypted.
+//
+// Encrypted value looks like enc:<key version>:<encrypted data key>:<encrypted data>. Values without
+// the prefix were written before the encryption was enabled and are returned as is.
+
+const (
+	prefix  = "enc:"
+	keySize = 32
+)
+
+var (
+	ErrUnknownKey     = errors.New("unknown encryption key version")
+	ErrMalformedValue = errors.New("malformed encrypted value")
+	ErrNoKeyring      = errors.New("encryption keyring is not set")
+)
+
+type Keyring struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring creates the keyring encrypting with the current key, all the keys are used for decryption.
+func NewKeyring(current string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("%w: %q is not among the keys", ErrUnknownKey, current)
+	}
+	k := &Keyring{current: current, keys: make(map[string]cipher.AEAD, len(keys))}
+	for version, key := range keys {
+		if version == "" || strings.Contains(version, ":") {
+			return nil, fmt.Errorf("invalid key version %q", version)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("key %q must be %d bytes long", version, keySize)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		k.keys[version] = aead
+	}
+	return k, nil
+}
+
+// ParseKeys parses comma separated version:key pairs with the keys encoded in base64.
+func ParseKeys(s string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, pair := range strings.Split(s, ",") {
+		version, encoded, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if !found {
+			return nil, fmt.Errorf("key must be in version:key format")
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q is not base64 encoded: %w", version, err)
+		}
+		keys[version] = key
+	}
+	return keys, nil
+}
+
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	encryptedKey, err := seal(k.keys[k.current], dataKey)
+	if err != nil {
+		return "", err
+	}
+	data, err := seal(dataAEAD, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return k.CurrentPrefix() + base64.RawStdEncoding.EncodeToString(encryptedKey) + ":" +
+		base64.RawStdEncoding.EncodeToString(data), nil
+}
+
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", ErrMalformedValue
+	}
+	keyAEAD, ok := k.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownKey, parts[0])
+	}
+	encryptedKey, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrMalformedValue
+	}
+	data, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrMalformedValue
+	}
+	dataKey, err := open(keyAEAD, encryptedKey)
+	if err != nil {
+		return "", err
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataAEAD, data)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// CurrentPrefix is the prefix of the values encrypted with the current key.
+func (k *Keyring) CurrentPrefix() string {
+	return prefix + k.current + ":"
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal returns the nonce followed by the encrypted data.
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(aead cipher.AEAD, data []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, ErrMalformedValue
+	}
+	return aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+}
+
+var (
+	defaultMu      sync.RWMutex
+	defaultKeyring *Keyring
+)
+
+// SetDefault sets the keyring used by the ent fields, it is called once on start.
+func SetDefault(k *Keyring) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultKeyring = k
+}
+
+// Default returns the keyring set on start, nil if the encryption is not configured.
+func Default() *Keyring {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultKeyring
+}
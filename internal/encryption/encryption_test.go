@@ -0,0 +1,134 @@
+package encryption
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testKeyring(t *testing.T, current string, versions ...string) *Keyring {
+	keys := make(map[string][]byte)
+	for i, v := range versions {
+		keys[v] = bytes.Repeat([]byte{byte(i + 1)}, keySize)
+	}
+	k, err := NewKeyring(current, keys)
+	require.NoError(t, err)
+	return k
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	k := testKeyring(t, "1", "1")
+	encrypted, err := k.Encrypt("1234 567890")
+	require.NoError(t, err)
+	require.True(t, IsEncrypted(encrypted))
+	require.Contains(t, encrypted, k.CurrentPrefix())
+	require.NotContains(t, encrypted, "567890")
+
+	again, err := k.Encrypt("1234 567890")
+	require.NoError(t, err)
+	require.NotEqual(t, encrypted, again)
+
+	decrypted, err := k.Decrypt(encrypted)
+	require.NoError(t, err)
+	require.Equal(t, "1234 567890", decrypted)
+
+	plaintext, err := k.Decrypt("not encrypted")
+	require.NoError(t, err)
+	require.Equal(t, "not encrypted", plaintext)
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	old := testKeyring(t, "1", "1")
+	encrypted, err := old.Encrypt("value")
+	require.NoError(t, err)
+
+	rotated := testKeyring(t, "2", "1", "2")
+	require.NotContains(t, encrypted, rotated.CurrentPrefix())
+	decrypted, err := rotated.Decrypt(encrypted)
+	require.NoError(t, err)
+	require.Equal(t, "value", decrypted)
+
+	withoutOld := testKeyring(t, "2", "2")
+	_, err = withoutOld.Decrypt(encrypted)
+	require.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeyring_DecryptErrors(t *testing.T) {
+	k := testKeyring(t, "1", "1")
+	_, err := k.Decrypt("enc:1:broken")
+	require.ErrorIs(t, err, ErrMalformedValue)
+
+	encrypted, err := k.Encrypt("value")
+	require.NoError(t, err)
+	_, err = k.Decrypt(encrypted[:len(encrypted)-2])
+	require.Error(t, err)
+}
+
+func TestNewKeyring_Errors(t *testing.T) {
+	_, err := NewKeyring("2", map[string][]byte{"1": make([]byte, keySize)})
+	require.ErrorIs(t, err, ErrUnknownKey)
+	_, err = NewKeyring("1", map[string][]byte{"1": make([]byte, 16)})
+	require.Error(t, err)
+	_, err = NewKeyring("1:2", map[string][]byte{"1:2": make([]byte, keySize)})
+	require.Error(t, err)
+}
+
+func TestParseKeys(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(make([]byte, keySize))
+	keys, err := ParseKeys("1:" + key + ", 2:" + key)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	require.Len(t, keys["2"], keySize)
+
+	_, err = ParseKeys(key)
+	require.Error(t, err)
+	_, err = ParseKeys("1:not base64")
+	require.Error(t, err)
+}
+
+func TestValueScanners(t *testing.T) {
+	defer SetDefault(nil)
+	issueDate := time.Date(2020, 5, 17, 0, 0, 0, 0, time.UTC)
+
+	// without the keyring nothing is stored as plaintext
+	SetDefault(nil)
+	_, err := String.Value("plain")
+	require.ErrorIs(t, err, ErrNoKeyring)
+	_, err = Time.Value(issueDate)
+	require.ErrorIs(t, err, ErrNoKeyring)
+
+	SetDefault(testKeyring(t, "1", "1"))
+	value, err := String.Value("secret")
+	require.NoError(t, err)
+	require.True(t, IsEncrypted(value.(string)))
+	s, err := String.FromValue(&sql.NullString{String: value.(string), Valid: true})
+	require.NoError(t, err)
+	require.Equal(t, "secret", s)
+	s, err = String.FromValue(&sql.NullString{})
+	require.NoError(t, err)
+	require.Empty(t, s)
+
+	value, err = Time.Value(issueDate)
+	require.NoError(t, err)
+	require.True(t, IsEncrypted(value.(string)))
+	date, err := Time.FromValue(&sql.NullString{String: value.(string), Valid: true})
+	require.NoError(t, err)
+	require.True(t, issueDate.Equal(date))
+	date, err = Time.FromValue(&sql.NullString{String: "2020-05-17T00:00:00Z", Valid: true})
+	require.NoError(t, err)
+	require.True(t, issueDate.Equal(date))
+	date, err = Time.FromValue(&sql.NullString{})
+	require.NoError(t, err)
+	require.True(t, date.IsZero())
+
+	SetDefault(nil)
+	_, err = String.FromValue(&sql.NullString{String: "enc:1:a:b", Valid: true})
+	require.ErrorIs(t, err, ErrNoKeyring)
+	s, err = String.FromValue(&sql.NullString{})
+	require.NoError(t, err)
+	require.Empty(t, s)
+}
@@ -0,0 +1,46 @@
+package encryption
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	"entgo.io/ent/schema/field"
+)
+
+// String and Time are the ent value scanners of the encrypted columns. They use the default keyring and fail
+// with ErrNoKeyring without it, so the passport data is never stored unencrypted by mistake.
+var (
+	String = field.ValueScannerFunc[string, *sql.NullString]{V: encryptValue, S: decryptValue}
+	Time   = field.ValueScannerFunc[time.Time, *sql.NullString]{
+		V: func(t time.Time) (driver.Value, error) {
+			return encryptValue(t.UTC().Format(time.RFC3339Nano))
+		},
+		S: func(ns *sql.NullString) (time.Time, error) {
+			s, err := decryptValue(ns)
+			if err != nil || s == "" {
+				return time.Time{}, err
+			}
+			return time.Parse(time.RFC3339Nano, s)
+		},
+	}
+)
+
+func encryptValue(s string) (driver.Value, error) {
+	k := Default()
+	if k == nil {
+		return nil, ErrNoKeyring
+	}
+	return k.Encrypt(s)
+}
+
+func decryptValue(ns *sql.NullString) (string, error) {
+	if !ns.Valid {
+		return "", nil
+	}
+	k := Default()
+	if k == nil {
+		return "", ErrNoKeyring
+	}
+	return k.Decrypt(ns.String)
+}
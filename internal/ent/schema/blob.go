@@ -0,0 +1,19 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+)
+
+// Blob keeps the content of the photos and their renditions when they are stored in the database.
+type Blob struct {
+	ent.Schema
+}
+
+// Fields of the Blob.
+func (Blob) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").Unique(),
+		field.Bytes("content"),
+	}
+}
@@ -26,5 +26,6 @@ func (Category) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.To("equipments", Equipment.Type),
 		edge.To("subcategories", Subcategory.Type),
+		edge.To("maintenance_plans", MaintenancePlan.Type),
 	}
 }
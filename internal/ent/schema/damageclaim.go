@@ -0,0 +1,42 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// DamageClaim holds the schema definition for the DamageClaim entity.
+// The claim records the damage of the equipment returned by the order and the compensation for it.
+type DamageClaim struct {
+	ent.Schema
+}
+
+// Fields of the DamageClaim.
+func (DamageClaim) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int64("amount").Min(0),
+		field.Enum("status").Values("open", "paid", "waived").Default("open"),
+		field.Text("notes").Default(""),
+		field.Time("created_at").Default(time.Now).Immutable(),
+		field.Time("updated_at").Default(time.Now).UpdateDefault(time.Now),
+	}
+}
+
+// Edges of the DamageClaim.
+func (DamageClaim) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("order", Order.Type).Ref("damage_claims").Unique().Required(),
+		edge.From("equipment", Equipment.Type).Ref("damage_claims").Unique().Required(),
+	}
+}
+
+// Indexes of the DamageClaim.
+func (DamageClaim) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Edges("order", "equipment").Unique(),
+	}
+}
@@ -35,9 +35,12 @@ func (Equipment) Edges() []ent.Edge {
 		edge.From("subcategory", Subcategory.Type).Ref("equipments").Unique(),
 		edge.From("current_status", EquipmentStatusName.Type).Ref("equipments").Unique(),
 		edge.From("pet_size", PetSize.Type).Ref("equipments").Unique(),
-		edge.From("photo", Photo.Type).Ref("equipments").Unique(),
+		edge.To("photos", EquipmentPhoto.Type),
 		edge.From("petKinds", PetKind.Type).Ref("equipments"),
 		edge.To("equipment_status", EquipmentStatus.Type),
 		edge.To("order", Order.Type),
+		edge.To("damage_claims", DamageClaim.Type),
+		edge.To("maintenance_plans", MaintenancePlan.Type),
+		edge.To("maintenance_windows", MaintenanceWindow.Type),
 	}
 }
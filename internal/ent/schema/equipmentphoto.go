@@ -0,0 +1,36 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// EquipmentPhoto holds the schema definition for the EquipmentPhoto entity.
+type EquipmentPhoto struct {
+	ent.Schema
+}
+
+// Fields of the EquipmentPhoto.
+func (EquipmentPhoto) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int("position").Default(0),
+		field.Bool("cover").Default(false),
+	}
+}
+
+// Edges of the EquipmentPhoto.
+func (EquipmentPhoto) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("equipment", Equipment.Type).Ref("photos").Unique().Required(),
+		edge.From("photo", Photo.Type).Ref("equipment_photos").Unique().Required(),
+	}
+}
+
+// Indexes of the EquipmentPhoto.
+func (EquipmentPhoto) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Edges("equipment", "photo").Unique(),
+	}
+}
@@ -30,5 +30,6 @@ func (EquipmentStatus) Edges() []ent.Edge {
 		edge.From("equipments", Equipment.Type).Ref("equipment_status").Unique(),
 		edge.From("equipment_status_name", EquipmentStatusName.Type).Ref("equipment_status").Unique(),
 		edge.From("order", Order.Type).Ref("equipment_status").Unique(),
+		edge.To("maintenance_window", MaintenanceWindow.Type).Unique(),
 	}
 }
@@ -3,6 +3,7 @@ package schema
 import (
 	"entgo.io/ent"
 	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
 )
 
 // Group holds the schema definition for the Group entity.
@@ -12,7 +13,10 @@ type Group struct {
 
 // Fields of the Group.
 func (Group) Fields() []ent.Field {
-	return nil
+	return []ent.Field{
+		field.String("name").NotEmpty().Unique(),
+		field.String("description").Default(""),
+	}
 }
 
 // Edges of the Group.
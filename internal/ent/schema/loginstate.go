@@ -0,0 +1,26 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+)
+
+// LoginState holds the schema definition for the LoginState entity.
+// It keeps the data of an OIDC authorization request until the user comes back with the code.
+type LoginState struct {
+	ent.Schema
+}
+
+// Fields of the LoginState.
+func (LoginState) Fields() []ent.Field {
+	return []ent.Field{
+		field.Time("ttl").
+			Default(time.Now()),
+		// state holds the SHA-256 hash of the state sent to the identity provider.
+		field.String("state").Unique(),
+		field.String("code_verifier").Sensitive(),
+		field.String("nonce").Sensitive(),
+	}
+}
@@ -0,0 +1,36 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// MaintenancePlan holds the schema definition for the MaintenancePlan entity.
+// The plan applies to one equipment or to all equipment of the category, the maintenance is due after
+// the number of rentals or the number of days since the last maintenance, whichever comes first.
+type MaintenancePlan struct {
+	ent.Schema
+}
+
+// Fields of the MaintenancePlan.
+func (MaintenancePlan) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("name"),
+		field.Int("every_rentals").Optional().Nillable().Min(1),
+		field.Int("every_days").Optional().Nillable().Min(1),
+		field.Int("duration_days").Default(1).Min(1),
+		field.Time("created_at").Default(time.Now).Immutable(),
+	}
+}
+
+// Edges of the MaintenancePlan.
+func (MaintenancePlan) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("equipment", Equipment.Type).Ref("maintenance_plans").Unique(),
+		edge.From("category", Category.Type).Ref("maintenance_plans").Unique(),
+		edge.To("windows", MaintenanceWindow.Type),
+	}
+}
@@ -0,0 +1,34 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// MaintenanceWindow holds the schema definition for the MaintenanceWindow entity.
+// The window is the maintenance of the equipment scheduled by the plan, the equipment is not available
+// during the window. Expired equipment statuses are deleted, the windows keep the history of the maintenance.
+type MaintenanceWindow struct {
+	ent.Schema
+}
+
+// Fields of the MaintenanceWindow.
+func (MaintenanceWindow) Fields() []ent.Field {
+	return []ent.Field{
+		field.Time("start_date"),
+		field.Time("end_date"),
+		field.Time("created_at").Default(time.Now).Immutable(),
+	}
+}
+
+// Edges of the MaintenanceWindow.
+func (MaintenanceWindow) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("plan", MaintenancePlan.Type).Ref("windows").Unique().Required(),
+		edge.From("equipment", Equipment.Type).Ref("maintenance_windows").Unique().Required(),
+		edge.From("equipment_status", EquipmentStatus.Type).Ref("maintenance_window").Unique(),
+	}
+}
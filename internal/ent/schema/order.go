@@ -28,9 +28,11 @@ func (Order) Fields() []ent.Field {
 func (Order) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.From("users", User.Type).Ref("order").Unique(),
+		edge.From("organization", Organization.Type).Ref("orders").Unique(),
 		edge.From("equipments", Equipment.Type).Ref("order"),
 		edge.From("current_status", OrderStatusName.Type).Ref("orders").Unique(),
 		edge.To("order_status", OrderStatus.Type),
 		edge.To("equipment_status", EquipmentStatus.Type),
+		edge.To("damage_claims", DamageClaim.Type),
 	}
 }
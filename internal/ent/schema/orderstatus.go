@@ -16,6 +16,8 @@ func (OrderStatus) Fields() []ent.Field {
 	return []ent.Field{
 		field.String("comment"),
 		field.Time("current_date"),
+		// condition_note describes the condition of the equipment on the handover and the return
+		field.String("condition_note").Default(""),
 	}
 }
 
@@ -25,5 +27,6 @@ func (OrderStatus) Edges() []ent.Edge {
 		edge.From("order", Order.Type).Ref("order_status").Unique(),
 		edge.From("order_status_name", OrderStatusName.Type).Ref("order_status").Unique(),
 		edge.From("users", User.Type).Ref("order_status").Unique(),
+		edge.To("photos", Photo.Type),
 	}
 }
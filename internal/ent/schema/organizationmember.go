@@ -0,0 +1,35 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// OrganizationMember holds the schema definition for the OrganizationMember entity.
+type OrganizationMember struct {
+	ent.Schema
+}
+
+// Fields of the OrganizationMember.
+func (OrganizationMember) Fields() []ent.Field {
+	return []ent.Field{
+		field.Enum("role").Values("owner", "member").Default("member"),
+	}
+}
+
+// Edges of the OrganizationMember.
+func (OrganizationMember) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("organization", Organization.Type).Ref("members").Unique().Required(),
+		edge.From("user", User.Type).Ref("organization_memberships").Unique().Required(),
+	}
+}
+
+// Indexes of the OrganizationMember.
+func (OrganizationMember) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Edges("organization", "user").Unique(),
+	}
+}
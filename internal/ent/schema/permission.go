@@ -14,7 +14,8 @@ type Permission struct {
 // Fields of the Permission.
 func (Permission) Fields() []ent.Field {
 	return []ent.Field{
-		field.String("name").Default("unknown"),
+		field.String("name").NotEmpty().Unique(),
+		field.String("description").Default(""),
 	}
 }
 
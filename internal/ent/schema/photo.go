@@ -1,6 +1,8 @@
 package schema
 
 import (
+	"time"
+
 	"entgo.io/ent"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
@@ -14,12 +16,15 @@ func (Photo) Fields() []ent.Field {
 	return []ent.Field{
 		field.String("id").Unique(),
 		field.String("fileName").Default("unknown.jpg"),
-		field.Bytes("content").Default(nil).Optional(),
+		field.String("mimeType").Default("image/jpeg"),
+		field.String("etag").Default(""),
+		field.Time("created_at").Default(time.Now).Immutable(),
 	}
 }
 
 func (Photo) Edges() []ent.Edge {
 	return []ent.Edge{
-		edge.To("equipments", Equipment.Type),
+		edge.To("equipment_photos", EquipmentPhoto.Type),
+		edge.From("order_statuses", OrderStatus.Type).Ref("photos"),
 	}
 }
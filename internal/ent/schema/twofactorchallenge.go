@@ -0,0 +1,29 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// TwoFactorChallenge holds the schema definition for the TwoFactorChallenge entity.
+// A challenge is issued after the password check and is consumed by the first successful second step.
+type TwoFactorChallenge struct {
+	ent.Schema
+}
+
+// Fields of the TwoFactorChallenge.
+func (TwoFactorChallenge) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("jti").NotEmpty().Unique(),
+		field.Int("failed_attempts").Default(0),
+		field.Time("expires_at"),
+	}
+}
+
+// Edges of the TwoFactorChallenge.
+func (TwoFactorChallenge) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("users", User.Type).Ref("two_factor_challenges").Unique().Required(),
+	}
+}
@@ -1,10 +1,13 @@
 package schema
 
 import (
+	"time"
+
 	"entgo.io/ent"
-	"entgo.io/ent/dialect"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/encryption"
 )
 
 // User holds the schema definition for the User entity.
@@ -21,14 +24,11 @@ func (User) Fields() []ent.Field {
 		field.String("name").Optional().Nillable(),
 		field.String("surname").Optional().Nillable(),
 		field.String("patronymic").Optional().Nillable(),
-		field.String("passport_series").Optional().Nillable(),
-		field.String("passport_number").Optional().Nillable(),
-		field.String("passport_authority").Optional().Nillable(),
-		field.Time("passport_issue_date").
-			Optional().
-			SchemaType(map[string]string{
-				dialect.Postgres: "timestamp",
-			}),
+		// passport data is encrypted by the application, see the encryption package
+		field.String("passport_series").Optional().ValueScanner(encryption.String),
+		field.String("passport_number").Optional().ValueScanner(encryption.String),
+		field.String("passport_authority").Optional().ValueScanner(encryption.String),
+		field.String("passport_issue_date").Optional().GoType(time.Time{}).ValueScanner(encryption.Time),
 		field.String("phone").Optional().Nillable(),
 		field.Bool("is_readonly").Default(false),
 		field.Enum("type").Values("person", "organization").Default("person"),
@@ -37,6 +37,17 @@ func (User) Fields() []ent.Field {
 		field.String("vk").Optional().Nillable(),
 		field.Bool("is_registration_confirmed").Default(false),
 		field.Bool("is_deleted").Default(false),
+		// deleted_at starts the retention period, the personal data of the user is anonymised when it ends
+		// and purged_at is set. The row stays so the orders keep their owner.
+		field.Time("deleted_at").Optional().Nillable(),
+		field.Time("purged_at").Optional().Nillable(),
+		field.String("totp_secret").Optional().Nillable().Sensitive(),
+		field.Bool("is_totp_enabled").Default(false),
+		// totp_last_step is the time step of the last accepted TOTP code, the codes of this and earlier steps
+		// are not accepted again.
+		field.Int64("totp_last_step").Optional().Nillable(),
+		// oidc_subject is the subject of the identity provider account linked to the user.
+		field.String("oidc_subject").Optional().Nillable().Unique(),
 	}
 }
 
@@ -52,5 +63,8 @@ func (User) Edges() []ent.Edge {
 		edge.To("password_reset", PasswordReset.Type),
 		edge.To("registration_confirm", RegistrationConfirm.Type),
 		edge.To("email_confirm", EmailConfirm.Type),
+		edge.To("recovery_codes", RecoveryCode.Type),
+		edge.To("two_factor_challenges", TwoFactorChallenge.Type),
+		edge.To("organization_memberships", OrganizationMember.Type),
 	}
 }
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/go-openapi/runtime/middleware"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/access"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/repositories"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+// SetAccessPolicyHandler registers the access policy introspection. The access manager is validated against
+// the endpoints of the initialized api, so it's created after the handlers are registered and passed as a getter.
+func SetAccessPolicyHandler(logger *zap.Logger, api *operations.BeAPI,
+	accessManager func() middlewares.AccessManager) {
+	userRepo := repositories.NewUserRepository()
+	accessPolicyHandler := NewAccessPolicy(logger, accessManager, api.GetExistingEndpoints)
+
+	api.AccessGetAccessPolicyHandler = accessPolicyHandler.GetAccessPolicyFunc(userRepo)
+	api.AccessExplainAccessHandler = accessPolicyHandler.ExplainAccessFunc(userRepo)
+}
+
+type AccessPolicy struct {
+	logger        *zap.Logger
+	accessManager func() middlewares.AccessManager
+	endpoints     func() map[string][]string
+}
+
+func NewAccessPolicy(logger *zap.Logger, accessManager func() middlewares.AccessManager,
+	endpoints func() map[string][]string) *AccessPolicy {
+	return &AccessPolicy{
+		logger:        logger,
+		accessManager: accessManager,
+		endpoints:     endpoints,
+	}
+}
+
+func (a AccessPolicy) GetAccessPolicyFunc(repository domain.UserRepository) access.GetAccessPolicyHandlerFunc {
+	return func(p access.GetAccessPolicyParams, principal *models.Principal) middleware.Responder {
+		if principal.Role != roles.Admin {
+			return access.NewGetAccessPolicyDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrAccessPolicyForbidden, ""))
+		}
+		ctx := p.HTTPRequest.Context()
+		subject, err := subjectPrincipal(ctx, repository, accessSubjectParams{
+			role:                    p.Role,
+			userID:                  p.UserID,
+			isRegistrationConfirmed: p.IsRegistrationConfirmed,
+			isPersonalDataConfirmed: p.IsPersonalDataConfirmed,
+			isReadonly:              p.IsReadonly,
+			isTwoFactorEnabled:      p.IsTwoFactorEnabled,
+		})
+		switch {
+		case errors.Is(err, errAccessSubjectNotSet):
+			return access.NewGetAccessPolicyBadRequest().
+				WithPayload(buildBadRequestErrorPayload(messages.ErrAccessPolicySubject, ""))
+		case ent.IsNotFound(err):
+			return access.NewGetAccessPolicyNotFound().
+				WithPayload(buildNotFoundErrorPayload(messages.ErrUserNotFound, ""))
+		case err != nil:
+			a.logger.Error(messages.ErrAccessPolicyUser, zap.Error(err))
+			return access.NewGetAccessPolicyDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrAccessPolicyUser, ""))
+		}
+
+		allowed := middlewares.AllowedEndpoints(a.accessManager(), a.endpoints(), subject)
+		methods := make([]string, 0, len(allowed))
+		for method := range allowed {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		endpoints := make([]*models.AllowedEndpoints, 0, len(methods))
+		for _, method := range methods {
+			if len(allowed[method]) == 0 {
+				continue
+			}
+			method := method
+			endpoints = append(endpoints, &models.AllowedEndpoints{
+				Method: &method,
+				Paths:  allowed[method],
+			})
+		}
+		permissions := subject.Permissions
+		if permissions == nil {
+			permissions = []string{}
+		}
+		return access.NewGetAccessPolicyOK().WithPayload(&models.AccessPolicy{
+			Role:                    &subject.Role,
+			IsRegistrationConfirmed: &subject.IsRegistrationConfirmed,
+			IsPersonalDataConfirmed: &subject.IsPersonalDataConfirmed,
+			IsReadonly:              &subject.IsReadonly,
+			IsTwoFactorEnabled:      &subject.IsTwoFactorEnabled,
+			Permissions:             permissions,
+			Endpoints:               endpoints,
+		})
+	}
+}
+
+func (a AccessPolicy) ExplainAccessFunc(repository domain.UserRepository) access.ExplainAccessHandlerFunc {
+	return func(p access.ExplainAccessParams, principal *models.Principal) middleware.Responder {
+		if principal.Role != roles.Admin {
+			return access.NewExplainAccessDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrAccessPolicyForbidden, ""))
+		}
+		ctx := p.HTTPRequest.Context()
+		subject, err := subjectPrincipal(ctx, repository, accessSubjectParams{
+			role:                    p.Role,
+			userID:                  p.UserID,
+			isRegistrationConfirmed: p.IsRegistrationConfirmed,
+			isPersonalDataConfirmed: p.IsPersonalDataConfirmed,
+			isReadonly:              p.IsReadonly,
+			isTwoFactorEnabled:      p.IsTwoFactorEnabled,
+		})
+		switch {
+		case errors.Is(err, errAccessSubjectNotSet):
+			return access.NewExplainAccessBadRequest().
+				WithPayload(buildBadRequestErrorPayload(messages.ErrAccessPolicySubject, ""))
+		case ent.IsNotFound(err):
+			return access.NewExplainAccessNotFound().
+				WithPayload(buildNotFoundErrorPayload(messages.ErrUserNotFound, ""))
+		case err != nil:
+			a.logger.Error(messages.ErrAccessPolicyUser, zap.Error(err))
+			return access.NewExplainAccessDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrAccessPolicyUser, ""))
+		}
+
+		decision := a.accessManager().Explain(subject, p.Method, p.Path)
+		explanation := &models.AccessExplanation{
+			Allowed:    &decision.Allowed,
+			Reason:     &decision.Reason,
+			Permission: decision.Permission,
+		}
+		if decision.Access != nil {
+			explanation.Binding = &models.AccessBinding{
+				Role:   &decision.Access.Role.Slug,
+				Method: &decision.Access.Method,
+				Path:   &decision.Access.Path,
+			}
+		}
+		return access.NewExplainAccessOK().WithPayload(explanation)
+	}
+}
+
+type accessSubjectParams struct {
+	role                    *string
+	userID                  *int64
+	isRegistrationConfirmed *bool
+	isPersonalDataConfirmed *bool
+	isReadonly              *bool
+	isTwoFactorEnabled      *bool
+}
+
+var errAccessSubjectNotSet = errors.New("role or user must be set")
+
+// subjectPrincipal returns the principal of the user if the user is set,
+// otherwise the principal of the role with the flags.
+func subjectPrincipal(ctx context.Context, repository domain.UserRepository,
+	params accessSubjectParams) (*models.Principal, error) {
+	if params.userID != nil {
+		user, err := repository.GetUserByID(ctx, int(*params.userID))
+		if err != nil {
+			return nil, err
+		}
+		return middlewares.PrincipalFromUser(user), nil
+	}
+	if params.role == nil || *params.role == "" {
+		return nil, errAccessSubjectNotSet
+	}
+	return &models.Principal{
+		Role:                    *params.role,
+		IsRegistrationConfirmed: isFlagSet(params.isRegistrationConfirmed),
+		IsPersonalDataConfirmed: isFlagSet(params.isPersonalDataConfirmed),
+		IsReadonly:              isFlagSet(params.isReadonly),
+		IsTwoFactorEnabled:      isFlagSet(params.isTwoFactorEnabled),
+	}, nil
+}
+
+func isFlagSet(flag *bool) bool {
+	return flag != nil && *flag
+}
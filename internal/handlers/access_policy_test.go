@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/runtime"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/mocks"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/access"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
+)
+
+func TestSetAccessPolicyHandler(t *testing.T) {
+	logger := zap.NewNop()
+
+	swaggerSpec, err := loads.Analyzed(restapi.SwaggerJSON, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := operations.NewBeAPI(swaggerSpec)
+	SetAccessPolicyHandler(logger, api, func() middlewares.AccessManager { return nil })
+	require.NotEmpty(t, api.AccessGetAccessPolicyHandler)
+	require.NotEmpty(t, api.AccessExplainAccessHandler)
+}
+
+type AccessPolicyTestSuite struct {
+	suite.Suite
+	logger     *zap.Logger
+	repository *mocks.UserRepository
+	handler    *AccessPolicy
+	admin      *models.Principal
+}
+
+func TestAccessPolicySuite(t *testing.T) {
+	suite.Run(t, new(AccessPolicyTestSuite))
+}
+
+func (s *AccessPolicyTestSuite) SetupTest() {
+	t := s.T()
+	s.logger = zap.NewNop()
+	s.repository = &mocks.UserRepository{}
+	s.admin = &models.Principal{ID: 1, Role: roles.Admin}
+
+	endpoints := middlewares.ExistingEndpoints{
+		http.MethodGet:  {"/v1/equipment", "/v1/orders/{orderId}"},
+		http.MethodPost: {"/v1/orders"},
+	}
+	manager, err := middlewares.NewAccessManager(
+		[]middlewares.Role{{Slug: roles.User}, {Slug: roles.Admin}},
+		[]middlewares.Role{{Slug: roles.Admin}},
+		endpoints,
+	)
+	require.NoError(t, err)
+	_, err = manager.AddNewAccess(middlewares.Role{Slug: roles.User, IsRegistrationConfirmed: true},
+		http.MethodGet, "/v1/orders/{orderId}")
+	require.NoError(t, err)
+	s.handler = NewAccessPolicy(s.logger, func() middlewares.AccessManager { return manager },
+		func() map[string][]string { return endpoints })
+}
+
+func (s *AccessPolicyTestSuite) TestAccessPolicy_GetAccessPolicy_NotAdmin() {
+	t := s.T()
+	request := http.Request{}
+	role := roles.User
+
+	handlerFunc := s.handler.GetAccessPolicyFunc(s.repository)
+	resp := handlerFunc.Handle(access.GetAccessPolicyParams{HTTPRequest: &request, Role: &role},
+		&models.Principal{ID: 1, Role: roles.Manager})
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	s.repository.AssertExpectations(t)
+}
+
+func (s *AccessPolicyTestSuite) TestAccessPolicy_GetAccessPolicy_NoSubject() {
+	t := s.T()
+	request := http.Request{}
+
+	handlerFunc := s.handler.GetAccessPolicyFunc(s.repository)
+	resp := handlerFunc.Handle(access.GetAccessPolicyParams{HTTPRequest: &request}, s.admin)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	s.repository.AssertExpectations(t)
+}
+
+func (s *AccessPolicyTestSuite) TestAccessPolicy_GetAccessPolicy_Role() {
+	t := s.T()
+	request := http.Request{}
+	role := roles.User
+	isConfirmed := true
+
+	handlerFunc := s.handler.GetAccessPolicyFunc(s.repository)
+	resp := handlerFunc.Handle(access.GetAccessPolicyParams{
+		HTTPRequest:             &request,
+		Role:                    &role,
+		IsRegistrationConfirmed: &isConfirmed,
+	}, s.admin)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var actual models.AccessPolicy
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+	require.True(t, *actual.IsRegistrationConfirmed)
+	require.Len(t, actual.Endpoints, 1)
+	require.Equal(t, http.MethodGet, *actual.Endpoints[0].Method)
+	require.Equal(t, []string{"/v1/orders/{orderId}"}, actual.Endpoints[0].Paths)
+	s.repository.AssertExpectations(t)
+}
+
+func (s *AccessPolicyTestSuite) TestAccessPolicy_GetAccessPolicy_UserNotFound() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	userID := int64(5)
+
+	s.repository.On("GetUserByID", ctx, 5).Return(nil, &ent.NotFoundError{})
+
+	handlerFunc := s.handler.GetAccessPolicyFunc(s.repository)
+	resp := handlerFunc.Handle(access.GetAccessPolicyParams{HTTPRequest: &request, UserID: &userID}, s.admin)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusNotFound, responseRecorder.Code)
+	s.repository.AssertExpectations(t)
+}
+
+func (s *AccessPolicyTestSuite) TestAccessPolicy_ExplainAccess_User() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	userID := int64(5)
+
+	s.repository.On("GetUserByID", ctx, 5).Return(&ent.User{
+		ID:    5,
+		Edges: ent.UserEdges{Role: &ent.Role{Slug: roles.User}},
+	}, nil)
+
+	handlerFunc := s.handler.ExplainAccessFunc(s.repository)
+	resp := handlerFunc.Handle(access.ExplainAccessParams{
+		HTTPRequest: &request,
+		UserID:      &userID,
+		Method:      http.MethodGet,
+		Path:        "/v1/orders/7",
+	}, s.admin)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var actual models.AccessExplanation
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+	require.False(t, *actual.Allowed)
+	require.Contains(t, *actual.Reason, "no confirmed email")
+	require.Nil(t, actual.Binding)
+	s.repository.AssertExpectations(t)
+}
+
+func (s *AccessPolicyTestSuite) TestAccessPolicy_ExplainAccess_Binding() {
+	t := s.T()
+	request := http.Request{}
+	role := roles.User
+	isConfirmed := true
+
+	handlerFunc := s.handler.ExplainAccessFunc(s.repository)
+	resp := handlerFunc.Handle(access.ExplainAccessParams{
+		HTTPRequest:             &request,
+		Role:                    &role,
+		IsRegistrationConfirmed: &isConfirmed,
+		Method:                  http.MethodGet,
+		Path:                    "/api/v1/orders/7",
+	}, s.admin)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var actual models.AccessExplanation
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+	require.True(t, *actual.Allowed)
+	require.Equal(t, "/v1/orders/{orderId}", *actual.Binding.Path)
+	require.Equal(t, roles.User, *actual.Binding.Role)
+	s.repository.AssertExpectations(t)
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/damage_claims"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/repositories"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+func SetDamageClaimHandler(logger *zap.Logger, api *operations.BeAPI) {
+	damageClaimRepo := repositories.NewDamageClaimRepository()
+	equipmentStatusRepo := repositories.NewEquipmentStatusRepository()
+	orderStatusRepo := repositories.NewOrderStatusRepository()
+	damageClaimHandler := NewDamageClaim(logger)
+
+	api.DamageClaimsListDamageClaimsHandler = damageClaimHandler.ListDamageClaimsFunc(damageClaimRepo)
+	api.DamageClaimsCreateDamageClaimHandler = damageClaimHandler.CreateDamageClaimFunc(damageClaimRepo,
+		equipmentStatusRepo, orderStatusRepo)
+	api.DamageClaimsGetDamageClaimHandler = damageClaimHandler.GetDamageClaimFunc(damageClaimRepo)
+	api.DamageClaimsUpdateDamageClaimHandler = damageClaimHandler.UpdateDamageClaimFunc(damageClaimRepo)
+}
+
+// DamageClaim records the damage of the returned equipment and the compensation for it,
+// the damaged equipment goes to repair.
+type DamageClaim struct {
+	logger *zap.Logger
+}
+
+func NewDamageClaim(logger *zap.Logger) *DamageClaim {
+	return &DamageClaim{
+		logger: logger,
+	}
+}
+
+func (d DamageClaim) ListDamageClaimsFunc(
+	repository domain.DamageClaimRepository) damage_claims.ListDamageClaimsHandlerFunc {
+	return func(p damage_claims.ListDamageClaimsParams, _ *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		filter := domain.DamageClaimFilter{Status: p.Status}
+		if p.OrderID != nil {
+			orderID := int(*p.OrderID)
+			filter.OrderID = &orderID
+		}
+		if p.EquipmentID != nil {
+			equipmentID := int(*p.EquipmentID)
+			filter.EquipmentID = &equipmentID
+		}
+		result, err := repository.DamageClaims(ctx, filter)
+		if err != nil {
+			d.logger.Error(messages.ErrQueryDamageClaims, zap.Error(err))
+			return damage_claims.NewListDamageClaimsDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrQueryDamageClaims, ""))
+		}
+		claims := models.DamageClaims{}
+		for _, claim := range result {
+			claims = append(claims, mapDamageClaim(claim))
+		}
+		return damage_claims.NewListDamageClaimsOK().WithPayload(claims)
+	}
+}
+
+func (d DamageClaim) CreateDamageClaimFunc(repository domain.DamageClaimRepository,
+	eqStatusRepository domain.EquipmentStatusRepository,
+	orderStatusRepo domain.OrderStatusRepository) damage_claims.CreateDamageClaimHandlerFunc {
+	return func(p damage_claims.CreateDamageClaimParams, principal *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		orderID := int(*p.Data.OrderID)
+		equipmentID := int(*p.Data.EquipmentID)
+		result, err := repository.CreateDamageClaim(ctx, orderID, equipmentID, p.Data.Amount, p.Data.Notes)
+		if err != nil {
+			switch {
+			case ent.IsNotFound(err):
+				return damage_claims.NewCreateDamageClaimNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrDamageClaimTargetNotFound, ""))
+			case errors.Is(err, domain.ErrEquipmentNotInOrder):
+				return damage_claims.NewCreateDamageClaimBadRequest().
+					WithPayload(buildBadRequestErrorPayload(messages.ErrEquipmentNotInOrder, ""))
+			case errors.Is(err, domain.ErrDamageClaimOrderActive):
+				return damage_claims.NewCreateDamageClaimConflict().
+					WithPayload(buildConflictErrorPayload(messages.ErrDamageClaimOrderActive, ""))
+			case errors.Is(err, domain.ErrDamageClaimExists):
+				return damage_claims.NewCreateDamageClaimConflict().
+					WithPayload(buildConflictErrorPayload(messages.ErrDamageClaimExists, ""))
+			}
+			d.logger.Error(messages.ErrCreateDamageClaim, zap.Error(err))
+			return damage_claims.NewCreateDamageClaimDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrCreateDamageClaim, ""))
+		}
+
+		// the equipment goes to repair for its own not available period, the booking of the closed order stays
+		// as it is. The active orders booked for the repair period are rejected the same way as the order
+		// whose equipment status is put in repair.
+		repairStart, repairEnd := repairPeriod(strfmt.DateTime(timeNowEquipmentStatus()), *p.Data.RepairEndDate)
+		_, err = eqStatusRepository.CreateNotAvailable(ctx, equipmentID, time.Time(repairStart), time.Time(repairEnd))
+		if err != nil {
+			d.logger.Error(messages.ErrPutEquipmentInRepair, zap.Error(err))
+			return damage_claims.NewCreateDamageClaimDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrPutEquipmentInRepair, ""))
+		}
+		overlappingOrders, err := eqStatusRepository.GetActiveOrdersByEquipmentInPeriod(ctx, equipmentID,
+			time.Time(repairStart), time.Time(repairEnd))
+		if err != nil {
+			d.logger.Error(messages.ErrPutEquipmentInRepair, zap.Error(err))
+			return damage_claims.NewCreateDamageClaimDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrPutEquipmentInRepair, ""))
+		}
+		for _, overlapping := range overlappingOrders {
+			if err = rejectOrderUnderRepair(ctx, orderStatusRepo, eqStatusRepository, int(principal.ID),
+				overlapping.ID); err != nil {
+				d.logger.Error(messages.ErrUpdateOrderStatus, zap.Error(err), zap.Int("orderID", overlapping.ID))
+				return damage_claims.NewCreateDamageClaimDefault(http.StatusInternalServerError).
+					WithPayload(buildInternalErrorPayload(messages.ErrUpdateOrderStatus, ""))
+			}
+		}
+		return damage_claims.NewCreateDamageClaimCreated().WithPayload(mapDamageClaim(result))
+	}
+}
+
+// rejectOrderUnderRepair rejects the order with the equipment under repair and makes the equipment of the order
+// available, as the rejection of the order does.
+func rejectOrderUnderRepair(ctx context.Context, orderStatusRepo domain.OrderStatusRepository,
+	eqStatusRepository domain.EquipmentStatusRepository, userID, orderID int) error {
+	comment := EQUIPMENT_UNDER_REPAIR_COMMENT_FOR_ORDER
+	timeNow := timeNowEquipmentStatus()
+	id := int64(orderID)
+	err := orderStatusRepo.UpdateStatus(ctx, userID, models.NewOrderStatus{
+		Comment:   &comment,
+		CreatedAt: (*strfmt.DateTime)(&timeNow),
+		OrderID:   &id,
+		Status:    &domain.OrderStatusRejected,
+	})
+	if err != nil {
+		return err
+	}
+	orderEquipmentStatuses, err := eqStatusRepository.GetEquipmentsStatusesByOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	return UpdateEqStatuses(ctx, eqStatusRepository, orderEquipmentStatuses, &models.EquipmentStatus{
+		StatusName: &domain.EquipmentStatusAvailable,
+	})
+}
+
+func (d DamageClaim) GetDamageClaimFunc(
+	repository domain.DamageClaimRepository) damage_claims.GetDamageClaimHandlerFunc {
+	return func(p damage_claims.GetDamageClaimParams, _ *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		result, err := repository.DamageClaimByID(ctx, int(p.ClaimID))
+		if err != nil {
+			if ent.IsNotFound(err) {
+				return damage_claims.NewGetDamageClaimNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrDamageClaimNotFound, ""))
+			}
+			d.logger.Error(messages.ErrGetDamageClaim, zap.Error(err))
+			return damage_claims.NewGetDamageClaimDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrGetDamageClaim, ""))
+		}
+		return damage_claims.NewGetDamageClaimOK().WithPayload(mapDamageClaim(result))
+	}
+}
+
+func (d DamageClaim) UpdateDamageClaimFunc(
+	repository domain.DamageClaimRepository) damage_claims.UpdateDamageClaimHandlerFunc {
+	return func(p damage_claims.UpdateDamageClaimParams, _ *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		result, err := repository.UpdateDamageClaim(ctx, int(p.ClaimID), *p.Data)
+		if err != nil {
+			switch {
+			case ent.IsNotFound(err):
+				return damage_claims.NewUpdateDamageClaimNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrDamageClaimNotFound, ""))
+			case errors.Is(err, domain.ErrDamageClaimNotOpen):
+				return damage_claims.NewUpdateDamageClaimConflict().
+					WithPayload(buildConflictErrorPayload(messages.ErrDamageClaimNotOpen, ""))
+			}
+			d.logger.Error(messages.ErrUpdateDamageClaim, zap.Error(err))
+			return damage_claims.NewUpdateDamageClaimDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrUpdateDamageClaim, ""))
+		}
+		return damage_claims.NewUpdateDamageClaimOK().WithPayload(mapDamageClaim(result))
+	}
+}
+
+func mapDamageClaim(claim *ent.DamageClaim) *models.DamageClaim {
+	id := int64(claim.ID)
+	status := claim.Status.String()
+	createdAt := strfmt.DateTime(claim.CreatedAt)
+	updatedAt := strfmt.DateTime(claim.UpdatedAt)
+	result := &models.DamageClaim{
+		ID:        &id,
+		Amount:    &claim.Amount,
+		Status:    &status,
+		Notes:     &claim.Notes,
+		CreatedAt: &createdAt,
+		UpdatedAt: &updatedAt,
+	}
+	if claim.Edges.Order != nil {
+		orderID := int64(claim.Edges.Order.ID)
+		result.OrderID = &orderID
+	}
+	if claim.Edges.Equipment != nil {
+		equipmentID := int64(claim.Edges.Equipment.ID)
+		result.EquipmentID = &equipmentID
+	}
+	return result
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/damageclaim"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/mocks"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/damage_claims"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+func TestSetDamageClaimHandler(t *testing.T) {
+	logger := zap.NewNop()
+
+	swaggerSpec, err := loads.Analyzed(restapi.SwaggerJSON, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := operations.NewBeAPI(swaggerSpec)
+	SetDamageClaimHandler(logger, api)
+	require.NotEmpty(t, api.DamageClaimsListDamageClaimsHandler)
+	require.NotEmpty(t, api.DamageClaimsCreateDamageClaimHandler)
+	require.NotEmpty(t, api.DamageClaimsGetDamageClaimHandler)
+	require.NotEmpty(t, api.DamageClaimsUpdateDamageClaimHandler)
+}
+
+type DamageClaimTestSuite struct {
+	suite.Suite
+	logger                    *zap.Logger
+	repository                *mocks.DamageClaimRepository
+	equipmentStatusRepository *mocks.EquipmentStatusRepository
+	orderStatusRepository     *mocks.OrderStatusRepository
+	handler                   *DamageClaim
+	operator                  *models.Principal
+}
+
+func TestDamageClaimSuite(t *testing.T) {
+	suite.Run(t, new(DamageClaimTestSuite))
+}
+
+func (s *DamageClaimTestSuite) SetupTest() {
+	s.logger = zap.NewNop()
+	s.repository = &mocks.DamageClaimRepository{}
+	s.equipmentStatusRepository = &mocks.EquipmentStatusRepository{}
+	s.orderStatusRepository = &mocks.OrderStatusRepository{}
+	s.handler = NewDamageClaim(s.logger)
+	s.operator = &models.Principal{ID: 1, Role: roles.Operator}
+}
+
+func (s *DamageClaimTestSuite) TearDownTest() {
+	s.repository.AssertExpectations(s.T())
+	s.equipmentStatusRepository.AssertExpectations(s.T())
+	s.orderStatusRepository.AssertExpectations(s.T())
+}
+
+func testDamageClaim(id int) *ent.DamageClaim {
+	return &ent.DamageClaim{
+		ID:        id,
+		Amount:    1500,
+		Status:    damageclaim.StatusOpen,
+		Notes:     "broken zipper",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Edges: ent.DamageClaimEdges{
+			Order:     &ent.Order{ID: 2},
+			Equipment: &ent.Equipment{ID: 3},
+		},
+	}
+}
+
+func newDamageClaimParams(request *http.Request, repairEnd time.Time) damage_claims.CreateDamageClaimParams {
+	orderID := int64(2)
+	equipmentID := int64(3)
+	repairEndDate := strfmt.DateTime(repairEnd)
+	return damage_claims.CreateDamageClaimParams{
+		HTTPRequest: request,
+		Data: &models.NewDamageClaim{
+			OrderID:       &orderID,
+			EquipmentID:   &equipmentID,
+			Notes:         "broken zipper",
+			RepairEndDate: &repairEndDate,
+		},
+	}
+}
+
+func (s *DamageClaimTestSuite) TestDamageClaim_Create_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	now := time.Date(2026, time.March, 10, 10, 0, 0, 0, time.UTC)
+	timeNowEquipmentStatus = func() time.Time { return now }
+	defer func() { timeNowEquipmentStatus = time.Now }()
+	repairEnd := now.AddDate(0, 0, 7)
+	repairStartDate, repairEndDate := now.AddDate(0, 0, -1), repairEnd.AddDate(0, 0, 1)
+
+	s.repository.On("CreateDamageClaim", ctx, 2, 3, (*int64)(nil), "broken zipper").
+		Return(testDamageClaim(1), nil)
+	// the closed order keeps its booking, the repair has its own status
+	s.equipmentStatusRepository.On("CreateNotAvailable", ctx, 3, repairStartDate, repairEndDate).
+		Return(&ent.EquipmentStatus{ID: 7}, nil)
+	s.equipmentStatusRepository.On("GetActiveOrdersByEquipmentInPeriod", ctx, 3, repairStartDate, repairEndDate).
+		Return([]*ent.Order{{ID: 8}}, nil)
+	comment := EQUIPMENT_UNDER_REPAIR_COMMENT_FOR_ORDER
+	overlappingOrderID := int64(8)
+	s.orderStatusRepository.On("UpdateStatus", ctx, int(s.operator.ID), models.NewOrderStatus{
+		Comment:   &comment,
+		CreatedAt: (*strfmt.DateTime)(&now),
+		OrderID:   &overlappingOrderID,
+		Status:    &domain.OrderStatusRejected,
+	}).Return(nil)
+	s.equipmentStatusRepository.On("GetEquipmentsStatusesByOrder", ctx, 8).Return([]*ent.EquipmentStatus{
+		{ID: 9, Edges: ent.EquipmentStatusEdges{Equipments: &ent.Equipment{ID: 3}}},
+	}, nil)
+	bookingID := int64(9)
+	s.equipmentStatusRepository.On("Update", ctx, &models.EquipmentStatus{
+		ID:         &bookingID,
+		StatusName: &domain.EquipmentStatusAvailable,
+	}).Return(&ent.EquipmentStatus{ID: 9}, nil)
+
+	handlerFunc := s.handler.CreateDamageClaimFunc(s.repository, s.equipmentStatusRepository, s.orderStatusRepository)
+	resp := handlerFunc.Handle(newDamageClaimParams(&request, repairEnd), s.operator)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusCreated, responseRecorder.Code)
+
+	var actual models.DamageClaim
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+	require.Equal(t, int64(1), *actual.ID)
+	require.Equal(t, int64(2), *actual.OrderID)
+	require.Equal(t, int64(3), *actual.EquipmentID)
+	require.Equal(t, int64(1500), *actual.Amount)
+	require.Equal(t, domain.DamageClaimStatusOpen, *actual.Status)
+}
+
+func (s *DamageClaimTestSuite) TestDamageClaim_Create_RepoErrors() {
+	tests := []struct {
+		name    string
+		err     error
+		code    int
+		message string
+	}{
+		{"not found", &ent.NotFoundError{}, http.StatusNotFound, messages.ErrDamageClaimTargetNotFound},
+		{"not in order", domain.ErrEquipmentNotInOrder, http.StatusBadRequest, messages.ErrEquipmentNotInOrder},
+		{"order active", domain.ErrDamageClaimOrderActive, http.StatusConflict, messages.ErrDamageClaimOrderActive},
+		{"exists", domain.ErrDamageClaimExists, http.StatusConflict, messages.ErrDamageClaimExists},
+		{"other", errors.New("test"), http.StatusInternalServerError, messages.ErrCreateDamageClaim},
+	}
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			t := s.T()
+			request := http.Request{}
+			repository := &mocks.DamageClaimRepository{}
+			repository.On("CreateDamageClaim", mock.Anything, 2, 3, (*int64)(nil), "broken zipper").
+				Return(nil, tc.err)
+
+			handlerFunc := s.handler.CreateDamageClaimFunc(repository, s.equipmentStatusRepository,
+				s.orderStatusRepository)
+			resp := handlerFunc.Handle(newDamageClaimParams(&request, time.Now()), s.operator)
+
+			responseRecorder := httptest.NewRecorder()
+			resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+			require.Equal(t, tc.code, responseRecorder.Code)
+			var actual models.SwaggerError
+			require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+			require.Equal(t, tc.message, *actual.Message)
+			repository.AssertExpectations(t)
+		})
+	}
+}
+
+func (s *DamageClaimTestSuite) TestDamageClaim_Create_RejectOrderError() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("CreateDamageClaim", ctx, 2, 3, (*int64)(nil), "broken zipper").
+		Return(testDamageClaim(1), nil)
+	s.equipmentStatusRepository.On("CreateNotAvailable", ctx, 3, mock.Anything, mock.Anything).
+		Return(&ent.EquipmentStatus{ID: 7}, nil)
+	s.equipmentStatusRepository.On("GetActiveOrdersByEquipmentInPeriod", ctx, 3, mock.Anything, mock.Anything).
+		Return([]*ent.Order{{ID: 8}}, nil)
+	s.orderStatusRepository.On("UpdateStatus", ctx, int(s.operator.ID), mock.Anything).Return(errors.New("test"))
+
+	handlerFunc := s.handler.CreateDamageClaimFunc(s.repository, s.equipmentStatusRepository, s.orderStatusRepository)
+	resp := handlerFunc.Handle(newDamageClaimParams(&request, time.Now()), s.operator)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+	var actual models.SwaggerError
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+	require.Equal(t, messages.ErrUpdateOrderStatus, *actual.Message)
+}
+
+func (s *DamageClaimTestSuite) TestDamageClaim_List_Filter() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	orderID := int64(2)
+	status := domain.DamageClaimStatusOpen
+
+	expectedOrderID := 2
+	s.repository.On("DamageClaims", ctx, domain.DamageClaimFilter{OrderID: &expectedOrderID, Status: &status}).
+		Return([]*ent.DamageClaim{testDamageClaim(1)}, nil)
+
+	handlerFunc := s.handler.ListDamageClaimsFunc(s.repository)
+	resp := handlerFunc.Handle(damage_claims.ListDamageClaimsParams{
+		HTTPRequest: &request,
+		OrderID:     &orderID,
+		Status:      &status,
+	}, s.operator)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+	var actual models.DamageClaims
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+	require.Len(t, actual, 1)
+	require.Equal(t, "broken zipper", *actual[0].Notes)
+}
+
+func (s *DamageClaimTestSuite) TestDamageClaim_Get_NotFound() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("DamageClaimByID", ctx, 1).Return(nil, &ent.NotFoundError{})
+
+	handlerFunc := s.handler.GetDamageClaimFunc(s.repository)
+	resp := handlerFunc.Handle(damage_claims.GetDamageClaimParams{HTTPRequest: &request, ClaimID: 1}, s.operator)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusNotFound, responseRecorder.Code)
+}
+
+func (s *DamageClaimTestSuite) TestDamageClaim_Update_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	status := domain.DamageClaimStatusPaid
+	update := models.DamageClaimUpdate{Status: &status}
+	paid := testDamageClaim(1)
+	paid.Status = damageclaim.StatusPaid
+
+	s.repository.On("UpdateDamageClaim", ctx, 1, update).Return(paid, nil)
+
+	handlerFunc := s.handler.UpdateDamageClaimFunc(s.repository)
+	resp := handlerFunc.Handle(damage_claims.UpdateDamageClaimParams{
+		HTTPRequest: &request,
+		ClaimID:     1,
+		Data:        &update,
+	}, s.operator)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+	var actual models.DamageClaim
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+	require.Equal(t, domain.DamageClaimStatusPaid, *actual.Status)
+}
+
+func (s *DamageClaimTestSuite) TestDamageClaim_Update_NotOpen() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	status := domain.DamageClaimStatusWaived
+	update := models.DamageClaimUpdate{Status: &status}
+
+	s.repository.On("UpdateDamageClaim", ctx, 1, update).Return(nil, domain.ErrDamageClaimNotOpen)
+
+	handlerFunc := s.handler.UpdateDamageClaimFunc(s.repository)
+	resp := handlerFunc.Handle(damage_claims.UpdateDamageClaimParams{
+		HTTPRequest: &request,
+		ClaimID:     1,
+		Data:        &update,
+	}, s.operator)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusConflict, responseRecorder.Code)
+}
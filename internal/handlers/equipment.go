@@ -15,6 +15,7 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/equipment"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/permissions"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/repositories"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
@@ -25,6 +26,7 @@ func SetEquipmentHandler(logger *zap.Logger, api *operations.BeAPI) {
 	eqRepo := repositories.NewEquipmentRepository()
 	eqStatusNameRepo := repositories.NewEquipmentStatusNameRepository()
 	eqStatusRepo := repositories.NewEquipmentStatusRepository()
+	eqPhotoRepo := repositories.NewEquipmentPhotoRepository()
 	equipmentHandler := NewEquipment(logger)
 	api.EquipmentCreateNewEquipmentHandler = equipmentHandler.PostEquipmentFunc(eqRepo, eqStatusNameRepo)
 	api.EquipmentGetEquipmentHandler = equipmentHandler.GetEquipmentFunc(eqRepo)
@@ -35,6 +37,11 @@ func SetEquipmentHandler(logger *zap.Logger, api *operations.BeAPI) {
 	api.EquipmentArchiveEquipmentHandler = equipmentHandler.ArchiveEquipmentFunc(eqRepo)
 	api.EquipmentBlockEquipmentHandler = equipmentHandler.BlockEquipmentFunc(eqRepo, eqStatusRepo)
 	api.EquipmentUnblockEquipmentHandler = equipmentHandler.UnblockEquipmentFunc(eqRepo)
+	api.EquipmentGetEquipmentPhotosHandler = equipmentHandler.GetEquipmentPhotosFunc(eqPhotoRepo)
+	api.EquipmentAddEquipmentPhotoHandler = equipmentHandler.AddEquipmentPhotoFunc(eqPhotoRepo)
+	api.EquipmentReorderEquipmentPhotosHandler = equipmentHandler.ReorderEquipmentPhotosFunc(eqPhotoRepo)
+	api.EquipmentSetEquipmentCoverPhotoHandler = equipmentHandler.SetEquipmentCoverPhotoFunc(eqPhotoRepo)
+	api.EquipmentRemoveEquipmentPhotoHandler = equipmentHandler.RemoveEquipmentPhotoFunc(eqPhotoRepo)
 }
 
 type Equipment struct {
@@ -91,6 +98,7 @@ func (c Equipment) GetEquipmentFunc(repository domain.EquipmentRepository) equip
 				WithPayload(buildInternalErrorPayload(messages.ErrMapEquipment, err.Error()))
 		}
 		returnEq.BlockingPeriods = mapUnavailabilityPeriods(eq.Edges.EquipmentStatus)
+		returnEq.Photos = mapEquipmentPhotos(eq.Edges.Photos)
 		return equipment.NewGetEquipmentOK().WithPayload(returnEq)
 	}
 }
@@ -115,23 +123,24 @@ func (c Equipment) ArchiveEquipmentFunc(repository domain.EquipmentRepository) e
 func (c Equipment) DeleteEquipmentFunc(repository domain.EquipmentRepository) equipment.DeleteEquipmentHandlerFunc {
 	return func(s equipment.DeleteEquipmentParams, _ *models.Principal) middleware.Responder {
 		ctx := s.HTTPRequest.Context()
-		eq, err := repository.EquipmentByID(ctx, int(s.EquipmentID))
+		_, err := repository.EquipmentByID(ctx, int(s.EquipmentID))
 		if err != nil {
 			c.logger.Error(messages.ErrGetEquipment, zap.Error(err))
 			return equipment.NewDeleteEquipmentDefault(http.StatusInternalServerError).
 				WithPayload(buildInternalErrorPayload(messages.ErrDeleteEquipment, err.Error()))
 		}
+		// the photos left without equipment are deleted with their content by the orphaned photos cleanup
 		err = repository.DeleteEquipmentByID(ctx, int(s.EquipmentID))
+		if errors.Is(err, domain.ErrEquipmentHasDamageClaims) {
+			return equipment.NewDeleteEquipmentConflict().
+				WithPayload(buildConflictErrorPayload(messages.ErrEquipmentHasDamageClaims, ""))
+		}
 		if err != nil {
 			c.logger.Error(messages.ErrDeleteEquipment, zap.Error(err))
 			return equipment.NewDeleteEquipmentDefault(http.StatusInternalServerError).
 				WithPayload(buildInternalErrorPayload(messages.ErrDeleteEquipment, err.Error()))
 		}
 
-		if err := repository.DeleteEquipmentPhoto(ctx, eq.Edges.Photo.ID); err != nil {
-			c.logger.Error("Error while deleting photo from db", zap.Error(err))
-		}
-
 		return equipment.NewDeleteEquipmentOK().WithPayload(messages.MsgEquipmentDeleted)
 	}
 }
@@ -272,10 +281,7 @@ func mapEquipmentResponse(eq *ent.Equipment) (*models.EquipmentResponse, error)
 		petSizeID = &idInt64
 	}
 
-	var photoID string
-	if eq.Edges.Photo != nil {
-		photoID = eq.Edges.Photo.ID
-	}
+	photoID := coverPhotoID(eq)
 
 	var eqReceiptDate int64
 	if eq.ReceiptDate != "" {
@@ -322,7 +328,7 @@ func (c Equipment) BlockEquipmentFunc(repository domain.EquipmentRepository, eqS
 				WithPayload(buildInternalErrorPayload(messages.ErrEquipmentBlock, ""))
 		}
 
-		if role != roles.Manager {
+		if role != roles.Manager && !permissions.Has(principal.Permissions, permissions.EquipmentBlock) {
 			c.logger.Warn("User have no right to block the equipment", zap.Any("principal", principal))
 			return equipment.
 				NewBlockEquipmentDefault(http.StatusForbidden).
@@ -371,7 +377,7 @@ func (c Equipment) UnblockEquipmentFunc(repository domain.EquipmentRepository) e
 		ctx := s.HTTPRequest.Context()
 		role := principal.Role
 
-		if role != roles.Manager {
+		if role != roles.Manager && !permissions.Has(principal.Permissions, permissions.EquipmentBlock) {
 			c.logger.Warn(messages.ErrEquipmentUnblockForbidden, zap.Any("principal", principal))
 			return equipment.
 				NewUnblockEquipmentDefault(http.StatusForbidden).
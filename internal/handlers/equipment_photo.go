@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/equipment"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/policies"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+func (c Equipment) GetEquipmentPhotosFunc(
+	repository domain.EquipmentPhotoRepository) equipment.GetEquipmentPhotosHandlerFunc {
+	return func(p equipment.GetEquipmentPhotosParams, _ *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		result, err := repository.EquipmentPhotos(ctx, int(p.EquipmentID))
+		if err != nil {
+			if ent.IsNotFound(err) {
+				return equipment.NewGetEquipmentPhotosNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrEquipmentNotFound, ""))
+			}
+			c.logger.Error(messages.ErrQueryEquipmentPhotos, zap.Error(err))
+			return equipment.NewGetEquipmentPhotosDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrQueryEquipmentPhotos, ""))
+		}
+		return equipment.NewGetEquipmentPhotosOK().WithPayload(mapEquipmentPhotosResponse(result))
+	}
+}
+
+func (c Equipment) AddEquipmentPhotoFunc(
+	repository domain.EquipmentPhotoRepository) equipment.AddEquipmentPhotoHandlerFunc {
+	return func(p equipment.AddEquipmentPhotoParams, principal *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		if !policies.Can(principal, policies.Update, policies.Resource{Kind: policies.Photo}) {
+			c.logger.Warn(messages.ErrPhotoForbidden, zap.Any("principal", principal))
+			return equipment.NewAddEquipmentPhotoForbidden().
+				WithPayload(buildForbiddenErrorPayload(messages.ErrPhotoForbidden, ""))
+		}
+		result, err := repository.AddEquipmentPhoto(ctx, int(p.EquipmentID), *p.Data.PhotoID, p.Data.Cover)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrEquipmentPhotoNotFound):
+				return equipment.NewAddEquipmentPhotoNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrEquipmentPhotoNotFound, ""))
+			case ent.IsNotFound(err):
+				return equipment.NewAddEquipmentPhotoNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrEquipmentNotFound, ""))
+			}
+			c.logger.Error(messages.ErrUpdateEquipmentPhotos, zap.Error(err))
+			return equipment.NewAddEquipmentPhotoDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrUpdateEquipmentPhotos, ""))
+		}
+		return equipment.NewAddEquipmentPhotoOK().WithPayload(mapEquipmentPhotosResponse(result))
+	}
+}
+
+func (c Equipment) ReorderEquipmentPhotosFunc(
+	repository domain.EquipmentPhotoRepository) equipment.ReorderEquipmentPhotosHandlerFunc {
+	return func(p equipment.ReorderEquipmentPhotosParams, principal *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		if !policies.Can(principal, policies.Update, policies.Resource{Kind: policies.Photo}) {
+			c.logger.Warn(messages.ErrPhotoForbidden, zap.Any("principal", principal))
+			return equipment.NewReorderEquipmentPhotosForbidden().
+				WithPayload(buildForbiddenErrorPayload(messages.ErrPhotoForbidden, ""))
+		}
+		result, err := repository.ReorderEquipmentPhotos(ctx, int(p.EquipmentID), p.Data.PhotoIds)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrEquipmentPhotosMismatch):
+				return equipment.NewReorderEquipmentPhotosBadRequest().
+					WithPayload(buildBadRequestErrorPayload(messages.ErrEquipmentPhotosMismatch, ""))
+			case ent.IsNotFound(err):
+				return equipment.NewReorderEquipmentPhotosNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrEquipmentNotFound, ""))
+			}
+			c.logger.Error(messages.ErrUpdateEquipmentPhotos, zap.Error(err))
+			return equipment.NewReorderEquipmentPhotosDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrUpdateEquipmentPhotos, ""))
+		}
+		return equipment.NewReorderEquipmentPhotosOK().WithPayload(mapEquipmentPhotosResponse(result))
+	}
+}
+
+func (c Equipment) SetEquipmentCoverPhotoFunc(
+	repository domain.EquipmentPhotoRepository) equipment.SetEquipmentCoverPhotoHandlerFunc {
+	return func(p equipment.SetEquipmentCoverPhotoParams, principal *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		if !policies.Can(principal, policies.Update, policies.Resource{Kind: policies.Photo}) {
+			c.logger.Warn(messages.ErrPhotoForbidden, zap.Any("principal", principal))
+			return equipment.NewSetEquipmentCoverPhotoForbidden().
+				WithPayload(buildForbiddenErrorPayload(messages.ErrPhotoForbidden, ""))
+		}
+		result, err := repository.SetEquipmentCoverPhoto(ctx, int(p.EquipmentID), p.PhotoID)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrEquipmentPhotoNotFound):
+				return equipment.NewSetEquipmentCoverPhotoNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrEquipmentPhotoNotFound, ""))
+			case ent.IsNotFound(err):
+				return equipment.NewSetEquipmentCoverPhotoNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrEquipmentNotFound, ""))
+			}
+			c.logger.Error(messages.ErrUpdateEquipmentPhotos, zap.Error(err))
+			return equipment.NewSetEquipmentCoverPhotoDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrUpdateEquipmentPhotos, ""))
+		}
+		return equipment.NewSetEquipmentCoverPhotoOK().WithPayload(mapEquipmentPhotosResponse(result))
+	}
+}
+
+// RemoveEquipmentPhotoFunc removes the photo from the gallery, the photo which is left in no gallery is deleted
+// by the orphaned photos cleanup.
+func (c Equipment) RemoveEquipmentPhotoFunc(
+	repository domain.EquipmentPhotoRepository) equipment.RemoveEquipmentPhotoHandlerFunc {
+	return func(p equipment.RemoveEquipmentPhotoParams, principal *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		if !policies.Can(principal, policies.Delete, policies.Resource{Kind: policies.Photo}) {
+			c.logger.Warn(messages.ErrPhotoForbidden, zap.Any("principal", principal))
+			return equipment.NewRemoveEquipmentPhotoForbidden().
+				WithPayload(buildForbiddenErrorPayload(messages.ErrPhotoForbidden, ""))
+		}
+		result, err := repository.RemoveEquipmentPhoto(ctx, int(p.EquipmentID), p.PhotoID)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrEquipmentPhotoNotFound):
+				return equipment.NewRemoveEquipmentPhotoNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrEquipmentPhotoNotFound, ""))
+			case ent.IsNotFound(err):
+				return equipment.NewRemoveEquipmentPhotoNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrEquipmentNotFound, ""))
+			}
+			c.logger.Error(messages.ErrUpdateEquipmentPhotos, zap.Error(err))
+			return equipment.NewRemoveEquipmentPhotoDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrUpdateEquipmentPhotos, ""))
+		}
+		return equipment.NewRemoveEquipmentPhotoOK().WithPayload(mapEquipmentPhotosResponse(result))
+	}
+}
+
+// coverPhotoID returns the id of the cover photo of the equipment or the empty string if it has no photos.
+func coverPhotoID(eq *ent.Equipment) string {
+	for _, p := range eq.Edges.Photos {
+		if p.Cover && p.Edges.Photo != nil {
+			return p.Edges.Photo.ID
+		}
+	}
+	return ""
+}
+
+func mapEquipmentPhotos(photos []*ent.EquipmentPhoto) []*models.EquipmentPhoto {
+	result := make([]*models.EquipmentPhoto, 0, len(photos))
+	for _, p := range photos {
+		if p.Edges.Photo == nil {
+			continue
+		}
+		photoID := p.Edges.Photo.ID
+		position := int64(p.Position)
+		cover := p.Cover
+		result = append(result, &models.EquipmentPhoto{
+			PhotoID:  &photoID,
+			Position: &position,
+			Cover:    &cover,
+		})
+	}
+	return result
+}
+
+func mapEquipmentPhotosResponse(photos []*ent.EquipmentPhoto) *models.EquipmentPhotos {
+	return &models.EquipmentPhotos{Items: mapEquipmentPhotos(photos)}
+}
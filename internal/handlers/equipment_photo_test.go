@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/mocks"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/equipment"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type EquipmentPhotoTestSuite struct {
+	suite.Suite
+	logger        *zap.Logger
+	repository    *mocks.EquipmentPhotoRepository
+	equipmentRepo *mocks.EquipmentRepository
+	handler       *Equipment
+	manager       *models.Principal
+	user          *models.Principal
+}
+
+func TestEquipmentPhotoSuite(t *testing.T) {
+	suite.Run(t, new(EquipmentPhotoTestSuite))
+}
+
+func (s *EquipmentPhotoTestSuite) SetupTest() {
+	s.logger = zap.NewNop()
+	s.repository = &mocks.EquipmentPhotoRepository{}
+	s.equipmentRepo = &mocks.EquipmentRepository{}
+	s.handler = NewEquipment(s.logger)
+	s.manager = &models.Principal{ID: 1, Role: roles.Manager}
+	s.user = &models.Principal{ID: 2, Role: roles.User}
+}
+
+func (s *EquipmentPhotoTestSuite) TearDownTest() {
+	s.repository.AssertExpectations(s.T())
+	s.equipmentRepo.AssertExpectations(s.T())
+}
+
+func testGallery(ids ...string) []*ent.EquipmentPhoto {
+	gallery := make([]*ent.EquipmentPhoto, len(ids))
+	for i, id := range ids {
+		gallery[i] = &ent.EquipmentPhoto{
+			ID:       i + 1,
+			Position: i,
+			Cover:    i == 0,
+			Edges:    ent.EquipmentPhotoEdges{Photo: &ent.Photo{ID: id}},
+		}
+	}
+	return gallery
+}
+
+func (s *EquipmentPhotoTestSuite) TestEquipmentPhoto_GetEquipmentPhotos_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("EquipmentPhotos", ctx, 1).Return(testGallery("first", "second"), nil)
+
+	handlerFunc := s.handler.GetEquipmentPhotosFunc(s.repository)
+	resp := handlerFunc.Handle(equipment.GetEquipmentPhotosParams{HTTPRequest: &request, EquipmentID: 1}, s.user)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var actual models.EquipmentPhotos
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+	require.Len(t, actual.Items, 2)
+	require.Equal(t, "first", *actual.Items[0].PhotoID)
+	require.True(t, *actual.Items[0].Cover)
+	require.Equal(t, int64(1), *actual.Items[1].Position)
+	require.False(t, *actual.Items[1].Cover)
+}
+
+func (s *EquipmentPhotoTestSuite) TestEquipmentPhoto_GetEquipmentPhotos_NotFound() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("EquipmentPhotos", ctx, 1).Return(nil, &ent.NotFoundError{})
+
+	handlerFunc := s.handler.GetEquipmentPhotosFunc(s.repository)
+	resp := handlerFunc.Handle(equipment.GetEquipmentPhotosParams{HTTPRequest: &request, EquipmentID: 1}, s.user)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusNotFound, responseRecorder.Code)
+}
+
+func (s *EquipmentPhotoTestSuite) TestEquipmentPhoto_AddEquipmentPhoto() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	handlerFunc := s.handler.AddEquipmentPhotoFunc(s.repository)
+	for photoID, testCase := range map[string]struct {
+		principal *models.Principal
+		err       error
+		code      int
+	}{
+		"forbidden": {principal: s.user, code: http.StatusForbidden},
+		"photo":     {principal: s.manager, code: http.StatusOK},
+		"unknown":   {principal: s.manager, err: domain.ErrEquipmentPhotoNotFound, code: http.StatusNotFound},
+		"equipment": {principal: s.manager, err: &ent.NotFoundError{}, code: http.StatusNotFound},
+		"failed":    {principal: s.manager, err: errors.New("test"), code: http.StatusInternalServerError},
+	} {
+		if testCase.code != http.StatusForbidden {
+			var gallery []*ent.EquipmentPhoto
+			if testCase.err == nil {
+				gallery = testGallery(photoID)
+			}
+			s.repository.On("AddEquipmentPhoto", ctx, 1, photoID, true).Return(gallery, testCase.err)
+		}
+		id := photoID
+		resp := handlerFunc.Handle(equipment.AddEquipmentPhotoParams{
+			HTTPRequest: &request,
+			EquipmentID: 1,
+			Data:        &models.AddEquipmentPhotoRequest{PhotoID: &id, Cover: true},
+		}, testCase.principal)
+
+		responseRecorder := httptest.NewRecorder()
+		resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+		require.Equal(t, testCase.code, responseRecorder.Code, photoID)
+	}
+}
+
+func (s *EquipmentPhotoTestSuite) TestEquipmentPhoto_ReorderEquipmentPhotos() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	handlerFunc := s.handler.ReorderEquipmentPhotosFunc(s.repository)
+	for equipmentID, testCase := range map[int64]struct {
+		err  error
+		code int
+	}{
+		1: {code: http.StatusOK},
+		2: {err: domain.ErrEquipmentPhotosMismatch, code: http.StatusBadRequest},
+		3: {err: &ent.NotFoundError{}, code: http.StatusNotFound},
+		4: {err: errors.New("test"), code: http.StatusInternalServerError},
+	} {
+		var gallery []*ent.EquipmentPhoto
+		if testCase.err == nil {
+			gallery = testGallery("second", "first")
+		}
+		ids := []string{"second", "first"}
+		s.repository.On("ReorderEquipmentPhotos", ctx, int(equipmentID), ids).Return(gallery, testCase.err)
+		resp := handlerFunc.Handle(equipment.ReorderEquipmentPhotosParams{
+			HTTPRequest: &request,
+			EquipmentID: equipmentID,
+			Data:        &models.ReorderEquipmentPhotosRequest{PhotoIds: ids},
+		}, s.manager)
+
+		responseRecorder := httptest.NewRecorder()
+		resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+		require.Equal(t, testCase.code, responseRecorder.Code, equipmentID)
+	}
+
+	resp := handlerFunc.Handle(equipment.ReorderEquipmentPhotosParams{
+		HTTPRequest: &request,
+		EquipmentID: 1,
+		Data:        &models.ReorderEquipmentPhotosRequest{PhotoIds: []string{"first"}},
+	}, s.user)
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusForbidden, responseRecorder.Code)
+}
+
+func (s *EquipmentPhotoTestSuite) TestEquipmentPhoto_SetEquipmentCoverPhoto() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("SetEquipmentCoverPhoto", ctx, 1, "second").Return(testGallery("second", "first"), nil)
+	s.repository.On("SetEquipmentCoverPhoto", ctx, 1, "unknown").Return(nil, domain.ErrEquipmentPhotoNotFound)
+
+	handlerFunc := s.handler.SetEquipmentCoverPhotoFunc(s.repository)
+	for photoID, code := range map[string]int{"second": http.StatusOK, "unknown": http.StatusNotFound} {
+		resp := handlerFunc.Handle(equipment.SetEquipmentCoverPhotoParams{
+			HTTPRequest: &request,
+			EquipmentID: 1,
+			PhotoID:     photoID,
+		}, s.manager)
+
+		responseRecorder := httptest.NewRecorder()
+		resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+		require.Equal(t, code, responseRecorder.Code, photoID)
+	}
+}
+
+func (s *EquipmentPhotoTestSuite) TestEquipmentPhoto_RemoveEquipmentPhoto_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("RemoveEquipmentPhoto", ctx, 1, "first").Return(testGallery("second"), nil)
+
+	handlerFunc := s.handler.RemoveEquipmentPhotoFunc(s.repository)
+	resp := handlerFunc.Handle(equipment.RemoveEquipmentPhotoParams{
+		HTTPRequest: &request,
+		EquipmentID: 1,
+		PhotoID:     "first",
+	}, s.manager)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var actual models.EquipmentPhotos
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+	require.Len(t, actual.Items, 1)
+	require.Equal(t, "second", *actual.Items[0].PhotoID)
+}
+
+func (s *EquipmentPhotoTestSuite) TestEquipmentPhoto_RemoveEquipmentPhoto_Errors() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("RemoveEquipmentPhoto", ctx, 1, "unknown").Return(nil, domain.ErrEquipmentPhotoNotFound)
+
+	handlerFunc := s.handler.RemoveEquipmentPhotoFunc(s.repository)
+	for principal, code := range map[*models.Principal]int{
+		s.user:    http.StatusForbidden,
+		s.manager: http.StatusNotFound,
+	} {
+		resp := handlerFunc.Handle(equipment.RemoveEquipmentPhotoParams{
+			HTTPRequest: &request,
+			EquipmentID: 1,
+			PhotoID:     "unknown",
+		}, principal)
+
+		responseRecorder := httptest.NewRecorder()
+		resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+		require.Equal(t, code, responseRecorder.Code, principal.Role)
+	}
+}
+
+func TestMapEquipmentResponse_CoverPhoto(t *testing.T) {
+	eq := ValidEquipment(t, 1)
+	eq.Edges.Photos = testGallery("cover", "other")
+	eq.Edges.Photos[0].Cover, eq.Edges.Photos[1].Cover = false, true
+
+	result, err := mapEquipmentResponse(eq)
+	require.NoError(t, err)
+	require.Equal(t, "other", *result.PhotoID)
+
+	eq.Edges.Photos = nil
+	result, err = mapEquipmentResponse(eq)
+	require.NoError(t, err)
+	require.Empty(t, *result.PhotoID)
+}
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"time"
 
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
 	eqStatus "git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/equipment_status"
@@ -122,30 +124,16 @@ func (c EquipmentStatus) PutEquipmentStatusInRepairFunc(
 				WithPayload(buildBadRequestErrorPayload(messages.ErrWrongEqStatus, ""))
 		}
 
-		reduceOneDayFromCurrentStartDate := strfmt.DateTime(
-			time.Time(*s.Name.StartDate).AddDate(0, 0, -1),
-		)
-
-		addOneDayToCurrentEndDate := strfmt.DateTime(
-			time.Time(*s.Name.EndDate).AddDate(0, 0, 1),
-		)
-
-		data := models.EquipmentStatus{
-			StartDate:  &reduceOneDayFromCurrentStartDate,
-			EndDate:    &addOneDayToCurrentEndDate,
-			StatusName: newStatus,
-			ID:         &s.EquipmentstatusID,
-		}
-
 		orderResult, userResult, err := eqStatusRepository.GetOrderAndUserByEquipmentStatusID(
-			ctx, int(*data.ID))
+			ctx, int(s.EquipmentstatusID))
 		if err != nil {
 			c.logger.Error(messages.ErrOrderAndUserByEqStatusID, zap.Error(err))
 			return eqStatus.NewUpdateEquipmentStatusOnUnavailableDefault(http.StatusInternalServerError).
 				WithPayload(buildInternalErrorPayload(messages.ErrOrderAndUserByEqStatusID, err.Error()))
 		}
 
-		updatedEqStatus, err := eqStatusRepository.Update(ctx, &data)
+		updatedEqStatus, err := putEquipmentStatusInRepair(ctx, eqStatusRepository, s.EquipmentstatusID,
+			*s.Name.StartDate, *s.Name.EndDate)
 		if err != nil {
 			c.logger.Error(messages.ErrUpdateEqStatus, zap.Error(err))
 			return eqStatus.NewUpdateEquipmentStatusOnUnavailableDefault(http.StatusInternalServerError).
@@ -170,7 +158,7 @@ func (c EquipmentStatus) PutEquipmentStatusInRepairFunc(
 		}
 
 		eqStatusResult, err := eqStatusRepository.GetEquipmentStatusByID(
-			ctx, int(*data.ID))
+			ctx, int(s.EquipmentstatusID))
 		if err != nil {
 			c.logger.Error("receiving equipment status by id failed during changing status to unavailable", zap.Error(err))
 			return eqStatus.NewCheckEquipmentStatusDefault(http.StatusInternalServerError).
@@ -298,6 +286,26 @@ func (c EquipmentStatus) PatchEquipmentStatusEditDatesFunc(
 	}
 }
 
+// putEquipmentStatusInRepair makes the equipment status not available for the repair period.
+func putEquipmentStatusInRepair(ctx context.Context, eqStatusRepository domain.EquipmentStatusRepository,
+	equipmentStatusID int64, startDate, endDate strfmt.DateTime) (*ent.EquipmentStatus, error) {
+	reduceOneDayFromCurrentStartDate, addOneDayToCurrentEndDate := repairPeriod(startDate, endDate)
+
+	return eqStatusRepository.Update(ctx, &models.EquipmentStatus{
+		StartDate:  &reduceOneDayFromCurrentStartDate,
+		EndDate:    &addOneDayToCurrentEndDate,
+		StatusName: &domain.EquipmentStatusNotAvailable,
+		ID:         &equipmentStatusID,
+	})
+}
+
+// repairPeriod returns the period the equipment is not available for the repair,
+// the repair dates are extended by a day on both sides.
+func repairPeriod(startDate, endDate strfmt.DateTime) (strfmt.DateTime, strfmt.DateTime) {
+	return strfmt.DateTime(time.Time(startDate).AddDate(0, 0, -1)),
+		strfmt.DateTime(time.Time(endDate).AddDate(0, 0, 1))
+}
+
 func newStatusIsUnavailable(status string) bool {
 	return status == domain.EquipmentStatusNotAvailable
 }
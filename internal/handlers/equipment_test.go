@@ -26,6 +26,7 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/equipment"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
 )
@@ -53,6 +54,11 @@ func TestSetEquipmentHandler(t *testing.T) {
 	require.NotEmpty(t, api.EquipmentArchiveEquipmentHandler)
 	require.NotEmpty(t, api.EquipmentBlockEquipmentHandler)
 	require.NotEmpty(t, api.EquipmentUnblockEquipmentHandler)
+	require.NotEmpty(t, api.EquipmentGetEquipmentPhotosHandler)
+	require.NotEmpty(t, api.EquipmentAddEquipmentPhotoHandler)
+	require.NotEmpty(t, api.EquipmentReorderEquipmentPhotosHandler)
+	require.NotEmpty(t, api.EquipmentSetEquipmentCoverPhotoHandler)
+	require.NotEmpty(t, api.EquipmentRemoveEquipmentPhotoHandler)
 }
 
 type EquipmentTestSuite struct {
@@ -80,8 +86,8 @@ func ValidEquipment(t *testing.T, id int) *ent.Equipment {
 		Edges: ent.EquipmentEdges{
 			Category:      &ent.Category{},
 			CurrentStatus: &ent.EquipmentStatusName{},
-			Photo: &ent.Photo{
-				ID: "photoid",
+			Photos: []*ent.EquipmentPhoto{
+				{Cover: true, Edges: ent.EquipmentPhotoEdges{Photo: &ent.Photo{ID: "photoid"}}},
 			},
 		},
 	}
@@ -381,6 +387,33 @@ func (s *EquipmentTestSuite) TestEquipment_DeleteEquipmentFunc_RepoErr() {
 	s.equipmentRepo.AssertExpectations(t)
 }
 
+func (s *EquipmentTestSuite) TestEquipment_DeleteEquipmentFunc_HasDamageClaims() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	handlerFunc := s.equipment.DeleteEquipmentFunc(s.equipmentRepo)
+	equipmentId := int64(1)
+	data := equipment.DeleteEquipmentParams{
+		HTTPRequest: &request,
+		EquipmentID: equipmentId,
+	}
+
+	equipmentToReturn := ValidEquipment(t, 1)
+	s.equipmentRepo.On("EquipmentByID", ctx, int(equipmentId)).Return(equipmentToReturn, nil)
+	s.equipmentRepo.On("DeleteEquipmentByID", ctx, int(equipmentId)).Return(domain.ErrEquipmentHasDamageClaims)
+
+	resp := handlerFunc(data, nil)
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusConflict, responseRecorder.Code)
+	var actual models.SwaggerError
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+	require.Equal(t, messages.ErrEquipmentHasDamageClaims, *actual.Message)
+	s.equipmentRepo.AssertExpectations(t)
+}
+
 func (s *EquipmentTestSuite) TestEquipment_DeleteEquipmentFunc_OK() {
 	t := s.T()
 	request := http.Request{}
@@ -396,7 +429,6 @@ func (s *EquipmentTestSuite) TestEquipment_DeleteEquipmentFunc_OK() {
 	equipmentToReturn := ValidEquipment(t, 1)
 	s.equipmentRepo.On("EquipmentByID", ctx, int(equipmentId)).Return(equipmentToReturn, nil)
 	s.equipmentRepo.On("DeleteEquipmentByID", ctx, int(equipmentId)).Return(nil)
-	s.equipmentRepo.On("DeleteEquipmentPhoto", ctx, equipmentToReturn.Edges.Photo.ID).Return(nil)
 
 	resp := handlerFunc(data, nil)
 	responseRecorder := httptest.NewRecorder()
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/permissions"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/repositories"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+func SetGroupHandler(logger *zap.Logger, api *operations.BeAPI) {
+	groupRepo := repositories.NewGroupRepository()
+	groupHandler := NewGroup(logger)
+
+	api.PermissionsGetPermissionsHandler = groupHandler.GetPermissionsFunc(groupRepo)
+	api.PermissionsGetGroupsHandler = groupHandler.GetGroupsFunc(groupRepo)
+	api.PermissionsGetGroupHandler = groupHandler.GetGroupFunc(groupRepo)
+	api.PermissionsCreateGroupHandler = groupHandler.CreateGroupFunc(groupRepo)
+	api.PermissionsUpdateGroupHandler = groupHandler.UpdateGroupFunc(groupRepo)
+	api.PermissionsDeleteGroupHandler = groupHandler.DeleteGroupFunc(groupRepo)
+	api.PermissionsSetGroupPermissionsHandler = groupHandler.SetGroupPermissionsFunc(groupRepo)
+	api.PermissionsAddGroupUsersHandler = groupHandler.AddGroupUsersFunc(groupRepo)
+	api.PermissionsRemoveGroupUserHandler = groupHandler.RemoveGroupUserFunc(groupRepo)
+}
+
+// Group manages permission groups. Managers and operators have access to all endpoints,
+// so the handlers allow only administrators to change who is granted what.
+type Group struct {
+	logger *zap.Logger
+}
+
+func NewGroup(logger *zap.Logger) *Group {
+	return &Group{
+		logger: logger,
+	}
+}
+
+func (g Group) GetPermissionsFunc(repository domain.GroupRepository) permissions.GetPermissionsHandlerFunc {
+	return func(p permissions.GetPermissionsParams, principal *models.Principal) middleware.Responder {
+		if principal.Role != roles.Admin {
+			return permissions.NewGetPermissionsDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrGroupsForbidden, ""))
+		}
+		ctx := p.HTTPRequest.Context()
+		result, err := repository.AllPermissions(ctx)
+		if err != nil {
+			g.logger.Error(messages.ErrQueryPermissions, zap.Error(err))
+			return permissions.NewGetPermissionsDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrQueryPermissions, ""))
+		}
+		listPermissions := models.ListPermissions{}
+		for _, element := range result {
+			id := int64(element.ID)
+			name := element.Name
+			description := element.Description
+			listPermissions = append(listPermissions, &models.Permission{
+				ID:          &id,
+				Name:        &name,
+				Description: &description,
+			})
+		}
+		return permissions.NewGetPermissionsOK().WithPayload(listPermissions)
+	}
+}
+
+func (g Group) GetGroupsFunc(repository domain.GroupRepository) permissions.GetGroupsHandlerFunc {
+	return func(p permissions.GetGroupsParams, principal *models.Principal) middleware.Responder {
+		if principal.Role != roles.Admin {
+			return permissions.NewGetGroupsDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrGroupsForbidden, ""))
+		}
+		ctx := p.HTTPRequest.Context()
+		result, err := repository.AllGroups(ctx)
+		if err != nil {
+			g.logger.Error(messages.ErrQueryGroups, zap.Error(err))
+			return permissions.NewGetGroupsDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrQueryGroups, ""))
+		}
+		listGroups := models.ListGroups{}
+		for _, element := range result {
+			listGroups = append(listGroups, mapGroup(element))
+		}
+		return permissions.NewGetGroupsOK().WithPayload(listGroups)
+	}
+}
+
+func (g Group) GetGroupFunc(repository domain.GroupRepository) permissions.GetGroupHandlerFunc {
+	return func(p permissions.GetGroupParams, principal *models.Principal) middleware.Responder {
+		if principal.Role != roles.Admin {
+			return permissions.NewGetGroupDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrGroupsForbidden, ""))
+		}
+		ctx := p.HTTPRequest.Context()
+		result, err := repository.GroupByID(ctx, int(p.GroupID))
+		if err != nil {
+			if ent.IsNotFound(err) {
+				return permissions.NewGetGroupNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrGroupNotFound, ""))
+			}
+			g.logger.Error(messages.ErrGetGroup, zap.Error(err))
+			return permissions.NewGetGroupDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrGetGroup, ""))
+		}
+		return permissions.NewGetGroupOK().WithPayload(mapGroup(result))
+	}
+}
+
+func (g Group) CreateGroupFunc(repository domain.GroupRepository) permissions.CreateGroupHandlerFunc {
+	return func(p permissions.CreateGroupParams, principal *models.Principal) middleware.Responder {
+		if principal.Role != roles.Admin {
+			return permissions.NewCreateGroupDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrGroupsForbidden, ""))
+		}
+		ctx := p.HTTPRequest.Context()
+		result, err := repository.CreateGroup(ctx, *p.Data.Name, p.Data.Description)
+		if err != nil {
+			if ent.IsConstraintError(err) {
+				return permissions.NewCreateGroupConflict().
+					WithPayload(buildConflictErrorPayload(messages.ErrGroupAlreadyExists, ""))
+			}
+			g.logger.Error(messages.ErrCreateGroup, zap.Error(err))
+			return permissions.NewCreateGroupDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrCreateGroup, ""))
+		}
+		return permissions.NewCreateGroupCreated().WithPayload(mapGroup(result))
+	}
+}
+
+func (g Group) UpdateGroupFunc(repository domain.GroupRepository) permissions.UpdateGroupHandlerFunc {
+	return func(p permissions.UpdateGroupParams, principal *models.Principal) middleware.Responder {
+		if principal.Role != roles.Admin {
+			return permissions.NewUpdateGroupDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrGroupsForbidden, ""))
+		}
+		ctx := p.HTTPRequest.Context()
+		result, err := repository.UpdateGroup(ctx, int(p.GroupID), *p.Data.Name, p.Data.Description)
+		if err != nil {
+			if ent.IsNotFound(err) {
+				return permissions.NewUpdateGroupNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrGroupNotFound, ""))
+			}
+			if ent.IsConstraintError(err) {
+				return permissions.NewUpdateGroupConflict().
+					WithPayload(buildConflictErrorPayload(messages.ErrGroupAlreadyExists, ""))
+			}
+			g.logger.Error(messages.ErrUpdateGroup, zap.Error(err))
+			return permissions.NewUpdateGroupDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrUpdateGroup, ""))
+		}
+		return permissions.NewUpdateGroupOK().WithPayload(mapGroup(result))
+	}
+}
+
+func (g Group) DeleteGroupFunc(repository domain.GroupRepository) permissions.DeleteGroupHandlerFunc {
+	return func(p permissions.DeleteGroupParams, principal *models.Principal) middleware.Responder {
+		if principal.Role != roles.Admin {
+			return permissions.NewDeleteGroupDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrGroupsForbidden, ""))
+		}
+		ctx := p.HTTPRequest.Context()
+		err := repository.DeleteGroup(ctx, int(p.GroupID))
+		if err != nil {
+			if ent.IsNotFound(err) {
+				return permissions.NewDeleteGroupNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrGroupNotFound, ""))
+			}
+			g.logger.Error(messages.ErrDeleteGroup, zap.Error(err))
+			return permissions.NewDeleteGroupDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrDeleteGroup, ""))
+		}
+		return permissions.NewDeleteGroupOK().WithPayload(messages.MsgGroupDeleted)
+	}
+}
+
+func (g Group) SetGroupPermissionsFunc(repository domain.GroupRepository) permissions.SetGroupPermissionsHandlerFunc {
+	return func(p permissions.SetGroupPermissionsParams, principal *models.Principal) middleware.Responder {
+		if principal.Role != roles.Admin {
+			return permissions.NewSetGroupPermissionsDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrGroupsForbidden, ""))
+		}
+		ctx := p.HTTPRequest.Context()
+		result, err := repository.SetGroupPermissions(ctx, int(p.GroupID), p.Data.Permissions)
+		if err != nil {
+			if errors.Is(err, domain.ErrUnknownPermission) {
+				return permissions.NewSetGroupPermissionsBadRequest().
+					WithPayload(buildBadRequestErrorPayload(messages.ErrUnknownPermission, ""))
+			}
+			if ent.IsNotFound(err) {
+				return permissions.NewSetGroupPermissionsNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrGroupNotFound, ""))
+			}
+			g.logger.Error(messages.ErrSetGroupPermissions, zap.Error(err))
+			return permissions.NewSetGroupPermissionsDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrSetGroupPermissions, ""))
+		}
+		return permissions.NewSetGroupPermissionsOK().WithPayload(mapGroup(result))
+	}
+}
+
+func (g Group) AddGroupUsersFunc(repository domain.GroupRepository) permissions.AddGroupUsersHandlerFunc {
+	return func(p permissions.AddGroupUsersParams, principal *models.Principal) middleware.Responder {
+		if principal.Role != roles.Admin {
+			return permissions.NewAddGroupUsersDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrGroupsForbidden, ""))
+		}
+		ctx := p.HTTPRequest.Context()
+		userIDs := make([]int, len(p.Data.UserIds))
+		for i, id := range p.Data.UserIds {
+			userIDs[i] = int(id)
+		}
+		result, err := repository.AddGroupUsers(ctx, int(p.GroupID), userIDs)
+		if err != nil {
+			if errors.Is(err, domain.ErrUnknownUser) {
+				return permissions.NewAddGroupUsersBadRequest().
+					WithPayload(buildBadRequestErrorPayload(messages.ErrUnknownUser, ""))
+			}
+			if ent.IsNotFound(err) {
+				return permissions.NewAddGroupUsersNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrGroupNotFound, ""))
+			}
+			g.logger.Error(messages.ErrAddGroupUsers, zap.Error(err))
+			return permissions.NewAddGroupUsersDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrAddGroupUsers, ""))
+		}
+		return permissions.NewAddGroupUsersOK().WithPayload(mapGroup(result))
+	}
+}
+
+func (g Group) RemoveGroupUserFunc(repository domain.GroupRepository) permissions.RemoveGroupUserHandlerFunc {
+	return func(p permissions.RemoveGroupUserParams, principal *models.Principal) middleware.Responder {
+		if principal.Role != roles.Admin {
+			return permissions.NewRemoveGroupUserDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrGroupsForbidden, ""))
+		}
+		ctx := p.HTTPRequest.Context()
+		result, err := repository.RemoveGroupUser(ctx, int(p.GroupID), int(p.UserID))
+		if err != nil {
+			if ent.IsNotFound(err) {
+				return permissions.NewRemoveGroupUserNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrGroupNotFound, ""))
+			}
+			g.logger.Error(messages.ErrRemoveGroupUser, zap.Error(err))
+			return permissions.NewRemoveGroupUserDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrRemoveGroupUser, ""))
+		}
+		return permissions.NewRemoveGroupUserOK().WithPayload(mapGroup(result))
+	}
+}
+
+func mapGroup(group *ent.Group) *models.Group {
+	id := int64(group.ID)
+	name := group.Name
+	description := group.Description
+	groupPermissions := make([]string, len(group.Edges.Permissions))
+	for i, p := range group.Edges.Permissions {
+		groupPermissions[i] = p.Name
+	}
+	userIDs := make([]int64, len(group.Edges.Users))
+	for i, u := range group.Edges.Users {
+		userIDs[i] = int64(u.ID)
+	}
+	return &models.Group{
+		ID:          &id,
+		Name:        &name,
+		Description: &description,
+		Permissions: groupPermissions,
+		UserIds:     userIDs,
+	}
+}
@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/runtime"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/mocks"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/permissions"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+func TestSetGroupHandler(t *testing.T) {
+	logger := zap.NewNop()
+
+	swaggerSpec, err := loads.Analyzed(restapi.SwaggerJSON, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := operations.NewBeAPI(swaggerSpec)
+	SetGroupHandler(logger, api)
+	require.NotEmpty(t, api.PermissionsGetPermissionsHandler)
+	require.NotEmpty(t, api.PermissionsGetGroupsHandler)
+	require.NotEmpty(t, api.PermissionsGetGroupHandler)
+	require.NotEmpty(t, api.PermissionsCreateGroupHandler)
+	require.NotEmpty(t, api.PermissionsUpdateGroupHandler)
+	require.NotEmpty(t, api.PermissionsDeleteGroupHandler)
+	require.NotEmpty(t, api.PermissionsSetGroupPermissionsHandler)
+	require.NotEmpty(t, api.PermissionsAddGroupUsersHandler)
+	require.NotEmpty(t, api.PermissionsRemoveGroupUserHandler)
+}
+
+type GroupTestSuite struct {
+	suite.Suite
+	logger     *zap.Logger
+	repository *mocks.GroupRepository
+	handler    *Group
+	admin      *models.Principal
+}
+
+func TestGroupSuite(t *testing.T) {
+	suite.Run(t, new(GroupTestSuite))
+}
+
+func (s *GroupTestSuite) SetupTest() {
+	s.logger = zap.NewNop()
+	s.repository = &mocks.GroupRepository{}
+	s.handler = NewGroup(s.logger)
+	s.admin = &models.Principal{ID: 1, Role: roles.Admin}
+}
+
+func (s *GroupTestSuite) TestGroup_GetPermissions_NotAdmin() {
+	t := s.T()
+	request := http.Request{}
+
+	handlerFunc := s.handler.GetPermissionsFunc(s.repository)
+	for _, role := range []string{roles.Manager, roles.Operator, roles.User} {
+		resp := handlerFunc.Handle(permissions.GetPermissionsParams{HTTPRequest: &request},
+			&models.Principal{ID: 1, Role: role})
+
+		responseRecorder := httptest.NewRecorder()
+		resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+		require.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	}
+	s.repository.AssertExpectations(t)
+}
+
+func (s *GroupTestSuite) TestGroup_GetPermissions_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("AllPermissions", ctx).Return([]*ent.Permission{
+		{ID: 1, Name: "orders:approve", Description: "approve orders"},
+	}, nil)
+
+	handlerFunc := s.handler.GetPermissionsFunc(s.repository)
+	resp := handlerFunc.Handle(permissions.GetPermissionsParams{HTTPRequest: &request}, s.admin)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var actual models.ListPermissions
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+	require.Len(t, actual, 1)
+	require.Equal(t, "orders:approve", *actual[0].Name)
+	s.repository.AssertExpectations(t)
+}
+
+func (s *GroupTestSuite) TestGroup_GetGroups_RepoErr() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("AllGroups", ctx).Return(nil, errors.New("test"))
+
+	handlerFunc := s.handler.GetGroupsFunc(s.repository)
+	resp := handlerFunc.Handle(permissions.GetGroupsParams{HTTPRequest: &request}, s.admin)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+	s.repository.AssertExpectations(t)
+}
+
+func (s *GroupTestSuite) TestGroup_GetGroup_NotFound() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("GroupByID", ctx, 1).Return(nil, &ent.NotFoundError{})
+
+	handlerFunc := s.handler.GetGroupFunc(s.repository)
+	resp := handlerFunc.Handle(permissions.GetGroupParams{HTTPRequest: &request, GroupID: 1}, s.admin)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusNotFound, responseRecorder.Code)
+	s.repository.AssertExpectations(t)
+}
+
+func (s *GroupTestSuite) TestGroup_CreateGroup_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	name := "approvers"
+
+	s.repository.On("CreateGroup", ctx, name, "approve orders").Return(&ent.Group{
+		ID: 1, Name: name, Description: "approve orders",
+	}, nil)
+
+	handlerFunc := s.handler.CreateGroupFunc(s.repository)
+	resp := handlerFunc.Handle(permissions.CreateGroupParams{
+		HTTPRequest: &request,
+		Data:        &models.GroupRequest{Name: &name, Description: "approve orders"},
+	}, s.admin)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusCreated, responseRecorder.Code)
+
+	var actual models.Group
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+	require.Equal(t, name, *actual.Name)
+	require.Empty(t, actual.Permissions)
+	s.repository.AssertExpectations(t)
+}
+
+func (s *GroupTestSuite) TestGroup_CreateGroup_Conflict() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	name := "approvers"
+
+	s.repository.On("CreateGroup", ctx, name, "").Return(nil, &ent.ConstraintError{})
+
+	handlerFunc := s.handler.CreateGroupFunc(s.repository)
+	resp := handlerFunc.Handle(permissions.CreateGroupParams{
+		HTTPRequest: &request,
+		Data:        &models.GroupRequest{Name: &name},
+	}, s.admin)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusConflict, responseRecorder.Code)
+	s.repository.AssertExpectations(t)
+}
+
+func (s *GroupTestSuite) TestGroup_DeleteGroup_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("DeleteGroup", ctx, 1).Return(nil)
+
+	handlerFunc := s.handler.DeleteGroupFunc(s.repository)
+	resp := handlerFunc.Handle(permissions.DeleteGroupParams{HTTPRequest: &request, GroupID: 1}, s.admin)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+	s.repository.AssertExpectations(t)
+}
+
+func (s *GroupTestSuite) TestGroup_SetGroupPermissions_UnknownPermission() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	names := []string{"orders:everything"}
+
+	s.repository.On("SetGroupPermissions", ctx, 1, names).Return(nil, domain.ErrUnknownPermission)
+
+	handlerFunc := s.handler.SetGroupPermissionsFunc(s.repository)
+	resp := handlerFunc.Handle(permissions.SetGroupPermissionsParams{
+		HTTPRequest: &request,
+		GroupID:     1,
+		Data:        &models.GroupPermissionsRequest{Permissions: names},
+	}, s.admin)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	s.repository.AssertExpectations(t)
+}
+
+func (s *GroupTestSuite) TestGroup_SetGroupPermissions_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	names := []string{"orders:approve"}
+
+	s.repository.On("SetGroupPermissions", ctx, 1, names).Return(&ent.Group{
+		ID:    1,
+		Name:  "approvers",
+		Edges: ent.GroupEdges{Permissions: []*ent.Permission{{ID: 2, Name: "orders:approve"}}},
+	}, nil)
+
+	handlerFunc := s.handler.SetGroupPermissionsFunc(s.repository)
+	resp := handlerFunc.Handle(permissions.SetGroupPermissionsParams{
+		HTTPRequest: &request,
+		GroupID:     1,
+		Data:        &models.GroupPermissionsRequest{Permissions: names},
+	}, s.admin)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var actual models.Group
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+	require.Equal(t, names, actual.Permissions)
+	s.repository.AssertExpectations(t)
+}
+
+func (s *GroupTestSuite) TestGroup_AddGroupUsers_UnknownUser() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("AddGroupUsers", ctx, 1, []int{5}).Return(nil, domain.ErrUnknownUser)
+
+	handlerFunc := s.handler.AddGroupUsersFunc(s.repository)
+	resp := handlerFunc.Handle(permissions.AddGroupUsersParams{
+		HTTPRequest: &request,
+		GroupID:     1,
+		Data:        &models.GroupUsersRequest{UserIds: []int64{5}},
+	}, s.admin)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	s.repository.AssertExpectations(t)
+}
+
+func (s *GroupTestSuite) TestGroup_RemoveGroupUser_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("RemoveGroupUser", ctx, 1, 5).Return(&ent.Group{ID: 1, Name: "approvers"}, nil)
+
+	handlerFunc := s.handler.RemoveGroupUserFunc(s.repository)
+	resp := handlerFunc.Handle(permissions.RemoveGroupUserParams{
+		HTTPRequest: &request,
+		GroupID:     1,
+		UserID:      5,
+	}, s.admin)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+	s.repository.AssertExpectations(t)
+}
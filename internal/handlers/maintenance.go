@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/maintenance"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/repositories"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+func SetMaintenanceHandler(logger *zap.Logger, api *operations.BeAPI) {
+	maintenancePlanRepo := repositories.NewMaintenancePlanRepository()
+	maintenanceHandler := NewMaintenance(logger)
+
+	api.MaintenanceListMaintenancePlansHandler = maintenanceHandler.ListMaintenancePlansFunc(maintenancePlanRepo)
+	api.MaintenanceCreateMaintenancePlanHandler = maintenanceHandler.CreateMaintenancePlanFunc(maintenancePlanRepo)
+	api.MaintenanceDeleteMaintenancePlanHandler = maintenanceHandler.DeleteMaintenancePlanFunc(maintenancePlanRepo)
+	api.MaintenanceListUpcomingMaintenanceHandler = maintenanceHandler.ListUpcomingMaintenanceFunc(
+		maintenancePlanRepo)
+}
+
+// Maintenance manages the recurring maintenance plans, the maintenance itself is scheduled periodically.
+type Maintenance struct {
+	logger *zap.Logger
+}
+
+func NewMaintenance(logger *zap.Logger) *Maintenance {
+	return &Maintenance{
+		logger: logger,
+	}
+}
+
+func (m Maintenance) ListMaintenancePlansFunc(
+	repository domain.MaintenancePlanRepository) maintenance.ListMaintenancePlansHandlerFunc {
+	return func(p maintenance.ListMaintenancePlansParams, _ *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		result, err := repository.MaintenancePlans(ctx)
+		if err != nil {
+			m.logger.Error(messages.ErrQueryMaintenancePlans, zap.Error(err))
+			return maintenance.NewListMaintenancePlansDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrQueryMaintenancePlans, ""))
+		}
+		plans := models.MaintenancePlans{}
+		for _, plan := range result {
+			plans = append(plans, mapMaintenancePlan(plan))
+		}
+		return maintenance.NewListMaintenancePlansOK().WithPayload(plans)
+	}
+}
+
+func (m Maintenance) CreateMaintenancePlanFunc(
+	repository domain.MaintenancePlanRepository) maintenance.CreateMaintenancePlanHandlerFunc {
+	return func(p maintenance.CreateMaintenancePlanParams, _ *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		if (p.Data.EquipmentID == nil) == (p.Data.CategoryID == nil) {
+			return maintenance.NewCreateMaintenancePlanBadRequest().
+				WithPayload(buildBadRequestErrorPayload(messages.ErrMaintenancePlanTarget, ""))
+		}
+		if p.Data.EveryRentals == nil && p.Data.EveryDays == nil {
+			return maintenance.NewCreateMaintenancePlanBadRequest().
+				WithPayload(buildBadRequestErrorPayload(messages.ErrMaintenancePlanInterval, ""))
+		}
+		result, err := repository.CreateMaintenancePlan(ctx, *p.Data)
+		if err != nil {
+			if ent.IsNotFound(err) {
+				return maintenance.NewCreateMaintenancePlanNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrMaintenanceTargetNotFound, ""))
+			}
+			m.logger.Error(messages.ErrCreateMaintenancePlan, zap.Error(err))
+			return maintenance.NewCreateMaintenancePlanDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrCreateMaintenancePlan, ""))
+		}
+		return maintenance.NewCreateMaintenancePlanCreated().WithPayload(mapMaintenancePlan(result))
+	}
+}
+
+func (m Maintenance) DeleteMaintenancePlanFunc(
+	repository domain.MaintenancePlanRepository) maintenance.DeleteMaintenancePlanHandlerFunc {
+	return func(p maintenance.DeleteMaintenancePlanParams, _ *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		err := repository.DeleteMaintenancePlan(ctx, int(p.PlanID))
+		if err != nil {
+			if ent.IsNotFound(err) {
+				return maintenance.NewDeleteMaintenancePlanNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrMaintenancePlanNotFound, ""))
+			}
+			m.logger.Error(messages.ErrDeleteMaintenancePlan, zap.Error(err))
+			return maintenance.NewDeleteMaintenancePlanDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrDeleteMaintenancePlan, ""))
+		}
+		return maintenance.NewDeleteMaintenancePlanNoContent()
+	}
+}
+
+func (m Maintenance) ListUpcomingMaintenanceFunc(
+	repository domain.MaintenancePlanRepository) maintenance.ListUpcomingMaintenanceHandlerFunc {
+	return func(p maintenance.ListUpcomingMaintenanceParams, _ *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		var equipmentID *int
+		if p.EquipmentID != nil {
+			id := int(*p.EquipmentID)
+			equipmentID = &id
+		}
+		result, err := repository.UpcomingMaintenance(ctx, time.Now(), equipmentID)
+		if err != nil {
+			m.logger.Error(messages.ErrQueryUpcomingMaintenance, zap.Error(err))
+			return maintenance.NewListUpcomingMaintenanceDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrQueryUpcomingMaintenance, ""))
+		}
+		windows := models.MaintenanceWindows{}
+		for _, window := range result {
+			windows = append(windows, mapMaintenanceWindow(window))
+		}
+		return maintenance.NewListUpcomingMaintenanceOK().WithPayload(windows)
+	}
+}
+
+func mapMaintenancePlan(plan *ent.MaintenancePlan) *models.MaintenancePlan {
+	id := int64(plan.ID)
+	durationDays := int64(plan.DurationDays)
+	createdAt := strfmt.DateTime(plan.CreatedAt)
+	result := &models.MaintenancePlan{
+		ID:           &id,
+		Name:         &plan.Name,
+		DurationDays: &durationDays,
+		CreatedAt:    &createdAt,
+	}
+	if plan.EveryRentals != nil {
+		everyRentals := int64(*plan.EveryRentals)
+		result.EveryRentals = &everyRentals
+	}
+	if plan.EveryDays != nil {
+		everyDays := int64(*plan.EveryDays)
+		result.EveryDays = &everyDays
+	}
+	if plan.Edges.Equipment != nil {
+		equipmentID := int64(plan.Edges.Equipment.ID)
+		result.EquipmentID = &equipmentID
+	}
+	if plan.Edges.Category != nil {
+		categoryID := int64(plan.Edges.Category.ID)
+		result.CategoryID = &categoryID
+	}
+	return result
+}
+
+func mapMaintenanceWindow(window *ent.MaintenanceWindow) *models.MaintenanceWindow {
+	id := int64(window.ID)
+	startDate := strfmt.DateTime(window.StartDate)
+	endDate := strfmt.DateTime(window.EndDate)
+	result := &models.MaintenanceWindow{
+		ID:        &id,
+		StartDate: &startDate,
+		EndDate:   &endDate,
+	}
+	if window.Edges.Plan != nil {
+		planID := int64(window.Edges.Plan.ID)
+		result.PlanID = &planID
+		result.PlanName = &window.Edges.Plan.Name
+	}
+	if window.Edges.Equipment != nil {
+		equipmentID := int64(window.Edges.Equipment.ID)
+		result.EquipmentID = &equipmentID
+		result.EquipmentName = &window.Edges.Equipment.Name
+	}
+	return result
+}
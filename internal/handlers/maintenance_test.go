@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/runtime"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/mocks"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/maintenance"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
+)
+
+func TestSetMaintenanceHandler(t *testing.T) {
+	logger := zap.NewNop()
+
+	swaggerSpec, err := loads.Analyzed(restapi.SwaggerJSON, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := operations.NewBeAPI(swaggerSpec)
+	SetMaintenanceHandler(logger, api)
+	require.NotEmpty(t, api.MaintenanceListMaintenancePlansHandler)
+	require.NotEmpty(t, api.MaintenanceCreateMaintenancePlanHandler)
+	require.NotEmpty(t, api.MaintenanceDeleteMaintenancePlanHandler)
+	require.NotEmpty(t, api.MaintenanceListUpcomingMaintenanceHandler)
+}
+
+type MaintenanceTestSuite struct {
+	suite.Suite
+	logger     *zap.Logger
+	repository *mocks.MaintenancePlanRepository
+	handler    *Maintenance
+	operator   *models.Principal
+}
+
+func TestMaintenanceSuite(t *testing.T) {
+	suite.Run(t, new(MaintenanceTestSuite))
+}
+
+func (s *MaintenanceTestSuite) SetupTest() {
+	s.logger = zap.NewNop()
+	s.repository = &mocks.MaintenancePlanRepository{}
+	s.handler = NewMaintenance(s.logger)
+	s.operator = &models.Principal{ID: 1, Role: roles.Operator}
+}
+
+func (s *MaintenanceTestSuite) TearDownTest() {
+	s.repository.AssertExpectations(s.T())
+}
+
+func testMaintenancePlan(id int) *ent.MaintenancePlan {
+	everyRentals := 10
+	return &ent.MaintenancePlan{
+		ID:           id,
+		Name:         "service",
+		EveryRentals: &everyRentals,
+		DurationDays: 2,
+		CreatedAt:    time.Now(),
+		Edges: ent.MaintenancePlanEdges{
+			Category: &ent.Category{ID: 3},
+		},
+	}
+}
+
+func (s *MaintenanceTestSuite) TestMaintenance_Create_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	name := "service"
+	categoryID := int64(3)
+	everyRentals := int64(10)
+	data := models.NewMaintenancePlan{Name: &name, CategoryID: &categoryID, EveryRentals: &everyRentals}
+
+	s.repository.On("CreateMaintenancePlan", ctx, data).Return(testMaintenancePlan(1), nil)
+
+	handlerFunc := s.handler.CreateMaintenancePlanFunc(s.repository)
+	resp := handlerFunc.Handle(maintenance.CreateMaintenancePlanParams{HTTPRequest: &request, Data: &data}, s.operator)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusCreated, responseRecorder.Code)
+
+	var actual models.MaintenancePlan
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+	require.Equal(t, int64(1), *actual.ID)
+	require.Equal(t, "service", *actual.Name)
+	require.Equal(t, int64(3), *actual.CategoryID)
+	require.Nil(t, actual.EquipmentID)
+	require.Equal(t, int64(10), *actual.EveryRentals)
+	require.Nil(t, actual.EveryDays)
+	require.Equal(t, int64(2), *actual.DurationDays)
+}
+
+func (s *MaintenanceTestSuite) TestMaintenance_Create_BadRequest() {
+	t := s.T()
+	request := http.Request{}
+	name := "service"
+	id := int64(3)
+	everyDays := int64(30)
+
+	tests := []struct {
+		data    models.NewMaintenancePlan
+		message string
+	}{
+		{models.NewMaintenancePlan{Name: &name, EveryDays: &everyDays}, messages.ErrMaintenancePlanTarget},
+		{models.NewMaintenancePlan{Name: &name, EquipmentID: &id, CategoryID: &id, EveryDays: &everyDays},
+			messages.ErrMaintenancePlanTarget},
+		{models.NewMaintenancePlan{Name: &name, EquipmentID: &id}, messages.ErrMaintenancePlanInterval},
+	}
+	handlerFunc := s.handler.CreateMaintenancePlanFunc(s.repository)
+	for _, tc := range tests {
+		data := tc.data
+		resp := handlerFunc.Handle(maintenance.CreateMaintenancePlanParams{HTTPRequest: &request, Data: &data}, s.operator)
+
+		responseRecorder := httptest.NewRecorder()
+		resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+		require.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+
+		var response models.SwaggerError
+		require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &response))
+		require.Equal(t, tc.message, *response.Message)
+	}
+}
+
+func (s *MaintenanceTestSuite) TestMaintenance_Create_TargetNotFound() {
+	t := s.T()
+	request := http.Request{}
+	name := "service"
+	equipmentID := int64(3)
+	everyDays := int64(30)
+	data := models.NewMaintenancePlan{Name: &name, EquipmentID: &equipmentID, EveryDays: &everyDays}
+
+	s.repository.On("CreateMaintenancePlan", mock.Anything, data).Return(nil, &ent.NotFoundError{})
+
+	handlerFunc := s.handler.CreateMaintenancePlanFunc(s.repository)
+	resp := handlerFunc.Handle(maintenance.CreateMaintenancePlanParams{HTTPRequest: &request, Data: &data}, s.operator)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusNotFound, responseRecorder.Code)
+
+	var response models.SwaggerError
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &response))
+	require.Equal(t, messages.ErrMaintenanceTargetNotFound, *response.Message)
+}
+
+func (s *MaintenanceTestSuite) TestMaintenance_List_RepoErr() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("MaintenancePlans", ctx).Return(nil, errors.New("error"))
+
+	handlerFunc := s.handler.ListMaintenancePlansFunc(s.repository)
+	resp := handlerFunc.Handle(maintenance.ListMaintenancePlansParams{HTTPRequest: &request}, s.operator)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+}
+
+func (s *MaintenanceTestSuite) TestMaintenance_Delete() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("DeleteMaintenancePlan", ctx, 1).Return(nil)
+	s.repository.On("DeleteMaintenancePlan", ctx, 2).Return(&ent.NotFoundError{})
+
+	handlerFunc := s.handler.DeleteMaintenancePlanFunc(s.repository)
+	resp := handlerFunc.Handle(maintenance.DeleteMaintenancePlanParams{HTTPRequest: &request, PlanID: 1}, s.operator)
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusNoContent, responseRecorder.Code)
+
+	resp = handlerFunc.Handle(maintenance.DeleteMaintenancePlanParams{HTTPRequest: &request, PlanID: 2}, s.operator)
+	responseRecorder = httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusNotFound, responseRecorder.Code)
+}
+
+func (s *MaintenanceTestSuite) TestMaintenance_ListUpcoming_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	equipmentID := int64(4)
+	start := time.Now().Add(24 * time.Hour)
+
+	s.repository.On("UpcomingMaintenance", ctx, mock.AnythingOfType("time.Time"), mock.MatchedBy(func(id *int) bool {
+		return id != nil && *id == 4
+	})).Return([]*ent.MaintenanceWindow{{
+		ID:        5,
+		StartDate: start,
+		EndDate:   start.Add(48 * time.Hour),
+		Edges: ent.MaintenanceWindowEdges{
+			Plan:      testMaintenancePlan(1),
+			Equipment: &ent.Equipment{ID: 4, Name: "tent"},
+		},
+	}}, nil)
+
+	handlerFunc := s.handler.ListUpcomingMaintenanceFunc(s.repository)
+	resp := handlerFunc.Handle(maintenance.ListUpcomingMaintenanceParams{
+		HTTPRequest: &request, EquipmentID: &equipmentID}, s.operator)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var actual models.MaintenanceWindows
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+	require.Len(t, actual, 1)
+	require.Equal(t, int64(5), *actual[0].ID)
+	require.Equal(t, int64(1), *actual[0].PlanID)
+	require.Equal(t, "service", *actual[0].PlanName)
+	require.Equal(t, int64(4), *actual[0].EquipmentID)
+	require.Equal(t, "tent", *actual[0].EquipmentName)
+}
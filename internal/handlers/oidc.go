@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/users"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+func SetOIDCHandler(logger *zap.Logger, api *operations.BeAPI, oidcService domain.OIDCService,
+	tokenManager domain.TokenManager, twoFactorService domain.TwoFactorService) {
+	oidcHandler := NewOIDC(logger, oidcService)
+
+	api.UsersStartOIDCLoginHandler = oidcHandler.StartLoginFunc()
+	api.UsersFinishOIDCLoginHandler = oidcHandler.FinishLoginFunc(tokenManager, twoFactorService)
+}
+
+type OIDC struct {
+	logger *zap.Logger
+	oidc   domain.OIDCService
+}
+
+func NewOIDC(logger *zap.Logger, oidcService domain.OIDCService) *OIDC {
+	return &OIDC{
+		logger: logger,
+		oidc:   oidcService,
+	}
+}
+
+func (c OIDC) StartLoginFunc() users.StartOIDCLoginHandlerFunc {
+	return func(p users.StartOIDCLoginParams) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		authorizationURL, err := c.oidc.StartLogin(ctx)
+		if err != nil {
+			c.logger.Error(messages.ErrOIDCStartLogin, zap.Error(err))
+			return users.NewStartOIDCLoginDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrOIDCStartLogin, ""))
+		}
+		return users.NewStartOIDCLoginOK().WithPayload(&models.OIDCAuthorization{
+			AuthorizationURL: &authorizationURL,
+		})
+	}
+}
+
+func (c OIDC) FinishLoginFunc(tokenManager domain.TokenManager,
+	twoFactorService domain.TwoFactorService) users.FinishOIDCLoginHandlerFunc {
+	return func(p users.FinishOIDCLoginParams) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		user, err := c.oidc.Authenticate(ctx, *p.Data.Code, *p.Data.State)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrOIDCLoginFailed):
+				c.logger.Warn(messages.ErrOIDCLogin, zap.Error(err))
+				return users.NewFinishOIDCLoginUnauthorized().
+					WithPayload(buildErrorPayload(http.StatusUnauthorized, messages.ErrOIDCLogin, ""))
+			case errors.Is(err, domain.ErrOIDCAccountNotLinkable):
+				return users.NewFinishOIDCLoginConflict().
+					WithPayload(buildConflictErrorPayload(messages.ErrOIDCAccountNotLinkable, ""))
+			}
+			c.logger.Error(messages.ErrOIDCLogin, zap.Error(err))
+			return users.NewFinishOIDCLoginDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrOIDCLogin, ""))
+		}
+
+		if user.IsTotpEnabled {
+			challengeToken, errChallenge := twoFactorService.IssueChallenge(ctx, user.Login)
+			if errChallenge != nil {
+				c.logger.Error(messages.ErrTwoFactorChallenge, zap.Error(errChallenge))
+				return users.NewFinishOIDCLoginDefault(http.StatusInternalServerError).
+					WithPayload(buildInternalErrorPayload(messages.ErrTwoFactorChallenge, ""))
+			}
+			return users.NewFinishOIDCLoginAccepted().WithPayload(&models.TwoFactorChallenge{
+				ChallengeToken: &challengeToken,
+			})
+		}
+
+		accessToken, refreshToken, isInternalErr, err := tokenManager.GenerateTokensByUserID(ctx, user.ID)
+		if err != nil {
+			if isInternalErr {
+				c.logger.Error("error while generating tokens", zap.Error(err))
+				return users.NewFinishOIDCLoginDefault(http.StatusInternalServerError).
+					WithPayload(buildInternalErrorPayload(messages.ErrOIDCLogin, ""))
+			}
+			return users.NewFinishOIDCLoginUnauthorized().
+				WithPayload(buildErrorPayload(http.StatusUnauthorized, messages.ErrOIDCLogin, ""))
+		}
+
+		return users.NewFinishOIDCLoginOK().WithPayload(&models.TokenPair{
+			AccessToken:  &accessToken,
+			RefreshToken: &refreshToken,
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/runtime"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/mocks"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/users"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+func TestSetOIDCHandler(t *testing.T) {
+	logger := zap.NewNop()
+	swaggerSpec, err := loads.Analyzed(restapi.SwaggerJSON, "")
+	require.NoError(t, err)
+	api := operations.NewBeAPI(swaggerSpec)
+	SetOIDCHandler(logger, api, &mocks.OIDCService{}, &mocks.TokenManager{}, &mocks.TwoFactorService{})
+
+	require.NotEmpty(t, api.UsersStartOIDCLoginHandler)
+	require.NotEmpty(t, api.UsersFinishOIDCLoginHandler)
+}
+
+type OIDCTestSuite struct {
+	suite.Suite
+	oidcService      *mocks.OIDCService
+	tokenManager     *mocks.TokenManager
+	twoFactorService *mocks.TwoFactorService
+	handler          *OIDC
+}
+
+func TestOIDCSuite(t *testing.T) {
+	suite.Run(t, new(OIDCTestSuite))
+}
+
+func (s *OIDCTestSuite) SetupTest() {
+	s.oidcService = &mocks.OIDCService{}
+	s.tokenManager = &mocks.TokenManager{}
+	s.twoFactorService = &mocks.TwoFactorService{}
+	s.handler = NewOIDC(zap.NewNop(), s.oidcService)
+}
+
+func (s *OIDCTestSuite) finishLogin(request *http.Request) *httptest.ResponseRecorder {
+	code, state := "code", "state"
+	resp := s.handler.FinishLoginFunc(s.tokenManager, s.twoFactorService)(users.FinishOIDCLoginParams{
+		HTTPRequest: request,
+		Data:        &models.OIDCCallbackRequest{Code: &code, State: &state},
+	})
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	return responseRecorder
+}
+
+func (s *OIDCTestSuite) TestOIDC_StartLogin_Err() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	s.oidcService.On("StartLogin", ctx).Return("", errors.New("error"))
+
+	resp := s.handler.StartLoginFunc()(users.StartOIDCLoginParams{HTTPRequest: &request})
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+	s.oidcService.AssertExpectations(t)
+}
+
+func (s *OIDCTestSuite) TestOIDC_StartLogin_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	s.oidcService.On("StartLogin", ctx).Return("https://idp.example.com/authorize", nil)
+
+	resp := s.handler.StartLoginFunc()(users.StartOIDCLoginParams{HTTPRequest: &request})
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var authorization models.OIDCAuthorization
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &authorization))
+	require.Equal(t, "https://idp.example.com/authorize", *authorization.AuthorizationURL)
+	s.oidcService.AssertExpectations(t)
+}
+
+func (s *OIDCTestSuite) TestOIDC_FinishLogin_Errors() {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{
+			name: "login failed",
+			err:  fmt.Errorf("%w: unknown state", domain.ErrOIDCLoginFailed),
+			code: http.StatusUnauthorized,
+		},
+		{
+			name: "account not linkable",
+			err:  domain.ErrOIDCAccountNotLinkable,
+			code: http.StatusConflict,
+		},
+		{
+			name: "internal error",
+			err:  errors.New("error"),
+			code: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.SetupTest()
+			t := s.T()
+			request := http.Request{}
+			ctx := request.Context()
+			s.oidcService.On("Authenticate", ctx, "code", "state").Return(nil, tt.err)
+
+			responseRecorder := s.finishLogin(&request)
+			require.Equal(t, tt.code, responseRecorder.Code)
+			s.tokenManager.AssertNotCalled(t, "GenerateTokensByUserID")
+		})
+	}
+}
+
+func (s *OIDCTestSuite) TestOIDC_FinishLogin_TwoFactorRequired() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	s.oidcService.On("Authenticate", ctx, "code", "state").
+		Return(&ent.User{ID: 1, Login: "login", IsTotpEnabled: true}, nil)
+	s.twoFactorService.On("IssueChallenge", ctx, "login").Return("challenge", nil)
+
+	responseRecorder := s.finishLogin(&request)
+	require.Equal(t, http.StatusAccepted, responseRecorder.Code)
+
+	var challenge models.TwoFactorChallenge
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &challenge))
+	require.Equal(t, "challenge", *challenge.ChallengeToken)
+	s.tokenManager.AssertNotCalled(t, "GenerateTokensByUserID")
+}
+
+func (s *OIDCTestSuite) TestOIDC_FinishLogin_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	s.oidcService.On("Authenticate", ctx, "code", "state").Return(&ent.User{ID: 1, Login: "login"}, nil)
+	s.tokenManager.On("GenerateTokensByUserID", ctx, 1).Return("access", "refresh", false, nil)
+
+	responseRecorder := s.finishLogin(&request)
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var tokenPair models.TokenPair
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &tokenPair))
+	require.Equal(t, "access", *tokenPair.AccessToken)
+	require.Equal(t, "refresh", *tokenPair.RefreshToken)
+	s.oidcService.AssertExpectations(t)
+	s.tokenManager.AssertExpectations(t)
+}
@@ -17,6 +17,7 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/orders"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/policies"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/repositories"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
@@ -26,10 +27,12 @@ func SetOrderHandler(logger *zap.Logger, api *operations.BeAPI) {
 	orderRepo := repositories.NewOrderRepository()
 	eqStatusRepo := repositories.NewEquipmentStatusRepository()
 	equipmentRepo := repositories.NewEquipmentRepository()
+	organizationRepo := repositories.NewOrganizationRepository()
 	ordersHandler := NewOrder(logger)
 
 	api.OrdersGetUserOrdersHandler = ordersHandler.ListUserOrdersFunc(orderRepo)
-	api.OrdersCreateOrderHandler = ordersHandler.CreateOrderFunc(orderRepo, eqStatusRepo, equipmentRepo)
+	api.OrdersCreateOrderHandler = ordersHandler.CreateOrderFunc(orderRepo, eqStatusRepo, equipmentRepo,
+		organizationRepo)
 	api.OrdersUpdateOrderHandler = ordersHandler.UpdateOrderFunc(orderRepo)
 	api.OrdersGetAllOrdersHandler = ordersHandler.ListAllOrdersFunc(orderRepo)
 }
@@ -73,10 +76,7 @@ func mapUserOrder(o *ent.Order, log *zap.Logger) (*models.UserOrder, error) {
 		if eq.Edges.CurrentStatus != nil {
 			statusId = int64(eq.Edges.CurrentStatus.ID)
 		}
-		var photoID string
-		if eq.Edges.Photo != nil {
-			photoID = eq.Edges.Photo.ID
-		}
+		photoID := coverPhotoID(eq)
 
 		var psID int64
 		eqID := int64(eq.ID)
@@ -128,6 +128,12 @@ func mapUserOrder(o *ent.Order, log *zap.Logger) (*models.UserOrder, error) {
 	ownerId := int64(owner.ID)
 	ownerName := owner.Login
 
+	var organizationID *int64
+	if o.Edges.Organization != nil {
+		id := int64(o.Edges.Organization.ID)
+		organizationID = &id
+	}
+
 	var statusToOrder *models.OrderStatus
 	allStatuses := o.Edges.OrderStatus
 	if len(allStatuses) != 0 {
@@ -156,8 +162,9 @@ func mapUserOrder(o *ent.Order, log *zap.Logger) (*models.UserOrder, error) {
 			ID:   &ownerId,
 			Name: &ownerName,
 		},
-		LastStatus: statusToOrder,
-		IsFirst:    &o.IsFirst,
+		LastStatus:     statusToOrder,
+		IsFirst:        &o.IsFirst,
+		OrganizationID: organizationID,
 	}, nil
 }
 
@@ -175,7 +182,8 @@ func mapUserOrdersToResponse(entOrders []*ent.Order, log *zap.Logger) ([]*models
 	return modelOrders, nil
 }
 
-func mapOrdersToResponse(entOrders []*ent.Order, log *zap.Logger) ([]*models.Order, error) {
+func mapOrdersToResponse(entOrders []*ent.Order, principal *models.Principal,
+	log *zap.Logger) ([]*models.Order, error) {
 	modelOrders := make([]*models.Order, len(entOrders))
 	for i, o := range entOrders {
 		uo, err := mapUserOrder(o, log)
@@ -184,6 +192,7 @@ func mapOrdersToResponse(entOrders []*ent.Order, log *zap.Logger) ([]*models.Ord
 			return nil, err
 		}
 		user := mapUserInfoWoRole(o.Edges.Users)
+		maskPassport(principal, user)
 		mo := &models.Order{
 			Description: uo.Description,
 			Equipments:  uo.Equipments,
@@ -259,7 +268,7 @@ func (o Order) ListUserOrdersFunc(repository domain.OrderRepository) orders.GetU
 }
 
 func (o Order) ListAllOrdersFunc(repository domain.OrderRepository) orders.GetAllOrdersHandlerFunc {
-	return func(p orders.GetAllOrdersParams, _ *models.Principal) middleware.Responder {
+	return func(p orders.GetAllOrdersParams, principal *models.Principal) middleware.Responder {
 		ctx := p.HTTPRequest.Context()
 		limit := utils.GetValueByPointerOrDefaultValue(p.Limit, math.MaxInt)
 		offset := utils.GetValueByPointerOrDefaultValue(p.Offset, 0)
@@ -306,7 +315,7 @@ func (o Order) ListAllOrdersFunc(repository domain.OrderRepository) orders.GetAl
 			}
 		}
 
-		mappedOrders, err := mapOrdersToResponse(items, o.logger)
+		mappedOrders, err := mapOrdersToResponse(items, principal, o.logger)
 		if err != nil {
 			o.logger.Error(messages.ErrMapOrder, zap.Error(err))
 			return orders.NewGetAllOrdersDefault(http.StatusInternalServerError).
@@ -325,11 +334,27 @@ func (o Order) CreateOrderFunc(
 	orderRepo domain.OrderRepository,
 	eqStatusRepo domain.EquipmentStatusRepository,
 	equipmentRepo domain.EquipmentRepository,
+	organizationRepo domain.OrganizationRepository,
 ) orders.CreateOrderHandlerFunc {
 	return func(p orders.CreateOrderParams, principal *models.Principal) middleware.Responder {
 		ctx := p.HTTPRequest.Context()
 		userID := int(principal.ID)
 
+		resource, err := withMemberRole(ctx, organizationRepo, principal, policies.Resource{
+			Kind:           policies.Order,
+			OwnerID:        userID,
+			OrganizationID: int(p.Data.OrganizationID),
+		})
+		if err != nil {
+			o.logger.Error(messages.ErrGetOrganization, zap.Error(err))
+			return orders.NewCreateOrderDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrGetOrganization, ""))
+		}
+		if !policies.Can(principal, policies.Create, resource) {
+			return orders.NewCreateOrderDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrCreateOrderForbidden, ""))
+		}
+
 		id := int(*p.Data.EquipmentID)
 		isEquipmentAvailable, err := eqStatusRepo.HasStatusByPeriod(ctx, domain.EquipmentStatusAvailable, id,
 			time.Time(*p.Data.RentStart), time.Time(*p.Data.RentEnd))
@@ -397,6 +422,22 @@ func (o Order) UpdateOrderFunc(repository domain.OrderRepository) orders.UpdateO
 		userID := int(principal.ID)
 		orderID := int(p.OrderID)
 
+		existingOrder, err := repository.Get(ctx, orderID)
+		if err != nil {
+			if ent.IsNotFound(err) {
+				return orders.NewUpdateOrderDefault(http.StatusNotFound).
+					WithPayload(buildNotFoundErrorPayload(messages.ErrOrderNotFound, ""))
+			}
+			o.logger.Error(messages.ErrUpdateOrder, zap.Error(err))
+			return orders.NewUpdateOrderDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrUpdateOrder, err.Error()))
+		}
+		if !policies.Can(principal, policies.Update, orderResource(existingOrder)) {
+			o.logger.Warn(messages.ErrUpdateOrderForbidden, zap.Any("principal", principal), zap.Int("order_id", orderID))
+			return orders.NewUpdateOrderDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrUpdateOrderForbidden, ""))
+		}
+
 		order, err := repository.Update(ctx, orderID, p.Data, userID)
 		if err != nil {
 			o.logger.Error(messages.ErrUpdateOrder, zap.Error(err))
@@ -414,3 +455,16 @@ func (o Order) UpdateOrderFunc(repository domain.OrderRepository) orders.UpdateO
 		return orders.NewUpdateOrderOK().WithPayload(mappedOrder)
 	}
 }
+
+// orderResource describes the order for the policies, the order must be loaded with its owner
+// and organization.
+func orderResource(entOrder *ent.Order) policies.Resource {
+	resource := policies.Resource{Kind: policies.Order}
+	if entOrder != nil && entOrder.Edges.Users != nil {
+		resource.OwnerID = entOrder.Edges.Users.ID
+	}
+	if entOrder != nil && entOrder.Edges.Organization != nil {
+		resource.OrganizationID = entOrder.Edges.Organization.ID
+	}
+	return resource
+}
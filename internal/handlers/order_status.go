@@ -18,6 +18,8 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/orders"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/permissions"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/policies"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/repositories"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
@@ -29,12 +31,14 @@ func SetOrderStatusHandler(logger *zap.Logger, api *operations.BeAPI) (domain.Or
 	orderFilterRepo := repositories.NewOrderFilter()
 	orderStatusNameRepo := repositories.NewOrderStatusNameRepository()
 	equipmentStatusRepo := repositories.NewEquipmentStatusRepository()
+	organizationRepo := repositories.NewOrganizationRepository()
 	orderStatusHandler := NewOrderStatus(logger)
 
 	api.OrdersGetOrdersByStatusHandler = orderStatusHandler.GetOrdersByStatus(orderFilterRepo)
 	api.OrdersGetOrdersByDateAndStatusHandler = orderStatusHandler.GetOrdersByPeriodAndStatus(orderFilterRepo)
 	api.OrdersAddNewOrderStatusHandler = orderStatusHandler.AddNewStatusToOrder(orderStatusRepo, equipmentStatusRepo)
-	api.OrdersGetFullOrderHistoryHandler = orderStatusHandler.OrderStatusesHistory(orderStatusRepo)
+	api.OrdersGetFullOrderHistoryHandler = orderStatusHandler.OrderStatusesHistory(orderStatusRepo,
+		organizationRepo)
 	api.OrdersGetAllStatusNamesHandler = orderStatusHandler.GetAllStatusNames(orderStatusNameRepo)
 	return orderStatusRepo, orderFilterRepo, equipmentStatusRepo
 }
@@ -49,12 +53,11 @@ func NewOrderStatus(logger *zap.Logger) *OrderStatus {
 	}
 }
 
-func (h *OrderStatus) OrderStatusesHistory(repository domain.OrderStatusRepository) orders.GetFullOrderHistoryHandlerFunc {
+func (h *OrderStatus) OrderStatusesHistory(repository domain.OrderStatusRepository,
+	organizationRepo domain.OrganizationRepository) orders.GetFullOrderHistoryHandlerFunc {
 	return func(p orders.GetFullOrderHistoryParams, principal *models.Principal) middleware.Responder {
 		h.logger.Info("ListOrderStatus begin")
 		ctx := p.HTTPRequest.Context()
-		userID := int(principal.ID)
-		role := principal.Role
 		orderID := int(p.OrderID)
 
 		history, err := repository.StatusHistory(ctx, orderID)
@@ -64,7 +67,13 @@ func (h *OrderStatus) OrderStatusesHistory(repository domain.OrderStatusReposito
 				WithPayload(buildInternalErrorPayload(messages.ErrQueryOrderHistory, err.Error()))
 		}
 
-		if !canUserAccessOrderHistory(userID, role, history) {
+		resource, err := withMemberRole(ctx, organizationRepo, principal, orderStatusResource(history))
+		if err != nil {
+			h.logger.Error("ListOrderStatus error", zap.Error(err))
+			return orders.NewGetFullOrderHistoryDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrQueryOrderHistory, err.Error()))
+		}
+		if !policies.Can(principal, policies.View, resource) {
 			h.logger.Warn("User have no right to get order history", zap.Any("principal", principal))
 			return orders.NewGetFullOrderHistoryDefault(http.StatusForbidden).
 				WithPayload(buildForbiddenErrorPayload(messages.ErrQueryOrderHistoryForbidden, ""))
@@ -123,29 +132,33 @@ func MapStatus(orderID int64, status *ent.OrderStatus) (*models.OrderStatus, err
 		ID:   &userID,
 		Name: &userName,
 	}
+	photoIDs := make([]string, len(status.Edges.Photos))
+	for i, p := range status.Edges.Photos {
+		photoIDs[i] = p.ID
+	}
 	tmpStatus := models.OrderStatus{
-		ChangedBy: &user,
-		Comment:   &status.Comment,
-		CreatedAt: &createdAt,
-		ID:        &statusID,
-		Status:    &statusName,
-		OrderID:   &orderID,
+		ChangedBy:     &user,
+		Comment:       &status.Comment,
+		ConditionNote: status.ConditionNote,
+		CreatedAt:     &createdAt,
+		ID:            &statusID,
+		PhotoIds:      photoIDs,
+		Status:        &statusName,
+		OrderID:       &orderID,
 	}
 	return &tmpStatus, nil
 }
 
-func canUserAccessOrderHistory(userID int, role string, history []*ent.OrderStatus) bool {
-	if role == roles.Admin || role == roles.Manager || role == roles.Operator {
-		return true
+// orderStatusResource describes the statuses of one order for the policies, the order of the statuses
+// must be loaded with its owner and organization. The empty history has no owner.
+func orderStatusResource(history []*ent.OrderStatus) policies.Resource {
+	resource := policies.Resource{Kind: policies.OrderStatus}
+	if len(history) > 0 && history[0] != nil {
+		orderOwner := orderResource(history[0].Edges.Order)
+		resource.OwnerID = orderOwner.OwnerID
+		resource.OrganizationID = orderOwner.OrganizationID
 	}
-
-	for _, status := range history {
-		if status.Edges.Users != nil && status.Edges.Users.ID == userID {
-			return true
-		}
-	}
-
-	return false
+	return resource
 }
 
 func (h *OrderStatus) AddNewStatusToOrder(
@@ -165,6 +178,14 @@ func (h *OrderStatus) AddNewStatusToOrder(
 				WithPayload(buildBadRequestErrorPayload(messages.ErrOrderStatusEmpty, ""))
 		}
 
+		// the photos and the condition note document the equipment when it is handed over and returned
+		hasReport := params.Data.ConditionNote != "" || len(params.Data.PhotoIds) > 0
+		if hasReport && *newOrderStatus != domain.OrderStatusInProgress && *newOrderStatus != domain.OrderStatusClosed {
+			h.logger.Error(messages.ErrOrderStatusReportNotAllowed, zap.String("status", *newOrderStatus))
+			return orders.NewAddNewOrderStatusDefault(http.StatusBadRequest).
+				WithPayload(buildBadRequestErrorPayload(messages.ErrOrderStatusReportNotAllowed, ""))
+		}
+
 		currentOrderStatus, err := orderStatusRepo.GetOrderCurrentStatus(ctx, int(*params.Data.OrderID))
 		if err != nil {
 			h.logger.Error(messages.ErrGetOrderStatus, zap.Error(err))
@@ -172,8 +193,15 @@ func (h *OrderStatus) AddNewStatusToOrder(
 				WithPayload(buildInternalErrorPayload(messages.ErrGetOrderStatus, err.Error()))
 		}
 
+		if !policies.Can(principal, policies.Create, orderStatusResource([]*ent.OrderStatus{currentOrderStatus})) {
+			h.logger.Warn(messages.ErrCreateOrderStatusForbidden, zap.Any("principal", principal))
+			return orders.NewAddNewOrderStatusDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrCreateOrderStatusForbidden, ""))
+		}
+
 		canUserCancelOrder := canUserCancelOrder(userID, currentOrderStatus, *newOrderStatus)
-		canRoleChangeStatus := canRoleChangeStatus(userRole, currentOrderStatus, *newOrderStatus)
+		canRoleChangeStatus := canRoleChangeStatus(userRole, currentOrderStatus, *newOrderStatus) ||
+			canPermissionChangeStatus(principal.Permissions, currentOrderStatus, *newOrderStatus)
 
 		if !canUserCancelOrder && !canRoleChangeStatus {
 			h.logger.Error("User does not have the right to create an order status", zap.Any("principal", principal))
@@ -196,6 +224,11 @@ func (h *OrderStatus) AddNewStatusToOrder(
 		}
 
 		err = orderStatusRepo.UpdateStatus(ctx, userID, *params.Data)
+		if errors.Is(err, domain.ErrOrderStatusPhotoNotFound) {
+			h.logger.Error(messages.ErrOrderStatusPhotoNotFound, zap.Error(err))
+			return orders.NewAddNewOrderStatusDefault(http.StatusBadRequest).
+				WithPayload(buildBadRequestErrorPayload(messages.ErrOrderStatusPhotoNotFound, ""))
+		}
 		if err != nil {
 			h.logger.Error(messages.ErrUpdateOrderStatus, zap.Error(err))
 			return orders.NewAddNewOrderStatusDefault(http.StatusInternalServerError).
@@ -432,6 +465,27 @@ func MapOrderStatusName(status *ent.OrderStatusName) (*models.OrderStatusName, e
 	}, nil
 }
 
+// canPermissionChangeStatus checks the permissions granted to the groups of the user,
+// each of them allows only its own step of the order workflow.
+func canPermissionChangeStatus(granted []string, currentStatus *ent.OrderStatus, newStatus string) bool {
+	current := currentStatus.Edges.OrderStatusName.Status
+	switch newStatus {
+	case domain.OrderStatusApproved, domain.OrderStatusRejected:
+		return current == domain.OrderStatusInReview && permissions.Has(granted, permissions.OrdersApprove)
+	case domain.OrderStatusPrepared:
+		return current == domain.OrderStatusApproved && permissions.Has(granted, permissions.OrdersPrepare)
+	case domain.OrderStatusInProgress:
+		return current == domain.OrderStatusPrepared && permissions.Has(granted, permissions.OrdersIssue)
+	case domain.OrderStatusClosed:
+		switch current {
+		case domain.OrderStatusApproved, domain.OrderStatusPrepared, domain.OrderStatusInProgress,
+			domain.OrderStatusOverdue, domain.OrderStatusBlocked:
+			return permissions.Has(granted, permissions.OrdersClose)
+		}
+	}
+	return false
+}
+
 func canRoleChangeStatus(role string, currentStatus *ent.OrderStatus, newStatus string) bool {
 	switch currentStatus.Edges.OrderStatusName.Status {
 	case domain.OrderStatusInReview:
@@ -28,6 +28,7 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/orders"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
 )
@@ -58,6 +59,7 @@ type OrderStatusTestSuite struct {
 	orderStatusRepository     *mocks.OrderStatusRepository
 	orderFilterRepository     *mocks.OrderRepositoryWithFilter
 	equipmentStatusRepository *mocks.EquipmentStatusRepository
+	organizationRepository    *mocks.OrganizationRepository
 	orderStatus               *OrderStatus
 }
 
@@ -125,6 +127,7 @@ func (s *OrderStatusTestSuite) SetupTest() {
 	s.orderStatusRepository = &mocks.OrderStatusRepository{}
 	s.orderFilterRepository = &mocks.OrderRepositoryWithFilter{}
 	s.equipmentStatusRepository = &mocks.EquipmentStatusRepository{}
+	s.organizationRepository = &mocks.OrganizationRepository{}
 	s.orderStatus = NewOrderStatus(s.logger)
 }
 
@@ -202,7 +205,7 @@ func (s *OrderStatusTestSuite) TestOrderStatus_OrderStatusesHistory_RepoErr() {
 	principal := &models.Principal{
 		Role: roles.Admin,
 	}
-	handlerFunc := s.orderStatus.OrderStatusesHistory(s.orderStatusRepository)
+	handlerFunc := s.orderStatus.OrderStatusesHistory(s.orderStatusRepository, s.organizationRepository)
 	orderID := int64(1)
 	data := orders.GetFullOrderHistoryParams{
 		HTTPRequest: &request,
@@ -224,7 +227,7 @@ func (s *OrderStatusTestSuite) TestOrderStatus_OrderStatusesHistory_CantAccess_H
 	request := http.Request{}
 	ctx := request.Context()
 	principal := &models.Principal{Role: roles.User}
-	handlerFunc := s.orderStatus.OrderStatusesHistory(s.orderStatusRepository)
+	handlerFunc := s.orderStatus.OrderStatusesHistory(s.orderStatusRepository, s.organizationRepository)
 	orderID := int64(1)
 	data := orders.GetFullOrderHistoryParams{
 		HTTPRequest: &request,
@@ -241,12 +244,89 @@ func (s *OrderStatusTestSuite) TestOrderStatus_OrderStatusesHistory_CantAccess_H
 	s.orderStatusRepository.AssertExpectations(t)
 }
 
+func (s *OrderStatusTestSuite) TestOrderStatus_OrderStatusesHistory_Owner() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	handlerFunc := s.orderStatus.OrderStatusesHistory(s.orderStatusRepository, s.organizationRepository)
+	orderID := int64(1)
+	ownerID := 5
+	changedByID := 7
+	data := orders.GetFullOrderHistoryParams{
+		HTTPRequest: &request,
+		OrderID:     orderID,
+	}
+	history := []*ent.OrderStatus{{
+		ID: 1,
+		Edges: ent.OrderStatusEdges{
+			Order:           &ent.Order{ID: int(orderID), Edges: ent.OrderEdges{Users: &ent.User{ID: ownerID}}},
+			OrderStatusName: &ent.OrderStatusName{Status: domain.OrderStatusInReview},
+			Users:           &ent.User{ID: changedByID, Login: "changed by"},
+		},
+	}}
+	s.orderStatusRepository.On("StatusHistory", ctx, int(orderID)).Return(history, nil)
+
+	resp := handlerFunc(data, &models.Principal{ID: int64(ownerID), Role: roles.User})
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	// changing the status of the order doesn't make the user its owner
+	resp = handlerFunc(data, &models.Principal{ID: int64(changedByID), Role: roles.User})
+	responseRecorder = httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	s.orderStatusRepository.AssertExpectations(t)
+}
+
+func (s *OrderStatusTestSuite) TestOrderStatus_OrderStatusesHistory_OrganizationMember() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	handlerFunc := s.orderStatus.OrderStatusesHistory(s.orderStatusRepository, s.organizationRepository)
+	orderID := int64(1)
+	organizationID := 3
+	memberID, outsiderID := 7, 8
+	data := orders.GetFullOrderHistoryParams{
+		HTTPRequest: &request,
+		OrderID:     orderID,
+	}
+	history := []*ent.OrderStatus{{
+		ID: 1,
+		Edges: ent.OrderStatusEdges{
+			Order: &ent.Order{ID: int(orderID), Edges: ent.OrderEdges{
+				Users:        &ent.User{ID: 5},
+				Organization: &ent.Organization{ID: organizationID},
+			}},
+			OrderStatusName: &ent.OrderStatusName{Status: domain.OrderStatusInReview},
+			Users:           &ent.User{ID: 5, Login: "owner"},
+		},
+	}}
+	s.orderStatusRepository.On("StatusHistory", ctx, int(orderID)).Return(history, nil)
+	s.organizationRepository.On("MemberRole", ctx, organizationID, memberID).
+		Return(domain.OrganizationRoleMember, nil)
+	s.organizationRepository.On("MemberRole", ctx, organizationID, outsiderID).Return("", nil)
+
+	resp := handlerFunc(data, &models.Principal{ID: int64(memberID), Role: roles.User})
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	resp = handlerFunc(data, &models.Principal{ID: int64(outsiderID), Role: roles.User})
+	responseRecorder = httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	s.organizationRepository.AssertExpectations(t)
+}
+
 func (s *OrderStatusTestSuite) TestOrderStatus_OrderStatusesHistory_EmptyHistory() {
 	t := s.T()
 	request := http.Request{}
 	ctx := request.Context()
 	principal := &models.Principal{Role: roles.Admin}
-	handlerFunc := s.orderStatus.OrderStatusesHistory(s.orderStatusRepository)
+	handlerFunc := s.orderStatus.OrderStatusesHistory(s.orderStatusRepository, s.organizationRepository)
 	orderID := int64(1)
 	data := orders.GetFullOrderHistoryParams{
 		HTTPRequest: &request,
@@ -268,7 +348,7 @@ func (s *OrderStatusTestSuite) TestOrderStatus_OrderStatusesHistory_MapError() {
 	request := http.Request{}
 	ctx := request.Context()
 	principal := &models.Principal{Role: roles.Admin}
-	handlerFunc := s.orderStatus.OrderStatusesHistory(s.orderStatusRepository)
+	handlerFunc := s.orderStatus.OrderStatusesHistory(s.orderStatusRepository, s.organizationRepository)
 	orderID := int64(1)
 	data := orders.GetFullOrderHistoryParams{
 		HTTPRequest: &request,
@@ -293,7 +373,7 @@ func (s *OrderStatusTestSuite) TestOrderStatus_OrderStatusesHistory_OK() {
 	request := http.Request{}
 	ctx := request.Context()
 	principal := &models.Principal{Role: roles.Admin}
-	handlerFunc := s.orderStatus.OrderStatusesHistory(s.orderStatusRepository)
+	handlerFunc := s.orderStatus.OrderStatusesHistory(s.orderStatusRepository, s.organizationRepository)
 	orderID := int64(1)
 	data := orders.GetFullOrderHistoryParams{
 		HTTPRequest: &request,
@@ -303,14 +383,16 @@ func (s *OrderStatusTestSuite) TestOrderStatus_OrderStatusesHistory_OK() {
 	count := 1
 	history := make([]*ent.OrderStatus, count)
 	history[0] = &ent.OrderStatus{
-		ID:          1,
-		Comment:     "comment",
-		CurrentDate: time.Now().UTC(),
+		ID:            1,
+		Comment:       "comment",
+		ConditionNote: "scratch on the lid",
+		CurrentDate:   time.Now().UTC(),
 		Edges: ent.OrderStatusEdges{
 			OrderStatusName: &ent.OrderStatusName{
 				ID:     0,
 				Status: "test status",
 			},
+			Photos: []*ent.Photo{{ID: "photo"}},
 			Users: &ent.User{
 				ID:    0,
 				Login: "test user",
@@ -336,6 +418,8 @@ func (s *OrderStatusTestSuite) TestOrderStatus_OrderStatusesHistory_OK() {
 	require.Equal(t, history[0].Edges.OrderStatusName.Status, *(*response)[0].Status)
 	require.Equal(t, history[0].Edges.Users.Login, *(*response)[0].ChangedBy.Name)
 	require.Equal(t, history[0].Edges.Users.ID, int(*(*response)[0].ChangedBy.ID))
+	require.Equal(t, history[0].ConditionNote, (*response)[0].ConditionNote)
+	require.Equal(t, []string{"photo"}, (*response)[0].PhotoIds)
 	s.orderStatusRepository.AssertExpectations(t)
 }
 
@@ -579,6 +663,94 @@ func (s *OrderStatusTestSuite) TestOrderStatus_AddNewStatusToOrder_OperatorInPro
 	s.orderStatusRepository.AssertExpectations(t)
 }
 
+func (s *OrderStatusTestSuite) TestOrderStatus_AddNewStatusToOrder_ReportNotAllowed() {
+	t := s.T()
+	request := http.Request{}
+	principal := &models.Principal{
+		ID:   1,
+		Role: roles.Operator,
+	}
+	handlerFunc := s.orderStatus.AddNewStatusToOrder(s.orderStatusRepository, s.equipmentStatusRepository)
+	statusComment := "test comment"
+	now := strfmt.DateTime(time.Now())
+	orderID := int64(1)
+	statusID := domain.OrderStatusPrepared
+	data := &models.NewOrderStatus{
+		Comment:   &statusComment,
+		CreatedAt: &now,
+		OrderID:   &orderID,
+		Status:    &statusID,
+		PhotoIds:  []string{"photo"},
+	}
+	params := orders.AddNewOrderStatusParams{
+		HTTPRequest: &request,
+		Data:        data,
+	}
+
+	resp := handlerFunc(params, principal)
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	response := &models.SwaggerError{}
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), response)
+	require.NoError(t, err)
+	require.Equal(t, messages.ErrOrderStatusReportNotAllowed, *response.Message)
+	s.orderStatusRepository.AssertExpectations(t)
+}
+
+func (s *OrderStatusTestSuite) TestOrderStatus_AddNewStatusToOrder_ReportPhotoNotFound() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	userID := 1
+	principal := &models.Principal{
+		ID:   int64(userID),
+		Role: roles.Operator,
+	}
+	handlerFunc := s.orderStatus.AddNewStatusToOrder(s.orderStatusRepository, s.equipmentStatusRepository)
+	statusComment := "test comment"
+	now := strfmt.DateTime(time.Now())
+	orderID := int64(1)
+	statusID := domain.OrderStatusClosed
+	data := &models.NewOrderStatus{
+		Comment:       &statusComment,
+		ConditionNote: "scratch on the lid",
+		CreatedAt:     &now,
+		OrderID:       &orderID,
+		Status:        &statusID,
+		PhotoIds:      []string{"photo"},
+	}
+	params := orders.AddNewOrderStatusParams{
+		HTTPRequest: &request,
+		Data:        data,
+	}
+	existingOrder := orderWithEdges(t, 1)
+	existingOrder.Edges.EquipmentStatus[0].EndDate = time.Time(now)
+	existingOrder.Edges.OrderStatus[0].ID = 3
+	existingOrder.Edges.OrderStatus[0].Edges.OrderStatusName = &ent.OrderStatusName{
+		Status: domain.OrderStatusInProgress,
+	}
+
+	s.orderStatusRepository.On("GetOrderCurrentStatus", ctx, int(*data.OrderID)).
+		Return(existingOrder.Edges.OrderStatus[0], nil)
+	s.equipmentStatusRepository.On("GetEquipmentsStatusesByOrder", ctx,
+		existingOrder.ID).
+		Return(existingOrder.Edges.EquipmentStatus, nil)
+	s.orderStatusRepository.On("UpdateStatus", ctx, userID, *data).Return(domain.ErrOrderStatusPhotoNotFound)
+
+	resp := handlerFunc(params, principal)
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	response := &models.SwaggerError{}
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), response)
+	require.NoError(t, err)
+	require.Equal(t, messages.ErrOrderStatusPhotoNotFound, *response.Message)
+	s.orderStatusRepository.AssertExpectations(t)
+}
+
 func (s *OrderStatusTestSuite) TestOrderStatus_AddNewStatusToOrder_OperatorOverdueToClosedOK() {
 	t := s.T()
 	request := http.Request{}
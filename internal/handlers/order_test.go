@@ -25,6 +25,7 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/orders"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
 )
@@ -89,6 +90,7 @@ type orderTestSuite struct {
 	orderRepository     *mocks.OrderRepository
 	eqStatusRepository  *mocks.EquipmentStatusRepository
 	equipmentRepository *mocks.EquipmentRepository
+	organizationRepo    *mocks.OrganizationRepository
 	orderHandler        *Order
 }
 
@@ -101,6 +103,7 @@ func (s *orderTestSuite) SetupTest() {
 	s.orderRepository = &mocks.OrderRepository{}
 	s.eqStatusRepository = &mocks.EquipmentStatusRepository{}
 	s.equipmentRepository = &mocks.EquipmentRepository{}
+	s.organizationRepo = &mocks.OrganizationRepository{}
 	s.orderHandler = NewOrder(s.logger)
 }
 
@@ -108,6 +111,7 @@ func (s *orderTestSuite) TearDownTest() {
 	s.orderRepository.AssertExpectations(s.T())
 	s.eqStatusRepository.AssertExpectations(s.T())
 	s.equipmentRepository.AssertExpectations(s.T())
+	s.organizationRepo.AssertExpectations(s.T())
 }
 
 func (s *orderTestSuite) TestOrder_ListUserOrders_RepoErr() {
@@ -731,7 +735,8 @@ func (s *orderTestSuite) TestOrder_CreateOrder_RepoErr() {
 	s.eqStatusRepository.On("HasStatusByPeriod", ctx, domain.EquipmentStatusAvailable, id,
 		time.Time(rentStart), time.Time(rentEnd)).Return(false, err)
 
-	handlerFunc := s.orderHandler.CreateOrderFunc(s.orderRepository, s.eqStatusRepository, s.equipmentRepository)
+	handlerFunc := s.orderHandler.CreateOrderFunc(s.orderRepository, s.eqStatusRepository, s.equipmentRepository,
+		s.organizationRepo)
 	data := orders.CreateOrderParams{
 		HTTPRequest: &request,
 		Data:        createOrder,
@@ -784,7 +789,8 @@ func (s *orderTestSuite) TestOrder_CreateOrder_MapErr() {
 		StatusName:  &domain.EquipmentStatusBooked,
 	}).Return(nil, nil)
 
-	handlerFunc := s.orderHandler.CreateOrderFunc(s.orderRepository, s.eqStatusRepository, s.equipmentRepository)
+	handlerFunc := s.orderHandler.CreateOrderFunc(s.orderRepository, s.eqStatusRepository, s.equipmentRepository,
+		s.organizationRepo)
 	data := orders.CreateOrderParams{
 		HTTPRequest: &request,
 		Data:        createOrder,
@@ -820,7 +826,8 @@ func (s *orderTestSuite) TestOrder_CreateOrder_NoAvailableEquipments() {
 	s.eqStatusRepository.On("HasStatusByPeriod", ctx, domain.EquipmentStatusAvailable, equipment.ID,
 		time.Time(rentStart), time.Time(rentEnd)).Return(false, nil)
 
-	handlerFunc := s.orderHandler.CreateOrderFunc(s.orderRepository, s.eqStatusRepository, s.equipmentRepository)
+	handlerFunc := s.orderHandler.CreateOrderFunc(s.orderRepository, s.eqStatusRepository, s.equipmentRepository,
+		s.organizationRepo)
 	data := orders.CreateOrderParams{
 		HTTPRequest: &request,
 		Data:        createOrder,
@@ -878,7 +885,8 @@ func (s *orderTestSuite) TestOrder_CreateOrder_OK() {
 		StatusName:  &domain.EquipmentStatusBooked,
 	}).Return(nil, nil)
 
-	handlerFunc := s.orderHandler.CreateOrderFunc(s.orderRepository, s.eqStatusRepository, s.equipmentRepository)
+	handlerFunc := s.orderHandler.CreateOrderFunc(s.orderRepository, s.eqStatusRepository, s.equipmentRepository,
+		s.organizationRepo)
 	data := orders.CreateOrderParams{
 		HTTPRequest: &request,
 		Data:        createOrder,
@@ -898,6 +906,83 @@ func (s *orderTestSuite) TestOrder_CreateOrder_OK() {
 	require.Equal(t, orderToReturn.ID, int(*responseOrder.ID))
 }
 
+func (s *orderTestSuite) TestOrder_CreateOrder_NotOrganizationMember() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	equipmentID := int64(1)
+	rentStart := strfmt.DateTime(time.Now())
+	rentEnd := strfmt.DateTime(time.Now().Add(time.Hour * 48))
+	createOrder := &models.OrderCreateRequest{
+		EquipmentID:    &equipmentID,
+		RentEnd:        &rentEnd,
+		RentStart:      &rentStart,
+		OrganizationID: 3,
+	}
+	userID := 1
+	s.organizationRepo.On("MemberRole", ctx, 3, userID).Return("", nil)
+
+	handlerFunc := s.orderHandler.CreateOrderFunc(s.orderRepository, s.eqStatusRepository, s.equipmentRepository,
+		s.organizationRepo)
+	data := orders.CreateOrderParams{
+		HTTPRequest: &request,
+		Data:        createOrder,
+	}
+	resp := handlerFunc.Handle(data, &models.Principal{ID: int64(userID), Role: roles.User})
+
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusForbidden, responseRecorder.Code)
+}
+
+func (s *orderTestSuite) TestOrder_UpdateOrder_NotFound() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	orderID := 2
+	s.orderRepository.On("Get", ctx, orderID).Return(nil, &ent.NotFoundError{})
+
+	handlerFunc := s.orderHandler.UpdateOrderFunc(s.orderRepository)
+	data := orders.UpdateOrderParams{
+		HTTPRequest: &request,
+		Data:        &models.OrderUpdateRequest{},
+		OrderID:     int64(orderID),
+	}
+	resp := handlerFunc.Handle(data, &models.Principal{ID: 1})
+
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusNotFound, responseRecorder.Code)
+}
+
+func (s *orderTestSuite) TestOrder_UpdateOrder_Forbidden() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	orderID := 2
+	s.orderRepository.On("Get", ctx, orderID).Return(orderOwnedBy(orderID, 3), nil)
+
+	handlerFunc := s.orderHandler.UpdateOrderFunc(s.orderRepository)
+	data := orders.UpdateOrderParams{
+		HTTPRequest: &request,
+		Data:        &models.OrderUpdateRequest{},
+		OrderID:     int64(orderID),
+	}
+	for _, role := range []string{roles.User, roles.Manager, roles.Admin} {
+		resp := handlerFunc.Handle(data, &models.Principal{ID: 1, Role: role})
+
+		responseRecorder := httptest.NewRecorder()
+		producer := runtime.JSONProducer()
+		resp.WriteResponse(responseRecorder, producer)
+		require.Equal(t, http.StatusForbidden, responseRecorder.Code, role)
+	}
+}
+
 func (s *orderTestSuite) TestOrder_UpdateOrder_RepoErr() {
 	t := s.T()
 	request := http.Request{}
@@ -916,6 +1001,7 @@ func (s *orderTestSuite) TestOrder_UpdateOrder_RepoErr() {
 	userID := 1
 	orderID := 2
 	err := errors.New("error")
+	s.orderRepository.On("Get", ctx, orderID).Return(orderOwnedBy(orderID, userID), nil)
 	s.orderRepository.On("Update", ctx, orderID, createOrder, userID).Return(nil, err)
 
 	handlerFunc := s.orderHandler.UpdateOrderFunc(s.orderRepository)
@@ -951,6 +1037,7 @@ func (s *orderTestSuite) TestOrder_UpdateOrder_MapErr() {
 	userID := 1
 	orderID := 2
 	orderToReturn := orderWithNoEdges()
+	s.orderRepository.On("Get", ctx, orderID).Return(orderOwnedBy(orderID, userID), nil)
 	s.orderRepository.On("Update", ctx, orderID, createOrder, userID).Return(orderToReturn, nil)
 
 	handlerFunc := s.orderHandler.UpdateOrderFunc(s.orderRepository)
@@ -986,6 +1073,7 @@ func (s *orderTestSuite) TestOrder_UpdateOrder_OK() {
 	userID := 1
 	orderID := 2
 	orderToReturn := orderWithAllEdges(t, 1)
+	s.orderRepository.On("Get", ctx, orderID).Return(orderOwnedBy(orderID, userID), nil)
 	s.orderRepository.On("Update", ctx, orderID, createOrder, userID).Return(orderToReturn, nil)
 
 	handlerFunc := s.orderHandler.UpdateOrderFunc(s.orderRepository)
@@ -1038,3 +1126,10 @@ func ordersDuplicated(t *testing.T, array1, array2 []*models.UserOrder) bool {
 	}
 	return false
 }
+
+func orderOwnedBy(orderID, ownerID int) *ent.Order {
+	return &ent.Order{
+		ID:    orderID,
+		Edges: ent.OrderEdges{Users: &ent.User{ID: ownerID}},
+	}
+}
@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/order"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/organizations"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/policies"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/repositories"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+func SetOrganizationHandler(logger *zap.Logger, api *operations.BeAPI) {
+	organizationRepo := repositories.NewOrganizationRepository()
+	orderRepo := repositories.NewOrderRepository()
+	organizationHandler := NewOrganization(logger)
+
+	api.OrganizationsGetUserOrganizationsHandler = organizationHandler.GetUserOrganizationsFunc(organizationRepo)
+	api.OrganizationsCreateOrganizationHandler = organizationHandler.CreateOrganizationFunc(organizationRepo)
+	api.OrganizationsGetOrganizationHandler = organizationHandler.GetOrganizationFunc(organizationRepo)
+	api.OrganizationsSetOrganizationMemberHandler = organizationHandler.SetOrganizationMemberFunc(organizationRepo)
+	api.OrganizationsRemoveOrganizationMemberHandler = organizationHandler.RemoveOrganizationMemberFunc(organizationRepo)
+	api.OrganizationsGetOrganizationOrdersHandler = organizationHandler.GetOrganizationOrdersFunc(organizationRepo,
+		orderRepo)
+}
+
+// Organization lets several users share the orders of one organization. Members see the organization
+// and its orders, owners manage the members.
+type Organization struct {
+	logger *zap.Logger
+}
+
+func NewOrganization(logger *zap.Logger) *Organization {
+	return &Organization{
+		logger: logger,
+	}
+}
+
+func (o Organization) GetUserOrganizationsFunc(
+	repository domain.OrganizationRepository) organizations.GetUserOrganizationsHandlerFunc {
+	return func(p organizations.GetUserOrganizationsParams, principal *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		result, err := repository.UserOrganizations(ctx, int(principal.ID))
+		if err != nil {
+			o.logger.Error(messages.ErrQueryOrganizations, zap.Error(err))
+			return organizations.NewGetUserOrganizationsDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrQueryOrganizations, ""))
+		}
+		listOrganizations := models.ListOrganizations{}
+		for _, element := range result {
+			listOrganizations = append(listOrganizations, mapOrganization(element))
+		}
+		return organizations.NewGetUserOrganizationsOK().WithPayload(listOrganizations)
+	}
+}
+
+func (o Organization) CreateOrganizationFunc(
+	repository domain.OrganizationRepository) organizations.CreateOrganizationHandlerFunc {
+	return func(p organizations.CreateOrganizationParams, principal *models.Principal) middleware.Responder {
+		if !policies.Can(principal, policies.Create, policies.Resource{Kind: policies.Organization}) {
+			return organizations.NewCreateOrganizationDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrCreateOrganization, ""))
+		}
+		ctx := p.HTTPRequest.Context()
+		var website *string
+		if p.Data.Website != "" {
+			website = &p.Data.Website
+		}
+		result, err := repository.CreateOrganization(ctx, *p.Data.Name, website, int(principal.ID))
+		if err != nil {
+			o.logger.Error(messages.ErrCreateOrganization, zap.Error(err))
+			return organizations.NewCreateOrganizationDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrCreateOrganization, ""))
+		}
+		return organizations.NewCreateOrganizationCreated().WithPayload(mapOrganization(result))
+	}
+}
+
+func (o Organization) GetOrganizationFunc(
+	repository domain.OrganizationRepository) organizations.GetOrganizationHandlerFunc {
+	return func(p organizations.GetOrganizationParams, principal *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		result, err := repository.OrganizationByID(ctx, int(p.OrganizationID))
+		if err != nil {
+			if ent.IsNotFound(err) {
+				return organizations.NewGetOrganizationNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrOrganizationNotFound, ""))
+			}
+			o.logger.Error(messages.ErrGetOrganization, zap.Error(err))
+			return organizations.NewGetOrganizationDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrGetOrganization, ""))
+		}
+		if !policies.Can(principal, policies.View, organizationResource(result, principal)) {
+			return organizations.NewGetOrganizationForbidden().
+				WithPayload(buildForbiddenErrorPayload(messages.ErrOrganizationForbidden, ""))
+		}
+		return organizations.NewGetOrganizationOK().WithPayload(mapOrganization(result))
+	}
+}
+
+func (o Organization) SetOrganizationMemberFunc(
+	repository domain.OrganizationRepository) organizations.SetOrganizationMemberHandlerFunc {
+	return func(p organizations.SetOrganizationMemberParams, principal *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		existing, err := repository.OrganizationByID(ctx, int(p.OrganizationID))
+		if err != nil {
+			if ent.IsNotFound(err) {
+				return organizations.NewSetOrganizationMemberNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrOrganizationNotFound, ""))
+			}
+			o.logger.Error(messages.ErrSetOrganizationMember, zap.Error(err))
+			return organizations.NewSetOrganizationMemberDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrSetOrganizationMember, ""))
+		}
+		if !policies.Can(principal, policies.Update, organizationResource(existing, principal)) {
+			return organizations.NewSetOrganizationMemberForbidden().
+				WithPayload(buildForbiddenErrorPayload(messages.ErrOrganizationManage, ""))
+		}
+		result, err := repository.SetMember(ctx, existing.ID, int(p.UserID), *p.Data.Role)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrUnknownUser):
+				return organizations.NewSetOrganizationMemberBadRequest().
+					WithPayload(buildBadRequestErrorPayload(messages.ErrUnknownUser, ""))
+			case errors.Is(err, domain.ErrLastOrganizationOwner):
+				return organizations.NewSetOrganizationMemberConflict().
+					WithPayload(buildConflictErrorPayload(messages.ErrLastOrganizationOwner, ""))
+			case ent.IsNotFound(err):
+				return organizations.NewSetOrganizationMemberNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrOrganizationNotFound, ""))
+			}
+			o.logger.Error(messages.ErrSetOrganizationMember, zap.Error(err))
+			return organizations.NewSetOrganizationMemberDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrSetOrganizationMember, ""))
+		}
+		return organizations.NewSetOrganizationMemberOK().WithPayload(mapOrganization(result))
+	}
+}
+
+func (o Organization) RemoveOrganizationMemberFunc(
+	repository domain.OrganizationRepository) organizations.RemoveOrganizationMemberHandlerFunc {
+	return func(p organizations.RemoveOrganizationMemberParams, principal *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		existing, err := repository.OrganizationByID(ctx, int(p.OrganizationID))
+		if err != nil {
+			if ent.IsNotFound(err) {
+				return organizations.NewRemoveOrganizationMemberNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrOrganizationNotFound, ""))
+			}
+			o.logger.Error(messages.ErrRemoveOrganizationMember, zap.Error(err))
+			return organizations.NewRemoveOrganizationMemberDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrRemoveOrganizationMember, ""))
+		}
+		// members may leave the organization themselves
+		isLeaving := int(p.UserID) == int(principal.ID) && memberRole(existing, int(principal.ID)) != ""
+		if !isLeaving && !policies.Can(principal, policies.Update, organizationResource(existing, principal)) {
+			return organizations.NewRemoveOrganizationMemberForbidden().
+				WithPayload(buildForbiddenErrorPayload(messages.ErrOrganizationManage, ""))
+		}
+		result, err := repository.RemoveMember(ctx, existing.ID, int(p.UserID))
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrNotOrganizationMember):
+				return organizations.NewRemoveOrganizationMemberNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrOrganizationMemberNotFound, ""))
+			case errors.Is(err, domain.ErrLastOrganizationOwner):
+				return organizations.NewRemoveOrganizationMemberConflict().
+					WithPayload(buildConflictErrorPayload(messages.ErrLastOrganizationOwner, ""))
+			case ent.IsNotFound(err):
+				return organizations.NewRemoveOrganizationMemberNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrOrganizationNotFound, ""))
+			}
+			o.logger.Error(messages.ErrRemoveOrganizationMember, zap.Error(err))
+			return organizations.NewRemoveOrganizationMemberDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrRemoveOrganizationMember, ""))
+		}
+		return organizations.NewRemoveOrganizationMemberOK().WithPayload(mapOrganization(result))
+	}
+}
+
+func (o Organization) GetOrganizationOrdersFunc(organizationRepo domain.OrganizationRepository,
+	orderRepo domain.OrderRepository) organizations.GetOrganizationOrdersHandlerFunc {
+	return func(p organizations.GetOrganizationOrdersParams, principal *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		existing, err := organizationRepo.OrganizationByID(ctx, int(p.OrganizationID))
+		if err != nil {
+			if ent.IsNotFound(err) {
+				return organizations.NewGetOrganizationOrdersNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrOrganizationNotFound, ""))
+			}
+			o.logger.Error(messages.ErrGetOrganization, zap.Error(err))
+			return organizations.NewGetOrganizationOrdersDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrGetOrganization, ""))
+		}
+		if !policies.Can(principal, policies.View, organizationResource(existing, principal)) {
+			return organizations.NewGetOrganizationOrdersForbidden().
+				WithPayload(buildForbiddenErrorPayload(messages.ErrOrganizationForbidden, ""))
+		}
+
+		if p.Status != nil {
+			if _, ok := domain.AllOrderStatuses[*p.Status]; !ok {
+				return organizations.NewGetOrganizationOrdersDefault(http.StatusBadRequest).
+					WithPayload(buildBadRequestErrorPayload(messages.ErrQueryOrders,
+						fmt.Sprintf("invalid order status '%v'", *p.Status)))
+			}
+		}
+		organizationID := existing.ID
+		orderFilter := domain.OrderFilter{
+			Filter: domain.Filter{
+				Limit:       int(utils.GetValueByPointerOrDefaultValue(p.Limit, math.MaxInt)),
+				Offset:      int(utils.GetValueByPointerOrDefaultValue(p.Offset, 0)),
+				OrderBy:     utils.GetValueByPointerOrDefaultValue(p.OrderBy, utils.AscOrder),
+				OrderColumn: utils.GetValueByPointerOrDefaultValue(p.OrderColumn, order.FieldID),
+			},
+			Status:         p.Status,
+			OrganizationID: &organizationID,
+		}
+
+		total, err := orderRepo.OrganizationOrdersTotal(ctx, organizationID)
+		if err != nil {
+			o.logger.Error(messages.ErrQueryTotalOrders, zap.Error(err))
+			return organizations.NewGetOrganizationOrdersDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrQueryTotalOrders, ""))
+		}
+		var organizationOrders []*ent.Order
+		if total > 0 {
+			organizationOrders, err = orderRepo.List(ctx, nil, orderFilter)
+			if err != nil {
+				o.logger.Error(messages.ErrQueryOrders, zap.Error(err))
+				return organizations.NewGetOrganizationOrdersDefault(http.StatusInternalServerError).
+					WithPayload(buildInternalErrorPayload(messages.ErrQueryOrders, ""))
+			}
+		}
+		mappedOrders, err := mapUserOrdersToResponse(organizationOrders, o.logger)
+		if err != nil {
+			o.logger.Error(messages.ErrMapOrder, zap.Error(err))
+			return organizations.NewGetOrganizationOrdersDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrMapOrder, ""))
+		}
+		totalOrders := int64(total)
+		return organizations.NewGetOrganizationOrdersOK().WithPayload(&models.UserOrdersList{
+			Items: mappedOrders,
+			Total: &totalOrders,
+		})
+	}
+}
+
+// memberRole returns the role of the user in the organization loaded with its members.
+func memberRole(organization *ent.Organization, userID int) string {
+	for _, member := range organization.Edges.Members {
+		if member.Edges.User != nil && member.Edges.User.ID == userID {
+			return member.Role.String()
+		}
+	}
+	return ""
+}
+
+func organizationResource(organization *ent.Organization, principal *models.Principal) policies.Resource {
+	return policies.Resource{
+		Kind:           policies.Organization,
+		OrganizationID: organization.ID,
+		MemberRole:     memberRole(organization, int(principal.ID)),
+	}
+}
+
+// withMemberRole sets the role of the principal in the organization of the resource, if there is one.
+func withMemberRole(ctx context.Context, repository domain.OrganizationRepository, principal *models.Principal,
+	resource policies.Resource) (policies.Resource, error) {
+	if resource.OrganizationID == 0 {
+		return resource, nil
+	}
+	role, err := repository.MemberRole(ctx, resource.OrganizationID, int(principal.ID))
+	if err != nil {
+		return resource, err
+	}
+	resource.MemberRole = role
+	return resource, nil
+}
+
+func mapOrganization(organization *ent.Organization) *models.Organization {
+	id := int64(organization.ID)
+	name := organization.Name
+	members := make([]*models.OrganizationMember, 0, len(organization.Edges.Members))
+	for _, member := range organization.Edges.Members {
+		if member.Edges.User == nil {
+			continue
+		}
+		userID := int64(member.Edges.User.ID)
+		login := member.Edges.User.Login
+		role := member.Role.String()
+		members = append(members, &models.OrganizationMember{
+			UserID: &userID,
+			Login:  &login,
+			Role:   &role,
+		})
+	}
+	return &models.Organization{
+		ID:      &id,
+		Name:    &name,
+		Website: organization.Website,
+		Members: members,
+	}
+}
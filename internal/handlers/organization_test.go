@@ -0,0 +1,354 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/runtime"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/organizationmember"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/mocks"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/organizations"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+func TestSetOrganizationHandler(t *testing.T) {
+	logger := zap.NewNop()
+
+	swaggerSpec, err := loads.Analyzed(restapi.SwaggerJSON, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := operations.NewBeAPI(swaggerSpec)
+	SetOrganizationHandler(logger, api)
+	require.NotEmpty(t, api.OrganizationsGetUserOrganizationsHandler)
+	require.NotEmpty(t, api.OrganizationsCreateOrganizationHandler)
+	require.NotEmpty(t, api.OrganizationsGetOrganizationHandler)
+	require.NotEmpty(t, api.OrganizationsSetOrganizationMemberHandler)
+	require.NotEmpty(t, api.OrganizationsRemoveOrganizationMemberHandler)
+	require.NotEmpty(t, api.OrganizationsGetOrganizationOrdersHandler)
+}
+
+type OrganizationTestSuite struct {
+	suite.Suite
+	logger          *zap.Logger
+	repository      *mocks.OrganizationRepository
+	orderRepository *mocks.OrderRepository
+	handler         *Organization
+	owner           *models.Principal
+	member          *models.Principal
+	outsider        *models.Principal
+}
+
+func TestOrganizationSuite(t *testing.T) {
+	suite.Run(t, new(OrganizationTestSuite))
+}
+
+func (s *OrganizationTestSuite) SetupTest() {
+	s.logger = zap.NewNop()
+	s.repository = &mocks.OrganizationRepository{}
+	s.orderRepository = &mocks.OrderRepository{}
+	s.handler = NewOrganization(s.logger)
+	s.owner = &models.Principal{ID: 1, Role: roles.User}
+	s.member = &models.Principal{ID: 2, Role: roles.User}
+	s.outsider = &models.Principal{ID: 3, Role: roles.User}
+}
+
+func (s *OrganizationTestSuite) TearDownTest() {
+	s.repository.AssertExpectations(s.T())
+	s.orderRepository.AssertExpectations(s.T())
+}
+
+func testOrganization(id int) *ent.Organization {
+	return &ent.Organization{
+		ID:   id,
+		Name: "shelter",
+		Edges: ent.OrganizationEdges{
+			Members: []*ent.OrganizationMember{
+				{ID: 1, Role: organizationmember.RoleOwner, Edges: ent.OrganizationMemberEdges{
+					User: &ent.User{ID: 1, Login: "owner"},
+				}},
+				{ID: 2, Role: organizationmember.RoleMember, Edges: ent.OrganizationMemberEdges{
+					User: &ent.User{ID: 2, Login: "member"},
+				}},
+			},
+		},
+	}
+}
+
+func (s *OrganizationTestSuite) TestOrganization_GetUserOrganizations_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("UserOrganizations", ctx, 2).Return([]*ent.Organization{testOrganization(1)}, nil)
+
+	handlerFunc := s.handler.GetUserOrganizationsFunc(s.repository)
+	resp := handlerFunc.Handle(organizations.GetUserOrganizationsParams{HTTPRequest: &request}, s.member)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var actual models.ListOrganizations
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+	require.Len(t, actual, 1)
+	require.Len(t, actual[0].Members, 2)
+	require.Equal(t, domain.OrganizationRoleOwner, *actual[0].Members[0].Role)
+}
+
+func (s *OrganizationTestSuite) TestOrganization_GetUserOrganizations_RepoErr() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("UserOrganizations", ctx, 2).Return(nil, errors.New("test"))
+
+	handlerFunc := s.handler.GetUserOrganizationsFunc(s.repository)
+	resp := handlerFunc.Handle(organizations.GetUserOrganizationsParams{HTTPRequest: &request}, s.member)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+}
+
+func (s *OrganizationTestSuite) TestOrganization_CreateOrganization_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	name := "shelter"
+	website := "https://shelter.example.com"
+	s.repository.On("CreateOrganization", ctx, name, &website, 3).Return(testOrganization(1), nil)
+
+	handlerFunc := s.handler.CreateOrganizationFunc(s.repository)
+	resp := handlerFunc.Handle(organizations.CreateOrganizationParams{
+		HTTPRequest: &request,
+		Data:        &models.OrganizationRequest{Name: &name, Website: website},
+	}, s.outsider)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusCreated, responseRecorder.Code)
+}
+
+func (s *OrganizationTestSuite) TestOrganization_GetOrganization_NotFound() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("OrganizationByID", ctx, 1).Return(nil, &ent.NotFoundError{})
+
+	handlerFunc := s.handler.GetOrganizationFunc(s.repository)
+	resp := handlerFunc.Handle(organizations.GetOrganizationParams{HTTPRequest: &request, OrganizationID: 1},
+		s.member)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusNotFound, responseRecorder.Code)
+}
+
+func (s *OrganizationTestSuite) TestOrganization_GetOrganization_Access() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("OrganizationByID", ctx, 1).Return(testOrganization(1), nil)
+
+	handlerFunc := s.handler.GetOrganizationFunc(s.repository)
+	params := organizations.GetOrganizationParams{HTTPRequest: &request, OrganizationID: 1}
+	for principal, code := range map[*models.Principal]int{
+		s.member:                     http.StatusOK,
+		s.outsider:                   http.StatusForbidden,
+		{ID: 4, Role: roles.Manager}: http.StatusOK,
+	} {
+		responseRecorder := httptest.NewRecorder()
+		handlerFunc.Handle(params, principal).WriteResponse(responseRecorder, runtime.JSONProducer())
+		require.Equal(t, code, responseRecorder.Code, principal.ID)
+	}
+}
+
+func (s *OrganizationTestSuite) TestOrganization_SetOrganizationMember_NotOwner() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("OrganizationByID", ctx, 1).Return(testOrganization(1), nil)
+
+	role := domain.OrganizationRoleOwner
+	handlerFunc := s.handler.SetOrganizationMemberFunc(s.repository)
+	resp := handlerFunc.Handle(organizations.SetOrganizationMemberParams{
+		HTTPRequest:    &request,
+		OrganizationID: 1,
+		UserID:         2,
+		Data:           &models.OrganizationMemberRequest{Role: &role},
+	}, s.member)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusForbidden, responseRecorder.Code)
+}
+
+func (s *OrganizationTestSuite) TestOrganization_SetOrganizationMember_Errors() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("OrganizationByID", ctx, 1).Return(testOrganization(1), nil)
+
+	role := domain.OrganizationRoleMember
+	handlerFunc := s.handler.SetOrganizationMemberFunc(s.repository)
+	for userID, testCase := range map[int64]struct {
+		err  error
+		code int
+	}{
+		1: {err: domain.ErrLastOrganizationOwner, code: http.StatusConflict},
+		5: {err: domain.ErrUnknownUser, code: http.StatusBadRequest},
+		6: {err: errors.New("test"), code: http.StatusInternalServerError},
+	} {
+		s.repository.On("SetMember", ctx, 1, int(userID), role).Return(nil, testCase.err)
+		resp := handlerFunc.Handle(organizations.SetOrganizationMemberParams{
+			HTTPRequest:    &request,
+			OrganizationID: 1,
+			UserID:         userID,
+			Data:           &models.OrganizationMemberRequest{Role: &role},
+		}, s.owner)
+
+		responseRecorder := httptest.NewRecorder()
+		resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+		require.Equal(t, testCase.code, responseRecorder.Code, userID)
+	}
+}
+
+func (s *OrganizationTestSuite) TestOrganization_SetOrganizationMember_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("OrganizationByID", ctx, 1).Return(testOrganization(1), nil)
+	s.repository.On("SetMember", ctx, 1, 3, domain.OrganizationRoleMember).Return(testOrganization(1), nil)
+
+	role := domain.OrganizationRoleMember
+	handlerFunc := s.handler.SetOrganizationMemberFunc(s.repository)
+	resp := handlerFunc.Handle(organizations.SetOrganizationMemberParams{
+		HTTPRequest:    &request,
+		OrganizationID: 1,
+		UserID:         3,
+		Data:           &models.OrganizationMemberRequest{Role: &role},
+	}, s.owner)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+}
+
+func (s *OrganizationTestSuite) TestOrganization_RemoveOrganizationMember_Leave() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("OrganizationByID", ctx, 1).Return(testOrganization(1), nil)
+	s.repository.On("RemoveMember", ctx, 1, 2).Return(testOrganization(1), nil)
+
+	handlerFunc := s.handler.RemoveOrganizationMemberFunc(s.repository)
+	resp := handlerFunc.Handle(organizations.RemoveOrganizationMemberParams{
+		HTTPRequest:    &request,
+		OrganizationID: 1,
+		UserID:         2,
+	}, s.member)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	// members can't remove each other
+	resp = handlerFunc.Handle(organizations.RemoveOrganizationMemberParams{
+		HTTPRequest:    &request,
+		OrganizationID: 1,
+		UserID:         1,
+	}, s.member)
+	responseRecorder = httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusForbidden, responseRecorder.Code)
+}
+
+func (s *OrganizationTestSuite) TestOrganization_RemoveOrganizationMember_NotMember() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("OrganizationByID", ctx, 1).Return(testOrganization(1), nil)
+	s.repository.On("RemoveMember", ctx, 1, 3).Return(nil, domain.ErrNotOrganizationMember)
+
+	handlerFunc := s.handler.RemoveOrganizationMemberFunc(s.repository)
+	resp := handlerFunc.Handle(organizations.RemoveOrganizationMemberParams{
+		HTTPRequest:    &request,
+		OrganizationID: 1,
+		UserID:         3,
+	}, s.owner)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusNotFound, responseRecorder.Code)
+}
+
+func (s *OrganizationTestSuite) TestOrganization_GetOrganizationOrders_Forbidden() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	s.repository.On("OrganizationByID", ctx, 1).Return(testOrganization(1), nil)
+
+	handlerFunc := s.handler.GetOrganizationOrdersFunc(s.repository, s.orderRepository)
+	resp := handlerFunc.Handle(organizations.GetOrganizationOrdersParams{
+		HTTPRequest:    &request,
+		OrganizationID: 1,
+	}, s.outsider)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusForbidden, responseRecorder.Code)
+}
+
+func (s *OrganizationTestSuite) TestOrganization_GetOrganizationOrders_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	organizationOrder := orderWithEdges(t, 1)
+	organizationOrder.Edges.Organization = &ent.Organization{ID: 1}
+	s.repository.On("OrganizationByID", ctx, 1).Return(testOrganization(1), nil)
+	s.orderRepository.On("OrganizationOrdersTotal", ctx, 1).Return(1, nil)
+	s.orderRepository.On("List", ctx, (*int)(nil), mock.MatchedBy(func(filter domain.OrderFilter) bool {
+		return filter.OrganizationID != nil && *filter.OrganizationID == 1
+	})).Return([]*ent.Order{organizationOrder}, nil)
+
+	handlerFunc := s.handler.GetOrganizationOrdersFunc(s.repository, s.orderRepository)
+	resp := handlerFunc.Handle(organizations.GetOrganizationOrdersParams{
+		HTTPRequest:    &request,
+		OrganizationID: 1,
+	}, s.member)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var actual models.UserOrdersList
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+	require.Equal(t, int64(1), *actual.Total)
+	require.Len(t, actual.Items, 1)
+	require.Equal(t, int64(1), *actual.Items[0].OrganizationID)
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/go-openapi/runtime/middleware"
 	"go.uber.org/zap"
@@ -18,6 +20,7 @@ func SetPasswordResetHandler(logger *zap.Logger, api *operations.BeAPI, password
 
 	api.PasswordResetSendLinkByLoginHandler = PasswordResetHandler.SendLinkByLoginFunc()
 	api.PasswordResetGetPasswordResetLinkHandler = PasswordResetHandler.GetPasswordResetLinkFunc()
+	api.PasswordResetSetNewPasswordHandler = PasswordResetHandler.SetNewPasswordFunc()
 }
 
 type passwordResetHandler struct {
@@ -55,12 +58,40 @@ func (c passwordResetHandler) SendLinkByLoginFunc() password_reset.SendLinkByLog
 func (c passwordResetHandler) GetPasswordResetLinkFunc() password_reset.GetPasswordResetLinkHandlerFunc {
 	return func(s password_reset.GetPasswordResetLinkParams) middleware.Responder {
 		ctx := s.HTTPRequest.Context()
-		token := s.Token
-		err := c.passwordReset.VerifyTokenAndSendPassword(ctx, token)
+		err := c.passwordReset.VerifyToken(ctx, s.Token)
+		if errors.Is(err, domain.ErrPasswordResetTokenInvalid) {
+			return password_reset.NewGetPasswordResetLinkBadRequest().
+				WithPayload(buildBadRequestErrorPayload(messages.ErrPasswordResetToken, ""))
+		}
+		if err != nil {
+			c.logger.Error("Failed to verify password reset token", zap.Error(err))
+			return password_reset.NewGetPasswordResetLinkDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrPasswordReset, ""))
+		}
+		return password_reset.NewGetPasswordResetLinkOK().
+			WithPayload(models.PasswordResetResponse(messages.MsgPasswordResetLinkValid))
+	}
+}
+
+func (c passwordResetHandler) SetNewPasswordFunc() password_reset.SetNewPasswordHandlerFunc {
+	return func(s password_reset.SetNewPasswordParams) middleware.Responder {
+		ctx := s.HTTPRequest.Context()
+		err := c.passwordReset.ResetPassword(ctx, s.Token, *s.Data.Password)
+		if errors.Is(err, domain.ErrPasswordResetTokenInvalid) {
+			return password_reset.NewSetNewPasswordBadRequest().
+				WithPayload(buildBadRequestErrorPayload(messages.ErrPasswordResetToken, ""))
+		}
+		var policyErr *domain.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			return password_reset.NewSetNewPasswordBadRequest().
+				WithPayload(buildBadRequestErrorPayload(messages.ErrPasswordPolicy, strings.Join(policyErr.Violations, "; ")))
+		}
 		if err != nil {
-			c.logger.Error("Failed to verify token or send email", zap.Error(err))
-			return password_reset.NewGetPasswordResetLinkOK().WithPayload("Check your email for a new password")
+			c.logger.Error("Failed to reset password", zap.Error(err))
+			return password_reset.NewSetNewPasswordDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrPasswordReset, ""))
 		}
-		return password_reset.NewGetPasswordResetLinkOK().WithPayload("Check your email for a new password")
+		return password_reset.NewSetNewPasswordOK().
+			WithPayload(models.PasswordResetResponse(messages.MsgPasswordResetDone))
 	}
 }
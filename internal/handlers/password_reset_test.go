@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/go-openapi/loads"
@@ -19,6 +21,8 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/password_reset"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
 )
 
 func TestSetPasswordResetHandler(t *testing.T) {
@@ -39,6 +43,7 @@ func TestSetPasswordResetHandler(t *testing.T) {
 
 	require.NotNil(t, api.PasswordResetGetPasswordResetLinkHandler)
 	require.NotNil(t, api.PasswordResetSendLinkByLoginHandler)
+	require.NotNil(t, api.PasswordResetSetNewPasswordHandler)
 }
 
 type PasswordResetHandlerTestSuite struct {
@@ -69,13 +74,32 @@ func (s *PasswordResetHandlerTestSuite) TestPasswordResetHandler_GetPasswordRese
 		Token:       token,
 	}
 	err := errors.New("error")
-	s.passwordService.On("VerifyTokenAndSendPassword", ctx, token).Return(err)
+	s.passwordService.On("VerifyToken", ctx, token).Return(err)
 	handlerFunc := s.handler.GetPasswordResetLinkFunc()
 	resp := handlerFunc.Handle(params)
 	responseRecorder := httptest.NewRecorder()
 	producer := runtime.JSONProducer()
 	resp.WriteResponse(responseRecorder, producer)
-	require.Equal(t, http.StatusOK, responseRecorder.Code)
+	require.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+	s.passwordService.AssertExpectations(t)
+}
+
+func (s *PasswordResetHandlerTestSuite) TestPasswordResetHandler_GetPasswordResetLinkFunc_InvalidToken() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	token := "token"
+	params := password_reset.GetPasswordResetLinkParams{
+		HTTPRequest: &request,
+		Token:       token,
+	}
+	s.passwordService.On("VerifyToken", ctx, token).Return(domain.ErrPasswordResetTokenInvalid)
+	handlerFunc := s.handler.GetPasswordResetLinkFunc()
+	resp := handlerFunc.Handle(params)
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusBadRequest, responseRecorder.Code)
 	s.passwordService.AssertExpectations(t)
 }
 
@@ -88,7 +112,7 @@ func (s *PasswordResetHandlerTestSuite) TestPasswordResetHandler_GetPasswordRese
 		HTTPRequest: &request,
 		Token:       token,
 	}
-	s.passwordService.On("VerifyTokenAndSendPassword", ctx, token).Return(nil)
+	s.passwordService.On("VerifyToken", ctx, token).Return(nil)
 	handlerFunc := s.handler.GetPasswordResetLinkFunc()
 	resp := handlerFunc.Handle(params)
 	responseRecorder := httptest.NewRecorder()
@@ -98,6 +122,96 @@ func (s *PasswordResetHandlerTestSuite) TestPasswordResetHandler_GetPasswordRese
 	s.passwordService.AssertExpectations(t)
 }
 
+func (s *PasswordResetHandlerTestSuite) TestPasswordResetHandler_SetNewPasswordFunc_InvalidToken() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	token := "token"
+	password := "Str0ng-Passw"
+	params := password_reset.SetNewPasswordParams{
+		HTTPRequest: &request,
+		Token:       token,
+		Data:        &models.SetNewPasswordRequest{Password: &password},
+	}
+	s.passwordService.On("ResetPassword", ctx, token, password).Return(domain.ErrPasswordResetTokenInvalid)
+	handlerFunc := s.handler.SetNewPasswordFunc()
+	resp := handlerFunc.Handle(params)
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	s.passwordService.AssertExpectations(t)
+}
+
+func (s *PasswordResetHandlerTestSuite) TestPasswordResetHandler_SetNewPasswordFunc_PolicyViolated() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	token := "token"
+	password := "password"
+	params := password_reset.SetNewPasswordParams{
+		HTTPRequest: &request,
+		Token:       token,
+		Data:        &models.SetNewPasswordRequest{Password: &password},
+	}
+	violations := []string{messages.ErrPasswordNoDigit, messages.ErrPasswordTooCommon}
+	s.passwordService.On("ResetPassword", ctx, token, password).
+		Return(&domain.PasswordPolicyError{Violations: violations})
+	handlerFunc := s.handler.SetNewPasswordFunc()
+	resp := handlerFunc.Handle(params)
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	actual := &models.SwaggerError{}
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), actual))
+	require.Equal(t, messages.ErrPasswordPolicy, *actual.Message)
+	require.Equal(t, strings.Join(violations, "; "), actual.Details)
+	s.passwordService.AssertExpectations(t)
+}
+
+func (s *PasswordResetHandlerTestSuite) TestPasswordResetHandler_SetNewPasswordFunc_ServiceErr() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	token := "token"
+	password := "Str0ng-Passw"
+	params := password_reset.SetNewPasswordParams{
+		HTTPRequest: &request,
+		Token:       token,
+		Data:        &models.SetNewPasswordRequest{Password: &password},
+	}
+	s.passwordService.On("ResetPassword", ctx, token, password).Return(errors.New("error"))
+	handlerFunc := s.handler.SetNewPasswordFunc()
+	resp := handlerFunc.Handle(params)
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+	s.passwordService.AssertExpectations(t)
+}
+
+func (s *PasswordResetHandlerTestSuite) TestPasswordResetHandler_SetNewPasswordFunc_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	token := "token"
+	password := "Str0ng-Passw"
+	params := password_reset.SetNewPasswordParams{
+		HTTPRequest: &request,
+		Token:       token,
+		Data:        &models.SetNewPasswordRequest{Password: &password},
+	}
+	s.passwordService.On("ResetPassword", ctx, token, password).Return(nil)
+	handlerFunc := s.handler.SetNewPasswordFunc()
+	resp := handlerFunc.Handle(params)
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+	s.passwordService.AssertExpectations(t)
+}
+
 func (s *PasswordResetHandlerTestSuite) TestPasswordResetHandler_SendLinkByLoginFunc_EmptyLogin() {
 	t := s.T()
 	request := http.Request{}
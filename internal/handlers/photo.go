@@ -1,10 +1,17 @@
 package handlers
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
@@ -14,20 +21,22 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/photos"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/images"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/policies"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/repositories"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
 )
 
-func SetPhotoHandler(logger *zap.Logger, api *operations.BeAPI) {
+func SetPhotoHandler(logger *zap.Logger, api *operations.BeAPI, blobStore domain.BlobStore, limits images.Limits) {
 	photoRepo := repositories.NewPhotoRepository()
 	photosHandler := NewPhoto(logger)
 
-	api.PhotosCreateNewPhotoHandler = photosHandler.CreateNewPhotoFunc(photoRepo)
-	api.PhotosGetPhotoHandler = photosHandler.GetPhotoFunc(photoRepo)
-	api.PhotosDeletePhotoHandler = photosHandler.DeletePhotoFunc(photoRepo)
-	api.PhotosDownloadPhotoHandler = photosHandler.DownloadPhotoFunc(photoRepo)
+	api.PhotosCreateNewPhotoHandler = photosHandler.CreateNewPhotoFunc(photoRepo, blobStore, limits)
+	api.PhotosGetPhotoHandler = photosHandler.GetPhotoFunc(photoRepo, blobStore)
+	api.PhotosDeletePhotoHandler = photosHandler.DeletePhotoFunc(photoRepo, blobStore)
+	api.PhotosDownloadPhotoHandler = photosHandler.DownloadPhotoFunc(photoRepo, blobStore)
 }
 
 type Photo struct {
@@ -40,11 +49,17 @@ func NewPhoto(logger *zap.Logger) *Photo {
 	}
 }
 
-func (p Photo) CreateNewPhotoFunc(repository domain.PhotoRepository) photos.CreateNewPhotoHandlerFunc {
-	return func(s photos.CreateNewPhotoParams, _ *models.Principal) middleware.Responder {
+func (p Photo) CreateNewPhotoFunc(repository domain.PhotoRepository,
+	blobStore domain.BlobStore, limits images.Limits) photos.CreateNewPhotoHandlerFunc {
+	return func(s photos.CreateNewPhotoParams, principal *models.Principal) middleware.Responder {
 		ctx := s.HTTPRequest.Context()
-		// read input file
-		fileBytes, err := io.ReadAll(s.File)
+		if !policies.Can(principal, policies.Create, policies.Resource{Kind: policies.Photo}) {
+			p.logger.Warn(messages.ErrPhotoForbidden, zap.Any("principal", principal))
+			return photos.NewCreateNewPhotoDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrPhotoForbidden, ""))
+		}
+		// read input file, one byte over the limit is enough to tell it is too large
+		fileBytes, err := io.ReadAll(io.LimitReader(s.File, limits.MaxFileSize+1))
 		if err != nil {
 			p.logger.Error("failed to read file", zap.Error(err))
 			return photos.NewCreateNewPhotoDefault(http.StatusInternalServerError).
@@ -61,12 +76,31 @@ func (p Photo) CreateNewPhotoFunc(repository domain.PhotoRepository) photos.Crea
 			return photos.NewCreateNewPhotoDefault(http.StatusBadRequest).
 				WithPayload(buildBadRequestErrorPayload(messages.ErrFileEmpty, ""))
 		}
-		// check if file is image jpg/jpeg
+		if int64(len(fileBytes)) > limits.MaxFileSize {
+			p.logger.Error(messages.ErrFileTooLarge, zap.Int64("limit", limits.MaxFileSize))
+			return photos.NewCreateNewPhotoDefault(http.StatusRequestEntityTooLarge).
+				WithPayload(buildErrorPayload(http.StatusRequestEntityTooLarge, messages.ErrFileTooLarge, ""))
+		}
+		// check if file is image jpg/jpeg, png or webp
 		mimeType := http.DetectContentType(fileBytes)
-		if mimeType != "image/jpg" && mimeType != "image/jpeg" {
-			p.logger.Error(fmt.Sprintf("wrong file format: %s. file should be jpg or jpeg", mimeType))
-			return photos.NewCreateNewPhotoDefault(http.StatusBadRequest).
-				WithPayload(buildBadRequestErrorPayload(messages.ErrWrongFormat, ""))
+		if !images.Supported(mimeType) {
+			p.logger.Error(fmt.Sprintf("wrong file format: %s. file should be jpg, png or webp", mimeType))
+			return photos.NewCreateNewPhotoDefault(http.StatusUnsupportedMediaType).
+				WithPayload(buildErrorPayload(http.StatusUnsupportedMediaType, messages.ErrWrongFormat, ""))
+		}
+
+		// the photo is encoded again, so it is upright and the metadata like the location is dropped,
+		// the mime type becomes the one the photo is stored with
+		mimeType, sizes, err := images.Process(fileBytes, limits)
+		if errors.Is(err, images.ErrImageTooLarge) {
+			p.logger.Error(messages.ErrImageTooLarge, zap.Error(err))
+			return photos.NewCreateNewPhotoDefault(http.StatusRequestEntityTooLarge).
+				WithPayload(buildErrorPayload(http.StatusRequestEntityTooLarge, messages.ErrImageTooLarge, ""))
+		}
+		if err != nil {
+			p.logger.Error("failed to process photo", zap.Error(err))
+			return photos.NewCreateNewPhotoDefault(http.StatusUnsupportedMediaType).
+				WithPayload(buildErrorPayload(http.StatusUnsupportedMediaType, messages.ErrWrongFormat, ""))
 		}
 
 		photoID, err := utils.GenerateFileName()
@@ -76,12 +110,12 @@ func (p Photo) CreateNewPhotoFunc(repository domain.PhotoRepository) photos.Crea
 				WithPayload(buildInternalErrorPayload(messages.ErrCreatePhoto, err.Error()))
 		}
 
-		fileName := fmt.Sprintf("%s.jpg", photoID)
-
+		hash := sha256.Sum256(sizes[images.Original])
 		newPhoto := &ent.Photo{
 			ID:       photoID,
-			FileName: fileName,
-			Content:  fileBytes,
+			FileName: photoID + images.Extension(mimeType),
+			MimeType: mimeType,
+			Etag:     hex.EncodeToString(hash[:]),
 		}
 		_, err = repository.CreatePhoto(ctx, newPhoto)
 		if err != nil {
@@ -89,16 +123,31 @@ func (p Photo) CreateNewPhotoFunc(repository domain.PhotoRepository) photos.Crea
 			return photos.NewCreateNewPhotoDefault(http.StatusInternalServerError).
 				WithPayload(buildInternalErrorPayload(messages.ErrCreatePhoto, err.Error()))
 		}
+		for size, content := range sizes {
+			err = blobStore.Put(ctx, size.Key(photoID), bytes.NewReader(content), int64(len(content)))
+			if err != nil {
+				p.logger.Error("failed to store photo content", zap.Error(err), zap.String("size", string(size)))
+				// the row is rolled back with the response, the content already stored would be left without it
+				for _, key := range images.Keys(photoID) {
+					if errDelete := blobStore.Delete(ctx, key); errDelete != nil {
+						p.logger.Error("failed to delete photo content", zap.Error(errDelete), zap.String("key", key))
+					}
+				}
+				return photos.NewCreateNewPhotoDefault(http.StatusInternalServerError).
+					WithPayload(buildInternalErrorPayload(messages.ErrCreatePhoto, err.Error()))
+			}
+		}
 		return photos.NewCreateNewPhotoCreated().WithPayload(&models.CreateNewPhotoResponse{
 			Data: &models.Photo{
 				FileName: newPhoto.FileName,
 				ID:       &newPhoto.ID,
+				MimeType: newPhoto.MimeType,
 			},
 		})
 	}
 }
 
-func (p Photo) GetPhotoFunc(repository domain.PhotoRepository) photos.GetPhotoHandlerFunc {
+func (p Photo) GetPhotoFunc(repository domain.PhotoRepository, blobStore domain.BlobStore) photos.GetPhotoHandlerFunc {
 	return func(s photos.GetPhotoParams, _ *models.Principal) middleware.Responder {
 		return middleware.ResponderFunc(func(w http.ResponseWriter, _ runtime.Producer) {
 			ctx := s.HTTPRequest.Context()
@@ -110,17 +159,40 @@ func (p Photo) GetPhotoFunc(repository domain.PhotoRepository) photos.GetPhotoHa
 				}
 				return
 			}
-			w.Header().Set("Content-Type", "image/jpg")
-			w.WriteHeader(http.StatusOK)
-			_, err = w.Write(photo.Content)
+			size := images.Original
+			if s.Size != nil {
+				size = images.Size(*s.Size)
+			}
+			etag := photoETag(photo, size)
+			setPhotoCacheHeaders(w, photo, etag)
+			if notModified(s.HTTPRequest, etag, photo.CreatedAt) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			content, err := blobStore.Get(ctx, size.Key(photo.ID))
+			// the photos uploaded before the renditions were made have only the original
+			if errors.Is(err, domain.ErrBlobNotFound) && size != images.Original {
+				content, err = blobStore.Get(ctx, photo.ID)
+			}
 			if err != nil {
+				p.logger.Error(messages.ErrGetPhoto, zap.Error(err))
+				if err := writeErrorInResponse(w, err); err != nil {
+					p.logger.Error("failed to response to client", zap.Error(err))
+				}
+				return
+			}
+			defer content.Close()
+			w.Header().Set("Content-Type", photo.MimeType)
+			w.WriteHeader(http.StatusOK)
+			if _, err = io.Copy(w, content); err != nil {
 				p.logger.Error("error while writing file", zap.Error(err))
 			}
 		})
 	}
 }
 
-func (p Photo) DownloadPhotoFunc(repository domain.PhotoRepository) photos.DownloadPhotoHandlerFunc {
+func (p Photo) DownloadPhotoFunc(repository domain.PhotoRepository,
+	blobStore domain.BlobStore) photos.DownloadPhotoHandlerFunc {
 	return func(s photos.DownloadPhotoParams, _ *models.Principal) middleware.Responder {
 		return middleware.ResponderFunc(func(w http.ResponseWriter, _ runtime.Producer) {
 
@@ -133,28 +205,38 @@ func (p Photo) DownloadPhotoFunc(repository domain.PhotoRepository) photos.Downl
 				}
 				return
 			}
-			//fileBytes, err := fileManager.ReadFile(photo.FileName)
-			//if err != nil {
-			//	p.logger.Error("failed to read photo file", zap.Error(err))
-			//	if err := writeErrorInResponse(w, err); err != nil {
-			//		p.logger.Error("failed to response to client", zap.Error(err))
-			//	}
-			//	return
-			//}
-			w.Header().Set("Content-Type", "application/octet-stream")
-			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment;filename=%s", photo.FileName))
-			w.WriteHeader(http.StatusOK)
-			_, err = w.Write(photo.Content)
+			etag := photoETag(photo, images.Original)
+			setPhotoCacheHeaders(w, photo, etag)
+			if notModified(s.HTTPRequest, etag, photo.CreatedAt) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			content, err := blobStore.Get(ctx, photo.ID)
 			if err != nil {
-				p.logger.Error("error while writing file", zap.Error(err))
+				p.logger.Error("failed to read photo file", zap.Error(err))
+				if err := writeErrorInResponse(w, err); err != nil {
+					p.logger.Error("failed to response to client", zap.Error(err))
+				}
+				return
 			}
+			defer content.Close()
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment;filename=%s", photo.FileName))
+			// serves the range requests, so the interrupted downloads are resumed, only the range is read
+			http.ServeContent(w, s.HTTPRequest, photo.FileName, photo.CreatedAt, content)
 		})
 	}
 }
 
-func (p Photo) DeletePhotoFunc(repository domain.PhotoRepository) photos.DeletePhotoHandlerFunc {
-	return func(s photos.DeletePhotoParams, _ *models.Principal) middleware.Responder {
+func (p Photo) DeletePhotoFunc(repository domain.PhotoRepository,
+	blobStore domain.BlobStore) photos.DeletePhotoHandlerFunc {
+	return func(s photos.DeletePhotoParams, principal *models.Principal) middleware.Responder {
 		ctx := s.HTTPRequest.Context()
+		if !policies.Can(principal, policies.Delete, policies.Resource{Kind: policies.Photo}) {
+			p.logger.Warn(messages.ErrPhotoForbidden, zap.Any("principal", principal))
+			return photos.NewDeletePhotoDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrPhotoForbidden, ""))
+		}
 		photo, err := repository.PhotoByID(ctx, s.PhotoID)
 		if err != nil {
 			p.logger.Error(messages.ErrGetPhoto, zap.Error(err))
@@ -162,21 +244,78 @@ func (p Photo) DeletePhotoFunc(repository domain.PhotoRepository) photos.DeleteP
 				WithPayload(buildInternalErrorPayload(messages.ErrDeletePhoto, err.Error()))
 		}
 
+		inUse, err := repository.PhotoInUse(ctx, photo.ID)
+		if err != nil {
+			p.logger.Error(messages.ErrGetPhoto, zap.Error(err))
+			return photos.NewDeletePhotoDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrDeletePhoto, err.Error()))
+		}
+		if inUse {
+			return photos.NewDeletePhotoConflict().
+				WithPayload(buildConflictErrorPayload(messages.ErrPhotoInUse, ""))
+		}
+
 		err = repository.DeletePhotoByID(ctx, photo.ID)
 		if err != nil {
 			p.logger.Error("delete photo failed", zap.Error(err))
 			return photos.NewDeletePhotoDefault(http.StatusInternalServerError).
 				WithPayload(buildInternalErrorPayload(messages.ErrDeletePhoto, err.Error()))
 		}
-		//err = fileManager.DeleteFile(photo.FileName)
-		//if err != nil {
-		//	p.logger.Error("failed to delete photo file", zap.Error(err))
-		//}
+		// the photo is deleted already, the content left in the store is only logged
+		for _, key := range images.Keys(photo.ID) {
+			if err = blobStore.Delete(ctx, key); err != nil {
+				p.logger.Error("failed to delete photo file", zap.Error(err), zap.String("key", key))
+			}
+		}
 
 		return photos.NewDeletePhotoOK().WithPayload(messages.MsgPhotoDeleted)
 	}
 }
 
+// photoCacheControl lets the browser keep the photo for a year as the content of the photo id never changes.
+// It is private because the photos are served to the signed in users only.
+const photoCacheControl = "private, max-age=31536000, immutable"
+
+// photoETag returns the entity tag of the size of the photo, the photos uploaded before the hash of the
+// content was kept are tagged by their id.
+func photoETag(photo *ent.Photo, size images.Size) string {
+	tag := photo.Etag
+	if tag == "" {
+		tag = photo.ID
+	}
+	if size != images.Original {
+		tag += "-" + string(size)
+	}
+	return strconv.Quote(tag)
+}
+
+func setPhotoCacheHeaders(w http.ResponseWriter, photo *ent.Photo, etag string) {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", photoCacheControl)
+	if !photo.CreatedAt.IsZero() {
+		w.Header().Set("Last-Modified", photo.CreatedAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+// notModified reports whether the copy the client has is the current one. If-Modified-Since is checked only
+// when there is no If-None-Match.
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		for _, tag := range strings.Split(header, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || modified.IsZero() {
+		return false
+	}
+	return !modified.Truncate(time.Second).After(since)
+}
+
 func writeErrorInResponse(w http.ResponseWriter, err error) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusInternalServerError)
@@ -6,11 +6,15 @@ import (
 	"errors"
 	"image"
 	"image/jpeg"
+	"image/png"
+	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-openapi/loads"
 	"github.com/go-openapi/runtime"
@@ -27,6 +31,9 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/photos"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/images"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
 )
 
 func TestSetPhotoHandler(t *testing.T) {
@@ -41,20 +48,32 @@ func TestSetPhotoHandler(t *testing.T) {
 	}
 	api := operations.NewBeAPI(swaggerSpec)
 
-	SetPhotoHandler(logger, api)
+	SetPhotoHandler(logger, api, &mocks.BlobStore{}, testPhotoLimits)
 	require.NotEmpty(t, api.PhotosCreateNewPhotoHandler)
 	require.NotEmpty(t, api.PhotosGetPhotoHandler)
 	require.NotEmpty(t, api.PhotosDeletePhotoHandler)
 	require.NotEmpty(t, api.PhotosDownloadPhotoHandler)
 }
 
+var testPhotoLimits = images.Limits{MaxFileSize: 1 << 20, MaxWidth: 1000, MaxHeight: 1000}
+
 type PhotoTestSuite struct {
 	suite.Suite
 	logger     *zap.Logger
 	repository *mocks.PhotoRepository
+	blobStore  *mocks.BlobStore
 	handler    *Photo
 }
 
+// blobContent is the content returned by the blob store mock.
+type blobContent struct {
+	*bytes.Reader
+}
+
+func (blobContent) Close() error {
+	return nil
+}
+
 func TestPhotoSuite(t *testing.T) {
 	suite.Run(t, new(PhotoTestSuite))
 }
@@ -62,6 +81,7 @@ func TestPhotoSuite(t *testing.T) {
 func (s *PhotoTestSuite) SetupTest() {
 	s.logger = zap.NewNop()
 	s.repository = &mocks.PhotoRepository{}
+	s.blobStore = &mocks.BlobStore{}
 	s.handler = NewPhoto(s.logger)
 }
 
@@ -82,9 +102,9 @@ func (s *PhotoTestSuite) TestPhoto_CreatePhoto_EmptyFile() {
 		File:        f,
 	}
 
-	handlerFunc := s.handler.CreateNewPhotoFunc(s.repository)
+	handlerFunc := s.handler.CreateNewPhotoFunc(s.repository, s.blobStore, testPhotoLimits)
 
-	resp := handlerFunc.Handle(data, nil)
+	resp := handlerFunc.Handle(data, &models.Principal{Role: roles.Admin})
 
 	responseRecorder := httptest.NewRecorder()
 	producer := runtime.JSONProducer()
@@ -101,6 +121,7 @@ func (s *PhotoTestSuite) TestPhoto_CreatePhoto_EmptyFile() {
 	require.Equal(t, "File is empty", *response.Message)
 
 	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
 }
 
 func (s *PhotoTestSuite) TestPhoto_CreatePhoto_WrongMimeType() {
@@ -125,14 +146,14 @@ func (s *PhotoTestSuite) TestPhoto_CreatePhoto_WrongMimeType() {
 		File:        f,
 	}
 
-	handlerFunc := s.handler.CreateNewPhotoFunc(s.repository)
+	handlerFunc := s.handler.CreateNewPhotoFunc(s.repository, s.blobStore, testPhotoLimits)
 
-	resp := handlerFunc.Handle(data, nil)
+	resp := handlerFunc.Handle(data, &models.Principal{Role: roles.Admin})
 
 	responseRecorder := httptest.NewRecorder()
 	producer := runtime.JSONProducer()
 	resp.WriteResponse(responseRecorder, producer)
-	require.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	require.Equal(t, http.StatusUnsupportedMediaType, responseRecorder.Code)
 
 	response := models.SwaggerError{}
 	err = json.Unmarshal(responseRecorder.Body.Bytes(), &response)
@@ -144,6 +165,7 @@ func (s *PhotoTestSuite) TestPhoto_CreatePhoto_WrongMimeType() {
 	require.Containsf(t, *response.Message, "Wrong file format", "returned wrong error")
 
 	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
 }
 
 func (s *PhotoTestSuite) TestPhoto_CreatePhoto_OK() {
@@ -174,14 +196,21 @@ func (s *PhotoTestSuite) TestPhoto_CreatePhoto_OK() {
 		HTTPRequest: &request,
 		File:        f,
 	}
-	s.repository.On("CreatePhoto", ctx, mock.Anything).Return(&ent.Photo{
+	s.repository.On("CreatePhoto", ctx, mock.MatchedBy(func(p *ent.Photo) bool {
+		return len(p.Etag) == 64
+	})).Return(&ent.Photo{
 		ID:       id,
 		FileName: fileName,
 	}, nil)
+	var keys []string
+	s.blobStore.On("Put", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) {
+			keys = append(keys, args.String(1))
+		})
 
-	handlerFunc := s.handler.CreateNewPhotoFunc(s.repository)
+	handlerFunc := s.handler.CreateNewPhotoFunc(s.repository, s.blobStore, testPhotoLimits)
 
-	resp := handlerFunc.Handle(data, nil)
+	resp := handlerFunc.Handle(data, &models.Principal{Role: roles.Admin})
 
 	responseRecorder := httptest.NewRecorder()
 	producer := runtime.JSONProducer()
@@ -194,9 +223,194 @@ func (s *PhotoTestSuite) TestPhoto_CreatePhoto_OK() {
 		t.Fatal(err)
 	}
 	require.NotEmpty(t, returnedPhoto.Data.ID)
-	require.NotEmpty(t, returnedPhoto.Data.FileName)
+	require.Equal(t, *returnedPhoto.Data.ID+".jpg", returnedPhoto.Data.FileName)
+	require.Equal(t, "image/jpeg", returnedPhoto.Data.MimeType)
+	require.ElementsMatch(t, images.Keys(*returnedPhoto.Data.ID), keys)
+
+	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
+}
+
+func (s *PhotoTestSuite) TestPhoto_CreatePhoto_BlobErr() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	img, err := generateImageBytes()
+	require.NoError(t, err)
+	data := photos.CreateNewPhotoParams{
+		HTTPRequest: &request,
+		File:        io.NopCloser(bytes.NewReader(img)),
+	}
+	s.repository.On("CreatePhoto", ctx, mock.Anything).Return(&ent.Photo{}, nil)
+	// the content stored before the failure is deleted
+	var photoID string
+	s.blobStore.On("Put", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once().
+		Run(func(args mock.Arguments) {
+			photoID = strings.SplitN(args.String(1), "_", 2)[0]
+		})
+	s.blobStore.On("Put", ctx, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("test")).Once()
+	var deleted []string
+	s.blobStore.On("Delete", ctx, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		deleted = append(deleted, args.String(1))
+	})
+
+	handlerFunc := s.handler.CreateNewPhotoFunc(s.repository, s.blobStore, testPhotoLimits)
+	resp := handlerFunc.Handle(data, &models.Principal{Role: roles.Admin})
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+	require.ElementsMatch(t, images.Keys(photoID), deleted)
+
+	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
+}
+
+func (s *PhotoTestSuite) TestPhoto_CreatePhoto_PNG() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	fileName := "testimagename.png"
+
+	buf := new(bytes.Buffer)
+	err := png.Encode(buf, image.Rect(0, 0, 100, 100))
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = createNonEmptyFile(fileName, buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.Remove(fileName)
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	data := photos.CreateNewPhotoParams{
+		HTTPRequest: &request,
+		File:        f,
+	}
+	s.repository.On("CreatePhoto", ctx, mock.MatchedBy(func(p *ent.Photo) bool {
+		return p.MimeType == "image/png" && p.FileName == p.ID+".png"
+	})).Return(&ent.Photo{}, nil)
+	s.blobStore.On("Put", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	handlerFunc := s.handler.CreateNewPhotoFunc(s.repository, s.blobStore, testPhotoLimits)
+
+	resp := handlerFunc.Handle(data, &models.Principal{Role: roles.Admin})
+
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusCreated, responseRecorder.Code)
+
+	returnedPhoto := models.CreateNewPhotoResponse{}
+	err = json.Unmarshal(responseRecorder.Body.Bytes(), &returnedPhoto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	require.Equal(t, "image/png", returnedPhoto.Data.MimeType)
+
+	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
+}
+
+func (s *PhotoTestSuite) TestPhoto_CreatePhoto_TooLarge() {
+	t := s.T()
+	request := http.Request{}
+
+	img, err := generateImageBytes()
+	if err != nil {
+		log.Fatal(err)
+	}
+	limits := testPhotoLimits
+	limits.MaxFileSize = int64(len(img) - 1)
+
+	tests := map[string]struct {
+		limits  images.Limits
+		message string
+	}{
+		"file size": {limits: limits, message: "File is too large"},
+		"dimensions": {
+			limits:  images.Limits{MaxFileSize: 1 << 20, MaxWidth: 50, MaxHeight: 50},
+			message: "Image dimensions are too large",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := photos.CreateNewPhotoParams{
+				HTTPRequest: &request,
+				File:        io.NopCloser(bytes.NewReader(img)),
+			}
+
+			handlerFunc := s.handler.CreateNewPhotoFunc(s.repository, s.blobStore, tc.limits)
+
+			resp := handlerFunc.Handle(data, &models.Principal{Role: roles.Admin})
+
+			responseRecorder := httptest.NewRecorder()
+			producer := runtime.JSONProducer()
+			resp.WriteResponse(responseRecorder, producer)
+			require.Equal(t, http.StatusRequestEntityTooLarge, responseRecorder.Code)
+
+			response := models.SwaggerError{}
+			err = json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+			require.NoError(t, err)
+			require.Equal(t, tc.message, *response.Message)
+		})
+	}
 
 	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
+}
+
+func (s *PhotoTestSuite) TestPhoto_CreatePhoto_BrokenImage() {
+	t := s.T()
+	request := http.Request{}
+	fileName := "testbroken.jpg"
+
+	img, err := generateImageBytes()
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = createNonEmptyFile(fileName, img[:len(img)/2])
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.Remove(fileName)
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	data := photos.CreateNewPhotoParams{
+		HTTPRequest: &request,
+		File:        f,
+	}
+
+	handlerFunc := s.handler.CreateNewPhotoFunc(s.repository, s.blobStore, testPhotoLimits)
+
+	resp := handlerFunc.Handle(data, &models.Principal{Role: roles.Admin})
+
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusUnsupportedMediaType, responseRecorder.Code)
+
+	response := models.SwaggerError{}
+	err = json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	require.Containsf(t, *response.Message, "Wrong file format", "returned wrong error")
+
+	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
 }
 
 func (s *PhotoTestSuite) TestPhoto_GetPhoto_OK() {
@@ -215,10 +429,11 @@ func (s *PhotoTestSuite) TestPhoto_GetPhoto_OK() {
 	s.repository.On("PhotoByID", ctx, data.PhotoID).Return(&ent.Photo{
 		ID:       id,
 		FileName: fileName,
-		Content:  []byte{1, 1, 1},
+		MimeType: "image/jpeg",
 	}, nil)
+	s.blobStore.On("Get", ctx, id).Return(blobContent{bytes.NewReader([]byte{1, 1, 1})}, nil)
 
-	handlerFunc := s.handler.GetPhotoFunc(s.repository)
+	handlerFunc := s.handler.GetPhotoFunc(s.repository, s.blobStore)
 
 	resp := handlerFunc.Handle(data, nil)
 
@@ -226,11 +441,141 @@ func (s *PhotoTestSuite) TestPhoto_GetPhoto_OK() {
 	producer := runtime.JSONProducer()
 	resp.WriteResponse(responseRecorder, producer)
 	require.Equal(t, http.StatusOK, responseRecorder.Code)
-	require.Equal(t, "image/jpg", responseRecorder.Header().Get("Content-Type"))
+	require.Equal(t, "image/jpeg", responseRecorder.Header().Get("Content-Type"))
+	require.Equal(t, `"testimagename"`, responseRecorder.Header().Get("ETag"))
+	require.Contains(t, responseRecorder.Header().Get("Cache-Control"), "immutable")
 
 	require.Equal(t, []byte{1, 1, 1}, responseRecorder.Body.Bytes())
 
 	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
+}
+
+func (s *PhotoTestSuite) TestPhoto_GetPhoto_Size() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	id := "testimagename"
+	size := string(images.Thumbnail)
+
+	data := photos.GetPhotoParams{
+		HTTPRequest: &request,
+		PhotoID:     id,
+		Size:        &size,
+	}
+
+	s.repository.On("PhotoByID", ctx, data.PhotoID).Return(&ent.Photo{ID: id}, nil)
+	s.blobStore.On("Get", ctx, "testimagename_thumbnail").
+		Return(blobContent{bytes.NewReader([]byte{2, 2})}, nil)
+
+	handlerFunc := s.handler.GetPhotoFunc(s.repository, s.blobStore)
+
+	resp := handlerFunc.Handle(data, nil)
+
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+	require.Equal(t, []byte{2, 2}, responseRecorder.Body.Bytes())
+
+	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
+}
+
+func (s *PhotoTestSuite) TestPhoto_GetPhoto_SizeFallsBackToOriginal() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	id := "testimagename"
+	size := string(images.Medium)
+
+	data := photos.GetPhotoParams{
+		HTTPRequest: &request,
+		PhotoID:     id,
+		Size:        &size,
+	}
+
+	s.repository.On("PhotoByID", ctx, data.PhotoID).Return(&ent.Photo{ID: id}, nil)
+	s.blobStore.On("Get", ctx, "testimagename_medium").Return(nil, domain.ErrBlobNotFound)
+	s.blobStore.On("Get", ctx, id).Return(blobContent{bytes.NewReader([]byte{1, 1, 1})}, nil)
+
+	handlerFunc := s.handler.GetPhotoFunc(s.repository, s.blobStore)
+
+	resp := handlerFunc.Handle(data, nil)
+
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+	require.Equal(t, []byte{1, 1, 1}, responseRecorder.Body.Bytes())
+
+	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
+}
+
+func (s *PhotoTestSuite) TestPhoto_GetPhoto_NotModified() {
+	t := s.T()
+	request := http.Request{Header: http.Header{}}
+	request.Header.Set("If-None-Match", `"other", W/"hash-thumbnail"`)
+	ctx := request.Context()
+
+	id := "testimagename"
+	size := string(images.Thumbnail)
+
+	data := photos.GetPhotoParams{
+		HTTPRequest: &request,
+		PhotoID:     id,
+		Size:        &size,
+	}
+
+	s.repository.On("PhotoByID", ctx, data.PhotoID).Return(&ent.Photo{ID: id, Etag: "hash"}, nil)
+
+	handlerFunc := s.handler.GetPhotoFunc(s.repository, s.blobStore)
+
+	resp := handlerFunc.Handle(data, nil)
+
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusNotModified, responseRecorder.Code)
+	require.Equal(t, `"hash-thumbnail"`, responseRecorder.Header().Get("ETag"))
+	require.Empty(t, responseRecorder.Body.Bytes())
+
+	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
+}
+
+func (s *PhotoTestSuite) TestPhoto_GetPhoto_ModifiedSince() {
+	t := s.T()
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	request := http.Request{Header: http.Header{}}
+	request.Header.Set("If-Modified-Since", created.Add(-time.Hour).Format(http.TimeFormat))
+	ctx := request.Context()
+
+	id := "testimagename"
+
+	data := photos.GetPhotoParams{
+		HTTPRequest: &request,
+		PhotoID:     id,
+	}
+
+	s.repository.On("PhotoByID", ctx, data.PhotoID).Return(&ent.Photo{ID: id, CreatedAt: created}, nil)
+	s.blobStore.On("Get", ctx, id).Return(blobContent{bytes.NewReader([]byte{1})}, nil)
+
+	handlerFunc := s.handler.GetPhotoFunc(s.repository, s.blobStore)
+
+	resp := handlerFunc.Handle(data, nil)
+
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+	require.Equal(t, created.Format(http.TimeFormat), responseRecorder.Header().Get("Last-Modified"))
+
+	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
 }
 
 func (s *PhotoTestSuite) TestPhoto_GetPhoto_RepoErr() {
@@ -247,7 +592,7 @@ func (s *PhotoTestSuite) TestPhoto_GetPhoto_RepoErr() {
 	errorToReturn := errors.New("repo err")
 	s.repository.On("PhotoByID", ctx, data.PhotoID).Return(nil, errorToReturn)
 
-	handlerFunc := s.handler.GetPhotoFunc(s.repository)
+	handlerFunc := s.handler.GetPhotoFunc(s.repository, s.blobStore)
 
 	resp := handlerFunc(data, nil)
 
@@ -267,6 +612,32 @@ func (s *PhotoTestSuite) TestPhoto_GetPhoto_RepoErr() {
 	require.Contains(t, errorToReturn.Error(), response.Details)
 
 	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
+}
+
+func (s *PhotoTestSuite) TestPhoto_GetPhoto_BlobErr() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	id := "testimagename"
+	data := photos.GetPhotoParams{
+		HTTPRequest: &request,
+		PhotoID:     id,
+	}
+	s.repository.On("PhotoByID", ctx, data.PhotoID).Return(&ent.Photo{ID: id}, nil)
+	s.blobStore.On("Get", ctx, id).Return(nil, domain.ErrBlobNotFound)
+
+	handlerFunc := s.handler.GetPhotoFunc(s.repository, s.blobStore)
+	resp := handlerFunc(data, nil)
+
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+	require.Equal(t, "application/json", responseRecorder.Header().Get("Content-Type"))
+
+	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
 }
 
 func (s *PhotoTestSuite) TestPhoto_DownloadPhoto_OK() {
@@ -286,10 +657,10 @@ func (s *PhotoTestSuite) TestPhoto_DownloadPhoto_OK() {
 	s.repository.On("PhotoByID", ctx, data.PhotoID).Return(&ent.Photo{
 		ID:       id,
 		FileName: fileName,
-		Content:  bytesToReturn,
 	}, nil)
+	s.blobStore.On("Get", ctx, id).Return(blobContent{bytes.NewReader(bytesToReturn)}, nil)
 
-	handlerFunc := s.handler.DownloadPhotoFunc(s.repository)
+	handlerFunc := s.handler.DownloadPhotoFunc(s.repository, s.blobStore)
 
 	resp := handlerFunc.Handle(data, nil)
 
@@ -302,6 +673,86 @@ func (s *PhotoTestSuite) TestPhoto_DownloadPhoto_OK() {
 	require.Equal(t, bytesToReturn, responseRecorder.Body.Bytes())
 
 	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
+}
+
+func (s *PhotoTestSuite) TestPhoto_DownloadPhoto_Range() {
+	t := s.T()
+	request := http.Request{Method: http.MethodGet, Header: http.Header{}}
+	request.Header.Set("Range", "bytes=1-2")
+	ctx := request.Context()
+
+	id := "testimagename"
+
+	data := photos.DownloadPhotoParams{
+		HTTPRequest: &request,
+		PhotoID:     id,
+	}
+
+	s.repository.On("PhotoByID", ctx, data.PhotoID).Return(&ent.Photo{ID: id, FileName: "testimagename.jpg"}, nil)
+	s.blobStore.On("Get", ctx, id).Return(blobContent{bytes.NewReader([]byte{1, 2, 3, 4})}, nil)
+
+	handlerFunc := s.handler.DownloadPhotoFunc(s.repository, s.blobStore)
+
+	resp := handlerFunc.Handle(data, nil)
+
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusPartialContent, responseRecorder.Code)
+	require.Equal(t, "bytes 1-2/4", responseRecorder.Header().Get("Content-Range"))
+	require.Equal(t, []byte{2, 3}, responseRecorder.Body.Bytes())
+
+	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
+}
+
+func (s *PhotoTestSuite) TestPhoto_DownloadPhoto_NotModified() {
+	t := s.T()
+	request := http.Request{Method: http.MethodGet, Header: http.Header{}}
+	request.Header.Set("If-None-Match", `"hash"`)
+	ctx := request.Context()
+
+	id := "testimagename"
+
+	data := photos.DownloadPhotoParams{
+		HTTPRequest: &request,
+		PhotoID:     id,
+	}
+
+	s.repository.On("PhotoByID", ctx, data.PhotoID).Return(&ent.Photo{ID: id, Etag: "hash"}, nil)
+
+	handlerFunc := s.handler.DownloadPhotoFunc(s.repository, s.blobStore)
+
+	resp := handlerFunc.Handle(data, nil)
+
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusNotModified, responseRecorder.Code)
+
+	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
+}
+
+func (s *PhotoTestSuite) TestPhoto_DeletePhoto_Forbidden() {
+	t := s.T()
+	request := http.Request{}
+	data := photos.DeletePhotoParams{
+		HTTPRequest: &request,
+		PhotoID:     "testimagename",
+	}
+
+	handlerFunc := s.handler.DeletePhotoFunc(s.repository, s.blobStore)
+	resp := handlerFunc.Handle(data, &models.Principal{ID: 1, Role: roles.User})
+
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusForbidden, responseRecorder.Code)
+
+	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
 }
 
 func (s *PhotoTestSuite) TestPhoto_DeletePhoto_NotExists() {
@@ -318,9 +769,9 @@ func (s *PhotoTestSuite) TestPhoto_DeletePhoto_NotExists() {
 	errorToReturn := errors.New("failed to delete photo")
 	s.repository.On("PhotoByID", ctx, data.PhotoID).Return(nil, errorToReturn)
 
-	handlerFunc := s.handler.DeletePhotoFunc(s.repository)
+	handlerFunc := s.handler.DeletePhotoFunc(s.repository, s.blobStore)
 
-	resp := handlerFunc.Handle(data, nil)
+	resp := handlerFunc.Handle(data, &models.Principal{Role: roles.Admin})
 
 	responseRecorder := httptest.NewRecorder()
 	producer := runtime.JSONProducer()
@@ -337,6 +788,7 @@ func (s *PhotoTestSuite) TestPhoto_DeletePhoto_NotExists() {
 	require.Equal(t, errorToReturn.Error(), *response.Message)
 
 	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
 }
 
 func (s *PhotoTestSuite) TestPhoto_DeletePhoto_OK() {
@@ -355,17 +807,53 @@ func (s *PhotoTestSuite) TestPhoto_DeletePhoto_OK() {
 		ID:       id,
 		FileName: fileName,
 	}, nil)
+	s.repository.On("PhotoInUse", ctx, data.PhotoID).Return(false, nil)
 	s.repository.On("DeletePhotoByID", ctx, data.PhotoID).Return(nil)
+	for _, key := range images.Keys(id) {
+		s.blobStore.On("Delete", ctx, key).Return(nil)
+	}
 
-	handlerFunc := s.handler.DeletePhotoFunc(s.repository)
+	handlerFunc := s.handler.DeletePhotoFunc(s.repository, s.blobStore)
 
-	resp := handlerFunc.Handle(data, nil)
+	resp := handlerFunc.Handle(data, &models.Principal{Role: roles.Admin})
 
 	responseRecorder := httptest.NewRecorder()
 	producer := runtime.JSONProducer()
 	resp.WriteResponse(responseRecorder, producer)
 	require.Equal(t, http.StatusOK, responseRecorder.Code)
 	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
+}
+
+func (s *PhotoTestSuite) TestPhoto_DeletePhoto_InUse() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	id := "testimagename"
+
+	data := photos.DeletePhotoParams{
+		HTTPRequest: &request,
+		PhotoID:     id,
+	}
+	s.repository.On("PhotoByID", ctx, data.PhotoID).Return(&ent.Photo{ID: id}, nil)
+	s.repository.On("PhotoInUse", ctx, data.PhotoID).Return(true, nil)
+
+	handlerFunc := s.handler.DeletePhotoFunc(s.repository, s.blobStore)
+
+	resp := handlerFunc.Handle(data, &models.Principal{Role: roles.Admin})
+
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusConflict, responseRecorder.Code)
+
+	response := models.SwaggerError{}
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &response))
+	require.Contains(t, *response.Message, "attached to equipment")
+
+	s.repository.AssertExpectations(t)
+	s.blobStore.AssertExpectations(t)
 }
 
 func createNonEmptyFile(name string, content []byte) error {
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/users"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+func SetTwoFactorHandler(logger *zap.Logger, api *operations.BeAPI,
+	tokenManager domain.TokenManager, twoFactorService domain.TwoFactorService) {
+	twoFactorHandler := NewTwoFactor(logger, twoFactorService)
+
+	api.UsersLoginTwoFactorHandler = twoFactorHandler.LoginTwoFactorFunc(tokenManager)
+	api.UsersEnrollTwoFactorHandler = twoFactorHandler.EnrollFunc()
+	api.UsersActivateTwoFactorHandler = twoFactorHandler.ActivateFunc()
+	api.UsersDisableTwoFactorHandler = twoFactorHandler.DisableFunc()
+	api.UsersRegenerateRecoveryCodesHandler = twoFactorHandler.RegenerateRecoveryCodesFunc()
+}
+
+type TwoFactor struct {
+	logger    *zap.Logger
+	twoFactor domain.TwoFactorService
+}
+
+func NewTwoFactor(logger *zap.Logger, twoFactorService domain.TwoFactorService) *TwoFactor {
+	return &TwoFactor{
+		logger:    logger,
+		twoFactor: twoFactorService,
+	}
+}
+
+func (c TwoFactor) LoginTwoFactorFunc(tokenManager domain.TokenManager) users.LoginTwoFactorHandlerFunc {
+	return func(p users.LoginTwoFactorParams) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		userID, err := c.twoFactor.VerifyChallenge(ctx, *p.Data.ChallengeToken, *p.Data.Code)
+		if err != nil {
+			if errors.Is(err, domain.ErrInvalidTwoFactorCode) || ent.IsNotFound(err) {
+				return users.NewLoginTwoFactorUnauthorized().
+					WithPayload(buildErrorPayload(http.StatusUnauthorized, messages.ErrInvalidTwoFactorCode, ""))
+			}
+			c.logger.Error("error while verifying two-factor challenge", zap.Error(err))
+			return users.NewLoginTwoFactorDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrInvalidTwoFactorCode, err.Error()))
+		}
+
+		accessToken, refreshToken, isInternalErr, err := tokenManager.GenerateTokensByUserID(ctx, userID)
+		if err != nil {
+			if isInternalErr {
+				c.logger.Error("error while generating tokens", zap.Error(err))
+				return users.NewLoginTwoFactorDefault(http.StatusInternalServerError)
+			}
+			return users.NewLoginTwoFactorUnauthorized().
+				WithPayload(buildErrorPayload(http.StatusUnauthorized, messages.ErrInvalidTwoFactorCode, ""))
+		}
+
+		return users.NewLoginTwoFactorOK().WithPayload(&models.TokenPair{
+			AccessToken:  &accessToken,
+			RefreshToken: &refreshToken,
+		})
+	}
+}
+
+func (c TwoFactor) EnrollFunc() users.EnrollTwoFactorHandlerFunc {
+	return func(p users.EnrollTwoFactorParams, principal *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		enrollment, err := c.twoFactor.Enroll(ctx, int(principal.ID))
+		if err != nil {
+			if errors.Is(err, domain.ErrTwoFactorEnabled) {
+				return users.NewEnrollTwoFactorConflict().
+					WithPayload(buildConflictErrorPayload(messages.ErrTwoFactorAlreadyEnabled, ""))
+			}
+			c.logger.Error(messages.ErrTwoFactorEnroll, zap.Error(err))
+			return users.NewEnrollTwoFactorDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrTwoFactorEnroll, err.Error()))
+		}
+
+		return users.NewEnrollTwoFactorOK().WithPayload(&models.TwoFactorEnrollment{
+			Secret:          &enrollment.Secret,
+			ProvisioningURI: &enrollment.ProvisioningURI,
+		})
+	}
+}
+
+func (c TwoFactor) ActivateFunc() users.ActivateTwoFactorHandlerFunc {
+	return func(p users.ActivateTwoFactorParams, principal *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		if p.Data == nil || p.Data.Code == nil || *p.Data.Code == "" {
+			return users.NewActivateTwoFactorBadRequest().
+				WithPayload(buildBadRequestErrorPayload(messages.ErrTwoFactorCodeEmpty, ""))
+		}
+
+		recoveryCodes, err := c.twoFactor.Activate(ctx, int(principal.ID), *p.Data.Code)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrTwoFactorEnabled):
+				return users.NewActivateTwoFactorConflict().
+					WithPayload(buildConflictErrorPayload(messages.ErrTwoFactorAlreadyEnabled, ""))
+			case errors.Is(err, domain.ErrTwoFactorNotEnrolled):
+				return users.NewActivateTwoFactorBadRequest().
+					WithPayload(buildBadRequestErrorPayload(messages.ErrTwoFactorNotEnrolled, ""))
+			case errors.Is(err, domain.ErrInvalidTwoFactorCode):
+				return users.NewActivateTwoFactorBadRequest().
+					WithPayload(buildBadRequestErrorPayload(messages.ErrInvalidTwoFactorCode, ""))
+			}
+			c.logger.Error(messages.ErrTwoFactorActivate, zap.Error(err))
+			return users.NewActivateTwoFactorDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrTwoFactorActivate, err.Error()))
+		}
+
+		return users.NewActivateTwoFactorOK().WithPayload(&models.TwoFactorRecoveryCodes{
+			RecoveryCodes: recoveryCodes,
+		})
+	}
+}
+
+func (c TwoFactor) DisableFunc() users.DisableTwoFactorHandlerFunc {
+	return func(p users.DisableTwoFactorParams, principal *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		if p.Data == nil || p.Data.Code == nil || *p.Data.Code == "" {
+			return users.NewDisableTwoFactorBadRequest().
+				WithPayload(buildBadRequestErrorPayload(messages.ErrTwoFactorCodeEmpty, ""))
+		}
+
+		err := c.twoFactor.Disable(ctx, int(principal.ID), *p.Data.Code)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrTwoFactorMandatory):
+				return users.NewDisableTwoFactorForbidden().
+					WithPayload(buildForbiddenErrorPayload(messages.ErrTwoFactorMandatory, ""))
+			case errors.Is(err, domain.ErrTwoFactorNotEnrolled):
+				return users.NewDisableTwoFactorBadRequest().
+					WithPayload(buildBadRequestErrorPayload(messages.ErrTwoFactorNotEnrolled, ""))
+			case errors.Is(err, domain.ErrInvalidTwoFactorCode):
+				return users.NewDisableTwoFactorBadRequest().
+					WithPayload(buildBadRequestErrorPayload(messages.ErrInvalidTwoFactorCode, ""))
+			}
+			c.logger.Error(messages.ErrTwoFactorDisable, zap.Error(err))
+			return users.NewDisableTwoFactorDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrTwoFactorDisable, err.Error()))
+		}
+
+		return users.NewDisableTwoFactorNoContent()
+	}
+}
+
+func (c TwoFactor) RegenerateRecoveryCodesFunc() users.RegenerateRecoveryCodesHandlerFunc {
+	return func(p users.RegenerateRecoveryCodesParams, principal *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		if p.Data == nil || p.Data.Code == nil || *p.Data.Code == "" {
+			return users.NewRegenerateRecoveryCodesBadRequest().
+				WithPayload(buildBadRequestErrorPayload(messages.ErrTwoFactorCodeEmpty, ""))
+		}
+
+		recoveryCodes, err := c.twoFactor.RegenerateRecoveryCodes(ctx, int(principal.ID), *p.Data.Code)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrTwoFactorNotEnrolled):
+				return users.NewRegenerateRecoveryCodesBadRequest().
+					WithPayload(buildBadRequestErrorPayload(messages.ErrTwoFactorNotEnrolled, ""))
+			case errors.Is(err, domain.ErrInvalidTwoFactorCode):
+				return users.NewRegenerateRecoveryCodesBadRequest().
+					WithPayload(buildBadRequestErrorPayload(messages.ErrInvalidTwoFactorCode, ""))
+			}
+			c.logger.Error(messages.ErrTwoFactorRecoveryCodes, zap.Error(err))
+			return users.NewRegenerateRecoveryCodesDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrTwoFactorRecoveryCodes, err.Error()))
+		}
+
+		return users.NewRegenerateRecoveryCodesOK().WithPayload(&models.TwoFactorRecoveryCodes{
+			RecoveryCodes: recoveryCodes,
+		})
+	}
+}
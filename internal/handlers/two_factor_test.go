@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/runtime"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/mocks"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/users"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+func TestSetTwoFactorHandler(t *testing.T) {
+	logger := zap.NewNop()
+	swaggerSpec, err := loads.Analyzed(restapi.SwaggerJSON, "")
+	require.NoError(t, err)
+	api := operations.NewBeAPI(swaggerSpec)
+	SetTwoFactorHandler(logger, api, &mocks.TokenManager{}, &mocks.TwoFactorService{})
+
+	require.NotEmpty(t, api.UsersLoginTwoFactorHandler)
+	require.NotEmpty(t, api.UsersEnrollTwoFactorHandler)
+	require.NotEmpty(t, api.UsersActivateTwoFactorHandler)
+	require.NotEmpty(t, api.UsersDisableTwoFactorHandler)
+	require.NotEmpty(t, api.UsersRegenerateRecoveryCodesHandler)
+}
+
+type TwoFactorTestSuite struct {
+	suite.Suite
+	tokenManager     *mocks.TokenManager
+	twoFactorService *mocks.TwoFactorService
+	handler          *TwoFactor
+}
+
+func TestTwoFactorSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorTestSuite))
+}
+
+func (s *TwoFactorTestSuite) SetupTest() {
+	s.tokenManager = &mocks.TokenManager{}
+	s.twoFactorService = &mocks.TwoFactorService{}
+	s.handler = NewTwoFactor(zap.NewNop(), s.twoFactorService)
+}
+
+func (s *TwoFactorTestSuite) TestTwoFactor_LoginTwoFactor_InvalidCode() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	challenge, code := "challenge", "123456"
+	s.twoFactorService.On("VerifyChallenge", ctx, challenge, code).Return(0, domain.ErrInvalidTwoFactorCode)
+
+	resp := s.handler.LoginTwoFactorFunc(s.tokenManager)(users.LoginTwoFactorParams{
+		HTTPRequest: &request,
+		Data:        &models.TwoFactorLoginRequest{ChallengeToken: &challenge, Code: &code},
+	})
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusUnauthorized, responseRecorder.Code)
+	s.twoFactorService.AssertExpectations(t)
+	s.tokenManager.AssertExpectations(t)
+}
+
+func (s *TwoFactorTestSuite) TestTwoFactor_LoginTwoFactor_ServiceErr() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	challenge, code := "challenge", "123456"
+	s.twoFactorService.On("VerifyChallenge", ctx, challenge, code).Return(0, errors.New("error"))
+
+	resp := s.handler.LoginTwoFactorFunc(s.tokenManager)(users.LoginTwoFactorParams{
+		HTTPRequest: &request,
+		Data:        &models.TwoFactorLoginRequest{ChallengeToken: &challenge, Code: &code},
+	})
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+	s.twoFactorService.AssertExpectations(t)
+}
+
+func (s *TwoFactorTestSuite) TestTwoFactor_LoginTwoFactor_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	challenge, code := "challenge", "123456"
+	userID := 1
+	s.twoFactorService.On("VerifyChallenge", ctx, challenge, code).Return(userID, nil)
+	s.tokenManager.On("GenerateTokensByUserID", ctx, userID).Return("access", "refresh", false, nil)
+
+	resp := s.handler.LoginTwoFactorFunc(s.tokenManager)(users.LoginTwoFactorParams{
+		HTTPRequest: &request,
+		Data:        &models.TwoFactorLoginRequest{ChallengeToken: &challenge, Code: &code},
+	})
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var tokenPair models.TokenPair
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &tokenPair))
+	require.Equal(t, "access", *tokenPair.AccessToken)
+	require.Equal(t, "refresh", *tokenPair.RefreshToken)
+	s.twoFactorService.AssertExpectations(t)
+	s.tokenManager.AssertExpectations(t)
+}
+
+func (s *TwoFactorTestSuite) TestTwoFactor_Enroll_AlreadyEnabled() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	s.twoFactorService.On("Enroll", ctx, 1).Return(nil, domain.ErrTwoFactorEnabled)
+
+	resp := s.handler.EnrollFunc()(users.EnrollTwoFactorParams{HTTPRequest: &request}, &models.Principal{ID: 1})
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusConflict, responseRecorder.Code)
+	s.twoFactorService.AssertExpectations(t)
+}
+
+func (s *TwoFactorTestSuite) TestTwoFactor_Enroll_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	enrollment := &domain.TwoFactorEnrollment{Secret: "secret", ProvisioningURI: "otpauth://totp/CSR:login"}
+	s.twoFactorService.On("Enroll", ctx, 1).Return(enrollment, nil)
+
+	resp := s.handler.EnrollFunc()(users.EnrollTwoFactorParams{HTTPRequest: &request}, &models.Principal{ID: 1})
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var result models.TwoFactorEnrollment
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &result))
+	require.Equal(t, enrollment.Secret, *result.Secret)
+	require.Equal(t, enrollment.ProvisioningURI, *result.ProvisioningURI)
+	s.twoFactorService.AssertExpectations(t)
+}
+
+func (s *TwoFactorTestSuite) TestTwoFactor_Activate_EmptyCode() {
+	t := s.T()
+	request := http.Request{}
+	code := ""
+
+	resp := s.handler.ActivateFunc()(users.ActivateTwoFactorParams{
+		HTTPRequest: &request,
+		Data:        &models.TwoFactorCode{Code: &code},
+	}, &models.Principal{ID: 1})
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	s.twoFactorService.AssertExpectations(t)
+}
+
+func (s *TwoFactorTestSuite) TestTwoFactor_Activate_InvalidCode() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	code := "000000"
+	s.twoFactorService.On("Activate", ctx, 1, code).Return(nil, domain.ErrInvalidTwoFactorCode)
+
+	resp := s.handler.ActivateFunc()(users.ActivateTwoFactorParams{
+		HTTPRequest: &request,
+		Data:        &models.TwoFactorCode{Code: &code},
+	}, &models.Principal{ID: 1})
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	s.twoFactorService.AssertExpectations(t)
+}
+
+func (s *TwoFactorTestSuite) TestTwoFactor_Activate_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	code := "123456"
+	recoveryCodes := []string{"aaaaa-bbbbb", "ccccc-ddddd"}
+	s.twoFactorService.On("Activate", ctx, 1, code).Return(recoveryCodes, nil)
+
+	resp := s.handler.ActivateFunc()(users.ActivateTwoFactorParams{
+		HTTPRequest: &request,
+		Data:        &models.TwoFactorCode{Code: &code},
+	}, &models.Principal{ID: 1})
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var result models.TwoFactorRecoveryCodes
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &result))
+	require.Equal(t, recoveryCodes, result.RecoveryCodes)
+	s.twoFactorService.AssertExpectations(t)
+}
+
+func (s *TwoFactorTestSuite) TestTwoFactor_Disable_Mandatory() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	code := "123456"
+	s.twoFactorService.On("Disable", ctx, 1, code).Return(domain.ErrTwoFactorMandatory)
+
+	resp := s.handler.DisableFunc()(users.DisableTwoFactorParams{
+		HTTPRequest: &request,
+		Data:        &models.TwoFactorCode{Code: &code},
+	}, &models.Principal{ID: 1})
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	s.twoFactorService.AssertExpectations(t)
+}
+
+func (s *TwoFactorTestSuite) TestTwoFactor_Disable_OK() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	code := "123456"
+	s.twoFactorService.On("Disable", ctx, 1, code).Return(nil)
+
+	resp := s.handler.DisableFunc()(users.DisableTwoFactorParams{
+		HTTPRequest: &request,
+		Data:        &models.TwoFactorCode{Code: &code},
+	}, &models.Principal{ID: 1})
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusNoContent, responseRecorder.Code)
+	s.twoFactorService.AssertExpectations(t)
+}
+
+func (s *TwoFactorTestSuite) TestTwoFactor_RegenerateRecoveryCodes_NotEnrolled() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	code := "123456"
+	s.twoFactorService.On("RegenerateRecoveryCodes", ctx, 1, code).Return(nil, domain.ErrTwoFactorNotEnrolled)
+
+	resp := s.handler.RegenerateRecoveryCodesFunc()(users.RegenerateRecoveryCodesParams{
+		HTTPRequest: &request,
+		Data:        &models.TwoFactorCode{Code: &code},
+	}, &models.Principal{ID: 1})
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	s.twoFactorService.AssertExpectations(t)
+}
+
+func (s *TwoFactorTestSuite) TestTwoFactor_RegenerateRecoveryCodes_Err() {
+	t := s.T()
+	request := http.Request{}
+	ctx := context.Background()
+	code := "123456"
+	s.twoFactorService.On("RegenerateRecoveryCodes", ctx, 1, code).Return(nil, errors.New("error"))
+
+	resp := s.handler.RegenerateRecoveryCodesFunc()(users.RegenerateRecoveryCodesParams{
+		HTTPRequest: &request,
+		Data:        &models.TwoFactorCode{Code: &code},
+	}, &models.Principal{ID: 1})
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+	s.twoFactorService.AssertExpectations(t)
+}
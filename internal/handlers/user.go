@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
@@ -16,31 +20,37 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/users"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/policies"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/repositories"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
 )
 
 func SetUserHandler(logger *zap.Logger, api *operations.BeAPI,
 	tokenManager domain.TokenManager,
-	regConfirmService domain.RegistrationConfirmService, changeEmailService domain.ChangeEmailService) {
+	regConfirmService domain.RegistrationConfirmService, changeEmailService domain.ChangeEmailService,
+	twoFactorService domain.TwoFactorService, passwordPolicy domain.PasswordPolicy,
+	userExportService domain.UserExportService, retentionPeriod time.Duration) {
 	userRepo := repositories.NewUserRepository()
 	userHandler := NewUser(logger)
 
-	api.UsersLoginHandler = userHandler.LoginUserFunc(tokenManager)
+	api.UsersLoginHandler = userHandler.LoginUserFunc(tokenManager, twoFactorService)
 	api.UsersRefreshHandler = userHandler.Refresh(tokenManager)
-	api.UsersPostUserHandler = userHandler.PostUserFunc(userRepo, regConfirmService)
+	api.UsersPostUserHandler = userHandler.PostUserFunc(userRepo, regConfirmService, passwordPolicy)
 	api.UsersGetCurrentUserHandler = userHandler.GetUserFunc(userRepo)
 	api.UsersPatchUserHandler = userHandler.PatchUserFunc(userRepo)
 	api.UsersGetUserHandler = userHandler.GetUserById(userRepo)
 	api.UsersGetAllUsersHandler = userHandler.GetUsersList(userRepo)
 	api.UsersAssignRoleToUserHandler = userHandler.AssignRoleToUserFunc(userRepo)
-	api.UsersChangePasswordHandler = userHandler.ChangePassword(userRepo)
+	api.UsersChangePasswordHandler = userHandler.ChangePassword(userRepo, passwordPolicy)
 	api.UsersLogoutHandler = userHandler.LogoutUserFunc(tokenManager)
 	api.UsersDeleteCurrentUserHandler = userHandler.DeleteCurrentUser(userRepo)
 	api.UsersDeleteUserHandler = userHandler.DeleteUser(userRepo)
+	api.UsersRestoreUserHandler = userHandler.RestoreUser(userRepo, retentionPeriod)
 	api.UsersUpdateReadonlyAccessHandler = userHandler.UpdateReadonlyAccess(userRepo)
 	api.UsersChangeEmailHandler = userHandler.ChangeEmail(userRepo, changeEmailService)
+	api.UsersExportCurrentUserHandler = userHandler.ExportCurrentUser(userExportService)
 }
 
 type User struct {
@@ -53,12 +63,23 @@ func NewUser(logger *zap.Logger) *User {
 	}
 }
 
-func (c User) LoginUserFunc(service domain.TokenManager) users.LoginHandlerFunc {
+func (c User) LoginUserFunc(service domain.TokenManager, twoFactorService domain.TwoFactorService) users.LoginHandlerFunc {
 	return func(p users.LoginParams) middleware.Responder {
 		ctx := p.HTTPRequest.Context()
 		login := *p.Login.Login
 		password := *p.Login.Password
 		accessToken, refreshToken, isInternalErr, err := service.GenerateTokens(ctx, login, password)
+		if errors.Is(err, domain.ErrTwoFactorRequired) {
+			challengeToken, errChallenge := twoFactorService.IssueChallenge(ctx, login)
+			if errChallenge != nil {
+				c.logger.Error(messages.ErrTwoFactorChallenge, zap.Error(errChallenge))
+				return users.NewLoginDefault(http.StatusInternalServerError).
+					WithPayload(buildInternalErrorPayload(messages.ErrTwoFactorChallenge, ""))
+			}
+			return users.NewLoginAccepted().WithPayload(&models.TwoFactorChallenge{
+				ChallengeToken: &challengeToken,
+			})
+		}
 		if err != nil {
 			if isInternalErr {
 				return users.NewLoginDefault(http.StatusInternalServerError)
@@ -88,9 +109,15 @@ func (c User) LogoutUserFunc(tokenManager domain.TokenManager) users.LogoutHandl
 	}
 }
 
-func (c User) PostUserFunc(repository domain.UserRepository, regConfirmService domain.RegistrationConfirmService) users.PostUserHandlerFunc {
+func (c User) PostUserFunc(repository domain.UserRepository, regConfirmService domain.RegistrationConfirmService,
+	passwordPolicy domain.PasswordPolicy) users.PostUserHandlerFunc {
 	return func(p users.PostUserParams) middleware.Responder {
 		ctx := p.HTTPRequest.Context()
+		violations := passwordPolicy.Validate(*p.Data.Password, *p.Data.Login, p.Data.Email.String())
+		if len(violations) > 0 {
+			return users.NewPostUserDefault(http.StatusBadRequest).
+				WithPayload(buildBadRequestErrorPayload(messages.ErrPasswordPolicy, strings.Join(violations, "; ")))
+		}
 		createdUser, err := repository.CreateUser(ctx, p.Data)
 		if err != nil {
 			if ent.IsConstraintError(err) {
@@ -162,6 +189,42 @@ func (c User) GetUserFunc(repository domain.UserRepository) users.GetCurrentUser
 	}
 }
 
+// ExportCurrentUser collects the data before writing anything, so a failure is still reported as the error payload.
+func (c User) ExportCurrentUser(service domain.UserExportService) users.ExportCurrentUserHandlerFunc {
+	return func(p users.ExportCurrentUserParams, principal *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		userID := int(principal.ID)
+
+		export, err := service.Export(ctx, userID)
+		if err != nil {
+			c.logger.Error(messages.ErrExportUser, zap.Error(err))
+			return users.NewExportCurrentUserDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrExportUser, ""))
+		}
+
+		format := domain.UserExportFormatJSON
+		if p.Format != nil {
+			format = *p.Format
+		}
+		return middleware.ResponderFunc(func(w http.ResponseWriter, _ runtime.Producer) {
+			fileName := fmt.Sprintf("user_%d_export.%s", userID, format)
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+			if format == domain.UserExportFormatZIP {
+				w.Header().Set("Content-Type", "application/zip")
+				w.WriteHeader(http.StatusOK)
+				err = service.WriteZIP(w, export)
+			} else {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				err = json.NewEncoder(w).Encode(export)
+			}
+			if err != nil {
+				c.logger.Error("error while writing user export", zap.Error(err))
+			}
+		})
+	}
+}
+
 func (c User) PatchUserFunc(repository domain.UserRepository) users.PatchUserHandlerFunc {
 	return func(p users.PatchUserParams, principal *models.Principal) middleware.Responder {
 		ctx := p.HTTPRequest.Context()
@@ -182,6 +245,11 @@ func (c User) AssignRoleToUserFunc(repository domain.UserRepository) users.Assig
 
 		ctx := p.HTTPRequest.Context()
 		userId := int(p.UserID)
+		if !policies.Can(principal, policies.Grant, policies.Resource{Kind: policies.User, OwnerID: userId}) {
+			c.logger.Warn(messages.ErrUserForbidden, zap.Any("principal", principal), zap.Int("userID", userId))
+			return users.NewAssignRoleToUserDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrUserForbidden, ""))
+		}
 		if p.Data.RoleID == nil {
 			return users.NewAssignRoleToUserDefault(http.StatusBadRequest).
 				WithPayload(buildInternalErrorPayload(messages.ErrRoleRequired, ""))
@@ -203,6 +271,11 @@ func (c User) GetUserById(repository domain.UserRepository) users.GetUserHandler
 	return func(p users.GetUserParams, principal *models.Principal) middleware.Responder {
 		ctx := p.HTTPRequest.Context()
 		id := int(p.UserID)
+		if !policies.Can(principal, policies.View, policies.Resource{Kind: policies.User, OwnerID: id}) {
+			c.logger.Warn(messages.ErrUserForbidden, zap.Any("principal", principal), zap.Int("userID", id))
+			return users.NewGetUserDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrUserForbidden, ""))
+		}
 		foundUser, err := repository.GetUserByID(ctx, id)
 		if err != nil {
 			return users.NewGetUserDefault(http.StatusInternalServerError).
@@ -215,19 +288,36 @@ func (c User) GetUserById(repository domain.UserRepository) users.GetUserHandler
 			return users.NewGetUserDefault(http.StatusInternalServerError).
 				WithPayload(buildInternalErrorPayload(messages.ErrMapUser, err.Error()))
 		}
+		maskPassport(principal, userToResponse)
 
 		return users.NewGetUserOK().WithPayload(userToResponse)
 	}
 }
 
 func (c User) GetUsersList(repository domain.UserRepository) users.GetAllUsersHandlerFunc {
-	return func(p users.GetAllUsersParams, _ *models.Principal) middleware.Responder {
+	return func(p users.GetAllUsersParams, principal *models.Principal) middleware.Responder {
 		ctx := p.HTTPRequest.Context()
-		limit := utils.GetValueByPointerOrDefaultValue(p.Limit, math.MaxInt)
-		offset := utils.GetValueByPointerOrDefaultValue(p.Offset, 0)
-		orderBy := utils.GetValueByPointerOrDefaultValue(p.OrderBy, utils.AscOrder)
-		orderColumn := utils.GetValueByPointerOrDefaultValue(p.OrderColumn, user.FieldID)
-		total, err := repository.UsersListTotal(ctx)
+		userFilter := domain.UserFilter{
+			Filter: domain.Filter{
+				Limit:       int(utils.GetValueByPointerOrDefaultValue(p.Limit, math.MaxInt)),
+				Offset:      int(utils.GetValueByPointerOrDefaultValue(p.Offset, 0)),
+				OrderBy:     utils.GetValueByPointerOrDefaultValue(p.OrderBy, utils.AscOrder),
+				OrderColumn: utils.GetValueByPointerOrDefaultValue(p.OrderColumn, user.FieldID),
+			},
+			Login:                   p.Login,
+			Email:                   p.Email,
+			Search:                  p.Search,
+			Role:                    p.Role,
+			Type:                    p.Type,
+			IsReadonly:              p.IsReadonly,
+			IsRegistrationConfirmed: p.IsRegistrationConfirmed,
+			IsDeleted:               p.IsDeleted,
+		}
+		if p.ActiveArea != nil {
+			activeAreaID := int(*p.ActiveArea)
+			userFilter.ActiveAreaID = &activeAreaID
+		}
+		total, err := repository.UsersListTotal(ctx, userFilter)
 		if err != nil {
 			c.logger.Error(messages.ErrQueryTotalUsers, zap.Error(err))
 			return users.NewGetAllUsersDefault(http.StatusInternalServerError).
@@ -235,7 +325,7 @@ func (c User) GetUsersList(repository domain.UserRepository) users.GetAllUsersHa
 		}
 		var allUsers []*ent.User
 		if total > 0 {
-			allUsers, err = repository.UserList(ctx, int(limit), int(offset), orderBy, orderColumn)
+			allUsers, err = repository.UserList(ctx, userFilter)
 			if err != nil {
 				c.logger.Error(messages.ErrQueryUsers, zap.Error(err))
 				return users.NewGetAllUsersDefault(http.StatusInternalServerError).
@@ -251,6 +341,7 @@ func (c User) GetUsersList(repository domain.UserRepository) users.GetAllUsersHa
 				return users.NewGetAllUsersDefault(http.StatusInternalServerError).
 					WithPayload(buildInternalErrorPayload(messages.ErrMapUser, errMap.Error()))
 			}
+			maskPassport(principal, userToResponse)
 			usersToResponse[i] = userToResponse
 		}
 		totalUsers := int64(total)
@@ -284,6 +375,11 @@ func (c User) DeleteUser(repo domain.UserRepository) users.DeleteUserHandlerFunc
 		ctx := p.HTTPRequest.Context()
 		userID := int(p.UserID)
 		deletedByUserID := int(principal.ID)
+		if !policies.Can(principal, policies.Delete, policies.Resource{Kind: policies.User, OwnerID: userID}) {
+			c.logger.Warn(messages.ErrUserForbidden, zap.Any("principal", principal), zap.Int("userID", userID))
+			return users.NewDeleteUserDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrUserForbidden, ""))
+		}
 
 		user, err := repo.GetUserByID(ctx, userID)
 		if err != nil {
@@ -317,7 +413,40 @@ func (c User) DeleteUser(repo domain.UserRepository) users.DeleteUserHandlerFunc
 	}
 }
 
-func (c User) ChangePassword(repo domain.UserRepository) users.ChangePasswordHandlerFunc {
+// RestoreUser undoes the deletion until the retention period is over and the personal data is anonymised.
+func (c User) RestoreUser(repo domain.UserRepository, retentionPeriod time.Duration) users.RestoreUserHandlerFunc {
+	return func(p users.RestoreUserParams, principal *models.Principal) middleware.Responder {
+		ctx := p.HTTPRequest.Context()
+		userID := int(p.UserID)
+		if principal.Role != roles.Admin {
+			return users.NewRestoreUserForbidden().
+				WithPayload(buildForbiddenErrorPayload(messages.ErrRestoreUserForbidden, ""))
+		}
+
+		err := repo.Restore(ctx, userID, time.Now().Add(-retentionPeriod))
+		if err != nil {
+			switch {
+			case ent.IsNotFound(err):
+				return users.NewRestoreUserNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrUserNotFound, ""))
+			case errors.Is(err, domain.ErrUserNotDeleted):
+				return users.NewRestoreUserNotFound().
+					WithPayload(buildNotFoundErrorPayload(messages.ErrUserNotDeleted, ""))
+			case errors.Is(err, domain.ErrRestorePeriodExpired):
+				return users.NewRestoreUserConflict().
+					WithPayload(buildConflictErrorPayload(messages.ErrRestorePeriodExpired, ""))
+			}
+			c.logger.Error(messages.ErrRestoreUser, zap.Error(err))
+			return users.NewRestoreUserDefault(http.StatusInternalServerError).
+				WithPayload(buildInternalErrorPayload(messages.ErrRestoreUser, ""))
+		}
+
+		c.logger.Info("User restored", zap.Int("userID", userID), zap.Int64("restoredByUserID", principal.ID))
+		return users.NewRestoreUserNoContent()
+	}
+}
+
+func (c User) ChangePassword(repo domain.UserRepository, passwordPolicy domain.PasswordPolicy) users.ChangePasswordHandlerFunc {
 	return func(p users.ChangePasswordParams, principal *models.Principal) middleware.Responder {
 		ctx := p.HTTPRequest.Context()
 		userID := int(principal.ID)
@@ -327,7 +456,6 @@ func (c User) ChangePassword(repo domain.UserRepository) users.ChangePasswordHan
 			return users.NewChangePasswordDefault(http.StatusBadRequest).
 				WithPayload(buildBadRequestErrorPayload(messages.ErrPasswordPatchEmpty, ""))
 		}
-		//TODO: add validation for password or ask frontend to do it
 		if p.PasswordPatch.OldPassword == p.PasswordPatch.NewPassword {
 			c.logger.Error("old and new passwords are the same", zap.Any("principal", principal))
 			return users.NewChangePasswordDefault(http.StatusBadRequest).
@@ -345,6 +473,12 @@ func (c User) ChangePassword(repo domain.UserRepository) users.ChangePasswordHan
 			return users.NewChangePasswordDefault(http.StatusForbidden).
 				WithPayload(buildForbiddenErrorPayload(messages.ErrWrongPassword, ""))
 		}
+		violations := passwordPolicy.Validate(p.PasswordPatch.NewPassword, requestedUser.Login, requestedUser.Email)
+		if len(violations) > 0 {
+			c.logger.Info(messages.ErrPasswordPolicy, zap.Strings("violations", violations))
+			return users.NewChangePasswordDefault(http.StatusBadRequest).
+				WithPayload(buildBadRequestErrorPayload(messages.ErrPasswordPolicy, strings.Join(violations, "; ")))
+		}
 		if err = repo.ChangePasswordByLogin(ctx, requestedUser.Login, p.PasswordPatch.NewPassword); err != nil {
 			c.logger.Error("error while changing password", zap.Error(err))
 			return users.NewChangePasswordDefault(http.StatusInternalServerError).
@@ -360,6 +494,11 @@ func (c User) UpdateReadonlyAccess(repo domain.UserRepository) users.UpdateReado
 		currentUserID := int(principal.ID)
 		userID := int(p.UserID)
 		isReadonly := p.Body.IsReadonly
+		if !policies.Can(principal, policies.Grant, policies.Resource{Kind: policies.User, OwnerID: userID}) {
+			c.logger.Warn(messages.ErrUserForbidden, zap.Any("principal", principal), zap.Int("userID", userID))
+			return users.NewUpdateReadonlyAccessDefault(http.StatusForbidden).
+				WithPayload(buildForbiddenErrorPayload(messages.ErrUserForbidden, ""))
+		}
 
 		if err := repo.SetIsReadonly(ctx, userID, isReadonly); err != nil {
 			c.logger.Error(messages.ErrUpdateROAccess, zap.Error(err))
@@ -438,15 +577,41 @@ func mapUserInfoWoRole(user *ent.User) *models.UserInfo {
 		Login:                   &user.Login,
 		Name:                    user.Name,
 		OrgName:                 user.OrgName,
-		PassportAuthority:       user.PassportAuthority,
+		PassportAuthority:       utils.PointerOrNil(user.PassportAuthority),
 		PassportIssueDate:       &passportDate,
-		PassportNumber:          user.PassportNumber,
-		PassportSeries:          user.PassportSeries,
+		PassportNumber:          utils.PointerOrNil(user.PassportNumber),
+		PassportSeries:          utils.PointerOrNil(user.PassportSeries),
 		Patronymic:              user.Patronymic,
 		PhoneNumber:             user.Phone,
 		Surname:                 user.Surname,
 		Type:                    &typeString,
 		IsRegistrationConfirmed: &user.IsRegistrationConfirmed,
+		IsTwoFactorEnabled:      user.IsTotpEnabled,
 	}
 	return result
 }
+
+// maskPassport hides the passport data of the user from the principal who doesn't need it.
+func maskPassport(principal *models.Principal, info *models.UserInfo) {
+	resource := policies.Resource{Kind: policies.User, OwnerID: int(*info.ID)}
+	if policies.Can(principal, policies.ViewPassport, resource) {
+		return
+	}
+	info.PassportSeries = maskValue(info.PassportSeries, 0)
+	info.PassportNumber = maskValue(info.PassportNumber, 2)
+	info.PassportAuthority = maskValue(info.PassportAuthority, 0)
+	info.PassportIssueDate = nil
+}
+
+// maskValue replaces all but the last visible characters of the value.
+func maskValue(value *string, visible int) *string {
+	if value == nil {
+		return nil
+	}
+	runes := []rune(*value)
+	for i := 0; i < len(runes)-visible; i++ {
+		runes[i] = '*'
+	}
+	masked := string(runes)
+	return &masked
+}
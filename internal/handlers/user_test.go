@@ -8,10 +8,13 @@ import (
 	"math"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-openapi/loads"
 	"github.com/go-openapi/runtime"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
@@ -24,8 +27,10 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/restapi/operations/users"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
 )
 
 func TestSetUserHandler(t *testing.T) {
@@ -41,10 +46,12 @@ func TestSetUserHandler(t *testing.T) {
 	api := operations.NewBeAPI(swaggerSpec)
 	tokenManager := &mocks.TokenManager{}
 	registrationConfirm := &mocks.RegistrationConfirmService{}
-	SetUserHandler(logger, api, tokenManager, registrationConfirm, nil)
+	SetUserHandler(logger, api, tokenManager, registrationConfirm, nil, &mocks.TwoFactorService{},
+		&mocks.PasswordPolicy{}, &mocks.UserExportService{}, time.Hour)
 
 	require.NotEmpty(t, api.UsersLoginHandler)
 	require.NotEmpty(t, api.UsersRefreshHandler)
+	require.NotEmpty(t, api.UsersExportCurrentUserHandler)
 	require.NotEmpty(t, api.UsersPostUserHandler)
 	require.NotEmpty(t, api.UsersGetCurrentUserHandler)
 	require.NotEmpty(t, api.UsersPatchUserHandler)
@@ -52,6 +59,7 @@ func TestSetUserHandler(t *testing.T) {
 	require.NotEmpty(t, api.UsersGetAllUsersHandler)
 	require.NotEmpty(t, api.UsersAssignRoleToUserHandler)
 	require.NotEmpty(t, api.UsersDeleteCurrentUserHandler)
+	require.NotEmpty(t, api.UsersRestoreUserHandler)
 }
 
 type UserTestSuite struct {
@@ -62,6 +70,8 @@ type UserTestSuite struct {
 	userRepository      *mocks.UserRepository
 	changeEmailService  *mocks.ChangeEmailService
 	registrationConfirm *mocks.RegistrationConfirmService
+	twoFactorService    *mocks.TwoFactorService
+	passwordPolicy      *mocks.PasswordPolicy
 }
 
 func TestUserSuite(t *testing.T) {
@@ -74,6 +84,8 @@ func (s *UserTestSuite) SetupTest() {
 	s.registrationConfirm = &mocks.RegistrationConfirmService{}
 	s.userRepository = &mocks.UserRepository{}
 	s.changeEmailService = &mocks.ChangeEmailService{}
+	s.twoFactorService = &mocks.TwoFactorService{}
+	s.passwordPolicy = &mocks.PasswordPolicy{}
 	s.user = NewUser(s.logger)
 }
 
@@ -84,7 +96,7 @@ func (s *UserTestSuite) TestUser_LoginUserFunc_InternalErr() {
 
 	login := "login"
 	password := "password"
-	handlerFunc := s.user.LoginUserFunc(s.service)
+	handlerFunc := s.user.LoginUserFunc(s.service, s.twoFactorService)
 	data := users.LoginParams{
 		HTTPRequest: &request,
 		Login: &models.LoginInfo{
@@ -110,7 +122,7 @@ func (s *UserTestSuite) TestUser_LoginUserFunc_UnauthorizedErr() {
 
 	login := "login"
 	password := "password"
-	handlerFunc := s.user.LoginUserFunc(s.service)
+	handlerFunc := s.user.LoginUserFunc(s.service, s.twoFactorService)
 	data := users.LoginParams{
 		HTTPRequest: &request,
 		Login: &models.LoginInfo{
@@ -136,7 +148,7 @@ func (s *UserTestSuite) TestUser_LoginUserFunc_OK() {
 
 	login := "login"
 	password := "password"
-	handlerFunc := s.user.LoginUserFunc(s.service)
+	handlerFunc := s.user.LoginUserFunc(s.service, s.twoFactorService)
 	data := users.LoginParams{
 		HTTPRequest: &request,
 		Login: &models.LoginInfo{
@@ -165,6 +177,40 @@ func (s *UserTestSuite) TestUser_LoginUserFunc_OK() {
 	s.service.AssertExpectations(t)
 }
 
+func (s *UserTestSuite) TestUser_LoginUserFunc_TwoFactorRequired() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	login := "login"
+	password := "password"
+	handlerFunc := s.user.LoginUserFunc(s.service, s.twoFactorService)
+	data := users.LoginParams{
+		HTTPRequest: &request,
+		Login: &models.LoginInfo{
+			Login:    &login,
+			Password: &password,
+		},
+	}
+	challengeToken := "challenge"
+	s.service.On("GenerateTokens", ctx, login, password).Return("", "", false, domain.ErrTwoFactorRequired)
+	s.twoFactorService.On("IssueChallenge", ctx, login).Return(challengeToken, nil)
+
+	resp := handlerFunc(data)
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusAccepted, responseRecorder.Code)
+
+	var challenge models.TwoFactorChallenge
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &challenge)
+	require.NoError(t, err)
+	require.Equal(t, challengeToken, *challenge.ChallengeToken)
+
+	s.service.AssertExpectations(t)
+	s.twoFactorService.AssertExpectations(t)
+}
+
 func (s *UserTestSuite) TestUser_PostUserFunc_LoginExistErr() {
 	t := s.T()
 	request := http.Request{}
@@ -172,7 +218,7 @@ func (s *UserTestSuite) TestUser_PostUserFunc_LoginExistErr() {
 
 	login := "login"
 	password := "password"
-	handlerFunc := s.user.PostUserFunc(s.userRepository, s.registrationConfirm)
+	handlerFunc := s.user.PostUserFunc(s.userRepository, s.registrationConfirm, s.passwordPolicy)
 	data := users.PostUserParams{
 		HTTPRequest: &request,
 		Data: &models.UserRegister{
@@ -181,6 +227,7 @@ func (s *UserTestSuite) TestUser_PostUserFunc_LoginExistErr() {
 		},
 	}
 	err := &ent.ConstraintError{}
+	s.passwordPolicy.On("Validate", password, login, "").Return(nil)
 	s.userRepository.On("CreateUser", ctx, data.Data).Return(nil, err)
 
 	resp := handlerFunc(data)
@@ -191,6 +238,37 @@ func (s *UserTestSuite) TestUser_PostUserFunc_LoginExistErr() {
 	s.userRepository.AssertExpectations(t)
 }
 
+func (s *UserTestSuite) TestUser_PostUserFunc_PasswordPolicyErr() {
+	t := s.T()
+	request := http.Request{}
+
+	login := "login"
+	password := "password"
+	handlerFunc := s.user.PostUserFunc(s.userRepository, s.registrationConfirm, s.passwordPolicy)
+	data := users.PostUserParams{
+		HTTPRequest: &request,
+		Data: &models.UserRegister{
+			Login:    &login,
+			Password: &password,
+		},
+	}
+	violations := []string{messages.ErrPasswordNoDigit, messages.ErrPasswordTooCommon}
+	s.passwordPolicy.On("Validate", password, login, "").Return(violations)
+
+	resp := handlerFunc(data)
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	actual := &models.SwaggerError{}
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), actual)
+	require.NoError(t, err)
+	require.Equal(t, messages.ErrPasswordPolicy, *actual.Message)
+	require.Equal(t, strings.Join(violations, "; "), actual.Details)
+	s.passwordPolicy.AssertExpectations(t)
+	s.userRepository.AssertExpectations(t)
+}
+
 func (s *UserTestSuite) TestUser_PostUserFunc_RepoErr() {
 	t := s.T()
 	request := http.Request{}
@@ -198,7 +276,7 @@ func (s *UserTestSuite) TestUser_PostUserFunc_RepoErr() {
 
 	login := "login"
 	password := "password"
-	handlerFunc := s.user.PostUserFunc(s.userRepository, s.registrationConfirm)
+	handlerFunc := s.user.PostUserFunc(s.userRepository, s.registrationConfirm, s.passwordPolicy)
 	data := users.PostUserParams{
 		HTTPRequest: &request,
 		Data: &models.UserRegister{
@@ -207,6 +285,7 @@ func (s *UserTestSuite) TestUser_PostUserFunc_RepoErr() {
 		},
 	}
 	err := errors.New("some error")
+	s.passwordPolicy.On("Validate", password, login, "").Return(nil)
 	s.userRepository.On("CreateUser", ctx, data.Data).Return(nil, err)
 
 	resp := handlerFunc(data)
@@ -224,7 +303,7 @@ func (s *UserTestSuite) TestUser_PostUserFunc_RegConfirmErr() {
 
 	login := "login"
 	password := "password"
-	handlerFunc := s.user.PostUserFunc(s.userRepository, s.registrationConfirm)
+	handlerFunc := s.user.PostUserFunc(s.userRepository, s.registrationConfirm, s.passwordPolicy)
 	data := users.PostUserParams{
 		HTTPRequest: &request,
 		Data: &models.UserRegister{
@@ -236,6 +315,7 @@ func (s *UserTestSuite) TestUser_PostUserFunc_RegConfirmErr() {
 		ID:    1,
 		Login: login,
 	}
+	s.passwordPolicy.On("Validate", password, login, "").Return(nil)
 	s.userRepository.On("CreateUser", ctx, data.Data).Return(user, nil)
 	err := errors.New("some error")
 	s.registrationConfirm.On("SendConfirmationLink", ctx, login).Return(err)
@@ -255,7 +335,7 @@ func (s *UserTestSuite) TestUser_PostUserFunc_OK() {
 
 	login := "login"
 	password := "password"
-	handlerFunc := s.user.PostUserFunc(s.userRepository, s.registrationConfirm)
+	handlerFunc := s.user.PostUserFunc(s.userRepository, s.registrationConfirm, s.passwordPolicy)
 	data := users.PostUserParams{
 		HTTPRequest: &request,
 		Data: &models.UserRegister{
@@ -267,6 +347,7 @@ func (s *UserTestSuite) TestUser_PostUserFunc_OK() {
 		ID:    1,
 		Login: login,
 	}
+	s.passwordPolicy.On("Validate", password, login, "").Return(nil)
 	s.userRepository.On("CreateUser", ctx, data.Data).Return(user, nil)
 	s.registrationConfirm.On("SendConfirmationLink", ctx, login).Return(nil)
 
@@ -570,7 +651,8 @@ func (s *UserTestSuite) TestUser_GetUsersList_RepositoryErr() {
 		HTTPRequest: &request,
 	}
 	err := errors.New("some err")
-	s.userRepository.On("UsersListTotal", ctx).Return(0, err)
+	filter := usersListFilter(math.MaxInt, 0, utils.AscOrder, user.FieldID)
+	s.userRepository.On("UsersListTotal", ctx, filter).Return(0, err)
 
 	resp := handlerFunc(data, nil)
 	responseRecorder := httptest.NewRecorder()
@@ -599,8 +681,9 @@ func (s *UserTestSuite) TestUser_GetUsersList_MapErr() {
 			ID: 1,
 		},
 	}
-	s.userRepository.On("UsersListTotal", ctx).Return(1, nil)
-	s.userRepository.On("UserList", ctx, limit, offset, orderBy, orderColumn).
+	filter := usersListFilter(limit, offset, orderBy, orderColumn)
+	s.userRepository.On("UsersListTotal", ctx, filter).Return(1, nil)
+	s.userRepository.On("UserList", ctx, filter).
 		Return(usersToReturn, nil)
 
 	resp := handlerFunc(data, nil)
@@ -621,7 +704,8 @@ func (s *UserTestSuite) TestUser_GetUsersList_NotFound() {
 	data := users.GetAllUsersParams{
 		HTTPRequest: &request,
 	}
-	s.userRepository.On("UsersListTotal", ctx).Return(0, nil)
+	filter := usersListFilter(math.MaxInt, 0, utils.AscOrder, user.FieldID)
+	s.userRepository.On("UsersListTotal", ctx, filter).Return(0, nil)
 
 	resp := handlerFunc(data, nil)
 	responseRecorder := httptest.NewRecorder()
@@ -639,6 +723,42 @@ func (s *UserTestSuite) TestUser_GetUsersList_NotFound() {
 	s.userRepository.AssertExpectations(t)
 }
 
+func (s *UserTestSuite) TestUser_GetUsersList_Filters() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	handlerFunc := s.user.GetUsersList(s.userRepository)
+	login, search, role, userType := "jo", "+7999", roles.Operator, "person"
+	activeArea := int64(2)
+	isDeleted := true
+	data := users.GetAllUsersParams{
+		HTTPRequest: &request,
+		Login:       &login,
+		Search:      &search,
+		Role:        &role,
+		ActiveArea:  &activeArea,
+		Type:        &userType,
+		IsDeleted:   &isDeleted,
+	}
+	activeAreaID := int(activeArea)
+	filter := usersListFilter(math.MaxInt, 0, utils.AscOrder, user.FieldID)
+	filter.Login = &login
+	filter.Search = &search
+	filter.Role = &role
+	filter.ActiveAreaID = &activeAreaID
+	filter.Type = &userType
+	filter.IsDeleted = &isDeleted
+	s.userRepository.On("UsersListTotal", ctx, filter).Return(1, nil)
+	s.userRepository.On("UserList", ctx, filter).Return([]*ent.User{validUser(t, 1)}, nil)
+
+	resp := handlerFunc(data, nil)
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+	s.userRepository.AssertExpectations(t)
+}
+
 func (s *UserTestSuite) TestUser_GetUsersList_EmptyParams() {
 	t := s.T()
 	request := http.Request{}
@@ -655,8 +775,9 @@ func (s *UserTestSuite) TestUser_GetUsersList_EmptyParams() {
 	usersToReturn := []*ent.User{
 		validUser(t, 1),
 	}
-	s.userRepository.On("UsersListTotal", ctx).Return(1, nil)
-	s.userRepository.On("UserList", ctx, limit, offset, orderBy, orderColumn).
+	filter := usersListFilter(limit, offset, orderBy, orderColumn)
+	s.userRepository.On("UsersListTotal", ctx, filter).Return(1, nil)
+	s.userRepository.On("UserList", ctx, filter).
 		Return(usersToReturn, nil)
 
 	resp := handlerFunc(data, nil)
@@ -698,8 +819,9 @@ func (s *UserTestSuite) TestUser_GetUsersList_LimitGreaterThanTotal() {
 		validUser(t, 2),
 		validUser(t, 3),
 	}
-	s.userRepository.On("UsersListTotal", ctx).Return(len(usersToReturn), nil)
-	s.userRepository.On("UserList", ctx, int(limit), int(offset), orderBy, orderColumn).
+	filter := usersListFilter(int(limit), int(offset), orderBy, orderColumn)
+	s.userRepository.On("UsersListTotal", ctx, filter).Return(len(usersToReturn), nil)
+	s.userRepository.On("UserList", ctx, filter).
 		Return(usersToReturn, nil)
 
 	resp := handlerFunc(data, nil)
@@ -745,8 +867,9 @@ func (s *UserTestSuite) TestUser_GetUsersList_LimitLessThanTotal() {
 		validUser(t, 5),
 		validUser(t, 6),
 	}
-	s.userRepository.On("UsersListTotal", ctx).Return(len(usersToReturn), nil)
-	s.userRepository.On("UserList", ctx, int(limit), int(offset), orderBy, orderColumn).
+	filter := usersListFilter(int(limit), int(offset), orderBy, orderColumn)
+	s.userRepository.On("UsersListTotal", ctx, filter).Return(len(usersToReturn), nil)
+	s.userRepository.On("UserList", ctx, filter).
 		Return(usersToReturn[:limit], nil)
 
 	resp := handlerFunc(data, nil)
@@ -792,8 +915,9 @@ func (s *UserTestSuite) TestUser_GetUsersList_SecondPage() {
 		validUser(t, 5),
 		validUser(t, 6),
 	}
-	s.userRepository.On("UsersListTotal", ctx).Return(len(usersToReturn), nil)
-	s.userRepository.On("UserList", ctx, int(limit), int(offset), orderBy, orderColumn).
+	filter := usersListFilter(int(limit), int(offset), orderBy, orderColumn)
+	s.userRepository.On("UsersListTotal", ctx, filter).Return(len(usersToReturn), nil)
+	s.userRepository.On("UserList", ctx, filter).
 		Return(usersToReturn[offset:], nil)
 
 	resp := handlerFunc(data, nil)
@@ -840,8 +964,9 @@ func (s *UserTestSuite) TestUser_GetUsersList_SeveralPages() {
 		validUser(t, 5),
 		validUser(t, 6),
 	}
-	s.userRepository.On("UsersListTotal", ctx).Return(len(usersToReturn), nil)
-	s.userRepository.On("UserList", ctx, int(limit), int(offset), orderBy, orderColumn).
+	filter := usersListFilter(int(limit), int(offset), orderBy, orderColumn)
+	s.userRepository.On("UsersListTotal", ctx, filter).Return(len(usersToReturn), nil)
+	s.userRepository.On("UserList", ctx, filter).
 		Return(usersToReturn[:limit], nil)
 
 	resp := handlerFunc(data, nil)
@@ -866,8 +991,9 @@ func (s *UserTestSuite) TestUser_GetUsersList_SeveralPages() {
 		OrderColumn: &orderColumn,
 		OrderBy:     &orderBy,
 	}
-	s.userRepository.On("UsersListTotal", ctx).Return(len(usersToReturn), nil)
-	s.userRepository.On("UserList", ctx, int(limit), int(offset), orderBy, orderColumn).
+	filter = usersListFilter(int(limit), int(offset), orderBy, orderColumn)
+	s.userRepository.On("UsersListTotal", ctx, filter).Return(len(usersToReturn), nil)
+	s.userRepository.On("UserList", ctx, filter).
 		Return(usersToReturn[offset:], nil)
 
 	resp = handlerFunc(data, nil)
@@ -890,6 +1016,25 @@ func (s *UserTestSuite) TestUser_GetUsersList_SeveralPages() {
 	s.userRepository.AssertExpectations(t)
 }
 
+func (s *UserTestSuite) TestUser_GetUserById_Forbidden() {
+	t := s.T()
+	request := http.Request{}
+
+	handlerFunc := s.user.GetUserById(s.userRepository)
+	data := users.GetUserParams{
+		HTTPRequest: &request,
+		UserID:      2,
+	}
+
+	resp := handlerFunc(data, &models.Principal{ID: 1, Role: roles.User})
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusForbidden, responseRecorder.Code)
+
+	s.userRepository.AssertExpectations(t)
+}
+
 func (s *UserTestSuite) TestUser_GetUserById_RepoErr() {
 	t := s.T()
 	request := http.Request{}
@@ -904,7 +1049,7 @@ func (s *UserTestSuite) TestUser_GetUserById_RepoErr() {
 	err := errors.New("some err")
 	s.userRepository.On("GetUserByID", ctx, userID).Return(nil, err)
 
-	resp := handlerFunc(data, nil)
+	resp := handlerFunc(data, &models.Principal{Role: roles.Admin})
 	responseRecorder := httptest.NewRecorder()
 	producer := runtime.JSONProducer()
 	resp.WriteResponse(responseRecorder, producer)
@@ -929,7 +1074,7 @@ func (s *UserTestSuite) TestUser_GetUserById_MapErr() {
 	}
 	s.userRepository.On("GetUserByID", ctx, userID).Return(user, nil)
 
-	resp := handlerFunc(data, nil)
+	resp := handlerFunc(data, &models.Principal{Role: roles.Admin})
 	responseRecorder := httptest.NewRecorder()
 	producer := runtime.JSONProducer()
 	resp.WriteResponse(responseRecorder, producer)
@@ -938,6 +1083,52 @@ func (s *UserTestSuite) TestUser_GetUserById_MapErr() {
 	s.userRepository.AssertExpectations(t)
 }
 
+func (s *UserTestSuite) TestUser_GetUserById_MaskedPassport() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+
+	handlerFunc := s.user.GetUserById(s.userRepository)
+	user := &ent.User{
+		ID:                2,
+		PassportSeries:    "1234",
+		PassportNumber:    "567890",
+		PassportAuthority: "authority",
+		PassportIssueDate: time.Date(2015, 3, 12, 0, 0, 0, 0, time.UTC),
+		Edges:             ent.UserEdges{Role: &ent.Role{}},
+	}
+	s.userRepository.On("GetUserByID", ctx, user.ID).Return(user, nil)
+
+	cases := map[string]*models.UserInfo{
+		roles.Operator: {
+			PassportSeries:    utils.PointerOrNil("****"),
+			PassportNumber:    utils.PointerOrNil("****90"),
+			PassportAuthority: utils.PointerOrNil("*********"),
+		},
+		roles.Manager: {
+			PassportSeries:    utils.PointerOrNil("1234"),
+			PassportNumber:    utils.PointerOrNil("567890"),
+			PassportAuthority: utils.PointerOrNil("authority"),
+			PassportIssueDate: utils.PointerOrNil(user.PassportIssueDate.String()),
+		},
+	}
+	for role, expected := range cases {
+		resp := handlerFunc(users.GetUserParams{HTTPRequest: &request, UserID: int64(user.ID)},
+			&models.Principal{ID: 1, Role: role})
+		responseRecorder := httptest.NewRecorder()
+		resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+		require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+		actual := &models.UserInfo{}
+		require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), actual))
+		require.Equal(t, expected.PassportSeries, actual.PassportSeries, role)
+		require.Equal(t, expected.PassportNumber, actual.PassportNumber, role)
+		require.Equal(t, expected.PassportAuthority, actual.PassportAuthority, role)
+		require.Equal(t, expected.PassportIssueDate, actual.PassportIssueDate, role)
+	}
+	s.userRepository.AssertExpectations(t)
+}
+
 func (s *UserTestSuite) TestUser_GetUserById_OK() {
 	t := s.T()
 	request := http.Request{}
@@ -958,7 +1149,7 @@ func (s *UserTestSuite) TestUser_GetUserById_OK() {
 
 	s.userRepository.On("GetUserByID", ctx, userID).Return(user, nil)
 
-	resp := handlerFunc(data, nil)
+	resp := handlerFunc(data, &models.Principal{Role: roles.Admin})
 	responseRecorder := httptest.NewRecorder()
 	producer := runtime.JSONProducer()
 	resp.WriteResponse(responseRecorder, producer)
@@ -1026,6 +1217,53 @@ func (s *UserTestSuite) TestUser_DeleteUser_OK() {
 	s.userRepository.AssertExpectations(t)
 }
 
+func (s *UserTestSuite) TestUser_RestoreUser_NotAdmin() {
+	t := s.T()
+	request := http.Request{}
+
+	handlerFunc := s.user.RestoreUser(s.userRepository, time.Hour)
+	for _, role := range []string{roles.Manager, roles.Operator, roles.User} {
+		resp := handlerFunc(users.RestoreUserParams{HTTPRequest: &request, UserID: 2},
+			&models.Principal{ID: 1, Role: role})
+		responseRecorder := httptest.NewRecorder()
+		resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+		require.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	}
+	s.userRepository.AssertExpectations(t)
+}
+
+func (s *UserTestSuite) TestUser_RestoreUser() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	retentionPeriod := time.Hour
+	deletedAfter := mock.MatchedBy(func(deletedAfter time.Time) bool {
+		return time.Since(deletedAfter) >= retentionPeriod
+	})
+
+	s.userRepository.On("Restore", ctx, 1, deletedAfter).Return(nil)
+	s.userRepository.On("Restore", ctx, 2, deletedAfter).Return(&ent.NotFoundError{})
+	s.userRepository.On("Restore", ctx, 3, deletedAfter).Return(domain.ErrUserNotDeleted)
+	s.userRepository.On("Restore", ctx, 4, deletedAfter).Return(domain.ErrRestorePeriodExpired)
+	s.userRepository.On("Restore", ctx, 5, deletedAfter).Return(errors.New("test"))
+
+	handlerFunc := s.user.RestoreUser(s.userRepository, retentionPeriod)
+	admin := &models.Principal{ID: 10, Role: roles.Admin}
+	for userID, code := range map[int64]int{
+		1: http.StatusNoContent,
+		2: http.StatusNotFound,
+		3: http.StatusNotFound,
+		4: http.StatusConflict,
+		5: http.StatusInternalServerError,
+	} {
+		resp := handlerFunc(users.RestoreUserParams{HTTPRequest: &request, UserID: userID}, admin)
+		responseRecorder := httptest.NewRecorder()
+		resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+		require.Equal(t, code, responseRecorder.Code, userID)
+	}
+	s.userRepository.AssertExpectations(t)
+}
+
 func (s *UserTestSuite) TestUser_DeleteUser_DeleteNonReadonlyUserError() {
 	t := s.T()
 
@@ -1057,7 +1295,7 @@ func (s *UserTestSuite) TestUser_DeleteUser_GetUserByID_UserNotFound() {
 
 	ctx := context.Background()
 	userID := 1232
-	principal := &models.Principal{}
+	principal := &models.Principal{Role: roles.Admin}
 	data := users.DeleteUserParams{
 		HTTPRequest: &http.Request{},
 		UserID:      int64(userID),
@@ -1081,7 +1319,7 @@ func (s *UserTestSuite) TestUser_DeleteUser_GetUserByID_InternalError() {
 
 	ctx := context.Background()
 	userID := 1232
-	principal := &models.Principal{}
+	principal := &models.Principal{Role: roles.Admin}
 	data := users.DeleteUserParams{
 		HTTPRequest: &http.Request{},
 		UserID:      int64(userID),
@@ -1105,7 +1343,7 @@ func (s *UserTestSuite) TestUser_DeleteUser_Delete_UserNotFound() {
 
 	ctx := context.Background()
 	userID := 1232
-	principal := &models.Principal{}
+	principal := &models.Principal{Role: roles.Admin}
 	data := users.DeleteUserParams{
 		HTTPRequest: &http.Request{},
 		UserID:      int64(userID),
@@ -1132,7 +1370,7 @@ func (s *UserTestSuite) TestUser_DeleteUser_Delete_InternalError() {
 
 	ctx := context.Background()
 	userID := 1232
-	principal := &models.Principal{}
+	principal := &models.Principal{Role: roles.Admin}
 	data := users.DeleteUserParams{
 		HTTPRequest: &http.Request{},
 		UserID:      int64(userID),
@@ -1158,7 +1396,7 @@ func (s *UserTestSuite) TestUser_ChangePasswordFunc_GetUserErr() {
 	t := s.T()
 	request := http.Request{}
 	ctx := request.Context()
-	handlerFunc := s.user.ChangePassword(s.userRepository)
+	handlerFunc := s.user.ChangePassword(s.userRepository, s.passwordPolicy)
 
 	id := 1
 	user := validUser(t, id)
@@ -1197,7 +1435,7 @@ func (s *UserTestSuite) TestUser_ChangePasswordFunc_ComparePasswordErr() {
 	t := s.T()
 	request := http.Request{}
 	ctx := request.Context()
-	handlerFunc := s.user.ChangePassword(s.userRepository)
+	handlerFunc := s.user.ChangePassword(s.userRepository, s.passwordPolicy)
 
 	id := 1
 	user := validUser(t, id)
@@ -1236,7 +1474,7 @@ func (s *UserTestSuite) TestUser_ChangePasswordFunc_ChangePasswordError() {
 	t := s.T()
 	request := http.Request{}
 	ctx := request.Context()
-	handlerFunc := s.user.ChangePassword(s.userRepository)
+	handlerFunc := s.user.ChangePassword(s.userRepository, s.passwordPolicy)
 
 	id := 1
 	user := validUser(t, id)
@@ -1262,6 +1500,7 @@ func (s *UserTestSuite) TestUser_ChangePasswordFunc_ChangePasswordError() {
 
 	err = errors.New("failed to change password")
 	s.userRepository.On("GetUserByID", ctx, user.ID).Return(user, nil)
+	s.passwordPolicy.On("Validate", newPassword, user.Login, user.Email).Return(nil)
 	s.userRepository.On("ChangePasswordByLogin", ctx, user.Login, newPassword).Return(err)
 
 	resp := handlerFunc(data, principal)
@@ -1272,11 +1511,52 @@ func (s *UserTestSuite) TestUser_ChangePasswordFunc_ChangePasswordError() {
 	s.userRepository.AssertExpectations(t)
 }
 
+func (s *UserTestSuite) TestUser_ChangePasswordFunc_PasswordPolicyErr() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	handlerFunc := s.user.ChangePassword(s.userRepository, s.passwordPolicy)
+
+	id := 1
+	user := validUser(t, id)
+	password := "password"
+	passwordHash, err := utils.PasswordHash(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.Password = passwordHash
+	newPassword := user.Login
+
+	data := users.ChangePasswordParams{
+		HTTPRequest: &request,
+		PasswordPatch: &models.PatchPasswordRequest{
+			OldPassword: password,
+			NewPassword: newPassword,
+		},
+	}
+	principal := &models.Principal{
+		ID:   int64(id),
+		Role: roles.Admin,
+	}
+
+	s.userRepository.On("GetUserByID", ctx, user.ID).Return(user, nil)
+	s.passwordPolicy.On("Validate", newPassword, user.Login, user.Email).
+		Return([]string{messages.ErrPasswordEqualUserData})
+
+	resp := handlerFunc(data, principal)
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	s.passwordPolicy.AssertExpectations(t)
+	s.userRepository.AssertExpectations(t)
+}
+
 func (s *UserTestSuite) TestUser_ChangePasswordFunc_OK() {
 	t := s.T()
 	request := http.Request{}
 	ctx := request.Context()
-	handlerFunc := s.user.ChangePassword(s.userRepository)
+	handlerFunc := s.user.ChangePassword(s.userRepository, s.passwordPolicy)
 
 	id := 1
 	user := validUser(t, id)
@@ -1301,6 +1581,7 @@ func (s *UserTestSuite) TestUser_ChangePasswordFunc_OK() {
 	}
 
 	s.userRepository.On("GetUserByID", ctx, user.ID).Return(user, nil)
+	s.passwordPolicy.On("Validate", newPassword, user.Login, user.Email).Return(nil)
 	s.userRepository.On("ChangePasswordByLogin", ctx, user.Login, newPassword).Return(nil)
 
 	resp := handlerFunc(data, principal)
@@ -1380,6 +1661,27 @@ func (s *UserTestSuite) TestUser_ChangeEmailFunc_Err() {
 	s.userRepository.AssertExpectations(t)
 }
 
+func (s *UserTestSuite) TestUser_UpdateReadonlyAccess_Forbidden() {
+	t := s.T()
+	userID := 1
+	data := users.UpdateReadonlyAccessParams{
+		HTTPRequest: &http.Request{},
+		UserID:      int64(userID),
+		Body:        users.UpdateReadonlyAccessBody{IsReadonly: false},
+	}
+
+	handlerFunc := s.user.UpdateReadonlyAccess(s.userRepository)
+
+	principal := &models.Principal{ID: int64(userID), Role: roles.User}
+	resp := handlerFunc(data, principal)
+	responseRecorder := httptest.NewRecorder()
+	producer := runtime.JSONProducer()
+	resp.WriteResponse(responseRecorder, producer)
+	require.Equal(t, http.StatusForbidden, responseRecorder.Code)
+
+	s.userRepository.AssertExpectations(t)
+}
+
 func (s *UserTestSuite) TestUser_UpdateReadonlyAccess_Grant() {
 	t := s.T()
 
@@ -1396,7 +1698,7 @@ func (s *UserTestSuite) TestUser_UpdateReadonlyAccess_Grant() {
 
 	handlerFunc := s.user.UpdateReadonlyAccess(s.userRepository)
 
-	principal := &models.Principal{}
+	principal := &models.Principal{Role: roles.Admin}
 	resp := handlerFunc(data, principal)
 	responseRecorder := httptest.NewRecorder()
 	producer := runtime.JSONProducer()
@@ -1421,7 +1723,7 @@ func (s *UserTestSuite) TestUser_UpdateReadonlyAccess_Revoke() {
 	s.userRepository.On("SetIsReadonly", ctx, userID, isReadonly).Return(nil)
 
 	handlerFunc := s.user.UpdateReadonlyAccess(s.userRepository)
-	principal := &models.Principal{}
+	principal := &models.Principal{Role: roles.Admin}
 	resp := handlerFunc(data, principal)
 	responseRecorder := httptest.NewRecorder()
 	producer := runtime.JSONProducer()
@@ -1447,7 +1749,7 @@ func (s *UserTestSuite) TestUser_UpdateReadonlyAccess_UserNotFound() {
 	s.userRepository.On("SetIsReadonly", ctx, userID, isReadonly).Return(expectedError)
 
 	handlerFunc := s.user.UpdateReadonlyAccess(s.userRepository)
-	principal := &models.Principal{}
+	principal := &models.Principal{Role: roles.Admin}
 	resp := handlerFunc(data, principal)
 	responseRecorder := httptest.NewRecorder()
 	producer := runtime.JSONProducer()
@@ -1473,7 +1775,7 @@ func (s *UserTestSuite) TestUser_UpdateReadonlyAccess_InternalError() {
 	s.userRepository.On("SetIsReadonly", ctx, userID, isReadonly).Return(expectedError)
 
 	handlerFunc := s.user.UpdateReadonlyAccess(s.userRepository)
-	principal := &models.Principal{}
+	principal := &models.Principal{Role: roles.Admin}
 	resp := handlerFunc(data, principal)
 	responseRecorder := httptest.NewRecorder()
 	producer := runtime.JSONProducer()
@@ -1514,3 +1816,46 @@ func usersDuplicated(t *testing.T, array1, array2 []*models.UserInfo) bool {
 	}
 	return false
 }
+
+func usersListFilter(limit, offset int, orderBy, orderColumn string) domain.UserFilter {
+	return domain.UserFilter{
+		Filter: domain.Filter{Limit: limit, Offset: offset, OrderBy: orderBy, OrderColumn: orderColumn},
+	}
+}
+
+func (s *UserTestSuite) TestUser_ExportCurrentUser() {
+	t := s.T()
+	request := http.Request{}
+	ctx := request.Context()
+	exportService := &mocks.UserExportService{}
+	export := &domain.UserExport{Profile: domain.UserExportProfile{ID: 1, Login: "login"}}
+	exportService.On("Export", ctx, 1).Return(export, nil)
+	exportService.On("Export", ctx, 2).Return(nil, errors.New("test"))
+	exportService.On("WriteZIP", mock.Anything, export).Return(nil)
+
+	handlerFunc := s.user.ExportCurrentUser(exportService)
+	resp := handlerFunc(users.ExportCurrentUserParams{HTTPRequest: &request}, &models.Principal{ID: 1})
+	responseRecorder := httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+	require.Equal(t, "application/json", responseRecorder.Header().Get("Content-Type"))
+	require.Contains(t, responseRecorder.Header().Get("Content-Disposition"), "user_1_export.json")
+	actual := domain.UserExport{}
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &actual))
+	require.Equal(t, export.Profile, actual.Profile)
+
+	format := domain.UserExportFormatZIP
+	resp = handlerFunc(users.ExportCurrentUserParams{HTTPRequest: &request, Format: &format},
+		&models.Principal{ID: 1})
+	responseRecorder = httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+	require.Equal(t, "application/zip", responseRecorder.Header().Get("Content-Type"))
+	require.Contains(t, responseRecorder.Header().Get("Content-Disposition"), "user_1_export.zip")
+
+	resp = handlerFunc(users.ExportCurrentUserParams{HTTPRequest: &request}, &models.Principal{ID: 2})
+	responseRecorder = httptest.NewRecorder()
+	resp.WriteResponse(responseRecorder, runtime.JSONProducer())
+	require.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+	exportService.AssertExpectations(t)
+}
@@ -0,0 +1,291 @@
+// Package images prepares the uploaded photos: it applies the EXIF orientation, drops the metadata
+// by encoding the image again and makes the smaller renditions of it. The jpeg and png photos keep
+// their format, the webp photos are stored as png as there is no webp encoder.
+package images
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	// registers the webp format for image.Decode
+	_ "golang.org/x/image/webp"
+)
+
+// The MIME types of the supported photos.
+const (
+	JPEG = "image/jpeg"
+	PNG  = "image/png"
+	WebP = "image/webp"
+)
+
+// Supported reports whether the photo of the MIME type can be uploaded.
+func Supported(mimeType string) bool {
+	return mimeType == JPEG || mimeType == PNG || mimeType == WebP
+}
+
+// Extension returns the file name extension of the stored photo of the MIME type.
+func Extension(mimeType string) string {
+	if mimeType == PNG {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+// Size is the rendition of the photo, the original is the full size photo without the metadata.
+type Size string
+
+const (
+	Original  Size = "original"
+	Thumbnail Size = "thumbnail"
+	Medium    Size = "medium"
+	Large     Size = "large"
+)
+
+// Renditions are the sizes made on upload with the maximum length of the longer side in pixels.
+// The photos smaller than the rendition are not enlarged.
+var Renditions = map[Size]int{
+	Thumbnail: 200,
+	Medium:    800,
+	Large:     1600,
+}
+
+// Key returns the key of the size of the photo in the blob store, the original is kept by the photo id.
+func (s Size) Key(photoID string) string {
+	if s == Original {
+		return photoID
+	}
+	return photoID + "_" + string(s)
+}
+
+// Keys returns the keys of the original and all the renditions of the photo.
+func Keys(photoID string) []string {
+	keys := []string{Original.Key(photoID)}
+	for size := range Renditions {
+		keys = append(keys, size.Key(photoID))
+	}
+	return keys
+}
+
+const (
+	originalQuality  = 90
+	renditionQuality = 80
+)
+
+var (
+	ErrUnsupportedImage = errors.New("unsupported image")
+	ErrImageTooLarge    = errors.New("image dimensions are too large")
+)
+
+// Limits are the largest photo that can be uploaded.
+type Limits struct {
+	MaxFileSize int64
+	MaxWidth    int
+	MaxHeight   int
+}
+
+// Process decodes the jpeg, png or webp photo and returns the MIME type the original and all the
+// renditions are encoded with. The dimensions are read from the header first, so the photo larger
+// than the limits is rejected before the memory for its pixels is allocated.
+func Process(data []byte, limits Limits) (string, map[Size][]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", nil, errors.Join(ErrUnsupportedImage, err)
+	}
+	if cfg.Width > limits.MaxWidth || cfg.Height > limits.MaxHeight {
+		return "", nil, ErrImageTooLarge
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", nil, errors.Join(ErrUnsupportedImage, err)
+	}
+	mimeType := PNG
+	if format == "jpeg" {
+		mimeType = JPEG
+		img = orient(img, Orientation(data))
+	}
+
+	result := make(map[Size][]byte, len(Renditions)+1)
+	if result[Original], err = encode(img, mimeType, originalQuality); err != nil {
+		return "", nil, err
+	}
+	for size, maxSide := range Renditions {
+		if result[size], err = encode(resize(img, maxSide), mimeType, renditionQuality); err != nil {
+			return "", nil, err
+		}
+	}
+	return mimeType, result, nil
+}
+
+func encode(img image.Image, mimeType string, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if mimeType == PNG {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resize scales the image down keeping the aspect ratio so that its longer side is maxSide.
+func resize(img image.Image, maxSide int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxSide && height <= maxSide {
+		return img
+	}
+	if width >= height {
+		height = max(1, height*maxSide/width)
+		width = maxSide
+	} else {
+		width = max(1, width*maxSide/height)
+		height = maxSide
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// orient rotates and flips the image the way the EXIF orientation says, so that it is shown upright
+// without the metadata. The decoded jpeg photos are rotated through their pixel buffers, the other
+// images fall back to the slower color by color copy.
+func orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+	switch src := img.(type) {
+	case *image.YCbCr:
+		if dst := orientYCbCr(src, orientation); dst != nil {
+			return dst
+		}
+	case *image.Gray:
+		return orientGray(src, orientation)
+	}
+	bounds := img.Bounds()
+	dstWidth, dstHeight := orientedSize(bounds.Dx(), bounds.Dy(), orientation)
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			srcX, srcY := orientedSource(bounds.Dx(), bounds.Dy(), orientation, x, y)
+			dst.Set(x, y, img.At(bounds.Min.X+srcX, bounds.Min.Y+srcY))
+		}
+	}
+	return dst
+}
+
+// orientedSize returns the size of the image after the orientation is applied: the orientations
+// from 5 to 8 swap the sides.
+func orientedSize(width, height, orientation int) (int, int) {
+	if orientation >= 5 {
+		return height, width
+	}
+	return width, height
+}
+
+// orientedSource returns the point of the width×height source image that ends up at x, y of the
+// oriented image.
+func orientedSource(width, height, orientation, x, y int) (int, int) {
+	switch orientation {
+	case 2:
+		return width - 1 - x, y
+	case 3:
+		return width - 1 - x, height - 1 - y
+	case 4:
+		return x, height - 1 - y
+	case 5:
+		return y, x
+	case 6:
+		return y, height - 1 - x
+	case 7:
+		return width - 1 - y, height - 1 - x
+	case 8:
+		return width - 1 - y, x
+	}
+	return x, y
+}
+
+// orientPlane copies the samples of the width×height src plane to dst so that they are oriented.
+// Both slices start at the top left sample.
+func orientPlane(dst []uint8, dstStride int, src []uint8, srcStride, width, height, orientation int) {
+	// the orientations are linear, so the source index moves by the same step along each row and column
+	index := func(x, y int) int {
+		srcX, srcY := orientedSource(width, height, orientation, x, y)
+		return srcY*srcStride + srcX
+	}
+	origin := index(0, 0)
+	stepX, stepY := index(1, 0)-origin, index(0, 1)-origin
+	dstWidth, dstHeight := orientedSize(width, height, orientation)
+	for y := 0; y < dstHeight; y++ {
+		row := dst[y*dstStride : y*dstStride+dstWidth]
+		i := origin + y*stepY
+		for x := range row {
+			row[x] = src[i]
+			i += stepX
+		}
+	}
+}
+
+func orientGray(src *image.Gray, orientation int) *image.Gray {
+	bounds := src.Bounds()
+	dstWidth, dstHeight := orientedSize(bounds.Dx(), bounds.Dy(), orientation)
+	dst := image.NewGray(image.Rect(0, 0, dstWidth, dstHeight))
+	orientPlane(dst.Pix, dst.Stride, src.Pix[src.PixOffset(bounds.Min.X, bounds.Min.Y):], src.Stride,
+		bounds.Dx(), bounds.Dy(), orientation)
+	return dst
+}
+
+// subsampling is how many luma samples share one chroma sample across and down.
+var subsampling = map[image.YCbCrSubsampleRatio][2]int{
+	image.YCbCrSubsampleRatio444: {1, 1},
+	image.YCbCrSubsampleRatio422: {2, 1},
+	image.YCbCrSubsampleRatio420: {2, 2},
+	image.YCbCrSubsampleRatio440: {1, 2},
+	image.YCbCrSubsampleRatio411: {4, 1},
+	image.YCbCrSubsampleRatio410: {4, 2},
+}
+
+// orientYCbCr orients the luma and chroma planes separately. The chroma sample of each block of the
+// oriented image is taken from under the top left pixel of the block. It returns nil when the swapped
+// subsampling of the orientations from 5 to 8 has no image.YCbCrSubsampleRatio.
+func orientYCbCr(src *image.YCbCr, orientation int) *image.YCbCr {
+	factors, ok := subsampling[src.SubsampleRatio]
+	if !ok {
+		return nil
+	}
+	if orientation >= 5 {
+		factors[0], factors[1] = factors[1], factors[0]
+	}
+	ratio := image.YCbCrSubsampleRatio(-1)
+	for r, f := range subsampling {
+		if f == factors {
+			ratio = r
+		}
+	}
+	if ratio < 0 {
+		return nil
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	dstWidth, dstHeight := orientedSize(width, height, orientation)
+	dst := image.NewYCbCr(image.Rect(0, 0, dstWidth, dstHeight), ratio)
+	orientPlane(dst.Y, dst.YStride, src.Y[src.YOffset(bounds.Min.X, bounds.Min.Y):], src.YStride,
+		width, height, orientation)
+	chromaHeight := len(dst.Cb) / dst.CStride
+	for y := 0; y < chromaHeight; y++ {
+		for x := 0; x < dst.CStride; x++ {
+			srcX, srcY := orientedSource(width, height, orientation, x*factors[0], y*factors[1])
+			i := src.COffset(bounds.Min.X+srcX, bounds.Min.Y+srcY)
+			dst.Cb[y*dst.CStride+x] = src.Cb[i]
+			dst.Cr[y*dst.CStride+x] = src.Cr[i]
+		}
+	}
+	return dst
+}
@@ -0,0 +1,268 @@
+package images
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testLimits = Limits{MaxFileSize: 10 << 20, MaxWidth: 4000, MaxHeight: 4000}
+
+// withOrientation puts the EXIF segment with the orientation right after the start of the jpeg.
+func withOrientation(t *testing.T, data []byte, orientation uint16) []byte {
+	t.Helper()
+	tiff := new(bytes.Buffer)
+	tiff.WriteString("MM")
+	// the header with the offset of the first IFD, the IFD with the only SHORT entry and no next IFD
+	for _, value := range []any{
+		uint16(42), uint32(8),
+		uint16(1),
+		uint16(tagOrientation), uint16(3), uint32(1), orientation, uint16(0),
+		uint32(0),
+	} {
+		require.NoError(t, binary.Write(tiff, binary.BigEndian, value))
+	}
+	segment := append(append([]byte{}, exifHeader...), tiff.Bytes()...)
+
+	result := []byte{0xff, markerSOI, 0xff, markerAPP1}
+	result = binary.BigEndian.AppendUint16(result, uint16(len(segment)+2))
+	result = append(result, segment...)
+	return append(result, data[2:]...)
+}
+
+// testImage is white with the red top left corner.
+func testImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+	for y := 0; y < height/4; y++ {
+		for x := 0; x < width/4; x++ {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	buf := new(bytes.Buffer)
+	require.NoError(t, jpeg.Encode(buf, img, &jpeg.Options{Quality: 100}))
+	return buf.Bytes()
+}
+
+func decode(t *testing.T, data []byte) image.Image {
+	t.Helper()
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	return img
+}
+
+func isRed(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return r > 0xc000 && g < 0x4000 && b < 0x4000
+}
+
+func TestOrientation(t *testing.T) {
+	data := testImage(t, 8, 4)
+	require.Equal(t, 1, Orientation(data))
+	require.Equal(t, 6, Orientation(withOrientation(t, data, 6)))
+	require.Equal(t, 1, Orientation(withOrientation(t, data, 9)))
+	require.Equal(t, 1, Orientation([]byte("not a jpeg")))
+	require.Equal(t, 1, Orientation(data[:3]))
+}
+
+func TestProcess_Orientation(t *testing.T) {
+	data := testImage(t, 80, 40)
+	tests := map[uint16]struct {
+		width, height int
+		redX, redY    int
+	}{
+		1: {80, 40, 0, 0},
+		2: {80, 40, 79, 0},
+		3: {80, 40, 79, 39},
+		4: {80, 40, 0, 39},
+		5: {40, 80, 0, 0},
+		6: {40, 80, 39, 0},
+		7: {40, 80, 39, 79},
+		8: {40, 80, 0, 79},
+	}
+	for orientation, tc := range tests {
+		mimeType, sizes, err := Process(withOrientation(t, data, orientation), testLimits)
+		require.NoError(t, err)
+		require.Equal(t, JPEG, mimeType)
+		original := sizes[Original]
+		require.Equal(t, 1, Orientation(original), "the metadata is dropped")
+		require.NotContains(t, string(original), "Exif")
+
+		img := decode(t, original)
+		require.Equal(t, tc.width, img.Bounds().Dx(), "orientation %d", orientation)
+		require.Equal(t, tc.height, img.Bounds().Dy(), "orientation %d", orientation)
+		require.True(t, isRed(img.At(tc.redX, tc.redY)), "orientation %d", orientation)
+	}
+}
+
+// opaqueImage hides the concrete type of the image, so that orient takes the generic path.
+type opaqueImage struct {
+	image.Image
+}
+
+func TestOrient_PixelBuffers(t *testing.T) {
+	// the odd sides leave partial chroma blocks on the edges
+	bounds := image.Rect(3, 2, 10, 7)
+	gray := image.NewGray(bounds)
+	for i := range gray.Pix {
+		gray.Pix[i] = uint8(i * 7)
+	}
+	for orientation := 1; orientation <= 8; orientation++ {
+		fast := orient(gray, orientation)
+		generic := orient(opaqueImage{gray}, orientation)
+		require.IsType(t, &image.Gray{}, fast)
+		require.Equal(t, generic.Bounds().Size(), fast.Bounds().Size(), "orientation %d", orientation)
+		for y := 0; y < generic.Bounds().Dy(); y++ {
+			for x := 0; x < generic.Bounds().Dx(); x++ {
+				require.Equal(t, color.RGBAModel.Convert(generic.At(x, y)), color.RGBAModel.Convert(fast.At(x, y)),
+					"orientation %d, x %d, y %d", orientation, x, y)
+			}
+		}
+	}
+
+	for ratio, factors := range subsampling {
+		src := image.NewYCbCr(bounds, ratio)
+		for i := range src.Y {
+			src.Y[i] = uint8(i * 7)
+		}
+		for i := range src.Cb {
+			src.Cb[i], src.Cr[i] = uint8(i*11), uint8(i*13)
+		}
+		for orientation := 1; orientation <= 8; orientation++ {
+			fast := orient(src, orientation)
+			generic := orient(opaqueImage{src}, orientation)
+			// the luma plane alone is compared through a gray image over it
+			luma := orient(opaqueImage{&image.Gray{Pix: src.Y, Stride: src.YStride, Rect: bounds}}, orientation)
+			require.Equal(t, generic.Bounds().Size(), fast.Bounds().Size(), "ratio %v, orientation %d", ratio, orientation)
+			dst, ok := fast.(*image.YCbCr)
+			if !ok {
+				// 4:1:1 and 4:1:0 turned sideways have no subsample ratio
+				require.True(t, orientation >= 5 && factors[0] == 4, "ratio %v, orientation %d", ratio, orientation)
+				continue
+			}
+			dstFactors := subsampling[dst.SubsampleRatio]
+			for y := 0; y < generic.Bounds().Dy(); y++ {
+				for x := 0; x < generic.Bounds().Dx(); x++ {
+					want := color.RGBAModel.Convert(generic.At(x, y)).(color.RGBA)
+					got := dst.YCbCrAt(x, y)
+					require.Equal(t, color.GrayModel.Convert(luma.At(x, y)).(color.Gray).Y, got.Y,
+						"ratio %v, orientation %d, x %d, y %d", ratio, orientation, x, y)
+					if x%dstFactors[0] == 0 && y%dstFactors[1] == 0 {
+						require.Equal(t, want, color.RGBAModel.Convert(got),
+							"ratio %v, orientation %d, x %d, y %d", ratio, orientation, x, y)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestProcess_Renditions(t *testing.T) {
+	_, sizes, err := Process(testImage(t, 2000, 1000), testLimits)
+	require.NoError(t, err)
+	require.Len(t, sizes, len(Renditions)+1)
+
+	require.Equal(t, 2000, decode(t, sizes[Original]).Bounds().Dx())
+	for size, maxSide := range Renditions {
+		bounds := decode(t, sizes[size]).Bounds()
+		require.Equal(t, maxSide, bounds.Dx(), size)
+		require.Equal(t, maxSide/2, bounds.Dy(), size)
+	}
+}
+
+func TestProcess_SmallImageIsNotEnlarged(t *testing.T) {
+	_, sizes, err := Process(testImage(t, 100, 150), testLimits)
+	require.NoError(t, err)
+	for size := range Renditions {
+		bounds := decode(t, sizes[size]).Bounds()
+		require.Equal(t, 100, bounds.Dx(), size)
+		require.Equal(t, 150, bounds.Dy(), size)
+	}
+}
+
+func TestProcess_Unsupported(t *testing.T) {
+	_, _, err := Process([]byte("not a jpeg"), testLimits)
+	require.ErrorIs(t, err, ErrUnsupportedImage)
+}
+
+func TestProcess_PNG(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 400, 100))
+	img.Set(0, 0, color.NRGBA{R: 255, A: 128})
+	buf := new(bytes.Buffer)
+	require.NoError(t, png.Encode(buf, img))
+
+	mimeType, sizes, err := Process(buf.Bytes(), testLimits)
+	require.NoError(t, err)
+	require.Equal(t, PNG, mimeType)
+
+	original, err := png.Decode(bytes.NewReader(sizes[Original]))
+	require.NoError(t, err)
+	_, _, _, alpha := original.At(0, 0).RGBA()
+	require.Less(t, alpha, uint32(0xffff), "the transparency is kept")
+
+	thumbnail, err := png.Decode(bytes.NewReader(sizes[Thumbnail]))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 200, 50), thumbnail.Bounds())
+}
+
+func TestProcess_WebP(t *testing.T) {
+	// the lossless 1x1 webp
+	data, err := base64.StdEncoding.DecodeString("UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA==")
+	require.NoError(t, err)
+
+	mimeType, sizes, err := Process(data, testLimits)
+	require.NoError(t, err)
+	require.Equal(t, PNG, mimeType)
+	img, err := png.Decode(bytes.NewReader(sizes[Original]))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 1, 1), img.Bounds())
+}
+
+func TestProcess_TooLarge(t *testing.T) {
+	_, _, err := Process(testImage(t, 300, 100), Limits{MaxWidth: 200, MaxHeight: 200})
+	require.ErrorIs(t, err, ErrImageTooLarge)
+	_, _, err = Process(testImage(t, 100, 300), Limits{MaxWidth: 200, MaxHeight: 200})
+	require.ErrorIs(t, err, ErrImageTooLarge)
+}
+
+func TestProcess_DecompressionBomb(t *testing.T) {
+	// the png of 100000x100000 pixels of the single color is only a few kilobytes
+	img := image.NewGray(image.Rect(0, 0, 100000, 1))
+	buf := new(bytes.Buffer)
+	require.NoError(t, png.Encode(buf, img))
+	data := buf.Bytes()
+	// the height in the IHDR chunk is changed, the decoder would fail on the pixels if it got to them
+	binary.BigEndian.PutUint32(data[20:], 100000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+
+	_, _, err := Process(data, testLimits)
+	require.ErrorIs(t, err, ErrImageTooLarge)
+}
+
+func TestSupported(t *testing.T) {
+	require.True(t, Supported(JPEG))
+	require.True(t, Supported(PNG))
+	require.True(t, Supported(WebP))
+	require.False(t, Supported("image/gif"))
+	require.Equal(t, ".jpg", Extension(JPEG))
+	require.Equal(t, ".png", Extension(PNG))
+}
+
+func TestKeys(t *testing.T) {
+	require.Equal(t, "photo", Original.Key("photo"))
+	require.Equal(t, "photo_thumbnail", Thumbnail.Key("photo"))
+	require.ElementsMatch(t, []string{"photo", "photo_thumbnail", "photo_medium", "photo_large"}, Keys("photo"))
+}
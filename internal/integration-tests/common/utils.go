@@ -95,7 +95,8 @@ func LoginUser(ctx context.Context, client *client.Be, login, password string) (
 	params.SetContext(ctx)
 	params.SetHTTPClient(http.DefaultClient)
 
-	return client.Users.Login(params)
+	loginOK, _, err := client.Users.Login(params)
+	return loginOK, err
 }
 
 func GetUser(ctx context.Context, client *client.Be, authInfo runtime.ClientAuthInfoWriter) (*users.GetCurrentUserOK, error) {
@@ -218,6 +219,8 @@ func NewAPIClient(host string, schemes []string) (*client.Be, error) {
 	// Generated client does not accept content types specified in schema
 	// https://github.com/go-swagger/go-swagger/issues/1244
 	be.Consumers["image/jpg"] = runtime.ByteStreamConsumer()
+	be.Consumers["image/jpeg"] = runtime.ByteStreamConsumer()
+	be.Consumers["image/png"] = runtime.ByteStreamConsumer()
 	return client.New(be, nil), nil
 }
 
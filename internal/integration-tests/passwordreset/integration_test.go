@@ -90,15 +90,35 @@ func TestIntegration_PasswordResetGetLink(t *testing.T) {
 	_, err = utils.CreateUser(ctx, client, l, p)
 	require.NoError(t, err)
 
-	t.Run("get password reset link by login failed: error while getting token", func(t *testing.T) {
+	t.Run("get password reset link failed: token not exist", func(t *testing.T) {
 		params := password_reset.NewGetPasswordResetLinkParamsWithContext(ctx)
 		params.Token = utils.TokenNotExist
-		got, err := client.PasswordReset.GetPasswordResetLink(params)
-		assert.NoError(t, err)
+		_, err := client.PasswordReset.GetPasswordResetLink(params)
+		require.Error(t, err)
 
-		want := &password_reset.GetPasswordResetLinkOK{
-			Payload: models.PasswordResetResponse("Check your email for a new password"),
+		errExp := password_reset.NewGetPasswordResetLinkBadRequest()
+		codeExp := int32(http.StatusBadRequest)
+		errExp.Payload = &models.SwaggerError{
+			Code:    &codeExp,
+			Message: &messages.ErrPasswordResetToken,
 		}
-		assert.Equal(t, want, got)
+		assert.Equal(t, errExp, err)
+	})
+
+	t.Run("set new password failed: token not exist", func(t *testing.T) {
+		params := password_reset.NewSetNewPasswordParamsWithContext(ctx)
+		params.Token = utils.TokenNotExist
+		password := "Str0ng-Passw"
+		params.Data = &models.SetNewPasswordRequest{Password: &password}
+		_, err := client.PasswordReset.SetNewPassword(params)
+		require.Error(t, err)
+
+		errExp := password_reset.NewSetNewPasswordBadRequest()
+		codeExp := int32(http.StatusBadRequest)
+		errExp.Payload = &models.SwaggerError{
+			Code:    &codeExp,
+			Message: &messages.ErrPasswordResetToken,
+		}
+		assert.Equal(t, errExp, err)
 	})
 }
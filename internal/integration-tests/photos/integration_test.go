@@ -81,19 +81,42 @@ func TestIntegration_PhotosUpload(t *testing.T) {
 		f.Close()
 	})
 
-	t.Run("Create New Photo failed: Wrong file format. File should be jpg or jpeg", func(t *testing.T) {
+	t.Run("Create New Photo Ok PNG", func(t *testing.T) {
 		fileName := "../common/cat3.png"
 		f, err := os.Open(fileName)
 		require.NoError(t, err)
 
+		params := photos.NewCreateNewPhotoParamsWithContext(ctx).WithFile(f)
+		res, err := beClient.Photos.CreateNewPhoto(params, auth)
+		require.NoError(t, err)
+
+		assert.NotEmpty(t, res.Payload.Data.ID)
+		assert.Equal(t, *res.Payload.Data.ID+".png", res.Payload.Data.FileName)
+		assert.Equal(t, "image/png", res.Payload.Data.MimeType)
+
+		// cleanup
+		_, err = beClient.Photos.DeletePhoto(photos.NewDeletePhotoParamsWithContext(ctx).WithPhotoID(*res.Payload.Data.ID), auth)
+		require.NoError(t, err)
+		f.Close()
+	})
+
+	t.Run("Create New Photo failed: Wrong file format. File should be jpg, png or webp", func(t *testing.T) {
+		f, err := os.CreateTemp("", "photo*.txt")
+		require.NoError(t, err)
+		defer os.Remove(f.Name())
+		_, err = f.WriteString("This is txt")
+		require.NoError(t, err)
+		_, err = f.Seek(0, 0)
+		require.NoError(t, err)
+
 		params := photos.NewCreateNewPhotoParamsWithContext(ctx).WithFile(f)
 		_, err = beClient.Photos.CreateNewPhoto(params, auth)
 		require.Error(t, err)
 
-		var phErr *photos.CreateNewPhotoBadRequest
+		var phErr *photos.CreateNewPhotoUnsupportedMediaType
 		require.True(t, errors.As(err, &phErr))
 
-		wantMessage := "Wrong file format. File should be jpg or jpeg"
+		wantMessage := "Wrong file format. File should be jpg, png or webp"
 		require.NotNil(t, phErr.Payload.Message)
 		assert.Equal(t, wantMessage, *phErr.Payload.Message)
 		f.Close()
@@ -27,7 +27,7 @@ func TestIntegration_ChangePassword(t *testing.T) {
 
 	loginUser, err := utils.LoginUser(ctx, client, l, p)
 	require.NoError(t, err)
-	newPassword := "newPassword"
+	newPassword := "newPassword1"
 
 	token := loginUser.GetPayload().AccessToken
 	t.Run("non-valid old password", func(t *testing.T) {
@@ -37,7 +37,7 @@ func TestIntegration_Login(t *testing.T) {
 		params.SetContext(ctx)
 		params.SetHTTPClient(http.DefaultClient)
 
-		login, err := client.Users.Login(params)
+		login, _, err := client.Users.Login(params)
 		require.NoError(t, err)
 
 		require.NotNil(t, login.GetPayload())
@@ -54,7 +54,7 @@ func TestIntegration_Login(t *testing.T) {
 		params.SetContext(ctx)
 		params.SetHTTPClient(http.DefaultClient)
 
-		_, err := client.Users.Login(params)
+		_, _, err := client.Users.Login(params)
 		require.Error(t, err)
 
 		gotErr, ok := err.(*users.LoginUnauthorized)
@@ -77,7 +77,7 @@ func TestIntegration_Login(t *testing.T) {
 		params.SetContext(ctx)
 		params.SetHTTPClient(http.DefaultClient)
 
-		_, err := client.Users.Login(params)
+		_, _, err := client.Users.Login(params)
 		require.Error(t, err)
 
 		gotErr, ok := err.(*users.LoginUnauthorized)
@@ -96,7 +96,7 @@ func TestIntegration_Login(t *testing.T) {
 		params.SetContext(ctx)
 		params.SetHTTPClient(http.DefaultClient)
 
-		_, err := client.Users.Login(params)
+		_, _, err := client.Users.Login(params)
 		require.Error(t, err)
 
 		gotErr, ok := err.(*users.LoginDefault)
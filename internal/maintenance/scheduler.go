@@ -0,0 +1,145 @@
+package maintenance
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+// timeNow necessary for mock time in tests
+var timeNow = time.Now
+
+type maintenanceScheduler struct {
+	planRepository            domain.MaintenancePlanRepository
+	equipmentStatusRepository domain.EquipmentStatusRepository
+	logger                    *zap.Logger
+}
+
+func NewMaintenanceScheduler(planRepository domain.MaintenancePlanRepository,
+	equipmentStatusRepository domain.EquipmentStatusRepository, logger *zap.Logger) domain.MaintenanceScheduler {
+	return &maintenanceScheduler{
+		planRepository:            planRepository,
+		equipmentStatusRepository: equipmentStatusRepository,
+		logger:                    logger,
+	}
+}
+
+// Schedule finds the equipment which is due for the maintenance by its plans and makes it not available
+// for the duration of the plan in the first gap between the bookings. The maintenance is due after
+// the number of rentals or days of the plan since the last maintenance or since the plan was created.
+// The equipment isn't scheduled again until its scheduled maintenance ends.
+func (s *maintenanceScheduler) Schedule(ctx context.Context, cln *ent.Client) (err error) {
+	tx, err := cln.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+
+	now := timeNow()
+	plans, err := s.planRepository.MaintenancePlans(ctx)
+	if err != nil {
+		return err
+	}
+	scheduled := 0
+	for _, plan := range plans {
+		for _, eq := range planEquipment(plan) {
+			var isScheduled bool
+			isScheduled, err = s.scheduleEquipment(ctx, plan, eq.ID, now)
+			if err != nil {
+				return err
+			}
+			if isScheduled {
+				scheduled++
+			}
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	if scheduled > 0 {
+		s.logger.Info("maintenance scheduled", zap.Int("equipment", scheduled))
+	}
+	return nil
+}
+
+func (s *maintenanceScheduler) scheduleEquipment(ctx context.Context, plan *ent.MaintenancePlan, equipmentID int,
+	now time.Time) (bool, error) {
+	since := plan.CreatedAt
+	last, err := s.planRepository.LastMaintenance(ctx, plan.ID, equipmentID)
+	if err != nil {
+		return false, err
+	}
+	if last != nil {
+		if last.EndDate.After(now) {
+			return false, nil
+		}
+		since = last.EndDate
+	}
+
+	due := plan.EveryDays != nil && !now.Before(since.AddDate(0, 0, *plan.EveryDays))
+	if !due && plan.EveryRentals != nil {
+		rentals, errCount := s.planRepository.RentalsSince(ctx, equipmentID, since)
+		if errCount != nil {
+			return false, errCount
+		}
+		due = rentals >= *plan.EveryRentals
+	}
+	if !due {
+		return false, nil
+	}
+
+	bookings, err := s.equipmentStatusRepository.GetUnavailableEquipmentStatusByEquipmentID(ctx, equipmentID)
+	if err != nil {
+		return false, err
+	}
+	duration := time.Duration(plan.DurationDays) * 24 * time.Hour
+	start := firstGap(bookings, now, duration)
+	if _, err = s.planRepository.ScheduleMaintenance(ctx, plan.ID, equipmentID, start,
+		start.Add(duration)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// planEquipment returns the equipment of the plan or all equipment of its category,
+// the plan of the deleted equipment or category has none.
+func planEquipment(plan *ent.MaintenancePlan) []*ent.Equipment {
+	if plan.Edges.Equipment != nil {
+		return []*ent.Equipment{plan.Edges.Equipment}
+	}
+	if plan.Edges.Category != nil {
+		return plan.Edges.Category.Edges.Equipments
+	}
+	return nil
+}
+
+// firstGap returns the earliest start after the time when the equipment is free for the duration.
+func firstGap(bookings []*ent.EquipmentStatus, from time.Time, duration time.Duration) time.Time {
+	sorted := make([]*ent.EquipmentStatus, len(bookings))
+	copy(sorted, bookings)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].StartDate.Before(sorted[j].StartDate)
+	})
+	start := from
+	for _, booking := range sorted {
+		if !start.Add(duration).After(booking.StartDate) {
+			break
+		}
+		if booking.EndDate.After(start) {
+			start = booking.EndDate
+		}
+	}
+	return start
+}
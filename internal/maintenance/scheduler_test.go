@@ -0,0 +1,122 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/enttest"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/mocks"
+)
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestMaintenanceScheduler_Schedule(t *testing.T) {
+	ctx := context.Background()
+	client := enttest.Open(t, "sqlite3", "file:maintenancescheduler?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+
+	now := time.Date(2026, time.May, 4, 10, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+	day := 24 * time.Hour
+
+	byRentals := &ent.MaintenancePlan{ID: 1, EveryRentals: intPtr(5), DurationDays: 1,
+		CreatedAt: now.Add(-100 * day),
+		Edges:     ent.MaintenancePlanEdges{Equipment: &ent.Equipment{ID: 10}}}
+	byDays := &ent.MaintenancePlan{ID: 2, EveryDays: intPtr(90), DurationDays: 2,
+		CreatedAt: now.Add(-200 * day),
+		Edges: ent.MaintenancePlanEdges{Category: &ent.Category{Edges: ent.CategoryEdges{
+			Equipments: []*ent.Equipment{{ID: 20}, {ID: 21}, {ID: 22}},
+		}}}}
+	withoutTarget := &ent.MaintenancePlan{ID: 3, EveryDays: intPtr(1), DurationDays: 1}
+
+	planRepository := &mocks.MaintenancePlanRepository{}
+	planRepository.On("MaintenancePlans", mock.Anything).
+		Return([]*ent.MaintenancePlan{byRentals, byDays, withoutTarget}, nil)
+	// the fifth rental since the plan was created, booked tomorrow, so maintained after the booking
+	planRepository.On("LastMaintenance", mock.Anything, 1, 10).Return(nil, nil)
+	planRepository.On("RentalsSince", mock.Anything, 10, byRentals.CreatedAt).Return(5, nil)
+	// maintained 100 days ago
+	lastDone := &ent.MaintenanceWindow{EndDate: now.Add(-100 * day)}
+	planRepository.On("LastMaintenance", mock.Anything, 2, 20).Return(lastDone, nil)
+	// maintained 10 days ago
+	planRepository.On("LastMaintenance", mock.Anything, 2, 21).
+		Return(&ent.MaintenanceWindow{EndDate: now.Add(-10 * day)}, nil)
+	// the maintenance is in progress
+	planRepository.On("LastMaintenance", mock.Anything, 2, 22).
+		Return(&ent.MaintenanceWindow{EndDate: now.Add(day)}, nil)
+
+	equipmentStatusRepository := &mocks.EquipmentStatusRepository{}
+	booking := &ent.EquipmentStatus{StartDate: now.Add(12 * time.Hour), EndDate: now.Add(3 * day)}
+	equipmentStatusRepository.On("GetUnavailableEquipmentStatusByEquipmentID", mock.Anything, 10).
+		Return([]*ent.EquipmentStatus{booking}, nil)
+	equipmentStatusRepository.On("GetUnavailableEquipmentStatusByEquipmentID", mock.Anything, 20).
+		Return([]*ent.EquipmentStatus{}, nil)
+
+	planRepository.On("ScheduleMaintenance", mock.Anything, 1, 10, booking.EndDate, booking.EndDate.Add(day)).
+		Return(&ent.MaintenanceWindow{}, nil)
+	planRepository.On("ScheduleMaintenance", mock.Anything, 2, 20, now, now.Add(2*day)).
+		Return(&ent.MaintenanceWindow{}, nil)
+
+	scheduler := NewMaintenanceScheduler(planRepository, equipmentStatusRepository, zap.NewNop())
+	require.NoError(t, scheduler.Schedule(ctx, client))
+	planRepository.AssertExpectations(t)
+	equipmentStatusRepository.AssertExpectations(t)
+}
+
+func TestMaintenanceScheduler_Schedule_NotDue(t *testing.T) {
+	ctx := context.Background()
+	client := enttest.Open(t, "sqlite3", "file:maintenancescheduler?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+
+	plan := &ent.MaintenancePlan{ID: 1, EveryRentals: intPtr(5), EveryDays: intPtr(90), DurationDays: 1,
+		CreatedAt: time.Now().Add(-24 * time.Hour),
+		Edges:     ent.MaintenancePlanEdges{Equipment: &ent.Equipment{ID: 10}}}
+	planRepository := &mocks.MaintenancePlanRepository{}
+	planRepository.On("MaintenancePlans", mock.Anything).Return([]*ent.MaintenancePlan{plan}, nil)
+	planRepository.On("LastMaintenance", mock.Anything, 1, 10).Return(nil, nil)
+	planRepository.On("RentalsSince", mock.Anything, 10, plan.CreatedAt).Return(4, nil)
+	equipmentStatusRepository := &mocks.EquipmentStatusRepository{}
+
+	scheduler := NewMaintenanceScheduler(planRepository, equipmentStatusRepository, zap.NewNop())
+	require.NoError(t, scheduler.Schedule(ctx, client))
+	planRepository.AssertExpectations(t)
+	equipmentStatusRepository.AssertExpectations(t)
+}
+
+func TestMaintenanceScheduler_Schedule_RepoErr(t *testing.T) {
+	ctx := context.Background()
+	client := enttest.Open(t, "sqlite3", "file:maintenancescheduler?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+
+	err := errors.New("error")
+	planRepository := &mocks.MaintenancePlanRepository{}
+	planRepository.On("MaintenancePlans", mock.Anything).Return(nil, err)
+
+	scheduler := NewMaintenanceScheduler(planRepository, &mocks.EquipmentStatusRepository{}, zap.NewNop())
+	require.ErrorIs(t, scheduler.Schedule(ctx, client), err)
+	planRepository.AssertExpectations(t)
+}
+
+func TestFirstGap(t *testing.T) {
+	from := time.Date(2026, time.May, 4, 10, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	bookings := []*ent.EquipmentStatus{
+		{StartDate: from.Add(5 * day), EndDate: from.Add(7 * day)},
+		{StartDate: from.Add(-day), EndDate: from.Add(day)},
+		{StartDate: from.Add(2 * day), EndDate: from.Add(4 * day)},
+	}
+	require.Equal(t, from, firstGap(nil, from, day))
+	require.Equal(t, from.Add(day), firstGap(bookings, from, day))
+	require.Equal(t, from.Add(7*day), firstGap(bookings, from, 2*day))
+}
@@ -5,6 +5,12 @@ package messages
 var (
 	MsgAllOk = "all ok"
 
+	// Access Policy
+
+	ErrAccessPolicyForbidden = "only administrators can inspect the access policy"
+	ErrAccessPolicySubject   = "role or user must be set"
+	ErrAccessPolicyUser      = "can't get user"
+
 	// Area
 
 	ErrQueryTotalAreas = "failed to query total active areas"
@@ -20,6 +26,21 @@ var (
 	ErrUpdateCategory       = "cant update category"
 	MsgCategoryDeleted      = "category deleted"
 
+	// Damage Claims
+
+	ErrCreateDamageClaim         = "can't create damage claim"
+	ErrGetDamageClaim            = "can't get damage claim"
+	ErrQueryDamageClaims         = "can't get damage claims"
+	ErrUpdateDamageClaim         = "can't update damage claim"
+	ErrDamageClaimNotFound       = "damage claim not found"
+	ErrDamageClaimTargetNotFound = "order or equipment not found"
+	ErrEquipmentNotInOrder       = "equipment is not in the order"
+	ErrDamageClaimExists         = "equipment already has a damage claim in the order"
+	ErrDamageClaimNotOpen        = "damage claim is already paid or waived"
+	ErrDamageClaimOrderActive    = "damage can be claimed only for the equipment of the closed order"
+	ErrPutEquipmentInRepair      = "can't put the damaged equipment in repair"
+	ErrEquipmentHasDamageClaims  = "equipment with damage claims can't be deleted"
+
 	// Email Confirmation
 
 	ErrEmailConfirm   = "failed to verify email confirmation token"
@@ -45,6 +66,13 @@ var (
 	ErrGetLastEqStatus          = "can't get last equipment status"
 	ErrEquipmentIsNotBlocked    = "equipment is not blocked"
 
+	// Equipment Photos
+
+	ErrQueryEquipmentPhotos    = "can't get equipment photos"
+	ErrUpdateEquipmentPhotos   = "can't update equipment photos"
+	ErrEquipmentPhotoNotFound  = "photo not found"
+	ErrEquipmentPhotosMismatch = "photos must contain every photo of the equipment once"
+
 	// Equipment
 
 	ErrCreateEquipment            = "error while creating equipment"
@@ -66,12 +94,49 @@ var (
 	ErrEndDateBeforeCurrentDate   = "End date must be after current date"
 	MsgEquipmentDeleted           = "equipment deleted"
 
+	// Groups
+
+	ErrGroupsForbidden     = "only administrators can manage groups and permissions"
+	ErrQueryPermissions    = "can't get permissions"
+	ErrQueryGroups         = "can't get groups"
+	ErrGetGroup            = "can't get group"
+	ErrGroupNotFound       = "group not found"
+	ErrGroupAlreadyExists  = "group with this name already exists"
+	ErrCreateGroup         = "can't create group"
+	ErrUpdateGroup         = "can't update group"
+	ErrDeleteGroup         = "can't delete group"
+	ErrUnknownPermission   = "unknown permission"
+	ErrSetGroupPermissions = "can't set group permissions"
+	ErrUnknownUser         = "unknown user"
+	ErrAddGroupUsers       = "can't add users to group"
+	ErrRemoveGroupUser     = "can't remove user from group"
+	MsgGroupDeleted        = "group deleted"
+
+	// Maintenance
+
+	ErrCreateMaintenancePlan     = "can't create maintenance plan"
+	ErrQueryMaintenancePlans     = "can't get maintenance plans"
+	ErrDeleteMaintenancePlan     = "can't delete maintenance plan"
+	ErrMaintenancePlanNotFound   = "maintenance plan not found"
+	ErrMaintenancePlanTarget     = "maintenance plan must have either equipment or category"
+	ErrMaintenancePlanInterval   = "maintenance plan must have the number of rentals or days"
+	ErrMaintenanceTargetNotFound = "equipment or category not found"
+	ErrQueryUpcomingMaintenance  = "can't get upcoming maintenance"
+
+	// OIDC
+
+	ErrOIDCStartLogin         = "can't start login through the identity provider"
+	ErrOIDCLogin              = "login through the identity provider failed"
+	ErrOIDCAccountNotLinkable = "user with this email already exists, log in with the password to link the account"
+
 	// Order Status
 
 	ErrQueryOrderHistory                 = "can't get order history"
 	ErrQueryOrderHistoryForbidden        = "you don't have rights to see this order"
 	ErrCreateOrderStatusForbidden        = "you don't have rights to add a new status"
 	ErrOrderStatusEmpty                  = "order status is empty"
+	ErrOrderStatusReportNotAllowed       = "photos and condition note can be added only to in progress and closed statuses"
+	ErrOrderStatusPhotoNotFound          = "photo of the order status is not found"
 	ErrGetOrderStatus                    = "can't get order current status"
 	ErrUpdateOrderStatus                 = "can't update status"
 	ErrQueryTotalOrdersByStatus          = "can't get total count of orders by status"
@@ -83,19 +148,49 @@ var (
 
 	// Order
 
-	ErrOrderNotFound       = "no order with such id"
-	ErrMapOrder            = "can't map order"
-	ErrQueryOrders         = "can't get orders"
-	ErrQueryTotalOrders    = "error while getting total of orders"
-	ErrUpdateOrder         = "update order failed"
-	ErrEquipmentIsNotFree  = "requested equipment is not free"
-	ErrCheckEqStatusFailed = "error while checking if equipment is available for period"
-	ErrSmallRentPeriod     = "small rent period"
+	ErrOrderNotFound        = "no order with such id"
+	ErrMapOrder             = "can't map order"
+	ErrQueryOrders          = "can't get orders"
+	ErrQueryTotalOrders     = "error while getting total of orders"
+	ErrUpdateOrder          = "update order failed"
+	ErrUpdateOrderForbidden = "you don't have rights to update this order"
+	ErrCreateOrderForbidden = "you can place orders only on behalf of your organizations"
+	ErrEquipmentIsNotFree   = "requested equipment is not free"
+	ErrCheckEqStatusFailed  = "error while checking if equipment is available for period"
+	ErrSmallRentPeriod      = "small rent period"
+
+	// Organizations
+
+	ErrQueryOrganizations         = "can't get organizations"
+	ErrGetOrganization            = "can't get organization"
+	ErrOrganizationNotFound       = "organization not found"
+	ErrOrganizationForbidden      = "you are not a member of this organization"
+	ErrOrganizationManage         = "only owners of the organization can manage its members"
+	ErrCreateOrganization         = "can't create organization"
+	ErrSetOrganizationMember      = "can't set organization member"
+	ErrRemoveOrganizationMember   = "can't remove organization member"
+	ErrOrganizationMemberNotFound = "user is not a member of the organization"
+	ErrLastOrganizationOwner      = "organization must have at least one owner"
+
+	// Password Policy
+
+	ErrPasswordPolicy        = "password does not meet the requirements"
+	ErrPasswordTooShort      = "password must be at least %d characters long"
+	ErrPasswordNoLowercase   = "password must contain a lowercase letter"
+	ErrPasswordNoUppercase   = "password must contain an uppercase letter"
+	ErrPasswordNoDigit       = "password must contain a digit"
+	ErrPasswordNoSpecial     = "password must contain a special character"
+	ErrPasswordEqualUserData = "password must not be equal to the login or email"
+	ErrPasswordTooCommon     = "password is too common"
 
 	// Password Reset
 
 	ErrLoginRequired          = "login is required"
+	ErrPasswordResetToken     = "password reset link is invalid or expired"
+	ErrPasswordReset          = "error while resetting password"
 	MsgPasswordResetSuccesful = "check your email for a reset link"
+	MsgPasswordResetLinkValid = "password reset link is valid"
+	MsgPasswordResetDone      = "password has been changed, please log in with the new password"
 
 	// Pet Kind
 
@@ -118,12 +213,16 @@ var (
 
 	// Photo
 
-	ErrCreatePhoto  = "failed to save photo"
-	ErrFileEmpty    = "File is empty"
-	ErrWrongFormat  = "Wrong file format. File should be jpg or jpeg"
-	ErrGetPhoto     = "failed to get photo"
-	ErrDeletePhoto  = "failed to delete photo"
-	MsgPhotoDeleted = "photo deleted"
+	ErrCreatePhoto    = "failed to save photo"
+	ErrFileEmpty      = "File is empty"
+	ErrWrongFormat    = "Wrong file format. File should be jpg, png or webp"
+	ErrFileTooLarge   = "File is too large"
+	ErrImageTooLarge  = "Image dimensions are too large"
+	ErrGetPhoto       = "failed to get photo"
+	ErrDeletePhoto    = "failed to delete photo"
+	ErrPhotoInUse     = "photo is attached to equipment or an order status"
+	MsgPhotoDeleted   = "photo deleted"
+	ErrPhotoForbidden = "you don't have rights to manage photos"
 
 	// Registration Confirm
 
@@ -149,6 +248,19 @@ var (
 	ErrUpdateSubcategory   = "failed to update subcategory"
 	MsgSubcategoryDeleted  = "subcategory deleted"
 
+	// Two-factor authentication
+
+	ErrTwoFactorEnroll         = "can't start two-factor authentication enrolment"
+	ErrTwoFactorActivate       = "can't activate two-factor authentication"
+	ErrTwoFactorDisable        = "can't disable two-factor authentication"
+	ErrTwoFactorRecoveryCodes  = "can't generate recovery codes"
+	ErrTwoFactorChallenge      = "can't create two-factor authentication challenge"
+	ErrTwoFactorAlreadyEnabled = "two-factor authentication is already enabled"
+	ErrTwoFactorNotEnrolled    = "two-factor authentication is not enabled"
+	ErrTwoFactorMandatory      = "two-factor authentication is mandatory for your role"
+	ErrInvalidTwoFactorCode    = "invalid two-factor authentication code"
+	ErrTwoFactorCodeEmpty      = "two-factor authentication code is empty"
+
 	// User
 
 	ErrInvalidLoginOrPass   = "invalid login or password"
@@ -165,6 +277,10 @@ var (
 	ErrQueryUsers           = "failed to get user list"
 	ErrDeleteUser           = "can't delete user"
 	ErrDeleteUserNotRO      = "user must be readonly for deletion"
+	ErrRestoreUser          = "can't restore user"
+	ErrRestoreUserForbidden = "only administrators can restore users"
+	ErrUserNotDeleted       = "user is not deleted"
+	ErrRestorePeriodExpired = "retention period of the deleted user is over, it can't be restored"
 	ErrUserPasswordChange   = "error while changing password"
 	ErrWrongPassword        = "wrong password"
 	ErrPasswordsAreSame     = "old and new passwords are the same"
@@ -173,6 +289,8 @@ var (
 	ErrChangeEmail          = "error while changing email"
 	ErrEmailPatchEmpty      = "email patch is empty"
 	ErrNewEmailConfirmation = "can't send link for confirmation new email"
+	ErrUserForbidden        = "you don't have rights to access this user"
+	ErrExportUser           = "can't export user data"
 	MsgLogoutSuccessful     = "successfully logged out"
 	MsgRoleAssigned         = "role assigned"
 )
@@ -3,8 +3,9 @@ package middlewares
 import (
 	"fmt"
 	"net/http"
-	"regexp"
+	"sort"
 	"strings"
+	"sync"
 
 	openApiErrors "github.com/go-openapi/errors"
 
@@ -16,17 +17,30 @@ const apiPrefix = "/api"
 
 const numRoleVariations = 8
 
+var errTwoFactorMustBeEnabled = openApiErrors.New(http.StatusForbidden, "two-factor authentication must be enabled")
+
 type AccessManager interface {
 	AddNewAccess(role Role, method, path string) (bool, error)
+	ReplaceAccess(accesses []Access) (added, removed []Access, err error)
 	VerifyAccess(role Role, method, path string) error
+	RequireTwoFactor(setupEndpoints ExistingEndpoints) error
 	Authorize(r *http.Request, i interface{}) error
+	Explain(principal *models.Principal, method, path string) Decision
 }
 
 type blackListAccessManager struct {
 	endpoints       ExistingEndpoints
+	router          *router
 	acceptableRoles []Role
 	fullAccessRoles []Role
-	accessMap       map[Role]map[string][]path
+	// mu guards accessMap and accesses which are replaced together when the access bindings are reloaded.
+	// accessMap holds the endpoint path templates allowed for the role by the method.
+	mu        sync.RWMutex
+	accessMap map[Role]map[string][]string
+	accesses  []Access
+	// twoFactorSetupEndpoints is not nil when full access roles must use two-factor authentication.
+	// Only these endpoints are available for them until two-factor authentication is enabled.
+	twoFactorSetupEndpoints ExistingEndpoints
 }
 
 type ExistingEndpoints map[string][]string
@@ -49,6 +63,25 @@ func (e ExistingEndpoints) Validate() error {
 	return nil
 }
 
+// Access is the endpoint allowed for the role.
+type Access struct {
+	Role   Role
+	Method string
+	Path   string
+}
+
+// Decision explains why the access to the endpoint is granted or denied.
+type Decision struct {
+	Allowed bool
+	Reason  string
+	// Access is the access binding which grants the endpoint.
+	Access *Access
+	// Permission is the permission of the user groups which grants the endpoint.
+	Permission string
+	// twoFactorRequired is set when nothing but two-factor authentication setup is available for the user.
+	twoFactorRequired bool
+}
+
 type Role struct {
 	Slug                    string
 	IsRegistrationConfirmed bool
@@ -62,13 +95,18 @@ func NewAccessManager(acceptableRoles, fullAccessRoles []Role, endpoints Existin
 	if err := endpoints.Validate(); err != nil {
 		return nil, err
 	}
+	endpointsRouter, err := newRouter(endpoints)
+	if err != nil {
+		return nil, err
+	}
 	acceptableRoleVariations := allRoleVariation(acceptableRoles)
 	fullAccessRoleVariations := allRoleVariation(fullAccessRoles)
 	return &blackListAccessManager{
 		endpoints:       endpoints,
+		router:          endpointsRouter,
 		acceptableRoles: acceptableRoleVariations,
 		fullAccessRoles: fullAccessRoleVariations,
-		accessMap:       make(map[Role]map[string][]path),
+		accessMap:       make(map[Role]map[string][]string),
 	}, nil
 }
 
@@ -94,58 +132,49 @@ func allRoleVariation(roles []Role) []Role {
 	return res
 }
 
-type path struct {
-	asString string
-	asRegexp *regexp.Regexp
+func endpointConversion(path string) string {
+	return apiPrefix + path
 }
 
-func (p *path) isMatch(st string) bool {
-	endpointPath := normalizePath(st)
-	if p.asString != "" {
-		return p.asString == endpointPath
-	}
-	return p.asRegexp.MatchString(endpointPath)
+// AddNewAccess adds new access to the access manager. Returns true if access was added, false if access was not added
+func (a *blackListAccessManager) AddNewAccess(role Role, endpointMethod, endpointPath string) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.addAccess(a.accessMap, &a.accesses, role, endpointMethod, endpointPath)
 }
 
-func normalizePath(endpointPath string) string {
-	if !strings.HasPrefix(endpointPath, "/") {
-		endpointPath = "/" + endpointPath
+// ReplaceAccess replaces all added accesses with the new ones. The new accesses are validated before the swap,
+// so the current accesses stay in effect if any of them is invalid.
+// It returns the accesses which were added and removed by the replacement.
+func (a *blackListAccessManager) ReplaceAccess(accesses []Access) ([]Access, []Access, error) {
+	accessMap := make(map[Role]map[string][]string)
+	var newAccesses []Access
+	for _, access := range accesses {
+		if _, err := a.addAccess(accessMap, &newAccesses, access.Role, access.Method, access.Path); err != nil {
+			return nil, nil, err
+		}
 	}
-	endpointPath = strings.TrimSuffix(endpointPath, "/")
-	return endpointPath
-}
 
-func newPath(endpointPath string) (path, error) {
-	if strings.Contains(endpointPath, "{") {
-		first := strings.Index(endpointPath, "{")
-		second := strings.Index(endpointPath, "}")
-		if second < first {
-			return path{}, fmt.Errorf("incorrect path %s", endpointPath)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var added, removed []Access
+	for _, access := range newAccesses {
+		if !utils.IsValueInList(access, a.accesses) {
+			added = append(added, access)
 		}
-		endpointPath = endpointPath[:first] + "(.*)" + endpointPath[second+1:]
-		reg, err := regexp.Compile(endpointPath)
-		if err != nil {
-			return path{}, err
-		}
-		return path{
-			asRegexp: reg,
-		}, nil
 	}
-	if strings.Contains(endpointPath, "{") {
-		return path{}, fmt.Errorf("incorrect path %s", endpointPath)
+	for _, access := range a.accesses {
+		if !utils.IsValueInList(access, newAccesses) {
+			removed = append(removed, access)
+		}
 	}
-
-	return path{
-		asString: endpointPath,
-	}, nil
+	a.accessMap = accessMap
+	a.accesses = newAccesses
+	return added, removed, nil
 }
 
-func endpointConversion(path string) string {
-	return apiPrefix + path
-}
-
-// AddNewAccess adds new access to the access manager. Returns true if access was added, false if access was not added
-func (a *blackListAccessManager) AddNewAccess(role Role, endpointMethod, endpointPath string) (bool, error) {
+func (a *blackListAccessManager) addAccess(accessMap map[Role]map[string][]string, accesses *[]Access,
+	role Role, endpointMethod, endpointPath string) (bool, error) {
 	if !utils.IsValueInList(role, a.acceptableRoles) {
 		return false, fmt.Errorf("role %v is not in the list of acceptable roles", role)
 	}
@@ -160,34 +189,19 @@ func (a *blackListAccessManager) AddNewAccess(role Role, endpointMethod, endpoin
 	if !utils.IsValueInList(endpointPath, paths) {
 		return false, fmt.Errorf("path %s is not in the list of existing endpoints", endpointPath)
 	}
-
-	newEndpointPath, err := newPath(endpointConversion(endpointPath))
-	if err != nil {
-		return false, err
+	access := Access{Role: role, Method: endpointMethod, Path: endpointPath}
+	if utils.IsValueInList(access, *accesses) {
+		return false, nil
 	}
 
-	endpointsByRole, ok2 := a.accessMap[role]
-	if !ok2 {
-		endpointsByRole = make(map[string][]path)
-		a.accessMap[role] = endpointsByRole
-	}
-	pathToUpdate, ok2 := endpointsByRole[endpointMethod]
-	if !ok2 {
-		pathToUpdate = []path{
-			newEndpointPath,
-		}
-		endpointsByRole[endpointMethod] = pathToUpdate
-		a.accessMap[role] = endpointsByRole
-		return true, nil
-	}
-	if !utils.IsValueInList(newEndpointPath, pathToUpdate) {
-		pathToUpdate = append(pathToUpdate, newEndpointPath)
-		endpointsByRole[endpointMethod] = pathToUpdate
-		a.accessMap[role] = endpointsByRole
-		return true, nil
+	endpointsByRole, ok := accessMap[role]
+	if !ok {
+		endpointsByRole = make(map[string][]string)
+		accessMap[role] = endpointsByRole
 	}
-	return false, nil
-
+	endpointsByRole[endpointMethod] = append(endpointsByRole[endpointMethod], endpointPath)
+	*accesses = append(*accesses, access)
+	return true, nil
 }
 
 // VerifyAccess checks if role has access to the endpoint.
@@ -197,14 +211,14 @@ func (a *blackListAccessManager) VerifyAccess(role Role, method, path string) er
 	if utils.IsValueInList(role, a.fullAccessRoles) {
 		return nil
 	}
+	a.mu.RLock()
 	allowedPaths, ok := a.accessMap[role][method]
+	a.mu.RUnlock()
 	if !ok {
 		return openApiErrors.New(http.StatusForbidden, "user is not authorized")
 	}
-	for _, allowedPath := range allowedPaths {
-		if allowedPath.isMatch(path) {
-			return nil
-		}
+	if template, ok := a.router.route(method, path); ok && utils.IsValueInList(template, allowedPaths) {
+		return nil
 	}
 	if !role.IsRegistrationConfirmed {
 		return openApiErrors.New(http.StatusForbidden, "user has no confirmed email")
@@ -212,18 +226,111 @@ func (a *blackListAccessManager) VerifyAccess(role Role, method, path string) er
 	return openApiErrors.New(http.StatusForbidden, "user is not authorized")
 }
 
+// RequireTwoFactor makes two-factor authentication mandatory for full access roles.
+// Users of these roles without enabled two-factor authentication can reach only setupEndpoints.
+func (a *blackListAccessManager) RequireTwoFactor(setupEndpoints ExistingEndpoints) error {
+	if err := setupEndpoints.Validate(); err != nil {
+		return err
+	}
+	setup := make(ExistingEndpoints)
+	for method, paths := range setupEndpoints {
+		method = strings.ToUpper(method)
+		for _, endpointPath := range paths {
+			if !utils.IsValueInList(endpointPath, a.endpoints[method]) {
+				return fmt.Errorf("path %s is not in the list of existing endpoints", endpointPath)
+			}
+			setup[method] = append(setup[method], endpointPath)
+		}
+	}
+	a.twoFactorSetupEndpoints = setup
+	return nil
+}
+
+func (a *blackListAccessManager) verifyTwoFactor(role Role, isTwoFactorEnabled bool, method, path string) error {
+	if a.twoFactorSetupEndpoints == nil || isTwoFactorEnabled || !utils.IsValueInList(role, a.fullAccessRoles) {
+		return nil
+	}
+	if template, ok := a.router.route(method, path); ok &&
+		utils.IsValueInList(template, a.twoFactorSetupEndpoints[method]) {
+		return nil
+	}
+	return errTwoFactorMustBeEnabled
+}
+
 func (a *blackListAccessManager) Authorize(r *http.Request, auth interface{}) error {
 	principal, ok := auth.(*models.Principal)
 	if !ok {
 		return openApiErrors.New(http.StatusForbidden, "user is not authorized")
 	}
 
-	role := Role{
+	role := principalRole(principal)
+
+	if err := a.verifyTwoFactor(role, principal.IsTwoFactorEnabled, r.Method, r.URL.Path); err != nil {
+		return err
+	}
+
+	return a.VerifyAccess(role, r.Method, r.URL.Path)
+}
+
+// Explain tells whether the principal has access to the endpoint and which access binding grants it
+// or why the access is denied. The path is the request path with or without the api prefix.
+func (a *blackListAccessManager) Explain(principal *models.Principal, method, endpointPath string) Decision {
+	method = strings.ToUpper(method)
+	role := principalRole(principal)
+
+	template, ok := a.router.route(method, endpointPath)
+	if !ok {
+		return Decision{Reason: fmt.Sprintf("%s %s is not an existing endpoint", method, endpointPath)}
+	}
+	if !utils.IsValueInList(role, a.acceptableRoles) {
+		return Decision{Reason: fmt.Sprintf("role %s is not in the list of acceptable roles", role.Slug)}
+	}
+	if err := a.verifyTwoFactor(role, principal.IsTwoFactorEnabled, method, endpointPath); err != nil {
+		return Decision{Reason: err.Error(), twoFactorRequired: true}
+	}
+	if utils.IsValueInList(role, a.fullAccessRoles) {
+		return Decision{Allowed: true, Reason: fmt.Sprintf("role %s has access to all endpoints", role.Slug)}
+	}
+	if a.hasAccess(role, method, template) {
+		access := Access{Role: role, Method: method, Path: template}
+		return Decision{Allowed: true, Reason: "granted by the access binding", Access: &access}
+	}
+	if !role.IsRegistrationConfirmed {
+		confirmedRole := role
+		confirmedRole.IsRegistrationConfirmed = true
+		if a.hasAccess(confirmedRole, method, template) {
+			return Decision{Reason: "user has no confirmed email, the endpoint is granted only after the confirmation"}
+		}
+	}
+	return Decision{Reason: "no access binding of the role grants the endpoint"}
+}
+
+func (a *blackListAccessManager) hasAccess(role Role, method, template string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return utils.IsValueInList(template, a.accessMap[role][method])
+}
+
+func principalRole(principal *models.Principal) Role {
+	return Role{
 		Slug:                    principal.Role,
 		IsRegistrationConfirmed: principal.IsRegistrationConfirmed,
 		IsPersonalDataConfirmed: principal.IsPersonalDataConfirmed,
 		IsReadonly:              principal.IsReadonly,
 	}
+}
 
-	return a.VerifyAccess(role, r.Method, r.URL.Path)
+// AllowedEndpoints returns the endpoints which the principal has access to, paths are sorted.
+func AllowedEndpoints(manager AccessManager, endpoints ExistingEndpoints,
+	principal *models.Principal) ExistingEndpoints {
+	allowed := make(ExistingEndpoints)
+	for method, paths := range endpoints {
+		for _, endpointPath := range paths {
+			if manager.Explain(principal, method, endpointPath).Allowed {
+				allowed[method] = append(allowed[method], endpointPath)
+			}
+		}
+		sort.Strings(allowed[method])
+	}
+	return allowed
 }
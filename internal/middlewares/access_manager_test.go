@@ -215,3 +215,123 @@ func Test_blackListAccessManager(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func Test_blackListAccessManager_RequireTwoFactor(t *testing.T) {
+	const setupPath = "/v1/users/me/2fa"
+	roles := []Role{{Slug: userRole}, {Slug: adminRole}}
+	fullAccessRoles := []Role{{Slug: adminRole}}
+	endpoints := ExistingEndpoints{
+		http.MethodGet:  {simpleValidPath},
+		http.MethodPost: {setupPath},
+	}
+	manager, err := NewAccessManager(roles, fullAccessRoles, endpoints)
+	assert.NoError(t, err)
+	_, err = manager.AddNewAccess(Role{Slug: userRole, IsRegistrationConfirmed: true}, http.MethodGet, simpleValidPath)
+	assert.NoError(t, err)
+
+	err = manager.RequireTwoFactor(ExistingEndpoints{http.MethodPost: {simpleInvalidPath}})
+	assert.Error(t, err)
+	err = manager.RequireTwoFactor(ExistingEndpoints{http.MethodPost: {setupPath}})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		principal    *models.Principal
+		method, path string
+		hasAccess    bool
+	}{
+		{
+			name:      "admin without 2fa can't use other endpoints",
+			principal: &models.Principal{Role: adminRole},
+			method:    http.MethodGet,
+			path:      endpointConversion(simpleValidPath),
+		},
+		{
+			name:      "admin without 2fa can enroll",
+			principal: &models.Principal{Role: adminRole},
+			method:    http.MethodPost,
+			path:      endpointConversion(setupPath),
+			hasAccess: true,
+		},
+		{
+			name:      "admin with 2fa has full access",
+			principal: &models.Principal{Role: adminRole, IsTwoFactorEnabled: true},
+			method:    http.MethodGet,
+			path:      endpointConversion(simpleValidPath),
+			hasAccess: true,
+		},
+		{
+			name:      "user is not affected",
+			principal: &models.Principal{Role: userRole, IsRegistrationConfirmed: true},
+			method:    http.MethodGet,
+			path:      endpointConversion(simpleValidPath),
+			hasAccess: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			request := &http.Request{Method: tc.method, URL: &url.URL{Path: tc.path}}
+			err := manager.Authorize(request, tc.principal)
+			if tc.hasAccess {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+			assert.Equal(t, tc.hasAccess, manager.Explain(tc.principal, tc.method, tc.path).Allowed)
+		})
+	}
+}
+
+func Test_blackListAccessManager_ReplaceAccess(t *testing.T) {
+	roles := []Role{{Slug: userRole}, {Slug: adminRole}}
+	fullAccessRoles := []Role{{Slug: adminRole}}
+	endpoints := ExistingEndpoints{
+		http.MethodGet: {simpleValidPath, validPathWithParam},
+	}
+	manager, err := NewAccessManager(roles, fullAccessRoles, endpoints)
+	assert.NoError(t, err)
+
+	user := Role{Slug: userRole, IsRegistrationConfirmed: true}
+	principal := &models.Principal{Role: userRole, IsRegistrationConfirmed: true}
+	simpleRequest := &http.Request{Method: http.MethodGet, URL: &url.URL{Path: endpointConversion(simpleValidPath)}}
+	paramRequest := &http.Request{
+		Method: http.MethodGet,
+		URL:    &url.URL{Path: endpointConversion(validPathWithParamExample)},
+	}
+
+	added, removed, err := manager.ReplaceAccess([]Access{
+		{Role: user, Method: "get", Path: simpleValidPath},
+		{Role: user, Method: http.MethodGet, Path: simpleValidPath},
+		{Role: Role{Slug: adminRole}, Method: http.MethodGet, Path: simpleValidPath},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []Access{{Role: user, Method: http.MethodGet, Path: simpleValidPath}}, added)
+	assert.Empty(t, removed)
+	assert.NoError(t, manager.Authorize(simpleRequest, principal))
+	assert.Error(t, manager.Authorize(paramRequest, principal))
+
+	t.Run("invalid accesses keep the current ones", func(t *testing.T) {
+		_, _, err := manager.ReplaceAccess([]Access{
+			{Role: user, Method: http.MethodGet, Path: validPathWithParam},
+			{Role: user, Method: http.MethodGet, Path: simpleInvalidPath},
+		})
+		assert.Error(t, err)
+		_, _, err = manager.ReplaceAccess([]Access{
+			{Role: Role{Slug: "unknown"}, Method: http.MethodGet, Path: simpleValidPath},
+		})
+		assert.Error(t, err)
+		assert.NoError(t, manager.Authorize(simpleRequest, principal))
+		assert.Error(t, manager.Authorize(paramRequest, principal))
+	})
+
+	t.Run("replacement reports added and removed accesses", func(t *testing.T) {
+		added, removed, err := manager.ReplaceAccess([]Access{
+			{Role: user, Method: http.MethodGet, Path: validPathWithParam},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []Access{{Role: user, Method: http.MethodGet, Path: validPathWithParam}}, added)
+		assert.Equal(t, []Access{{Role: user, Method: http.MethodGet, Path: simpleValidPath}}, removed)
+		assert.Error(t, manager.Authorize(simpleRequest, principal))
+		assert.NoError(t, manager.Authorize(paramRequest, principal))
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
 
 	"github.com/go-openapi/errors"
 	"github.com/golang-jwt/jwt"
@@ -12,6 +13,7 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/services"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
 )
 
@@ -20,7 +22,8 @@ func TokenInvalidError() error {
 	return errors.New(http.StatusUnauthorized, messages.ErrInvalidToken)
 }
 
-func APIKeyAuthFunc(key interface{}, userRepository domain.UserRepository) func(context.Context, string) (context.Context, interface{}, error) {
+func APIKeyAuthFunc(key interface{}, userRepository domain.UserRepository,
+	tokenRepository domain.TokenRepository) func(context.Context, string) (context.Context, interface{}, error) {
 	return func(ctx context.Context, token string) (context.Context, interface{}, error) {
 		claims := jwt.MapClaims{}
 		parsedToken, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
@@ -33,6 +36,16 @@ func APIKeyAuthFunc(key interface{}, userRepository domain.UserRepository) func(
 			return ctx, nil, TokenInvalidError()
 		}
 
+		if _, ok := claims[services.TokenPurposeClaim]; ok {
+			return ctx, nil, TokenInvalidError()
+		}
+
+		// revoked sessions are removed from the token storage, their access tokens must not be accepted anymore
+		isActive, err := tokenRepository.AccessTokenExists(ctx, token)
+		if err != nil || !isActive {
+			return ctx, nil, TokenInvalidError()
+		}
+
 		userID, ok := claims[services.UserIDTokenClaim].(float64)
 		if !ok {
 			return ctx, nil, fmt.Errorf("invalid user ID format")
@@ -42,13 +55,17 @@ func APIKeyAuthFunc(key interface{}, userRepository domain.UserRepository) func(
 		if err != nil {
 			return ctx, nil, fmt.Errorf("can't get user by ID")
 		}
+		if user.IsDeleted {
+			return ctx, nil, TokenInvalidError()
+		}
 
-		principal := createPrincipal(user)
+		principal := PrincipalFromUser(user)
 		return ctx, principal, nil
 	}
 }
 
-func createPrincipal(user *ent.User) *models.Principal {
+// PrincipalFromUser creates the principal of the user, the role and the groups with permissions must be loaded.
+func PrincipalFromUser(user *ent.User) *models.Principal {
 	if user == nil {
 		return nil
 	}
@@ -59,11 +76,27 @@ func createPrincipal(user *ent.User) *models.Principal {
 		IsRegistrationConfirmed: user.IsRegistrationConfirmed,
 		IsPersonalDataConfirmed: isPersonalDataConfirmed(user),
 		IsReadonly:              user.IsReadonly,
+		IsTwoFactorEnabled:      user.IsTotpEnabled,
+		Permissions:             userPermissions(user),
 	}
 
 	return principal
 }
 
+// userPermissions returns the sorted names of the permissions granted to the groups of the user.
+func userPermissions(user *ent.User) []string {
+	var names []string
+	for _, group := range user.Edges.Groups {
+		for _, permission := range group.Edges.Permissions {
+			if !utils.IsValueInList(permission.Name, names) {
+				names = append(names, permission.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 func isPersonalDataConfirmed(user *ent.User) bool {
 	return user != nil &&
 		user.Name != nil && *user.Name != "" &&
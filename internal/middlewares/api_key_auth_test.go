@@ -4,11 +4,13 @@ import (
 	"context"
 	"testing"
 
+	"github.com/golang-jwt/jwt"
 	"github.com/stretchr/testify/assert"
 
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/mocks"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/services"
 )
 
 const tokenString = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJpZCI6MX0.hd6SCn616Yum7hzklWuFQ5okxz_k3TifhWlqbFFugIQ"
@@ -30,8 +32,10 @@ func TestAPIKeyAuthFunc(t *testing.T) {
 		Edges: ent.UserEdges{Role: &ent.Role{Slug: "user"}},
 	}
 	mockUserRepository.On("GetUserByID", ctx, userID).Return(expectedUser, nil)
+	mockTokenRepository := &mocks.TokenRepository{}
+	mockTokenRepository.On("AccessTokenExists", ctx, tokenString).Return(true, nil)
 
-	authFunc := APIKeyAuthFunc(key, mockUserRepository)
+	authFunc := APIKeyAuthFunc(key, mockUserRepository, mockTokenRepository)
 	newCtx, principal, err := authFunc(ctx, tokenString)
 
 	assert.NoError(t, err)
@@ -48,4 +52,83 @@ func TestAPIKeyAuthFunc(t *testing.T) {
 	}, principalObj)
 
 	mockUserRepository.AssertExpectations(t)
+	mockTokenRepository.AssertExpectations(t)
+}
+
+func TestAPIKeyAuthFunc_PermissionsOfGroups(t *testing.T) {
+	ctx := context.TODO()
+	userID := 1
+	mockUserRepository := &mocks.UserRepository{}
+	mockUserRepository.On("GetUserByID", ctx, userID).Return(&ent.User{
+		ID: userID,
+		Edges: ent.UserEdges{
+			Role: &ent.Role{Slug: "user"},
+			Groups: []*ent.Group{
+				{Edges: ent.GroupEdges{Permissions: []*ent.Permission{{Name: "orders:view"}, {Name: "equipment:block"}}}},
+				{Edges: ent.GroupEdges{Permissions: []*ent.Permission{{Name: "orders:view"}}}},
+			},
+		},
+	}, nil)
+	mockTokenRepository := &mocks.TokenRepository{}
+	mockTokenRepository.On("AccessTokenExists", ctx, tokenString).Return(true, nil)
+
+	_, principal, err := APIKeyAuthFunc("123", mockUserRepository, mockTokenRepository)(ctx, tokenString)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"equipment:block", "orders:view"}, principal.(*models.Principal).Permissions)
+}
+
+func TestAPIKeyAuthFunc_RevokedTokenIsRejected(t *testing.T) {
+	ctx := context.TODO()
+	key := "123"
+	mockUserRepository := &mocks.UserRepository{}
+	mockTokenRepository := &mocks.TokenRepository{}
+	mockTokenRepository.On("AccessTokenExists", ctx, tokenString).Return(false, nil)
+
+	authFunc := APIKeyAuthFunc(key, mockUserRepository, mockTokenRepository)
+	_, principal, err := authFunc(ctx, tokenString)
+
+	assert.Error(t, err)
+	assert.Nil(t, principal)
+	mockUserRepository.AssertExpectations(t)
+	mockTokenRepository.AssertExpectations(t)
+}
+
+func TestAPIKeyAuthFunc_DeletedUserIsRejected(t *testing.T) {
+	ctx := context.TODO()
+	userID := 1
+	mockUserRepository := &mocks.UserRepository{}
+	mockUserRepository.On("GetUserByID", ctx, userID).Return(&ent.User{
+		ID:        userID,
+		IsDeleted: true,
+		Edges:     ent.UserEdges{Role: &ent.Role{Slug: "user"}},
+	}, nil)
+	mockTokenRepository := &mocks.TokenRepository{}
+	mockTokenRepository.On("AccessTokenExists", ctx, tokenString).Return(true, nil)
+
+	_, principal, err := APIKeyAuthFunc("123", mockUserRepository, mockTokenRepository)(ctx, tokenString)
+
+	assert.Error(t, err)
+	assert.Nil(t, principal)
+	mockUserRepository.AssertExpectations(t)
+	mockTokenRepository.AssertExpectations(t)
+}
+
+func TestAPIKeyAuthFunc_ChallengeTokenIsRejected(t *testing.T) {
+	ctx := context.TODO()
+	key := "123"
+	mockUserRepository := &mocks.UserRepository{}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		services.UserIDTokenClaim:  1,
+		services.TokenPurposeClaim: services.TwoFactorChallengePurpose,
+	})
+	challengeToken, err := token.SignedString([]byte(key))
+	assert.NoError(t, err)
+
+	authFunc := APIKeyAuthFunc(key, mockUserRepository, &mocks.TokenRepository{})
+	_, principal, err := authFunc(ctx, challengeToken)
+
+	assert.Error(t, err)
+	assert.Nil(t, principal)
+	mockUserRepository.AssertExpectations(t)
 }
@@ -0,0 +1,99 @@
+package middlewares
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
+)
+
+type permissionAccessManager struct {
+	AccessManager
+	router *router
+	// grants holds the endpoint path templates available with each permission by the method.
+	grants map[string]ExistingEndpoints
+}
+
+// NewPermissionAccessManager creates the access manager which allows the endpoints granted by the permissions
+// of the user in addition to the endpoints allowed for the role by roleAccessManager.
+// Permissions are granted to the groups of users at runtime, so they are read from the principal on every request.
+func NewPermissionAccessManager(roleAccessManager AccessManager, endpoints ExistingEndpoints,
+	permissionEndpoints map[string]ExistingEndpoints) (AccessManager, error) {
+	endpointsRouter, err := newRouter(endpoints)
+	if err != nil {
+		return nil, err
+	}
+	grants := make(map[string]ExistingEndpoints, len(permissionEndpoints))
+	for permission, granted := range permissionEndpoints {
+		if err := granted.Validate(); err != nil {
+			return nil, fmt.Errorf("permission %s: %w", permission, err)
+		}
+		grants[permission] = make(ExistingEndpoints)
+		for method, paths := range granted {
+			method = strings.ToUpper(method)
+			for _, endpointPath := range paths {
+				if !utils.IsValueInList(endpointPath, endpoints[method]) {
+					return nil, fmt.Errorf("permission %s: path %s is not in the list of existing endpoints",
+						permission, endpointPath)
+				}
+				grants[permission][method] = append(grants[permission][method], endpointPath)
+			}
+		}
+	}
+	return &permissionAccessManager{
+		AccessManager: roleAccessManager,
+		router:        endpointsRouter,
+		grants:        grants,
+	}, nil
+}
+
+// Authorize allows the request if the role has access to the endpoint or one of the permissions grants it.
+// Permissions don't lift the two-factor authentication requirement.
+func (a *permissionAccessManager) Authorize(r *http.Request, auth interface{}) error {
+	err := a.AccessManager.Authorize(r, auth)
+	if err == nil || errors.Is(err, errTwoFactorMustBeEnabled) {
+		return err
+	}
+	principal, ok := auth.(*models.Principal)
+	if !ok {
+		return err
+	}
+	if _, ok := a.grantingPermission(principal, r.Method, r.URL.Path); ok {
+		return nil
+	}
+	return err
+}
+
+// Explain tells whether the role or one of the permissions of the principal grants the endpoint.
+func (a *permissionAccessManager) Explain(principal *models.Principal, method, endpointPath string) Decision {
+	decision := a.AccessManager.Explain(principal, method, endpointPath)
+	if decision.Allowed || decision.twoFactorRequired {
+		return decision
+	}
+	if permission, ok := a.grantingPermission(principal, method, endpointPath); ok {
+		return Decision{
+			Allowed:    true,
+			Reason:     fmt.Sprintf("granted by the permission %s", permission),
+			Permission: permission,
+		}
+	}
+	return decision
+}
+
+func (a *permissionAccessManager) grantingPermission(principal *models.Principal,
+	method, endpointPath string) (string, bool) {
+	method = strings.ToUpper(method)
+	template, ok := a.router.route(method, endpointPath)
+	if !ok {
+		return "", false
+	}
+	for _, permission := range principal.Permissions {
+		if utils.IsValueInList(template, a.grants[permission][method]) {
+			return permission, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,139 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+)
+
+func Test_permissionAccessManager(t *testing.T) {
+	const (
+		permission = "orders:view"
+		setupPath  = "/v1/users/me/2fa"
+	)
+	roles := []Role{{Slug: userRole}, {Slug: adminRole}}
+	fullAccessRoles := []Role{{Slug: adminRole}}
+	endpoints := ExistingEndpoints{
+		http.MethodGet:  {simpleValidPath, validPathWithParam},
+		http.MethodPost: {setupPath},
+	}
+	roleManager, err := NewAccessManager(roles, fullAccessRoles, endpoints)
+	assert.NoError(t, err)
+	_, err = roleManager.AddNewAccess(Role{Slug: userRole, IsRegistrationConfirmed: true},
+		http.MethodGet, simpleValidPath)
+	assert.NoError(t, err)
+	assert.NoError(t, roleManager.RequireTwoFactor(ExistingEndpoints{http.MethodPost: {setupPath}}))
+
+	_, err = NewPermissionAccessManager(roleManager, endpoints, map[string]ExistingEndpoints{
+		permission: {http.MethodGet: {simpleInvalidPath}},
+	})
+	assert.Error(t, err)
+	manager, err := NewPermissionAccessManager(roleManager, endpoints, map[string]ExistingEndpoints{
+		permission: {http.MethodGet: {validPathWithParam}},
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		principal    *models.Principal
+		method, path string
+		hasAccess    bool
+	}{
+		{
+			name:      "role access is kept",
+			principal: &models.Principal{Role: userRole, IsRegistrationConfirmed: true},
+			method:    http.MethodGet,
+			path:      endpointConversion(simpleValidPath),
+			hasAccess: true,
+		},
+		{
+			name:      "user without permission",
+			principal: &models.Principal{Role: userRole, IsRegistrationConfirmed: true},
+			method:    http.MethodGet,
+			path:      endpointConversion(validPathWithParamExample),
+		},
+		{
+			name: "user with permission",
+			principal: &models.Principal{Role: userRole, IsRegistrationConfirmed: true,
+				Permissions: []string{permission}},
+			method:    http.MethodGet,
+			path:      endpointConversion(validPathWithParamExample),
+			hasAccess: true,
+		},
+		{
+			name:      "permission grants only its method",
+			principal: &models.Principal{Role: userRole, Permissions: []string{permission}},
+			method:    http.MethodPost,
+			path:      endpointConversion(validPathWithParamExample),
+		},
+		{
+			name:      "permission doesn't lift two-factor requirement",
+			principal: &models.Principal{Role: adminRole, Permissions: []string{permission}},
+			method:    http.MethodGet,
+			path:      endpointConversion(validPathWithParamExample),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			request := &http.Request{Method: tc.method, URL: &url.URL{Path: tc.path}}
+			err := manager.Authorize(request, tc.principal)
+			if tc.hasAccess {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+			assert.Equal(t, tc.hasAccess, manager.Explain(tc.principal, tc.method, tc.path).Allowed)
+		})
+	}
+}
+
+func Test_permissionAccessManager_Explain(t *testing.T) {
+	const permission = "orders:view"
+	roles := []Role{{Slug: userRole}, {Slug: adminRole}}
+	fullAccessRoles := []Role{{Slug: adminRole}}
+	endpoints := ExistingEndpoints{
+		http.MethodGet: {simpleValidPath, validPathWithParam},
+	}
+	roleManager, err := NewAccessManager(roles, fullAccessRoles, endpoints)
+	assert.NoError(t, err)
+	user := Role{Slug: userRole, IsRegistrationConfirmed: true}
+	_, err = roleManager.AddNewAccess(user, http.MethodGet, simpleValidPath)
+	assert.NoError(t, err)
+	manager, err := NewPermissionAccessManager(roleManager, endpoints, map[string]ExistingEndpoints{
+		permission: {http.MethodGet: {validPathWithParam}},
+	})
+	assert.NoError(t, err)
+
+	principal := &models.Principal{Role: userRole, IsRegistrationConfirmed: true, Permissions: []string{permission}}
+	decision := manager.Explain(principal, "get", simpleValidPath)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, &Access{Role: user, Method: http.MethodGet, Path: simpleValidPath}, decision.Access)
+
+	decision = manager.Explain(principal, http.MethodGet, endpointConversion(validPathWithParamExample))
+	assert.True(t, decision.Allowed)
+	assert.Nil(t, decision.Access)
+	assert.Equal(t, permission, decision.Permission)
+
+	decision = manager.Explain(&models.Principal{Role: userRole}, http.MethodGet, simpleValidPath)
+	assert.False(t, decision.Allowed)
+	assert.Contains(t, decision.Reason, "no confirmed email")
+
+	decision = manager.Explain(principal, http.MethodGet, simpleInvalidPath)
+	assert.False(t, decision.Allowed)
+	assert.Contains(t, decision.Reason, "not an existing endpoint")
+
+	decision = manager.Explain(&models.Principal{Role: "unknown"}, http.MethodGet, simpleValidPath)
+	assert.False(t, decision.Allowed)
+	assert.Contains(t, decision.Reason, "acceptable roles")
+
+	assert.Equal(t, ExistingEndpoints{http.MethodGet: {simpleValidPath, validPathWithParam}},
+		AllowedEndpoints(manager, endpoints, principal))
+	assert.Equal(t, ExistingEndpoints{http.MethodGet: {simpleValidPath}},
+		AllowedEndpoints(manager, endpoints, &models.Principal{Role: userRole, IsRegistrationConfirmed: true}))
+	assert.Equal(t, ExistingEndpoints{http.MethodGet: {simpleValidPath, validPathWithParam}},
+		AllowedEndpoints(manager, endpoints, &models.Principal{Role: adminRole}))
+}
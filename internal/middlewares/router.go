@@ -0,0 +1,134 @@
+package middlewares
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// paramRegexp matches the parameters of the endpoint path template, e.g. {orderId}.
+var paramRegexp = regexp.MustCompile(`\{[^{}/]+\}`)
+
+// router resolves request paths to the endpoint path templates from the swagger specification.
+// Every segment of the request path must match the segment of the template, a parameter matches a part of
+// one segment only. When several templates match, the one with literal segments wins over the one with
+// parameters in the same position, so an access granted to one endpoint never applies to another.
+type router struct {
+	routes map[string][]route
+}
+
+type route struct {
+	template string
+	segments []segment
+}
+
+// segment is either the literal or the pattern when the segment of the template has parameters.
+type segment struct {
+	literal string
+	pattern *regexp.Regexp
+	// specificity ranks literal segments over segments mixing text and parameters over bare parameters.
+	specificity int
+}
+
+func newRouter(endpoints ExistingEndpoints) (*router, error) {
+	r := &router{
+		routes: make(map[string][]route),
+	}
+	for method, templates := range endpoints {
+		method = strings.ToUpper(method)
+		for _, template := range templates {
+			endpointRoute, err := newRoute(template)
+			if err != nil {
+				return nil, err
+			}
+			r.routes[method] = append(r.routes[method], endpointRoute)
+		}
+	}
+	return r, nil
+}
+
+func newRoute(template string) (route, error) {
+	endpointRoute := route{
+		template: template,
+	}
+	for _, part := range splitPath(template) {
+		if !strings.ContainsAny(part, "{}") {
+			endpointRoute.segments = append(endpointRoute.segments, segment{literal: part, specificity: 2})
+			continue
+		}
+		literals := paramRegexp.Split(part, -1)
+		quoted := make([]string, len(literals))
+		for i, literal := range literals {
+			if strings.ContainsAny(literal, "{}") {
+				return route{}, fmt.Errorf("incorrect path %s", template)
+			}
+			quoted[i] = regexp.QuoteMeta(literal)
+		}
+		pattern, err := regexp.Compile("^" + strings.Join(quoted, "[^/]+") + "$")
+		if err != nil {
+			return route{}, fmt.Errorf("incorrect path %s: %w", template, err)
+		}
+		specificity := 0
+		if strings.Join(literals, "") != "" {
+			specificity = 1
+		}
+		endpointRoute.segments = append(endpointRoute.segments, segment{pattern: pattern, specificity: specificity})
+	}
+	return endpointRoute, nil
+}
+
+// route returns the template of the endpoint which serves the request path, the api prefix is optional.
+func (r *router) route(method, requestPath string) (string, bool) {
+	parts := splitPath(trimAPIPrefix(requestPath))
+	routes := r.routes[strings.ToUpper(method)]
+	var best *route
+	for i := range routes {
+		if routes[i].isMatch(parts) && (best == nil || routes[i].isMoreSpecific(best)) {
+			best = &routes[i]
+		}
+	}
+	if best == nil {
+		return "", false
+	}
+	return best.template, true
+}
+
+func (r *route) isMatch(parts []string) bool {
+	if len(parts) != len(r.segments) {
+		return false
+	}
+	for i, s := range r.segments {
+		if s.pattern == nil && s.literal != parts[i] {
+			return false
+		}
+		if s.pattern != nil && !s.pattern.MatchString(parts[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *route) isMoreSpecific(other *route) bool {
+	for i := range r.segments {
+		if r.segments[i].specificity != other.segments[i].specificity {
+			return r.segments[i].specificity > other.segments[i].specificity
+		}
+	}
+	return false
+}
+
+func splitPath(endpointPath string) []string {
+	endpointPath = strings.Trim(endpointPath, "/")
+	if endpointPath == "" {
+		return nil
+	}
+	return strings.Split(endpointPath, "/")
+}
+
+func trimAPIPrefix(requestPath string) string {
+	requestPath = "/" + strings.Trim(requestPath, "/")
+	if requestPath == apiPrefix {
+		return "/"
+	}
+	return strings.TrimPrefix(requestPath, apiPrefix+"/")
+}
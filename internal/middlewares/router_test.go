@@ -0,0 +1,123 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+)
+
+// swaggerEndpoints returns the endpoints declared in swagger.yaml.
+func swaggerEndpoints(t *testing.T) ExistingEndpoints {
+	doc, err := loads.Spec("../../swagger.yaml")
+	require.NoError(t, err)
+	endpoints := make(ExistingEndpoints)
+	for method, paths := range doc.Analyzer.Operations() {
+		for endpointPath := range paths {
+			endpoints[strings.ToUpper(method)] = append(endpoints[strings.ToUpper(method)], endpointPath)
+		}
+	}
+	require.NotEmpty(t, endpoints)
+	return endpoints
+}
+
+// examplePath substitutes all parameters of the template with the value.
+func examplePath(template, value string) string {
+	return paramRegexp.ReplaceAllString(template, value)
+}
+
+func Test_router_route(t *testing.T) {
+	endpoints := swaggerEndpoints(t)
+	r, err := newRouter(endpoints)
+	require.NoError(t, err)
+
+	tests := []struct {
+		method, path string
+		template     string
+	}{
+		{http.MethodGet, "/api/equipment/1", "/equipment/{equipmentId}"},
+		{http.MethodPost, "/api/equipment/1/blocking", "/equipment/{equipmentId}/blocking"},
+		{http.MethodGet, "/api/v1/users/me", "/v1/users/me"},
+		{http.MethodDelete, "/api/v1/users/5", "/v1/users/{userId}"},
+		{http.MethodGet, "/api/v1/users/5", ""},
+		{http.MethodPut, "/api/v1/orders/5", "/v1/orders/{orderId}"},
+		{http.MethodGet, "/api/v1/orders/status/closed", "/v1/orders/status/{status}"},
+		{
+			http.MethodGet,
+			"/api/v1/orders/from=2023-01-01&to=2023-02-01&status=closed",
+			"/v1/orders/from={fromDate}&to={toDate}&status={statusName}",
+		},
+		{http.MethodDelete, "/api/v1/management/groups/1/users/2", "/v1/management/groups/{groupId}/users/{userId}"},
+		{http.MethodPost, "/api/v1/order_statuses", "/v1/order_statuses/"},
+		{http.MethodPost, "api/v1/order_statuses/", "/v1/order_statuses/"},
+		{http.MethodPut, "/v1/orders/5", "/v1/orders/{orderId}"},
+		{http.MethodPut, "/api/v1/orders/5/", "/v1/orders/{orderId}"},
+		{http.MethodGet, "/api/equipment/1/unknown", ""},
+		{http.MethodGet, "/api/equipment/1/2", ""},
+		{http.MethodGet, "/api/v1/orders/from=2023-01-01", ""},
+		{http.MethodPut, "/api/v1/orders/from=2023-01-01&to=2023-02-01&status=closed", "/v1/orders/{orderId}"},
+		{http.MethodGet, "/api/v1/management/groups//users", ""},
+		{http.MethodPatch, "/api/v1/orders/5", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			template, ok := r.route(tc.method, tc.path)
+			assert.Equal(t, tc.template != "", ok)
+			assert.Equal(t, tc.template, template)
+		})
+	}
+
+	for method, templates := range endpoints {
+		for _, template := range templates {
+			t.Run(method+" "+template, func(t *testing.T) {
+				routed, ok := r.route(method, endpointConversion(examplePath(template, "1")))
+				assert.True(t, ok)
+				assert.Equal(t, template, routed)
+			})
+		}
+	}
+}
+
+func Test_newRoute_IncorrectPath(t *testing.T) {
+	_, err := newRoute("/v1/orders/{orderId")
+	assert.Error(t, err)
+	_, err = newRoute("/v1/orders/orderId}")
+	assert.Error(t, err)
+}
+
+// Test_blackListAccessManager_GrantDoesNotLeak grants every endpoint of swagger.yaml alone
+// and checks that no other endpoint becomes available.
+func Test_blackListAccessManager_GrantDoesNotLeak(t *testing.T) {
+	endpoints := swaggerEndpoints(t)
+	role := Role{Slug: userRole, IsRegistrationConfirmed: true}
+	principal := &models.Principal{Role: userRole, IsRegistrationConfirmed: true}
+
+	for method, templates := range endpoints {
+		for _, template := range templates {
+			t.Run(method+" "+template, func(t *testing.T) {
+				manager, err := NewAccessManager([]Role{{Slug: userRole}}, nil, endpoints)
+				require.NoError(t, err)
+				_, err = manager.AddNewAccess(role, method, template)
+				require.NoError(t, err)
+
+				for otherMethod, otherTemplates := range endpoints {
+					for _, otherTemplate := range otherTemplates {
+						request := &http.Request{
+							Method: otherMethod,
+							URL:    &url.URL{Path: endpointConversion(examplePath(otherTemplate, "1"))},
+						}
+						isGranted := otherMethod == method && otherTemplate == template
+						err := manager.Authorize(request, principal)
+						assert.Equalf(t, isGranted, err == nil, "%s %s", otherMethod, otherTemplate)
+					}
+				}
+			})
+		}
+	}
+}
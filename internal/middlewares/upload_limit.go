@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
+)
+
+// multipartOverhead is left for the boundaries and the headers of the parts of the uploaded file.
+const multipartOverhead = 64 << 10
+
+// UploadLimit limits the body of the multipart requests to the max file size. The request which length is
+// known is rejected with 413 before the body is read, the body of the others is cut when it gets too large,
+// so the large uploads are not written to the temporary files while the form is parsed.
+func UploadLimit(maxFileSize int64) func(next http.Handler) http.Handler {
+	limit := maxFileSize + multipartOverhead
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.ContentLength > limit {
+				utils.WriteErrorResponse(w, http.StatusRequestEntityTooLarge, messages.ErrFileTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
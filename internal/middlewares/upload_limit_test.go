@@ -0,0 +1,51 @@
+package middlewares
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadLimit(t *testing.T) {
+	var read int
+	var readErr error
+	handler := UploadLimit(10)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data []byte
+		data, readErr = io.ReadAll(r.Body)
+		read = len(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+	large := strings.Repeat("a", multipartOverhead+11)
+
+	t.Run("known length", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/equipment/photos", strings.NewReader(large))
+		req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("unknown length", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/equipment/photos", strings.NewReader(large))
+		req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+		req.ContentLength = -1
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Error(t, readErr)
+		require.Equal(t, multipartOverhead+10, read)
+	})
+
+	t.Run("not multipart", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/equipment", strings.NewReader(large))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, readErr)
+		require.Equal(t, len(large), read)
+	})
+}
@@ -0,0 +1,299 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+const (
+	testClientID     = "csr"
+	testClientSecret = "secret"
+	testRedirectURL  = "https://csr.example.com/oidc/callback"
+	testKeyID        = "key-1"
+)
+
+type authorization struct {
+	codeChallenge string
+	nonce         string
+	claims        jwt.MapClaims
+}
+
+// stubIdP is the local identity provider, it issues codes without user interaction
+// and checks the client credentials and the PKCE verifier like the real one.
+type stubIdP struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	// tamper changes the claims of the issued ID token.
+	tamper func(claims jwt.MapClaims)
+	// signingKey signs the ID token instead of the published key.
+	signingKey *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]authorization
+}
+
+func newStubIdP(t *testing.T) *stubIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	idp := &stubIdP{t: t, key: key, codes: make(map[string]authorization)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, idp.discovery)
+	mux.HandleFunc("/jwks", idp.jwks)
+	mux.HandleFunc("/token", idp.token)
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func (idp *stubIdP) issuer() string {
+	return idp.server.URL
+}
+
+func (idp *stubIdP) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 idp.issuer(),
+		"authorization_endpoint": idp.issuer() + "/authorize",
+		"token_endpoint":         idp.issuer() + "/token",
+		"jwks_uri":               idp.issuer() + "/jwks",
+	})
+}
+
+func (idp *stubIdP) jwks(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testKeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(idp.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(idp.key.E)).Bytes()),
+		}},
+	})
+}
+
+// authorize emulates the user logging in at the authorization endpoint and returns the code.
+func (idp *stubIdP) authorize(authCodeURL string, claims jwt.MapClaims) string {
+	parsed, err := url.Parse(authCodeURL)
+	require.NoError(idp.t, err)
+	query := parsed.Query()
+	require.Equal(idp.t, idp.issuer()+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	require.Equal(idp.t, "code", query.Get("response_type"))
+	require.Equal(idp.t, testClientID, query.Get("client_id"))
+	require.Equal(idp.t, testRedirectURL, query.Get("redirect_uri"))
+	require.Equal(idp.t, "openid email", query.Get("scope"))
+	require.Equal(idp.t, codeChallengeMethod, query.Get("code_challenge_method"))
+	require.NotEmpty(idp.t, query.Get("state"))
+
+	code := base64.RawURLEncoding.EncodeToString([]byte(query.Get("state")))
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	idp.codes[code] = authorization{
+		codeChallenge: query.Get("code_challenge"),
+		nonce:         query.Get("nonce"),
+		claims:        claims,
+	}
+	return code
+}
+
+func (idp *stubIdP) token(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok || clientID != testClientID || clientSecret != testClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if r.PostFormValue("grant_type") != "authorization_code" ||
+		r.PostFormValue("redirect_uri") != testRedirectURL {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	idp.mu.Lock()
+	auth, ok := idp.codes[r.PostFormValue("code")]
+	delete(idp.codes, r.PostFormValue("code"))
+	idp.mu.Unlock()
+	if !ok || codeChallenge(r.PostFormValue("code_verifier")) != auth.codeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   idp.issuer(),
+		"aud":   testClientID,
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": auth.nonce,
+	}
+	for k, v := range auth.claims {
+		claims[k] = v
+	}
+	if idp.tamper != nil {
+		idp.tamper(claims)
+	}
+	signingKey := idp.key
+	if idp.signingKey != nil {
+		signingKey = idp.signingKey
+	}
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	idToken.Header["kid"] = testKeyID
+	signed, err := idToken.SignedString(signingKey)
+	require.NoError(idp.t, err)
+	writeJSON(w, http.StatusOK, map[string]string{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"id_token":     signed,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func codeChallenge(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+func newTestProvider(issuer string) domain.OIDCProvider {
+	return NewProvider(Config{
+		Issuer:       issuer,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+		Scopes:       []string{"openid", "email"},
+	}, http.DefaultClient)
+}
+
+var testUserClaims = jwt.MapClaims{
+	"sub":                "subject",
+	"email":              "user@example.com",
+	"email_verified":     "true",
+	"name":               "User",
+	"preferred_username": "user",
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	ctx := context.Background()
+	idp := newStubIdP(t)
+	provider := newTestProvider(idp.issuer())
+	verifier := "verifier-verifier-verifier-verifier-verifier"
+
+	authCodeURL, err := provider.AuthCodeURL(ctx, "state", "nonce", codeChallenge(verifier))
+	require.NoError(t, err)
+	code := idp.authorize(authCodeURL, testUserClaims)
+
+	claims, err := provider.Exchange(ctx, code, verifier, "nonce")
+	require.NoError(t, err)
+	require.Equal(t, &domain.OIDCClaims{
+		Subject:           "subject",
+		Email:             "user@example.com",
+		EmailVerified:     true,
+		Name:              "User",
+		PreferredUsername: "user",
+	}, claims)
+
+	_, err = provider.Exchange(ctx, code, verifier, "nonce")
+	require.ErrorIs(t, err, domain.ErrOIDCLoginFailed, "code is used only once")
+}
+
+func TestProvider_ExchangeRejected(t *testing.T) {
+	anotherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	verifier := "verifier-verifier-verifier-verifier-verifier"
+
+	tests := []struct {
+		name     string
+		verifier string
+		nonce    string
+		setup    func(idp *stubIdP)
+	}{
+		{
+			name:     "wrong code verifier",
+			verifier: "another-verifier-another-verifier-another",
+			nonce:    "nonce",
+		},
+		{
+			name:     "wrong nonce",
+			verifier: verifier,
+			nonce:    "another nonce",
+		},
+		{
+			name:     "unknown signing key",
+			verifier: verifier,
+			nonce:    "nonce",
+			setup: func(idp *stubIdP) {
+				idp.signingKey = anotherKey
+			},
+		},
+		{
+			name:     "another audience",
+			verifier: verifier,
+			nonce:    "nonce",
+			setup: func(idp *stubIdP) {
+				idp.tamper = func(claims jwt.MapClaims) { claims["aud"] = "another client" }
+			},
+		},
+		{
+			name:     "another issuer",
+			verifier: verifier,
+			nonce:    "nonce",
+			setup: func(idp *stubIdP) {
+				idp.tamper = func(claims jwt.MapClaims) { claims["iss"] = "https://another.example.com" }
+			},
+		},
+		{
+			name:     "expired token",
+			verifier: verifier,
+			nonce:    "nonce",
+			setup: func(idp *stubIdP) {
+				idp.tamper = func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Minute).Unix() }
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			idp := newStubIdP(t)
+			if tt.setup != nil {
+				tt.setup(idp)
+			}
+			provider := newTestProvider(idp.issuer())
+
+			authCodeURL, err := provider.AuthCodeURL(ctx, "state", "nonce", codeChallenge(verifier))
+			require.NoError(t, err)
+			code := idp.authorize(authCodeURL, testUserClaims)
+
+			_, err = provider.Exchange(ctx, code, tt.verifier, tt.nonce)
+			require.ErrorIs(t, err, domain.ErrOIDCLoginFailed)
+		})
+	}
+}
+
+func TestProvider_DiscoveryIssuerMismatch(t *testing.T) {
+	idp := newStubIdP(t)
+	// the discovery document is found, but its issuer differs from the configured one
+	provider := newTestProvider(idp.issuer() + "/")
+
+	_, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "challenge")
+	require.Error(t, err)
+}
@@ -0,0 +1,24 @@
+package permissions
+
+// Permissions are granted to groups of users in addition to what the role of the user allows.
+// The list must match the permissions table filled by the migrations.
+const (
+	EquipmentBlock = "equipment:block"
+	OrdersView     = "orders:view"
+	OrdersApprove  = "orders:approve"
+	OrdersPrepare  = "orders:prepare"
+	OrdersIssue    = "orders:issue"
+	OrdersClose    = "orders:close"
+)
+
+// Has reports whether one of the permissions is granted.
+func Has(granted []string, permissions ...string) bool {
+	for _, g := range granted {
+		for _, p := range permissions {
+			if g == p {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package policies
+
+import (
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/permissions"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+// Policies decide whether the principal may act on the particular resource. The access manager checks only
+// that the endpoint is allowed for the principal, the handlers call Can once the owner of the resource is known.
+
+type Action string
+
+const (
+	View   Action = "view"
+	Create Action = "create"
+	Update Action = "update"
+	Delete Action = "delete"
+	// Grant changes the role or the access of the user.
+	Grant Action = "grant"
+	// ViewPassport shows the passport data of the user, it is masked for everyone else.
+	ViewPassport Action = "view passport"
+)
+
+type Kind string
+
+const (
+	Order        Kind = "order"
+	OrderStatus  Kind = "order status"
+	User         Kind = "user"
+	Photo        Kind = "photo"
+	Organization Kind = "organization"
+)
+
+// Resource is the object of the action. OwnerID is the user who owns the order, the user itself for users
+// and is not used for photos and organizations. OrganizationID is the organization the order is placed
+// on behalf of or the organization itself, MemberRole is the role of the principal in that organization,
+// empty if the principal isn't its member.
+type Resource struct {
+	Kind           Kind
+	OwnerID        int
+	OrganizationID int
+	MemberRole     string
+}
+
+// OrderPermissions allow to see any order, each of the workflow permissions also allows to add the status
+// of its own step to any order.
+var OrderPermissions = []string{
+	permissions.OrdersView,
+	permissions.OrdersApprove,
+	permissions.OrdersPrepare,
+	permissions.OrdersIssue,
+	permissions.OrdersClose,
+}
+
+var orderWorkflowPermissions = OrderPermissions[1:]
+
+// Can reports whether the principal may perform the action on the resource.
+func Can(principal *models.Principal, action Action, resource Resource) bool {
+	if principal == nil {
+		return false
+	}
+	isOwner := principal.ID != 0 && int(principal.ID) == resource.OwnerID
+	isStaff := IsStaff(principal.Role)
+	isMember := resource.MemberRole != ""
+	isOrganizationOwner := resource.MemberRole == domain.OrganizationRoleOwner
+
+	switch resource.Kind {
+	case Order:
+		switch action {
+		case View:
+			return isOwner || isMember || isStaff || permissions.Has(principal.Permissions, OrderPermissions...)
+		case Create:
+			return isOwner && (resource.OrganizationID == 0 || isMember)
+		case Update:
+			return isOwner
+		}
+	case OrderStatus:
+		switch action {
+		case View:
+			return isOwner || isMember || isStaff || permissions.Has(principal.Permissions, OrderPermissions...)
+		case Create:
+			return isOwner || isStaff || permissions.Has(principal.Permissions, orderWorkflowPermissions...)
+		}
+	case User:
+		// the staff only sees the other users, changing them is left to the administrator: otherwise an
+		// operator could delete an administrator or make anyone, themselves too, an administrator
+		isAdmin := principal.Role == roles.Admin
+		switch action {
+		case View:
+			return isOwner || isStaff
+		case Update, Delete:
+			return isOwner || isAdmin
+		case Grant:
+			return isAdmin
+		case ViewPassport:
+			return isOwner || principal.Role == roles.Admin || principal.Role == roles.Manager
+		}
+	case Photo:
+		switch action {
+		case View:
+			return true
+		case Create, Update, Delete:
+			return isStaff
+		}
+	case Organization:
+		switch action {
+		case View:
+			return isMember || isStaff
+		case Create:
+			return true
+		case Update, Delete:
+			return isOrganizationOwner || isStaff
+		}
+	}
+	return false
+}
+
+// IsStaff reports whether the role manages the resources of other users.
+func IsStaff(role string) bool {
+	return role == roles.Admin || role == roles.Manager || role == roles.Operator
+}
@@ -0,0 +1,208 @@
+package policies
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/permissions"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+func TestCan(t *testing.T) {
+	const (
+		principalID = 1
+		otherID     = 2
+	)
+	type expected map[Action]bool
+	tests := []struct {
+		kind        Kind
+		permissions []string
+		// organizationID and memberRole describe the organization of the resource.
+		organizationID int
+		memberRole     string
+		// own and other are the expected decisions for the resource owned by the principal and by somebody else
+		// for each role: user, operator, manager, administrator.
+		own, other map[string]expected
+	}{
+		{
+			kind: Order,
+			own: map[string]expected{
+				roles.User:     {View: true, Create: true, Update: true},
+				roles.Operator: {View: true, Create: true, Update: true},
+				roles.Manager:  {View: true, Create: true, Update: true},
+				roles.Admin:    {View: true, Create: true, Update: true},
+			},
+			other: map[string]expected{
+				roles.User:     {},
+				roles.Operator: {View: true},
+				roles.Manager:  {View: true},
+				roles.Admin:    {View: true},
+			},
+		},
+		{
+			kind:        Order,
+			permissions: []string{permissions.OrdersView},
+			other: map[string]expected{
+				roles.User: {View: true},
+			},
+		},
+		{
+			kind: OrderStatus,
+			own: map[string]expected{
+				roles.User:     {View: true, Create: true},
+				roles.Operator: {View: true, Create: true},
+				roles.Manager:  {View: true, Create: true},
+				roles.Admin:    {View: true, Create: true},
+			},
+			other: map[string]expected{
+				roles.User:     {},
+				roles.Operator: {View: true, Create: true},
+				roles.Manager:  {View: true, Create: true},
+				roles.Admin:    {View: true, Create: true},
+			},
+		},
+		{
+			kind:        OrderStatus,
+			permissions: []string{permissions.OrdersView},
+			other: map[string]expected{
+				roles.User: {View: true},
+			},
+		},
+		{
+			kind:        OrderStatus,
+			permissions: []string{permissions.OrdersIssue},
+			other: map[string]expected{
+				roles.User: {View: true, Create: true},
+			},
+		},
+		{
+			kind: User,
+			own: map[string]expected{
+				roles.User:     {View: true, Update: true, Delete: true, ViewPassport: true},
+				roles.Operator: {View: true, Update: true, Delete: true, ViewPassport: true},
+				roles.Manager:  {View: true, Update: true, Delete: true, ViewPassport: true},
+				roles.Admin:    {View: true, Update: true, Delete: true, Grant: true, ViewPassport: true},
+			},
+			other: map[string]expected{
+				roles.User:     {},
+				roles.Operator: {View: true},
+				roles.Manager:  {View: true, ViewPassport: true},
+				roles.Admin:    {View: true, Update: true, Delete: true, Grant: true, ViewPassport: true},
+			},
+		},
+		{
+			kind:        User,
+			permissions: []string{permissions.OrdersView, permissions.EquipmentBlock},
+			other: map[string]expected{
+				roles.User: {},
+			},
+		},
+		{
+			kind:           Order,
+			organizationID: 3,
+			own: map[string]expected{
+				roles.User:  {View: true, Update: true},
+				roles.Admin: {View: true, Update: true},
+			},
+			other: map[string]expected{
+				roles.User:    {},
+				roles.Manager: {View: true},
+			},
+		},
+		{
+			kind:           Order,
+			organizationID: 3,
+			memberRole:     domain.OrganizationRoleMember,
+			own: map[string]expected{
+				roles.User: {View: true, Create: true, Update: true},
+			},
+			other: map[string]expected{
+				roles.User: {View: true},
+			},
+		},
+		{
+			kind:           OrderStatus,
+			organizationID: 3,
+			memberRole:     domain.OrganizationRoleOwner,
+			other: map[string]expected{
+				roles.User: {View: true},
+			},
+		},
+		{
+			kind: Organization,
+			other: map[string]expected{
+				roles.User:     {Create: true},
+				roles.Operator: {View: true, Create: true, Update: true, Delete: true},
+				roles.Manager:  {View: true, Create: true, Update: true, Delete: true},
+				roles.Admin:    {View: true, Create: true, Update: true, Delete: true},
+			},
+		},
+		{
+			kind:       Organization,
+			memberRole: domain.OrganizationRoleMember,
+			other: map[string]expected{
+				roles.User: {View: true, Create: true},
+			},
+		},
+		{
+			kind:       Organization,
+			memberRole: domain.OrganizationRoleOwner,
+			other: map[string]expected{
+				roles.User: {View: true, Create: true, Update: true, Delete: true},
+			},
+		},
+		{
+			kind: Photo,
+			other: map[string]expected{
+				roles.User:     {View: true},
+				roles.Operator: {View: true, Create: true, Update: true, Delete: true},
+				roles.Manager:  {View: true, Create: true, Update: true, Delete: true},
+				roles.Admin:    {View: true, Create: true, Update: true, Delete: true},
+			},
+		},
+	}
+	actions := []Action{View, Create, Update, Delete, Grant, ViewPassport}
+	for _, tc := range tests {
+		for ownership, cases := range map[string]map[string]expected{"own": tc.own, "other": tc.other} {
+			ownerID := principalID
+			if ownership == "other" {
+				ownerID = otherID
+			}
+			for role, allowed := range cases {
+				principal := &models.Principal{ID: principalID, Role: role, Permissions: tc.permissions}
+				for _, action := range actions {
+					resource := Resource{
+						Kind:           tc.kind,
+						OwnerID:        ownerID,
+						OrganizationID: tc.organizationID,
+						MemberRole:     tc.memberRole,
+					}
+					assert.Equal(t, allowed[action], Can(principal, action, resource),
+						"%s %s %s %s with %v as %q", role, action, ownership, tc.kind, tc.permissions, tc.memberRole)
+				}
+			}
+		}
+	}
+}
+
+func TestCan_StaffAgainstAdmin(t *testing.T) {
+	admin := Resource{Kind: User, OwnerID: 2}
+	for _, role := range []string{roles.Operator, roles.Manager} {
+		principal := &models.Principal{ID: 1, Role: role}
+		assert.True(t, Can(principal, View, admin), role)
+		assert.False(t, Can(principal, Update, admin), role)
+		assert.False(t, Can(principal, Delete, admin), role)
+		assert.False(t, Can(principal, Grant, admin), role)
+		// the role of the principal is not theirs to raise either
+		assert.False(t, Can(principal, Grant, Resource{Kind: User, OwnerID: 1}), role)
+	}
+}
+
+func TestCan_NoPrincipal(t *testing.T) {
+	assert.False(t, Can(nil, View, Resource{Kind: Photo}))
+	assert.False(t, Can(&models.Principal{Role: roles.User}, View, Resource{Kind: Order}))
+	assert.False(t, Can(&models.Principal{ID: 1, Role: roles.Admin}, View, Resource{Kind: "unknown", OwnerID: 1}))
+}
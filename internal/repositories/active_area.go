@@ -6,6 +6,7 @@ import (
 
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/activearea"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/user"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
@@ -46,3 +47,12 @@ func (r *activeAreaRepository) TotalActiveAreas(ctx context.Context) (int, error
 	}
 	return tx.ActiveArea.Query().Count(ctx)
 }
+
+func (r *activeAreaRepository) UserActiveAreas(ctx context.Context, userID int) ([]*ent.ActiveArea, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.ActiveArea.Query().Where(activearea.HasUsersWith(user.ID(userID))).
+		Order(ent.Asc(activearea.FieldName)).All(ctx)
+}
@@ -251,3 +251,24 @@ func mapContainsArea(t *testing.T, value string, m map[int]string) bool {
 	}
 	return false
 }
+
+func (s *ActiveAreasSuite) TestActiveAreaRepository_UserActiveAreas() {
+	t := s.T()
+	ctx := s.ctx
+	areas, err := s.client.ActiveArea.Query().Order(ent.Asc(activearea.FieldName)).Limit(2).All(ctx)
+	require.NoError(t, err)
+	u, err := s.client.User.Create().SetLogin("areas").SetEmail("areas@example.com").SetPassword("password").
+		AddActiveAreas(areas...).Save(ctx)
+	require.NoError(t, err)
+	defer s.client.User.DeleteOneID(u.ID).ExecX(ctx)
+
+	tx, err := s.client.Tx(ctx)
+	require.NoError(t, err)
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+	userAreas, err := s.repository.UserActiveAreas(ctx, u.ID)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	require.Len(t, userAreas, 2)
+	require.Equal(t, areas[0].Name, userAreas[0].Name)
+	require.Equal(t, areas[1].Name, userAreas[1].Name)
+}
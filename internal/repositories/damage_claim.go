@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/damageclaim"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/equipment"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/order"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/predicate"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type damageClaimRepository struct {
+}
+
+func NewDamageClaimRepository() domain.DamageClaimRepository {
+	return &damageClaimRepository{}
+}
+
+func damageClaimQuery(tx *ent.Tx) *ent.DamageClaimQuery {
+	return tx.DamageClaim.Query().WithOrder().WithEquipment()
+}
+
+func (r *damageClaimRepository) CreateDamageClaim(ctx context.Context, orderID, equipmentID int, amount *int64,
+	notes string) (*ent.DamageClaim, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claimedOrder, err := tx.Order.Query().Where(order.ID(orderID)).WithCurrentStatus().Only(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claimedEquipment, err := tx.Equipment.Get(ctx, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+	currentStatus := claimedOrder.Edges.CurrentStatus
+	if currentStatus == nil || currentStatus.Status != domain.OrderStatusClosed {
+		return nil, domain.ErrDamageClaimOrderActive
+	}
+	inOrder, err := claimedOrder.QueryEquipments().Where(equipment.ID(equipmentID)).Exist(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !inOrder {
+		return nil, domain.ErrEquipmentNotInOrder
+	}
+	claimed, err := tx.DamageClaim.Query().
+		Where(damageclaim.HasOrderWith(order.ID(orderID)), damageclaim.HasEquipmentWith(equipment.ID(equipmentID))).
+		Exist(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if claimed {
+		return nil, domain.ErrDamageClaimExists
+	}
+	if amount == nil {
+		amount = &claimedEquipment.CompensationCost
+	}
+	created, err := tx.DamageClaim.Create().
+		SetOrder(claimedOrder).
+		SetEquipment(claimedEquipment).
+		SetAmount(*amount).
+		SetNotes(notes).
+		Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.DamageClaimByID(ctx, created.ID)
+}
+
+func (r *damageClaimRepository) DamageClaimByID(ctx context.Context, id int) (*ent.DamageClaim, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return damageClaimQuery(tx).Where(damageclaim.ID(id)).Only(ctx)
+}
+
+func (r *damageClaimRepository) DamageClaims(ctx context.Context,
+	filter domain.DamageClaimFilter) ([]*ent.DamageClaim, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var predicates []predicate.DamageClaim
+	if filter.OrderID != nil {
+		predicates = append(predicates, damageclaim.HasOrderWith(order.ID(*filter.OrderID)))
+	}
+	if filter.EquipmentID != nil {
+		predicates = append(predicates, damageclaim.HasEquipmentWith(equipment.ID(*filter.EquipmentID)))
+	}
+	if filter.Status != nil {
+		predicates = append(predicates, damageclaim.StatusEQ(damageclaim.Status(*filter.Status)))
+	}
+	return damageClaimQuery(tx).Where(predicates...).Order(ent.Asc(damageclaim.FieldID)).All(ctx)
+}
+
+func (r *damageClaimRepository) UpdateDamageClaim(ctx context.Context, id int,
+	update models.DamageClaimUpdate) (*ent.DamageClaim, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claim, err := tx.DamageClaim.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if claim.Status != damageclaim.StatusOpen {
+		return nil, domain.ErrDamageClaimNotOpen
+	}
+	edit := claim.Update()
+	if update.Amount != nil {
+		edit.SetAmount(*update.Amount)
+	}
+	if update.Notes != nil {
+		edit.SetNotes(*update.Notes)
+	}
+	if update.Status != nil {
+		edit.SetStatus(damageclaim.Status(*update.Status))
+	}
+	if _, err = edit.Save(ctx); err != nil {
+		return nil, err
+	}
+	return r.DamageClaimByID(ctx, id)
+}
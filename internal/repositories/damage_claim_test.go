@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/enttest"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/equipment"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type damageClaimRepositorySuite struct {
+	suite.Suite
+	ctx         context.Context
+	client      *ent.Client
+	repository  domain.DamageClaimRepository
+	closedOrder *ent.Order
+	activeOrder *ent.Order
+	equipment   *ent.Equipment
+	other       *ent.Equipment
+}
+
+func TestDamageClaimRepositorySuite(t *testing.T) {
+	suite.Run(t, new(damageClaimRepositorySuite))
+}
+
+func (s *damageClaimRepositorySuite) SetupTest() {
+	s.ctx = context.Background()
+	s.client = enttest.Open(s.T(), "sqlite3", "file:damageclaims?mode=memory&cache=shared&_fk=1")
+	s.repository = NewDamageClaimRepository()
+
+	closed := s.client.OrderStatusName.Create().SetStatus(domain.OrderStatusClosed).SaveX(s.ctx)
+	inProgress := s.client.OrderStatusName.Create().SetStatus(domain.OrderStatusInProgress).SaveX(s.ctx)
+	s.equipment = s.client.Equipment.Create().SetName("tent").SetTitle("tent").
+		SetCompensationCost(1500).SaveX(s.ctx)
+	s.other = s.client.Equipment.Create().SetName("bowl").SetTitle("bowl").SaveX(s.ctx)
+	s.closedOrder = s.client.Order.Create().SetDescription("closed").SetQuantity(1).
+		SetRentStart(time.Now()).SetRentEnd(time.Now()).SetCurrentStatus(closed).
+		AddEquipments(s.equipment).SaveX(s.ctx)
+	s.activeOrder = s.client.Order.Create().SetDescription("active").SetQuantity(1).
+		SetRentStart(time.Now()).SetRentEnd(time.Now()).SetCurrentStatus(inProgress).
+		AddEquipments(s.equipment).SaveX(s.ctx)
+}
+
+func (s *damageClaimRepositorySuite) TearDownTest() {
+	s.client.DamageClaim.Delete().ExecX(s.ctx)
+	s.client.Order.Delete().ExecX(s.ctx)
+	s.client.Equipment.Delete().ExecX(s.ctx)
+	s.client.OrderStatusName.Delete().ExecX(s.ctx)
+	s.client.Close()
+}
+
+func (s *damageClaimRepositorySuite) txContext() (context.Context, *ent.Tx) {
+	tx, err := s.client.Tx(s.ctx)
+	require.NoError(s.T(), err)
+	return context.WithValue(s.ctx, middlewares.TxContextKey, tx), tx
+}
+
+func (s *damageClaimRepositorySuite) TestDamageClaimRepository_CreateDamageClaim_DefaultAmount() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	claim, err := s.repository.CreateDamageClaim(ctx, s.closedOrder.ID, s.equipment.ID, nil, "broken zipper")
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	require.Equal(t, int64(1500), claim.Amount)
+	require.Equal(t, domain.DamageClaimStatusOpen, claim.Status.String())
+	require.Equal(t, "broken zipper", claim.Notes)
+	require.Equal(t, s.closedOrder.ID, claim.Edges.Order.ID)
+	require.Equal(t, s.equipment.ID, claim.Edges.Equipment.ID)
+}
+
+func (s *damageClaimRepositorySuite) TestDamageClaimRepository_CreateDamageClaim_Amount() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	amount := int64(300)
+	claim, err := s.repository.CreateDamageClaim(ctx, s.closedOrder.ID, s.equipment.ID, &amount, "")
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	require.Equal(t, amount, claim.Amount)
+}
+
+func (s *damageClaimRepositorySuite) TestDamageClaimRepository_CreateDamageClaim_Errors() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	_, err := s.repository.CreateDamageClaim(ctx, s.activeOrder.ID, s.equipment.ID, nil, "")
+	require.ErrorIs(t, err, domain.ErrDamageClaimOrderActive)
+	_, err = s.repository.CreateDamageClaim(ctx, s.closedOrder.ID, s.other.ID, nil, "")
+	require.ErrorIs(t, err, domain.ErrEquipmentNotInOrder)
+	_, err = s.repository.CreateDamageClaim(ctx, s.closedOrder.ID+100, s.equipment.ID, nil, "")
+	require.True(t, ent.IsNotFound(err))
+	_, err = s.repository.CreateDamageClaim(ctx, s.closedOrder.ID, s.other.ID+100, nil, "")
+	require.True(t, ent.IsNotFound(err))
+
+	_, err = s.repository.CreateDamageClaim(ctx, s.closedOrder.ID, s.equipment.ID, nil, "")
+	require.NoError(t, err)
+	_, err = s.repository.CreateDamageClaim(ctx, s.closedOrder.ID, s.equipment.ID, nil, "")
+	require.ErrorIs(t, err, domain.ErrDamageClaimExists)
+	require.NoError(t, tx.Commit())
+}
+
+func (s *damageClaimRepositorySuite) TestDamageClaimRepository_DamageClaims() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	claim, err := s.repository.CreateDamageClaim(ctx, s.closedOrder.ID, s.equipment.ID, nil, "")
+	require.NoError(t, err)
+
+	claims, err := s.repository.DamageClaims(ctx, domain.DamageClaimFilter{OrderID: &s.closedOrder.ID})
+	require.NoError(t, err)
+	require.Len(t, claims, 1)
+	require.Equal(t, claim.ID, claims[0].ID)
+
+	claims, err = s.repository.DamageClaims(ctx, domain.DamageClaimFilter{EquipmentID: &s.other.ID})
+	require.NoError(t, err)
+	require.Empty(t, claims)
+
+	status := domain.DamageClaimStatusPaid
+	claims, err = s.repository.DamageClaims(ctx, domain.DamageClaimFilter{Status: &status})
+	require.NoError(t, err)
+	require.Empty(t, claims)
+	require.NoError(t, tx.Commit())
+}
+
+func (s *damageClaimRepositorySuite) TestDamageClaimRepository_UpdateDamageClaim() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	claim, err := s.repository.CreateDamageClaim(ctx, s.closedOrder.ID, s.equipment.ID, nil, "")
+	require.NoError(t, err)
+
+	amount := int64(700)
+	notes := "paid in cash"
+	status := domain.DamageClaimStatusPaid
+	updated, err := s.repository.UpdateDamageClaim(ctx, claim.ID, models.DamageClaimUpdate{
+		Amount: &amount,
+		Notes:  &notes,
+		Status: &status,
+	})
+	require.NoError(t, err)
+	require.Equal(t, amount, updated.Amount)
+	require.Equal(t, notes, updated.Notes)
+	require.Equal(t, status, updated.Status.String())
+
+	_, err = s.repository.UpdateDamageClaim(ctx, claim.ID, models.DamageClaimUpdate{Notes: &notes})
+	require.ErrorIs(t, err, domain.ErrDamageClaimNotOpen)
+
+	_, err = s.repository.UpdateDamageClaim(ctx, claim.ID+100, models.DamageClaimUpdate{Notes: &notes})
+	require.True(t, ent.IsNotFound(err))
+	require.NoError(t, tx.Commit())
+}
+
+func (s *damageClaimRepositorySuite) TestDamageClaimRepository_DeleteEquipmentWithClaims() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	defer tx.Rollback()
+	_, err := s.repository.CreateDamageClaim(ctx, s.closedOrder.ID, s.equipment.ID, nil, "")
+	require.NoError(t, err)
+
+	equipmentRepository := NewEquipmentRepository()
+	require.ErrorIs(t, equipmentRepository.DeleteEquipmentByID(ctx, s.equipment.ID), domain.ErrEquipmentHasDamageClaims)
+	require.True(t, tx.Equipment.Query().Where(equipment.ID(s.equipment.ID)).ExistX(ctx))
+	require.NoError(t, equipmentRepository.DeleteEquipmentByID(ctx, s.other.ID))
+	require.False(t, tx.Equipment.Query().Where(equipment.ID(s.other.ID)).ExistX(ctx))
+}
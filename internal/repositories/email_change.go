@@ -8,6 +8,7 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/emailconfirm"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/user"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
 )
 
@@ -31,7 +32,7 @@ func (p *confirmEmailRepository) CreateToken(ctx context.Context, token string,
 			return errDelete
 		}
 	}
-	_, err = tx.EmailConfirm.Create().SetToken(token).SetTTL(ttl).SetEmail(email).
+	_, err = tx.EmailConfirm.Create().SetToken(utils.HashToken(token)).SetTTL(ttl).SetEmail(email).
 		SetUsersID(userID).Save(ctx)
 	return err
 }
@@ -42,7 +43,7 @@ func (p *confirmEmailRepository) GetToken(ctx context.Context, token string) (*e
 		return nil, err
 	}
 
-	return tx.EmailConfirm.Query().Where(emailconfirm.TokenEQ(token)).WithUsers().Only(ctx)
+	return tx.EmailConfirm.Query().Where(emailconfirm.TokenEQ(utils.HashToken(token))).WithUsers().Only(ctx)
 }
 
 func (p *confirmEmailRepository) DeleteToken(ctx context.Context, token string) error {
@@ -50,10 +51,18 @@ func (p *confirmEmailRepository) DeleteToken(ctx context.Context, token string)
 	if err != nil {
 		return err
 	}
-	_, err = tx.EmailConfirm.Delete().Where(emailconfirm.TokenEQ(token)).Exec(ctx)
+	_, err = tx.EmailConfirm.Delete().Where(emailconfirm.TokenEQ(utils.HashToken(token))).Exec(ctx)
 	return err
 }
 
+func (p *confirmEmailRepository) DeleteExpiredTokens(ctx context.Context) (int, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return tx.EmailConfirm.Delete().Where(emailconfirm.TTLLT(time.Now())).Exec(ctx)
+}
+
 func NewConfirmEmailRepository() domain.EmailConfirmRepository {
 	return &confirmEmailRepository{}
 }
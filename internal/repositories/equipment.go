@@ -10,14 +10,17 @@ import (
 
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/category"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/damageclaim"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/equipment"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/equipmentphoto"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/equipmentstatus"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/equipmentstatusname"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/maintenanceplan"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/maintenancewindow"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/order"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/orderstatusname"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/petkind"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/petsize"
-	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/photo"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/predicate"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/subcategory"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
@@ -87,7 +90,7 @@ func (r *equipmentRepository) EquipmentsByFilter(ctx context.Context, filter mod
 		WithCategory().
 		WithSubcategory().
 		WithCurrentStatus().
-		WithPhoto().
+		WithPhotos(withCoverPhoto).
 		WithPetKinds().
 		All(ctx)
 	if err != nil {
@@ -127,14 +130,19 @@ func (r *equipmentRepository) CreateEquipment(ctx context.Context, NewEquipment
 		SetCurrentStatusID(int(*NewEquipment.Status)).
 		AddPetKindIDs(petKinds...).
 		SetTitle(*NewEquipment.Title).
-		SetPhoto(&ent.Photo{ID: *NewEquipment.PhotoID}).
 		SetPetSizeID(int(*NewEquipment.PetSize)).
 		Save(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if NewEquipment.PhotoID != nil && *NewEquipment.PhotoID != "" {
+		if err = addEquipmentPhoto(ctx, tx, eq.ID, *NewEquipment.PhotoID, true); err != nil {
+			return nil, err
+		}
+	}
 	result, err := tx.Equipment.Query().Where(equipment.ID(eq.ID)).
-		WithCategory().WithSubcategory().WithCurrentStatus().WithPhoto().WithPetKinds().WithPetSize().Only(ctx)
+		WithCategory().WithSubcategory().WithCurrentStatus().WithPhotos(withGallery).WithPetKinds().WithPetSize().
+		Only(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -147,7 +155,7 @@ func (r *equipmentRepository) EquipmentByID(ctx context.Context, id int) (*ent.E
 		return nil, err
 	}
 	result, err := tx.Equipment.Query().Where(equipment.ID(id)).
-		WithCategory().WithSubcategory().WithCurrentStatus().WithPetKinds().WithPetSize().WithPhoto().
+		WithCategory().WithSubcategory().WithCurrentStatus().WithPetKinds().WithPetSize().WithPhotos(withGallery).
 		WithEquipmentStatus(func(esq *ent.EquipmentStatusQuery) {
 			esq.
 				Where(equipmentstatus.EndDateGTE(time.Now())).
@@ -166,19 +174,26 @@ func (r *equipmentRepository) DeleteEquipmentByID(ctx context.Context, id int) e
 	if err != nil {
 		return err
 	}
-	_, err = tx.Equipment.Delete().Where(equipment.ID(id)).Exec(ctx)
+	hasClaims, err := tx.DamageClaim.Query().Where(damageclaim.HasEquipmentWith(equipment.ID(id))).Exist(ctx)
 	if err != nil {
 		return err
 	}
-	return nil
-}
-
-func (r *equipmentRepository) DeleteEquipmentPhoto(ctx context.Context, id string) error {
-	tx, err := middlewares.TxFromContext(ctx)
+	if hasClaims {
+		return domain.ErrEquipmentHasDamageClaims
+	}
+	_, err = tx.EquipmentPhoto.Delete().Where(equipmentphoto.HasEquipmentWith(equipment.ID(id))).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = tx.MaintenanceWindow.Delete().Where(maintenancewindow.HasEquipmentWith(equipment.ID(id))).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = tx.MaintenancePlan.Delete().Where(maintenanceplan.HasEquipmentWith(equipment.ID(id))).Exec(ctx)
 	if err != nil {
 		return err
 	}
-	_, err = tx.Photo.Delete().Where(photo.ID(id)).Exec(ctx)
+	_, err = tx.Equipment.Delete().Where(equipment.ID(id)).Exec(ctx)
 	if err != nil {
 		return err
 	}
@@ -213,7 +228,7 @@ func (r *equipmentRepository) AllEquipments(
 		WithCurrentStatus().
 		WithPetKinds().
 		WithPetSize().
-		WithPhoto().
+		WithPhotos(withCoverPhoto).
 		WithEquipmentStatus(func(esq *ent.EquipmentStatusQuery) {
 			conditions := []predicate.EquipmentStatus{
 				equipmentstatus.EndDateGTE(time.Now()),
@@ -418,15 +433,18 @@ func (r *equipmentRepository) UpdateEquipmentByID(ctx context.Context, id int, e
 	if *eq.Status != 0 {
 		edit.SetCurrentStatus(&ent.EquipmentStatusName{ID: int(*eq.Status)})
 	}
-	if *eq.PhotoID != "" {
-		edit.SetPhoto(&ent.Photo{ID: *eq.PhotoID})
-	}
 	_, err = edit.Save(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if eq.PhotoID != nil && *eq.PhotoID != "" {
+		if err = addEquipmentPhoto(ctx, tx, eqToUpdate.ID, *eq.PhotoID, true); err != nil {
+			return nil, err
+		}
+	}
 	result, err := tx.Equipment.Query().Where(equipment.ID(eqToUpdate.ID)).
-		WithCategory().WithSubcategory().WithCurrentStatus().WithPetSize().WithPetKinds().WithPhoto().Only(ctx)
+		WithCategory().WithSubcategory().WithCurrentStatus().WithPetSize().WithPetKinds().WithPhotos(withGallery).
+		Only(ctx)
 	if err != nil {
 		return nil, err
 	}
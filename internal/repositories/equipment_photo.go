@@ -0,0 +1,222 @@
+package repositories
+
+import (
+	"context"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/equipment"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/equipmentphoto"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/photo"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type equipmentPhotoRepository struct {
+}
+
+func NewEquipmentPhotoRepository() domain.EquipmentPhotoRepository {
+	return &equipmentPhotoRepository{}
+}
+
+func (r *equipmentPhotoRepository) EquipmentPhotos(ctx context.Context,
+	equipmentID int) ([]*ent.EquipmentPhoto, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tx.Equipment.Get(ctx, equipmentID); err != nil {
+		return nil, err
+	}
+	return galleryQuery(tx, equipmentID).All(ctx)
+}
+
+// AddEquipmentPhoto adds the photo to the end of the gallery, the photo already in the gallery keeps its position.
+// The first photo of the gallery always becomes the cover.
+func (r *equipmentPhotoRepository) AddEquipmentPhoto(ctx context.Context, equipmentID int, photoID string,
+	cover bool) ([]*ent.EquipmentPhoto, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tx.Equipment.Get(ctx, equipmentID); err != nil {
+		return nil, err
+	}
+	if err = addEquipmentPhoto(ctx, tx, equipmentID, photoID, cover); err != nil {
+		return nil, err
+	}
+	return galleryQuery(tx, equipmentID).All(ctx)
+}
+
+// ReorderEquipmentPhotos sets the order of the gallery, photoIDs must contain every photo of the gallery once.
+func (r *equipmentPhotoRepository) ReorderEquipmentPhotos(ctx context.Context, equipmentID int,
+	photoIDs []string) ([]*ent.EquipmentPhoto, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tx.Equipment.Get(ctx, equipmentID); err != nil {
+		return nil, err
+	}
+	gallery, err := galleryQuery(tx, equipmentID).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(photoIDs) != len(gallery) {
+		return nil, domain.ErrEquipmentPhotosMismatch
+	}
+	links := make(map[string]*ent.EquipmentPhoto, len(gallery))
+	for _, link := range gallery {
+		links[link.Edges.Photo.ID] = link
+	}
+	for position, id := range photoIDs {
+		link, ok := links[id]
+		if !ok {
+			return nil, domain.ErrEquipmentPhotosMismatch
+		}
+		// every photo is reordered once, so the duplicates are missing in the map the second time
+		delete(links, id)
+		if link.Position == position {
+			continue
+		}
+		if err = tx.EquipmentPhoto.UpdateOne(link).SetPosition(position).Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return galleryQuery(tx, equipmentID).All(ctx)
+}
+
+func (r *equipmentPhotoRepository) SetEquipmentCoverPhoto(ctx context.Context, equipmentID int,
+	photoID string) ([]*ent.EquipmentPhoto, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tx.Equipment.Get(ctx, equipmentID); err != nil {
+		return nil, err
+	}
+	link, err := galleryPhotoQuery(tx, equipmentID, photoID).Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, domain.ErrEquipmentPhotoNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = setCoverPhoto(ctx, tx, equipmentID, link); err != nil {
+		return nil, err
+	}
+	return galleryQuery(tx, equipmentID).All(ctx)
+}
+
+// RemoveEquipmentPhoto removes the photo from the gallery, the next photo becomes the cover if the cover is removed.
+// The photo itself is kept.
+func (r *equipmentPhotoRepository) RemoveEquipmentPhoto(ctx context.Context, equipmentID int,
+	photoID string) ([]*ent.EquipmentPhoto, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tx.Equipment.Get(ctx, equipmentID); err != nil {
+		return nil, err
+	}
+	link, err := galleryPhotoQuery(tx, equipmentID, photoID).Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, domain.ErrEquipmentPhotoNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.EquipmentPhoto.DeleteOne(link).Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	gallery, err := galleryQuery(tx, equipmentID).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for position, rest := range gallery {
+		update := tx.EquipmentPhoto.UpdateOne(rest).SetPosition(position)
+		if link.Cover && position == 0 {
+			update.SetCover(true)
+		}
+		if err = update.Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return galleryQuery(tx, equipmentID).All(ctx)
+}
+
+func addEquipmentPhoto(ctx context.Context, tx *ent.Tx, equipmentID int, photoID string, cover bool) error {
+	exists, err := tx.Photo.Query().Where(photo.ID(photoID)).Exist(ctx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrEquipmentPhotoNotFound
+	}
+	link, err := galleryPhotoQuery(tx, equipmentID, photoID).Only(ctx)
+	if err != nil && !ent.IsNotFound(err) {
+		return err
+	}
+	if link == nil {
+		var count int
+		count, err = tx.EquipmentPhoto.Query().
+			Where(equipmentphoto.HasEquipmentWith(equipment.ID(equipmentID))).
+			Count(ctx)
+		if err != nil {
+			return err
+		}
+		link, err = tx.EquipmentPhoto.Create().
+			SetEquipmentID(equipmentID).
+			SetPhotoID(photoID).
+			SetPosition(count).
+			Save(ctx)
+		if err != nil {
+			return err
+		}
+		cover = cover || count == 0
+	}
+	if !cover {
+		return nil
+	}
+	return setCoverPhoto(ctx, tx, equipmentID, link)
+}
+
+func setCoverPhoto(ctx context.Context, tx *ent.Tx, equipmentID int, link *ent.EquipmentPhoto) error {
+	err := tx.EquipmentPhoto.Update().
+		Where(equipmentphoto.HasEquipmentWith(equipment.ID(equipmentID)), equipmentphoto.Cover(true)).
+		SetCover(false).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	return tx.EquipmentPhoto.UpdateOne(link).SetCover(true).Exec(ctx)
+}
+
+func galleryQuery(tx *ent.Tx, equipmentID int) *ent.EquipmentPhotoQuery {
+	q := tx.EquipmentPhoto.Query().Where(equipmentphoto.HasEquipmentWith(equipment.ID(equipmentID)))
+	withGallery(q)
+	return q
+}
+
+func galleryPhotoQuery(tx *ent.Tx, equipmentID int, photoID string) *ent.EquipmentPhotoQuery {
+	return tx.EquipmentPhoto.Query().
+		Where(
+			equipmentphoto.HasEquipmentWith(equipment.ID(equipmentID)),
+			equipmentphoto.HasPhotoWith(photo.ID(photoID)),
+		)
+}
+
+// withGallery loads the photos of the gallery in their order.
+func withGallery(q *ent.EquipmentPhotoQuery) {
+	q.Order(ent.Asc(equipmentphoto.FieldPosition), ent.Asc(equipmentphoto.FieldID)).WithPhoto(selectPhotoID)
+}
+
+// withCoverPhoto loads only the cover of the gallery, it is enough for the lists of the equipment.
+func withCoverPhoto(q *ent.EquipmentPhotoQuery) {
+	q.Where(equipmentphoto.Cover(true)).WithPhoto(selectPhotoID)
+}
+
+// selectPhotoID skips the content of the photos kept in the database.
+func selectPhotoID(q *ent.PhotoQuery) {
+	q.Select(photo.FieldID)
+}
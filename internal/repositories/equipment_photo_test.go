@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/enttest"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type equipmentPhotoRepositorySuite struct {
+	suite.Suite
+	ctx        context.Context
+	client     *ent.Client
+	repository domain.EquipmentPhotoRepository
+	equipment  *ent.Equipment
+}
+
+func TestEquipmentPhotoSuite(t *testing.T) {
+	suite.Run(t, new(equipmentPhotoRepositorySuite))
+}
+
+func (s *equipmentPhotoRepositorySuite) SetupTest() {
+	t := s.T()
+	s.ctx = context.Background()
+	s.client = enttest.Open(t, "sqlite3", "file:equipmentphoto?mode=memory&cache=shared&_fk=1")
+	s.repository = NewEquipmentPhotoRepository()
+
+	eq, err := s.client.Equipment.Create().SetName("equipment").Save(s.ctx)
+	require.NoError(t, err)
+	s.equipment = eq
+	for _, id := range []string{"first", "second", "third"} {
+		_, err = s.client.Photo.Create().SetID(id).Save(s.ctx)
+		require.NoError(t, err)
+	}
+}
+
+func (s *equipmentPhotoRepositorySuite) TearDownTest() {
+	s.client.Close()
+}
+
+func (s *equipmentPhotoRepositorySuite) txContext() (context.Context, *ent.Tx) {
+	tx, err := s.client.Tx(s.ctx)
+	require.NoError(s.T(), err)
+	return context.WithValue(s.ctx, middlewares.TxContextKey, tx), tx
+}
+
+func (s *equipmentPhotoRepositorySuite) addPhotos(ctx context.Context, ids ...string) []*ent.EquipmentPhoto {
+	t := s.T()
+	var gallery []*ent.EquipmentPhoto
+	for _, id := range ids {
+		var err error
+		gallery, err = s.repository.AddEquipmentPhoto(ctx, s.equipment.ID, id, false)
+		require.NoError(t, err)
+	}
+	return gallery
+}
+
+// requireGallery checks the order of the photos and that only the cover photo is marked as the cover.
+func requireGallery(t *testing.T, gallery []*ent.EquipmentPhoto, cover string, want ...string) {
+	t.Helper()
+	require.Len(t, gallery, len(want))
+	for i, link := range gallery {
+		require.Equal(t, want[i], link.Edges.Photo.ID)
+		require.Equal(t, i, link.Position)
+		require.Equal(t, cover == link.Edges.Photo.ID, link.Cover, link.Edges.Photo.ID)
+	}
+}
+
+func (s *equipmentPhotoRepositorySuite) TestEquipmentPhotoRepository_AddEquipmentPhoto() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	defer tx.Rollback()
+
+	gallery := s.addPhotos(ctx, "first", "second")
+	requireGallery(t, gallery, "first", "first", "second")
+
+	gallery, err := s.repository.AddEquipmentPhoto(ctx, s.equipment.ID, "third", true)
+	require.NoError(t, err)
+	requireGallery(t, gallery, "third", "first", "second", "third")
+
+	gallery, err = s.repository.AddEquipmentPhoto(ctx, s.equipment.ID, "first", true)
+	require.NoError(t, err)
+	requireGallery(t, gallery, "first", "first", "second", "third")
+
+	_, err = s.repository.AddEquipmentPhoto(ctx, s.equipment.ID, "unknown", false)
+	require.ErrorIs(t, err, domain.ErrEquipmentPhotoNotFound)
+	_, err = s.repository.AddEquipmentPhoto(ctx, s.equipment.ID+1, "first", false)
+	require.True(t, ent.IsNotFound(err))
+}
+
+func (s *equipmentPhotoRepositorySuite) TestEquipmentPhotoRepository_ReorderEquipmentPhotos() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	defer tx.Rollback()
+	s.addPhotos(ctx, "first", "second", "third")
+
+	gallery, err := s.repository.ReorderEquipmentPhotos(ctx, s.equipment.ID, []string{"third", "first", "second"})
+	require.NoError(t, err)
+	requireGallery(t, gallery, "first", "third", "first", "second")
+
+	for _, ids := range [][]string{{"third", "first"}, {"third", "first", "first"}, {"third", "first", "unknown"}} {
+		_, err = s.repository.ReorderEquipmentPhotos(ctx, s.equipment.ID, ids)
+		require.ErrorIs(t, err, domain.ErrEquipmentPhotosMismatch, ids)
+	}
+	gallery, err = s.repository.EquipmentPhotos(ctx, s.equipment.ID)
+	require.NoError(t, err)
+	requireGallery(t, gallery, "first", "third", "first", "second")
+}
+
+func (s *equipmentPhotoRepositorySuite) TestEquipmentPhotoRepository_SetEquipmentCoverPhoto() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	defer tx.Rollback()
+	s.addPhotos(ctx, "first", "second")
+
+	gallery, err := s.repository.SetEquipmentCoverPhoto(ctx, s.equipment.ID, "second")
+	require.NoError(t, err)
+	requireGallery(t, gallery, "second", "first", "second")
+
+	_, err = s.repository.SetEquipmentCoverPhoto(ctx, s.equipment.ID, "third")
+	require.ErrorIs(t, err, domain.ErrEquipmentPhotoNotFound)
+}
+
+func (s *equipmentPhotoRepositorySuite) TestEquipmentPhotoRepository_RemoveEquipmentPhoto() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	defer tx.Rollback()
+	s.addPhotos(ctx, "first", "second", "third")
+
+	gallery, err := s.repository.RemoveEquipmentPhoto(ctx, s.equipment.ID, "second")
+	require.NoError(t, err)
+	requireGallery(t, gallery, "first", "first", "third")
+
+	gallery, err = s.repository.RemoveEquipmentPhoto(ctx, s.equipment.ID, "first")
+	require.NoError(t, err)
+	requireGallery(t, gallery, "third", "third")
+
+	_, err = s.repository.RemoveEquipmentPhoto(ctx, s.equipment.ID, "first")
+	require.ErrorIs(t, err, domain.ErrEquipmentPhotoNotFound)
+
+	gallery, err = s.repository.RemoveEquipmentPhoto(ctx, s.equipment.ID, "third")
+	require.NoError(t, err)
+	require.Empty(t, gallery)
+	exists, err := tx.Photo.Query().Exist(ctx)
+	require.NoError(t, err)
+	require.True(t, exists)
+}
@@ -9,6 +9,7 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/equipmentstatus"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/equipmentstatusname"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/order"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/orderstatusname"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/user"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
@@ -71,6 +72,51 @@ func (r *equipmentStatusRepository) Create(ctx context.Context, data *models.New
 		Save(ctx)
 }
 
+// CreateNotAvailable creates the not available status of the equipment for the period, without the limit of the
+// reservation time, as the repair of the equipment takes as long as it takes.
+func (r *equipmentStatusRepository) CreateNotAvailable(ctx context.Context, equipmentID int,
+	startDate, endDate time.Time) (*ent.EquipmentStatus, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statusName, err := tx.EquipmentStatusName.Query().
+		Where(equipmentstatusname.NameEQ(domain.EquipmentStatusNotAvailable)).
+		Only(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.EquipmentStatus.Create().
+		SetCreatedAt(time.Now()).
+		SetStartDate(startDate).
+		SetEndDate(endDate).
+		SetEquipmentsID(equipmentID).
+		SetEquipmentStatusName(statusName).
+		SetUpdatedAt(time.Now()).
+		Save(ctx)
+}
+
+// GetActiveOrdersByEquipmentInPeriod returns the active orders having the equipment booked for a period overlapping
+// the given one.
+func (r *equipmentStatusRepository) GetActiveOrdersByEquipmentInPeriod(ctx context.Context, equipmentID int,
+	startDate, endDate time.Time) ([]*ent.Order, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.Order.Query().
+		Where(order.HasEquipmentStatusWith(
+			equipmentstatus.HasEquipmentsWith(equipment.IDEQ(equipmentID)),
+			equipmentstatus.StartDateLTE(endDate),
+			equipmentstatus.EndDateGTE(startDate),
+		)).
+		Where(order.HasCurrentStatusWith(
+			orderstatusname.StatusIn(domain.OrderStatusAggregation[domain.OrderStatusActive]...),
+		)).
+		Order(ent.Asc(order.FieldID)).
+		All(ctx)
+}
+
 func (r *equipmentStatusRepository) GetEquipmentsStatusesByOrder(ctx context.Context, orderID int) ([]*ent.EquipmentStatus, error) {
 	tx, err := middlewares.TxFromContext(ctx)
 	if err != nil {
@@ -79,6 +125,7 @@ func (r *equipmentStatusRepository) GetEquipmentsStatusesByOrder(ctx context.Con
 	return tx.EquipmentStatus.Query().
 		QueryOrder().Where(order.IDEQ(orderID)).QueryEquipmentStatus().
 		WithEquipmentStatusName().
+		WithEquipments().
 		All(ctx)
 }
 
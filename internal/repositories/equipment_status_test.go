@@ -12,6 +12,7 @@ import (
 
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/enttest"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/equipmentstatusname"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
@@ -448,3 +449,58 @@ func (s *equipmentStatusTestSuite) TestEquipmentStatusRepository_GetEquipmentsSt
 	require.Greater(t, len(eqStatus), 0)
 	require.NoError(t, tx.Rollback())
 }
+
+func (s *equipmentStatusTestSuite) TestEquipmentStatusRepository_CreateNotAvailable() {
+	t := s.T()
+	ctx := s.ctx
+	tx, err := s.client.Tx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+
+	// the repair is not limited by the reservation time of the category
+	startDate := time.Now()
+	endDate := startDate.AddDate(1, 0, 0)
+	created, err := s.repository.CreateNotAvailable(ctx, s.equipment.ID, startDate, endDate)
+	require.NoError(t, err)
+	found, err := s.repository.GetEquipmentStatusByID(ctx, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.EquipmentStatusNotAvailable, found.Edges.EquipmentStatusName.Name)
+	require.Equal(t, s.equipment.ID, found.Edges.Equipments.ID)
+	require.True(t, startDate.Equal(found.StartDate))
+	require.True(t, endDate.Equal(found.EndDate))
+	require.NotEqual(t, s.eqStatus.ID, created.ID)
+}
+
+func (s *equipmentStatusTestSuite) TestEquipmentStatusRepository_GetActiveOrdersByEquipmentInPeriod() {
+	t := s.T()
+	ctx := s.ctx
+	tx, err := s.client.Tx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+
+	approved := tx.OrderStatusName.Create().SetStatus(domain.OrderStatusApproved).SaveX(ctx)
+	closed := tx.OrderStatusName.Create().SetStatus(domain.OrderStatusClosed).SaveX(ctx)
+	booked := tx.EquipmentStatusName.Query().Where(equipmentstatusname.NameEQ(domain.EquipmentStatusBooked)).OnlyX(ctx)
+	now := time.Now()
+	book := func(status *ent.OrderStatusName, start time.Time) *ent.Order {
+		o := tx.Order.Create().SetDescription("order").SetQuantity(1).SetRentStart(start).
+			SetRentEnd(start.AddDate(0, 0, 3)).SetCurrentStatus(status).SaveX(ctx)
+		tx.EquipmentStatus.Create().SetEquipments(s.equipment).SetOrder(o).SetEquipmentStatusName(booked).
+			SetStartDate(o.RentStart).SetEndDate(o.RentEnd).SaveX(ctx)
+		return o
+	}
+	overlapping := book(approved, now.AddDate(0, 0, 5))
+	book(approved, now.AddDate(0, 0, 30))
+	book(closed, now.AddDate(0, 0, 5))
+
+	orders, err := s.repository.GetActiveOrdersByEquipmentInPeriod(ctx, s.equipment.ID, now, now.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	require.Equal(t, overlapping.ID, orders[0].ID)
+
+	orders, err = s.repository.GetActiveOrdersByEquipmentInPeriod(ctx, s.equipment.ID+1, now, now.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	require.Empty(t, orders)
+}
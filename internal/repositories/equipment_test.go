@@ -84,7 +84,9 @@ func (s *EquipmentSuite) SetupTest() {
 	}
 
 	photoID := "photoID"
-	_, err = s.client.Photo.Delete().Exec(s.ctx) // clean up
+	_, err = s.client.EquipmentPhoto.Delete().Exec(s.ctx) // clean up
+	require.NoError(t, err)
+	_, err = s.client.Photo.Delete().Exec(s.ctx)
 	require.NoError(t, err)
 
 	photo, err := s.client.Photo.Create().SetID(photoID).Save(s.ctx)
@@ -164,13 +166,16 @@ func (s *EquipmentSuite) SetupTest() {
 			SetCurrentStatus(eqStatus).
 			SetCategory(category).
 			SetSubcategory(subcategory).
-			SetPhoto(photo).
 			SetPetSizeID(petSize.ID).
 			AddPetKinds(petKind).
 			Save(s.ctx)
 		if errCreate != nil {
 			t.Fatal(errCreate)
 		}
+		_, errCreate = s.client.EquipmentPhoto.Create().SetEquipment(eq).SetPhoto(photo).SetCover(true).Save(s.ctx)
+		if errCreate != nil {
+			t.Fatal(errCreate)
+		}
 		s.equipments[i].ID = eq.ID
 	}
 
@@ -706,7 +711,9 @@ func (s *EquipmentSuite) TestEquipmentRepository_TaskUnblockExpiredEquipment() {
 		{"Belt", []blockPeriod{{1 * day, 20 * day, false}}, true, domain.EquipmentStatusAvailable}, // This was not blocked yet
 	}
 
-	_, err := client.Equipment.Delete().Exec(ctx)
+	_, err := client.EquipmentPhoto.Delete().Exec(ctx)
+	require.NoError(t, err)
+	_, err = client.Equipment.Delete().Exec(ctx)
 	require.NoError(t, err)
 	_, err = client.EquipmentStatus.Delete().Exec(ctx)
 	require.NoError(t, err)
@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"context"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/group"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/permission"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/user"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type groupRepository struct {
+}
+
+func NewGroupRepository() domain.GroupRepository {
+	return &groupRepository{}
+}
+
+func (r *groupRepository) AllPermissions(ctx context.Context) ([]*ent.Permission, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.Permission.Query().Order(ent.Asc(permission.FieldName)).All(ctx)
+}
+
+func (r *groupRepository) AllGroups(ctx context.Context) ([]*ent.Group, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return groupQuery(tx).Order(ent.Asc(group.FieldName)).All(ctx)
+}
+
+func (r *groupRepository) GroupByID(ctx context.Context, id int) (*ent.Group, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return groupQuery(tx).Where(group.ID(id)).Only(ctx)
+}
+
+func (r *groupRepository) CreateGroup(ctx context.Context, name, description string) (*ent.Group, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	created, err := tx.Group.Create().SetName(name).SetDescription(description).Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.GroupByID(ctx, created.ID)
+}
+
+func (r *groupRepository) UpdateGroup(ctx context.Context, id int, name, description string) (*ent.Group, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Group.UpdateOneID(id).SetName(name).SetDescription(description).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.GroupByID(ctx, id)
+}
+
+func (r *groupRepository) DeleteGroup(ctx context.Context, id int) error {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	return tx.Group.DeleteOneID(id).Exec(ctx)
+}
+
+// SetGroupPermissions replaces the permissions of the group, all names must be known permissions.
+func (r *groupRepository) SetGroupPermissions(ctx context.Context, id int,
+	permissionNames []string) (*ent.Group, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := tx.Permission.Query().Where(permission.NameIn(permissionNames...)).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(permissions) != len(utils.Unique(permissionNames)) {
+		return nil, domain.ErrUnknownPermission
+	}
+	err = tx.Group.UpdateOneID(id).ClearPermissions().AddPermissions(permissions...).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.GroupByID(ctx, id)
+}
+
+func (r *groupRepository) AddGroupUsers(ctx context.Context, id int, userIDs []int) (*ent.Group, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users, err := tx.User.Query().Where(user.IDIn(userIDs...), user.IsDeleted(false)).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) != len(utils.Unique(userIDs)) {
+		return nil, domain.ErrUnknownUser
+	}
+	existing, err := tx.Group.Query().Where(group.ID(id)).QueryUsers().IDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var newUserIDs []int
+	for _, u := range users {
+		if !utils.IsValueInList(u.ID, existing) {
+			newUserIDs = append(newUserIDs, u.ID)
+		}
+	}
+	err = tx.Group.UpdateOneID(id).AddUserIDs(newUserIDs...).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.GroupByID(ctx, id)
+}
+
+func (r *groupRepository) RemoveGroupUser(ctx context.Context, id int, userID int) (*ent.Group, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Group.UpdateOneID(id).RemoveUserIDs(userID).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.GroupByID(ctx, id)
+}
+
+func groupQuery(tx *ent.Tx) *ent.GroupQuery {
+	return tx.Group.Query().
+		WithPermissions(func(q *ent.PermissionQuery) {
+			q.Order(ent.Asc(permission.FieldName))
+		}).
+		WithUsers(func(q *ent.UserQuery) {
+			q.Select(user.FieldID).Order(ent.Asc(user.FieldID))
+		})
+}
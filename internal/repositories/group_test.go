@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/enttest"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type GroupSuite struct {
+	suite.Suite
+	ctx        context.Context
+	client     *ent.Client
+	repository domain.GroupRepository
+	users      []*ent.User
+}
+
+func TestGroupSuite(t *testing.T) {
+	suite.Run(t, new(GroupSuite))
+}
+
+func (s *GroupSuite) SetupTest() {
+	t := s.T()
+	s.ctx = context.Background()
+	s.client = enttest.Open(t, "sqlite3", "file:group?mode=memory&cache=shared&_fk=1")
+	s.repository = NewGroupRepository()
+
+	_, err := s.client.Group.Delete().Exec(s.ctx)
+	require.NoError(t, err)
+	_, err = s.client.Permission.Delete().Exec(s.ctx)
+	require.NoError(t, err)
+	_, err = s.client.User.Delete().Exec(s.ctx)
+	require.NoError(t, err)
+
+	for _, name := range []string{"orders:view", "orders:approve", "equipment:block"} {
+		_, err = s.client.Permission.Create().SetName(name).Save(s.ctx)
+		require.NoError(t, err)
+	}
+	s.users = nil
+	for _, login := range []string{"first", "second"} {
+		user, errCreate := s.client.User.Create().SetLogin(login).SetEmail(login + "@example.com").
+			SetPassword("password").Save(s.ctx)
+		require.NoError(t, errCreate)
+		s.users = append(s.users, user)
+	}
+}
+
+func (s *GroupSuite) TearDownSuite() {
+	s.client.Close()
+}
+
+func (s *GroupSuite) txContext() (context.Context, *ent.Tx) {
+	tx, err := s.client.Tx(s.ctx)
+	require.NoError(s.T(), err)
+	return context.WithValue(s.ctx, middlewares.TxContextKey, tx), tx
+}
+
+func (s *GroupSuite) TestGroupRepository_AllPermissions() {
+	t := s.T()
+	ctx, tx := s.txContext()
+
+	permissions, err := s.repository.AllPermissions(ctx)
+	require.NoError(t, err)
+	require.Len(t, permissions, 3)
+	require.Equal(t, "equipment:block", permissions[0].Name)
+	require.NoError(t, tx.Commit())
+}
+
+func (s *GroupSuite) TestGroupRepository_CreateUpdateDelete() {
+	t := s.T()
+	ctx, tx := s.txContext()
+
+	group, err := s.repository.CreateGroup(ctx, "warehouse", "warehouse staff")
+	require.NoError(t, err)
+	require.Equal(t, "warehouse", group.Name)
+
+	_, err = s.repository.CreateGroup(ctx, "warehouse", "")
+	require.True(t, ent.IsConstraintError(err))
+
+	group, err = s.repository.UpdateGroup(ctx, group.ID, "storekeepers", "")
+	require.NoError(t, err)
+	require.Equal(t, "storekeepers", group.Name)
+	require.Empty(t, group.Description)
+
+	groups, err := s.repository.AllGroups(ctx)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+
+	require.NoError(t, s.repository.DeleteGroup(ctx, group.ID))
+	_, err = s.repository.GroupByID(ctx, group.ID)
+	require.True(t, ent.IsNotFound(err))
+	require.True(t, ent.IsNotFound(s.repository.DeleteGroup(ctx, group.ID)))
+	require.NoError(t, tx.Commit())
+}
+
+func (s *GroupSuite) TestGroupRepository_SetGroupPermissions() {
+	t := s.T()
+	ctx, tx := s.txContext()
+
+	group, err := s.repository.CreateGroup(ctx, "approvers", "")
+	require.NoError(t, err)
+
+	group, err = s.repository.SetGroupPermissions(ctx, group.ID, []string{"orders:view", "orders:approve"})
+	require.NoError(t, err)
+	require.Len(t, group.Edges.Permissions, 2)
+
+	group, err = s.repository.SetGroupPermissions(ctx, group.ID, []string{"orders:view", "orders:view"})
+	require.NoError(t, err)
+	require.Len(t, group.Edges.Permissions, 1)
+	require.Equal(t, "orders:view", group.Edges.Permissions[0].Name)
+
+	_, err = s.repository.SetGroupPermissions(ctx, group.ID, []string{"orders:view", "unknown"})
+	require.ErrorIs(t, err, domain.ErrUnknownPermission)
+	require.NoError(t, tx.Commit())
+}
+
+func (s *GroupSuite) TestGroupRepository_GroupUsers() {
+	t := s.T()
+	ctx, tx := s.txContext()
+
+	group, err := s.repository.CreateGroup(ctx, "approvers", "")
+	require.NoError(t, err)
+
+	group, err = s.repository.AddGroupUsers(ctx, group.ID, []int{s.users[0].ID})
+	require.NoError(t, err)
+	require.Len(t, group.Edges.Users, 1)
+
+	group, err = s.repository.AddGroupUsers(ctx, group.ID, []int{s.users[0].ID, s.users[1].ID})
+	require.NoError(t, err)
+	require.Len(t, group.Edges.Users, 2)
+
+	_, err = s.repository.AddGroupUsers(ctx, group.ID, []int{s.users[1].ID + 100})
+	require.ErrorIs(t, err, domain.ErrUnknownUser)
+
+	group, err = s.repository.RemoveGroupUser(ctx, group.ID, s.users[0].ID)
+	require.NoError(t, err)
+	require.Len(t, group.Edges.Users, 1)
+	require.Equal(t, s.users[1].ID, group.Edges.Users[0].ID)
+	require.NoError(t, tx.Commit())
+}
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/enttest"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
+)
+
+// hashedTokenRepository adapts the registration confirmation, email change and password reset repositories,
+// which store the tokens sent to users in the same way.
+type hashedTokenRepository struct {
+	create func(ctx context.Context, token string, ttl time.Time, userID int) error
+	// get returns the owner of the token.
+	get                 func(ctx context.Context, token string) (int, error)
+	deleteToken         func(ctx context.Context, token string) error
+	deleteExpiredTokens func(ctx context.Context) (int, error)
+	// storedTokens returns the token column of all rows.
+	storedTokens func(ctx context.Context, tx *ent.Tx) []string
+}
+
+func TestHashedTokenRepositories(t *testing.T) {
+	passwordReset := NewPasswordResetRepository()
+	registrationConfirm := NewRegistrationConfirmRepository()
+	emailConfirm := NewConfirmEmailRepository()
+
+	tests := []struct {
+		name       string
+		repository hashedTokenRepository
+	}{
+		{
+			name: "password reset",
+			repository: hashedTokenRepository{
+				create: passwordReset.CreateToken,
+				get: func(ctx context.Context, token string) (int, error) {
+					found, err := passwordReset.GetToken(ctx, token)
+					if err != nil {
+						return 0, err
+					}
+					return found.Edges.Users.ID, nil
+				},
+				deleteToken:         passwordReset.DeleteToken,
+				deleteExpiredTokens: passwordReset.DeleteExpiredTokens,
+				storedTokens: func(ctx context.Context, tx *ent.Tx) []string {
+					var tokens []string
+					for _, row := range tx.PasswordReset.Query().AllX(ctx) {
+						tokens = append(tokens, row.Token)
+					}
+					return tokens
+				},
+			},
+		},
+		{
+			name: "registration confirmation",
+			repository: hashedTokenRepository{
+				create: registrationConfirm.CreateToken,
+				get: func(ctx context.Context, token string) (int, error) {
+					found, err := registrationConfirm.GetToken(ctx, token)
+					if err != nil {
+						return 0, err
+					}
+					return found.Edges.Users.ID, nil
+				},
+				deleteToken:         registrationConfirm.DeleteToken,
+				deleteExpiredTokens: registrationConfirm.DeleteExpiredTokens,
+				storedTokens: func(ctx context.Context, tx *ent.Tx) []string {
+					var tokens []string
+					for _, row := range tx.RegistrationConfirm.Query().AllX(ctx) {
+						tokens = append(tokens, row.Token)
+					}
+					return tokens
+				},
+			},
+		},
+		{
+			name: "email change",
+			repository: hashedTokenRepository{
+				create: func(ctx context.Context, token string, ttl time.Time, userID int) error {
+					return emailConfirm.CreateToken(ctx, token, ttl, userID, token+"@email.com")
+				},
+				get: func(ctx context.Context, token string) (int, error) {
+					found, err := emailConfirm.GetToken(ctx, token)
+					if err != nil {
+						return 0, err
+					}
+					return found.Edges.Users.ID, nil
+				},
+				deleteToken:         emailConfirm.DeleteToken,
+				deleteExpiredTokens: emailConfirm.DeleteExpiredTokens,
+				storedTokens: func(ctx context.Context, tx *ent.Tx) []string {
+					var tokens []string
+					for _, row := range tx.EmailConfirm.Query().AllX(ctx) {
+						tokens = append(tokens, row.Token)
+					}
+					return tokens
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := enttest.Open(t, "sqlite3", "file:hashedtokens?mode=memory&cache=shared&_fk=1")
+			defer client.Close()
+			user := client.User.Create().SetLogin("login").SetEmail("email").SetPassword("password").SaveX(ctx)
+			other := client.User.Create().SetLogin("other").SetEmail("other").SetPassword("password").SaveX(ctx)
+			repository := tc.repository
+
+			tx, err := client.Tx(ctx)
+			require.NoError(t, err)
+			defer tx.Rollback()
+			ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+
+			// the token is stored as its hash and is found only by the token itself
+			token := "token"
+			require.NoError(t, repository.create(ctx, token, time.Now().Add(time.Hour), user.ID))
+			stored := repository.storedTokens(ctx, tx)
+			require.Equal(t, []string{utils.HashToken(token)}, stored)
+
+			userID, err := repository.get(ctx, token)
+			require.NoError(t, err)
+			require.Equal(t, user.ID, userID)
+			_, err = repository.get(ctx, stored[0])
+			require.True(t, ent.IsNotFound(err))
+
+			require.NoError(t, repository.deleteToken(ctx, token))
+			_, err = repository.get(ctx, token)
+			require.True(t, ent.IsNotFound(err))
+
+			// only the expired tokens are deleted
+			require.NoError(t, repository.create(ctx, "expired", time.Now().Add(-time.Hour), user.ID))
+			require.NoError(t, repository.create(ctx, "active", time.Now().Add(time.Hour), other.ID))
+			deleted, err := repository.deleteExpiredTokens(ctx)
+			require.NoError(t, err)
+			require.Equal(t, 1, deleted)
+			stored = repository.storedTokens(ctx, tx)
+			require.Equal(t, []string{utils.HashToken("active")}, stored)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/loginstate"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type loginStateRepository struct {
+}
+
+func (r *loginStateRepository) CreateState(ctx context.Context, state, codeVerifier, nonce string,
+	ttl time.Time) error {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = tx.LoginState.Create().
+		SetState(utils.HashToken(state)).
+		SetCodeVerifier(codeVerifier).
+		SetNonce(nonce).
+		SetTTL(ttl).
+		Save(ctx)
+	return err
+}
+
+// ConsumeState returns the login state and deletes it, so every state is used only once.
+func (r *loginStateRepository) ConsumeState(ctx context.Context, state string) (*ent.LoginState, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	loginState, err := tx.LoginState.Query().Where(loginstate.StateEQ(utils.HashToken(state))).Only(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := tx.LoginState.Delete().Where(loginstate.ID(loginState.ID)).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		return nil, &ent.NotFoundError{}
+	}
+	return loginState, nil
+}
+
+func (r *loginStateRepository) DeleteExpiredTokens(ctx context.Context) (int, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return tx.LoginState.Delete().Where(loginstate.TTLLT(time.Now())).Exec(ctx)
+}
+
+func NewLoginStateRepository() domain.LoginStateRepository {
+	return &loginStateRepository{}
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/enttest"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type LoginStateSuite struct {
+	suite.Suite
+	ctx        context.Context
+	client     *ent.Client
+	repository domain.LoginStateRepository
+}
+
+func TestLoginStateSuite(t *testing.T) {
+	suite.Run(t, new(LoginStateSuite))
+}
+
+func (s *LoginStateSuite) SetupTest() {
+	t := s.T()
+	s.ctx = context.Background()
+	s.client = enttest.Open(t, "sqlite3", "file:loginstate?mode=memory&cache=shared&_fk=1")
+	s.repository = NewLoginStateRepository()
+
+	_, err := s.client.LoginState.Delete().Exec(s.ctx)
+	require.NoError(t, err)
+}
+
+func (s *LoginStateSuite) TearDownSuite() {
+	s.client.Close()
+}
+
+func (s *LoginStateSuite) txContext() (context.Context, *ent.Tx) {
+	tx, err := s.client.Tx(s.ctx)
+	require.NoError(s.T(), err)
+	return context.WithValue(s.ctx, middlewares.TxContextKey, tx), tx
+}
+
+func (s *LoginStateSuite) TestLoginStateRepository_ConsumeStateOnce() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	state := "state"
+
+	require.NoError(t, s.repository.CreateState(ctx, state, "verifier", "nonce", time.Now().Add(time.Hour)))
+
+	stored, err := tx.LoginState.Query().Only(ctx)
+	require.NoError(t, err)
+	require.Equal(t, utils.HashToken(state), stored.State)
+
+	consumed, err := s.repository.ConsumeState(ctx, state)
+	require.NoError(t, err)
+	require.Equal(t, "verifier", consumed.CodeVerifier)
+	require.Equal(t, "nonce", consumed.Nonce)
+
+	_, err = s.repository.ConsumeState(ctx, state)
+	require.True(t, ent.IsNotFound(err))
+	require.NoError(t, tx.Commit())
+}
+
+func (s *LoginStateSuite) TestLoginStateRepository_DeleteExpiredTokens() {
+	t := s.T()
+	ctx, tx := s.txContext()
+
+	require.NoError(t, s.repository.CreateState(ctx, "expired", "verifier", "nonce", time.Now().Add(-time.Minute)))
+	require.NoError(t, s.repository.CreateState(ctx, "valid", "verifier", "nonce", time.Now().Add(time.Hour)))
+
+	deleted, err := s.repository.DeleteExpiredTokens(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+
+	_, err = s.repository.ConsumeState(ctx, "valid")
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+}
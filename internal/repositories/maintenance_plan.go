@@ -0,0 +1,192 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/equipment"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/equipmentstatus"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/equipmentstatusname"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/maintenanceplan"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/maintenancewindow"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/order"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/orderstatusname"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type maintenancePlanRepository struct {
+}
+
+func NewMaintenancePlanRepository() domain.MaintenancePlanRepository {
+	return &maintenancePlanRepository{}
+}
+
+func maintenancePlanQuery(tx *ent.Tx) *ent.MaintenancePlanQuery {
+	return tx.MaintenancePlan.Query().
+		WithEquipment().
+		WithCategory(func(query *ent.CategoryQuery) {
+			query.WithEquipments()
+		})
+}
+
+func (r *maintenancePlanRepository) CreateMaintenancePlan(ctx context.Context,
+	plan models.NewMaintenancePlan) (*ent.MaintenancePlan, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	create := tx.MaintenancePlan.Create().SetName(*plan.Name)
+	if plan.EquipmentID != nil {
+		planEquipment, errGet := tx.Equipment.Get(ctx, int(*plan.EquipmentID))
+		if errGet != nil {
+			return nil, errGet
+		}
+		create.SetEquipment(planEquipment)
+	}
+	if plan.CategoryID != nil {
+		planCategory, errGet := tx.Category.Get(ctx, int(*plan.CategoryID))
+		if errGet != nil {
+			return nil, errGet
+		}
+		create.SetCategory(planCategory)
+	}
+	if plan.EveryRentals != nil {
+		create.SetEveryRentals(int(*plan.EveryRentals))
+	}
+	if plan.EveryDays != nil {
+		create.SetEveryDays(int(*plan.EveryDays))
+	}
+	if plan.DurationDays != nil {
+		create.SetDurationDays(int(*plan.DurationDays))
+	}
+	created, err := create.Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return maintenancePlanQuery(tx).Where(maintenanceplan.ID(created.ID)).Only(ctx)
+}
+
+func (r *maintenancePlanRepository) MaintenancePlans(ctx context.Context) ([]*ent.MaintenancePlan, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return maintenancePlanQuery(tx).Order(ent.Asc(maintenanceplan.FieldID)).All(ctx)
+}
+
+func (r *maintenancePlanRepository) DeleteMaintenancePlan(ctx context.Context, id int) error {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.MaintenancePlan.Get(ctx, id); err != nil {
+		return err
+	}
+	cancelled, err := tx.MaintenanceWindow.Query().
+		Where(maintenancewindow.HasPlanWith(maintenanceplan.ID(id)), maintenancewindow.StartDateGT(time.Now())).
+		QueryEquipmentStatus().
+		IDs(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = tx.MaintenanceWindow.Delete().Where(maintenancewindow.HasPlanWith(maintenanceplan.ID(id))).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = tx.EquipmentStatus.Delete().Where(equipmentstatus.IDIn(cancelled...)).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	return tx.MaintenancePlan.DeleteOneID(id).Exec(ctx)
+}
+
+func (r *maintenancePlanRepository) LastMaintenance(ctx context.Context,
+	planID, equipmentID int) (*ent.MaintenanceWindow, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	window, err := tx.MaintenanceWindow.Query().
+		Where(
+			maintenancewindow.HasPlanWith(maintenanceplan.ID(planID)),
+			maintenancewindow.HasEquipmentWith(equipment.ID(equipmentID)),
+		).
+		Order(ent.Desc(maintenancewindow.FieldEndDate)).
+		First(ctx)
+	if ent.IsNotFound(err) {
+		return nil, nil
+	}
+	return window, err
+}
+
+func (r *maintenancePlanRepository) RentalsSince(ctx context.Context, equipmentID int,
+	since time.Time) (int, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return tx.Order.Query().
+		Where(
+			order.HasEquipmentsWith(equipment.ID(equipmentID)),
+			order.HasCurrentStatusWith(orderstatusname.StatusEQ(domain.OrderStatusClosed)),
+			order.RentEndGT(since),
+		).
+		Count(ctx)
+}
+
+func (r *maintenancePlanRepository) ScheduleMaintenance(ctx context.Context, planID, equipmentID int,
+	start, end time.Time) (*ent.MaintenanceWindow, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := tx.MaintenancePlan.Get(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	statusName, err := tx.EquipmentStatusName.Query().
+		Where(equipmentstatusname.NameEQ(domain.EquipmentStatusNotAvailable)).
+		Only(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status, err := tx.EquipmentStatus.Create().
+		SetComment(fmt.Sprintf("maintenance: %s", plan.Name)).
+		SetCreatedAt(time.Now()).
+		SetUpdatedAt(time.Now()).
+		SetStartDate(start).
+		SetEndDate(end).
+		SetEquipmentsID(equipmentID).
+		SetEquipmentStatusName(statusName).
+		Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.MaintenanceWindow.Create().
+		SetPlan(plan).
+		SetEquipmentID(equipmentID).
+		SetEquipmentStatus(status).
+		SetStartDate(start).
+		SetEndDate(end).
+		Save(ctx)
+}
+
+func (r *maintenancePlanRepository) UpcomingMaintenance(ctx context.Context, from time.Time,
+	equipmentID *int) ([]*ent.MaintenanceWindow, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := tx.MaintenanceWindow.Query().
+		Where(maintenancewindow.EndDateGTE(from)).
+		WithPlan().
+		WithEquipment()
+	if equipmentID != nil {
+		query.Where(maintenancewindow.HasEquipmentWith(equipment.ID(*equipmentID)))
+	}
+	return query.Order(ent.Asc(maintenancewindow.FieldStartDate)).All(ctx)
+}
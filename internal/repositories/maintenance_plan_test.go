@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/enttest"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type maintenancePlanRepositorySuite struct {
+	suite.Suite
+	ctx        context.Context
+	client     *ent.Client
+	repository domain.MaintenancePlanRepository
+	category   *ent.Category
+	equipment  *ent.Equipment
+	closed     *ent.OrderStatusName
+}
+
+func TestMaintenancePlanRepositorySuite(t *testing.T) {
+	suite.Run(t, new(maintenancePlanRepositorySuite))
+}
+
+func (s *maintenancePlanRepositorySuite) SetupTest() {
+	s.ctx = context.Background()
+	s.client = enttest.Open(s.T(), "sqlite3", "file:maintenanceplans?mode=memory&cache=shared&_fk=1")
+	s.repository = NewMaintenancePlanRepository()
+
+	s.client.EquipmentStatusName.Create().SetName(domain.EquipmentStatusNotAvailable).SaveX(s.ctx)
+	s.closed = s.client.OrderStatusName.Create().SetStatus(domain.OrderStatusClosed).SaveX(s.ctx)
+	s.category = s.client.Category.Create().SetName("tents").SaveX(s.ctx)
+	s.equipment = s.client.Equipment.Create().SetName("tent").SetTitle("tent").
+		SetCategory(s.category).SaveX(s.ctx)
+}
+
+func (s *maintenancePlanRepositorySuite) TearDownTest() {
+	s.client.MaintenanceWindow.Delete().ExecX(s.ctx)
+	s.client.MaintenancePlan.Delete().ExecX(s.ctx)
+	s.client.EquipmentStatus.Delete().ExecX(s.ctx)
+	s.client.Order.Delete().ExecX(s.ctx)
+	s.client.Equipment.Delete().ExecX(s.ctx)
+	s.client.Category.Delete().ExecX(s.ctx)
+	s.client.OrderStatusName.Delete().ExecX(s.ctx)
+	s.client.EquipmentStatusName.Delete().ExecX(s.ctx)
+	s.client.Close()
+}
+
+func (s *maintenancePlanRepositorySuite) txContext() (context.Context, *ent.Tx) {
+	tx, err := s.client.Tx(s.ctx)
+	require.NoError(s.T(), err)
+	return context.WithValue(s.ctx, middlewares.TxContextKey, tx), tx
+}
+
+func (s *maintenancePlanRepositorySuite) createPlan(ctx context.Context) *ent.MaintenancePlan {
+	name := "service"
+	categoryID := int64(s.category.ID)
+	everyDays := int64(90)
+	plan, err := s.repository.CreateMaintenancePlan(ctx, models.NewMaintenancePlan{
+		Name: &name, CategoryID: &categoryID, EveryDays: &everyDays,
+	})
+	require.NoError(s.T(), err)
+	return plan
+}
+
+func (s *maintenancePlanRepositorySuite) TestMaintenancePlanRepository_CreateMaintenancePlan() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	plan := s.createPlan(ctx)
+	require.NoError(t, tx.Commit())
+	require.Equal(t, "service", plan.Name)
+	require.Equal(t, 90, *plan.EveryDays)
+	require.Nil(t, plan.EveryRentals)
+	require.Equal(t, 1, plan.DurationDays)
+	require.Nil(t, plan.Edges.Equipment)
+	require.Equal(t, s.category.ID, plan.Edges.Category.ID)
+	require.Len(t, plan.Edges.Category.Edges.Equipments, 1)
+}
+
+func (s *maintenancePlanRepositorySuite) TestMaintenancePlanRepository_CreateMaintenancePlan_NotFound() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	name := "service"
+	equipmentID := int64(s.equipment.ID + 100)
+	everyRentals := int64(5)
+	_, err := s.repository.CreateMaintenancePlan(ctx, models.NewMaintenancePlan{
+		Name: &name, EquipmentID: &equipmentID, EveryRentals: &everyRentals,
+	})
+	require.True(t, ent.IsNotFound(err))
+	require.NoError(t, tx.Rollback())
+}
+
+func (s *maintenancePlanRepositorySuite) TestMaintenancePlanRepository_ScheduleMaintenance() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	plan := s.createPlan(ctx)
+
+	last, err := s.repository.LastMaintenance(ctx, plan.ID, s.equipment.ID)
+	require.NoError(t, err)
+	require.Nil(t, last)
+
+	start := time.Now().Add(24 * time.Hour)
+	window, err := s.repository.ScheduleMaintenance(ctx, plan.ID, s.equipment.ID, start, start.Add(24*time.Hour))
+	require.NoError(t, err)
+
+	status, err := window.QueryEquipmentStatus().WithEquipmentStatusName().WithEquipments().Only(ctx)
+	require.NoError(t, err)
+	require.Equal(t, domain.EquipmentStatusNotAvailable, status.Edges.EquipmentStatusName.Name)
+	require.Equal(t, s.equipment.ID, status.Edges.Equipments.ID)
+	require.Equal(t, "maintenance: service", status.Comment)
+
+	last, err = s.repository.LastMaintenance(ctx, plan.ID, s.equipment.ID)
+	require.NoError(t, err)
+	require.Equal(t, window.ID, last.ID)
+
+	upcoming, err := s.repository.UpcomingMaintenance(ctx, time.Now(), &s.equipment.ID)
+	require.NoError(t, err)
+	require.Len(t, upcoming, 1)
+	require.Equal(t, "service", upcoming[0].Edges.Plan.Name)
+	require.Equal(t, s.equipment.ID, upcoming[0].Edges.Equipment.ID)
+
+	upcoming, err = s.repository.UpcomingMaintenance(ctx, start.Add(48*time.Hour), nil)
+	require.NoError(t, err)
+	require.Empty(t, upcoming)
+	require.NoError(t, tx.Commit())
+}
+
+func (s *maintenancePlanRepositorySuite) TestMaintenancePlanRepository_RentalsSince() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	since := time.Now().Add(-10 * 24 * time.Hour)
+	for _, rentEnd := range []time.Time{since.Add(-time.Hour), since.Add(time.Hour), time.Now()} {
+		s.client.Order.Create().SetDescription("order").SetQuantity(1).
+			SetRentStart(rentEnd.Add(-time.Hour)).SetRentEnd(rentEnd).SetCurrentStatus(s.closed).
+			AddEquipments(s.equipment).SaveX(s.ctx)
+	}
+	s.client.Order.Create().SetDescription("active").SetQuantity(1).
+		SetRentStart(time.Now()).SetRentEnd(time.Now()).AddEquipments(s.equipment).SaveX(s.ctx)
+
+	rentals, err := s.repository.RentalsSince(ctx, s.equipment.ID, since)
+	require.NoError(t, err)
+	require.Equal(t, 2, rentals)
+	require.NoError(t, tx.Commit())
+}
+
+func (s *maintenancePlanRepositorySuite) TestMaintenancePlanRepository_DeleteMaintenancePlan() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	plan := s.createPlan(ctx)
+	past := time.Now().Add(-48 * time.Hour)
+	_, err := s.repository.ScheduleMaintenance(ctx, plan.ID, s.equipment.ID, past, past.Add(24*time.Hour))
+	require.NoError(t, err)
+	future := time.Now().Add(24 * time.Hour)
+	_, err = s.repository.ScheduleMaintenance(ctx, plan.ID, s.equipment.ID, future, future.Add(24*time.Hour))
+	require.NoError(t, err)
+
+	require.NoError(t, s.repository.DeleteMaintenancePlan(ctx, plan.ID))
+	require.True(t, ent.IsNotFound(s.repository.DeleteMaintenancePlan(ctx, plan.ID)))
+
+	plans, err := s.repository.MaintenancePlans(ctx)
+	require.NoError(t, err)
+	require.Empty(t, plans)
+	require.Zero(t, tx.MaintenanceWindow.Query().CountX(ctx))
+	statuses := tx.EquipmentStatus.Query().AllX(ctx)
+	require.Len(t, statuses, 1)
+	require.True(t, statuses[0].StartDate.Before(time.Now()))
+	require.NoError(t, tx.Commit())
+}
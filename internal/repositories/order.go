@@ -15,6 +15,7 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/order"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/orderstatus"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/orderstatusname"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/organization"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/user"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
@@ -87,7 +88,7 @@ func (r *orderRepository) List(ctx context.Context, ownerId *int, filter domain.
 
 	query = r.applyListFilters(query, filter)
 
-	items, err := query.WithUsers().WithOrderStatus().WithEquipments().All(ctx)
+	items, err := query.WithUsers().WithOrganization().WithOrderStatus().WithEquipments().All(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -107,6 +108,14 @@ func (r *orderRepository) OrdersTotal(ctx context.Context, ownerId *int) (int, e
 	return query.Count(ctx)
 }
 
+func (r *orderRepository) OrganizationOrdersTotal(ctx context.Context, organizationID int) (int, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return tx.Order.Query().Where(order.HasOrganizationWith(organization.ID(organizationID))).Count(ctx)
+}
+
 func (r *orderRepository) Create(ctx context.Context, data *models.OrderCreateRequest, ownerId int, equipmentIDs []int) (*ent.Order, error) {
 	tx, err := middlewares.TxFromContext(ctx)
 	if err != nil {
@@ -118,7 +127,7 @@ func (r *orderRepository) Create(ctx context.Context, data *models.OrderCreateRe
 	equipments := make([]*ent.Equipment, len(equipmentIDs))
 	for i, eqID := range equipmentIDs {
 		eq, err := tx.Equipment.Query().Where(equipment.ID(eqID)).
-			WithCategory().WithCurrentStatus().WithPetKinds().WithPetSize().WithPhoto().Only(ctx)
+			WithCategory().WithCurrentStatus().WithPetKinds().WithPetSize().WithPhotos(withCoverPhoto).Only(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -160,7 +169,7 @@ func (r *orderRepository) Create(ctx context.Context, data *models.OrderCreateRe
 		return nil, err
 	}
 
-	createdOrder, err := tx.Order.
+	orderCreate := tx.Order.
 		Create().
 		SetDescription(data.Description).
 		SetQuantity(1).
@@ -171,8 +180,11 @@ func (r *orderRepository) Create(ctx context.Context, data *models.OrderCreateRe
 		SetIsFirst(isFirst).
 		SetCurrentStatus(statusName).
 		AddEquipments(equipments...).
-		AddEquipmentIDs(equipmentIDs...).
-		Save(ctx)
+		AddEquipmentIDs(equipmentIDs...)
+	if data.OrganizationID != 0 {
+		orderCreate.SetOrganizationID(int(data.OrganizationID))
+	}
+	createdOrder, err := orderCreate.Save(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -189,7 +201,7 @@ func (r *orderRepository) Create(ctx context.Context, data *models.OrderCreateRe
 		return nil, err
 	}
 	newOrder, err := tx.Order.Query().Where(order.IDEQ(createdOrder.ID)). // get order with relations
-										WithUsers().WithOrderStatus().Only(ctx)
+										WithUsers().WithOrganization().WithOrderStatus().Only(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -200,6 +212,15 @@ func (r *orderRepository) Create(ctx context.Context, data *models.OrderCreateRe
 	return newOrder, nil
 }
 
+// Get returns the order with its owner.
+func (r *orderRepository) Get(ctx context.Context, id int) (*ent.Order, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.Order.Query().Where(order.IDEQ(id)).WithUsers().WithOrganization().Only(ctx)
+}
+
 func (r *orderRepository) Update(ctx context.Context, id int, data *models.OrderUpdateRequest, userId int) (*ent.Order, error) {
 	tx, err := middlewares.TxFromContext(ctx)
 	if err != nil {
@@ -250,7 +271,7 @@ func (r *orderRepository) Update(ctx context.Context, id int, data *models.Order
 	}
 
 	returnOrder, err := tx.Order.Query().Where(order.IDEQ(createdOrder.ID)). // get order with relations
-											WithUsers().WithOrderStatus().Only(ctx)
+											WithUsers().WithOrganization().WithOrderStatus().Only(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -275,6 +296,9 @@ func (r *orderRepository) applyListFilters(q *ent.OrderQuery, filter domain.Orde
 	if filter.EquipmentID != nil {
 		q = q.Where(order.HasEquipmentsWith(equipment.ID(*filter.EquipmentID)))
 	}
+	if filter.OrganizationID != nil {
+		q = q.Where(order.HasOrganizationWith(organization.ID(*filter.OrganizationID)))
+	}
 	return q
 }
 func (r *orderRepository) getFullOrder(ctx context.Context, order *ent.Order) (*ent.Order, error) {
@@ -292,7 +316,7 @@ func (r *orderRepository) getFullOrder(ctx context.Context, order *ent.Order) (*
 	}
 	eq, err := order.QueryEquipments().
 		WithCategory().WithSubcategory().WithCurrentStatus().
-		WithPhoto().WithPetSize().WithPetKinds().
+		WithPhotos(withCoverPhoto).WithPetSize().WithPetKinds().
 		All(ctx)
 	if err != nil {
 		return nil, err
@@ -9,6 +9,7 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/order"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/orderstatus"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/orderstatusname"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/photo"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/user"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
@@ -66,7 +67,12 @@ func (r *orderStatusRepository) StatusHistory(ctx context.Context, orderId int)
 	}
 	statuses, err := tx.OrderStatus.Query().
 		QueryOrder().Where(order.IDEQ(orderId)).QueryOrderStatus().
-		WithOrder().WithOrderStatusName().WithUsers().All(ctx)
+		WithOrder(func(query *ent.OrderQuery) {
+			query.WithUsers().WithOrganization()
+		}).WithOrderStatusName().WithUsers().
+		WithPhotos(func(query *ent.PhotoQuery) {
+			query.Select(photo.FieldID).Order(ent.Asc(photo.FieldID))
+		}).All(ctx)
 
 	return statuses, err
 }
@@ -94,12 +100,25 @@ func (r *orderStatusRepository) UpdateStatus(ctx context.Context, userID int, st
 	if err != nil {
 		return fmt.Errorf("status history error, failed to get user: %s", err)
 	}
-	_, err = tx.OrderStatus.Create().
+	create := tx.OrderStatus.Create().
 		SetComment(*status.Comment).
 		SetCurrentDate(time.Now()).
+		SetConditionNote(status.ConditionNote).
 		SetOrder(receivedOrder).
 		SetOrderStatusName(statusName).
-		SetUsers(receivedUser).Save(ctx)
+		SetUsers(receivedUser)
+	if len(status.PhotoIds) > 0 {
+		photoIDs := uniquePhotoIDs(status.PhotoIds)
+		found, errCount := tx.Photo.Query().Where(photo.IDIn(photoIDs...)).Count(ctx)
+		if errCount != nil {
+			return fmt.Errorf("status history error, failed to get photos: %s", errCount)
+		}
+		if found != len(photoIDs) {
+			return domain.ErrOrderStatusPhotoNotFound
+		}
+		create.AddPhotoIDs(photoIDs...)
+	}
+	_, err = create.Save(ctx)
 
 	if err != nil {
 		return fmt.Errorf("status history error, failed to create order status: %s", err)
@@ -167,3 +186,16 @@ func (r *orderStatusRepository) GetUserStatusHistory(ctx context.Context, userId
 	}
 	return pointersStatuses, nil
 }
+
+func uniquePhotoIDs(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		result = append(result, id)
+	}
+	return result
+}
@@ -189,6 +189,67 @@ func (s *orderStatusTestSuite) TestOrderStatusRepository_UpdateStatus_OK() {
 	}
 }
 
+func (s *orderStatusTestSuite) TestOrderStatusRepository_UpdateStatus_PhotoNotExists() {
+	t := s.T()
+	userID := s.adminUser.ID
+	comment := "test comment"
+	createdAt := strfmt.DateTime(time.Now().UTC())
+	orderID := int64(s.order.ID)
+	status := domain.OrderStatusInProgress
+	data := models.NewOrderStatus{
+		Comment:   &comment,
+		CreatedAt: &createdAt,
+		OrderID:   &orderID,
+		Status:    &status,
+		PhotoIds:  []string{"missing"},
+	}
+	ctx := s.ctx
+	tx, err := s.client.Tx(ctx)
+	require.NoError(t, err)
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+	err = s.repository.UpdateStatus(ctx, userID, data)
+	require.ErrorIs(t, err, domain.ErrOrderStatusPhotoNotFound)
+	require.NoError(t, tx.Rollback())
+}
+
+func (s *orderStatusTestSuite) TestOrderStatusRepository_UpdateStatus_WithReport() {
+	t := s.T()
+	s.client.Photo.Create().SetID("front").SaveX(s.ctx)
+	s.client.Photo.Create().SetID("back").SaveX(s.ctx)
+	userID := s.adminUser.ID
+	comment := "test comment"
+	createdAt := strfmt.DateTime(time.Now().UTC())
+	orderID := int64(s.order.ID)
+	status := domain.OrderStatusClosed
+	data := models.NewOrderStatus{
+		Comment:       &comment,
+		ConditionNote: "scratch on the lid",
+		CreatedAt:     &createdAt,
+		OrderID:       &orderID,
+		Status:        &status,
+		PhotoIds:      []string{"front", "back", "front"},
+	}
+	ctx := s.ctx
+	tx, err := s.client.Tx(ctx)
+	require.NoError(t, err)
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+	err = s.repository.UpdateStatus(ctx, userID, data)
+	require.NoError(t, err)
+	statuses, err := s.repository.StatusHistory(ctx, s.order.ID)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	require.Equal(t, 1, len(statuses))
+	require.Equal(t, "scratch on the lid", statuses[0].ConditionNote)
+	require.Equal(t, 2, len(statuses[0].Edges.Photos))
+	require.Equal(t, "back", statuses[0].Edges.Photos[0].ID)
+	require.Equal(t, "front", statuses[0].Edges.Photos[1].ID)
+	_, err = s.client.OrderStatus.Delete().Exec(s.ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.client.Photo.Delete().ExecX(s.ctx)
+}
+
 func (s *orderStatusTestSuite) TestOrderStatusRepository_StatusHistory_Empty() {
 	t := s.T()
 	orderID := s.order.ID
@@ -224,6 +285,7 @@ func (s *orderStatusTestSuite) TestOrderStatusRepository_StatusHistory() {
 	require.Equal(t, orderStatus.ID, statuses[0].ID)
 	require.Equal(t, orderStatus.Comment, statuses[0].Comment)
 	require.Equal(t, orderStatus.CurrentDate, statuses[0].CurrentDate)
+	require.Equal(t, orderID, statuses[0].Edges.Order.ID)
 	_, err = s.client.OrderStatus.Delete().Exec(s.ctx)
 	if err != nil {
 		t.Fatal(err)
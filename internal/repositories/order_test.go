@@ -844,6 +844,80 @@ func (s *OrderSuite) TestOrderRepository_List_EquipmentFilter() {
 	}
 }
 
+func (s *OrderSuite) TestOrderRepository_Get() {
+	t := s.T()
+	ctx := s.ctx
+	crtx, err := s.client.Tx(ctx)
+	require.NoError(t, err)
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, crtx)
+
+	eqID := int64(1)
+	startDate := strfmt.DateTime(time.Now().UTC())
+	endDate := strfmt.DateTime(time.Now().UTC().Add(time.Hour * 24 * 5))
+	data := &models.OrderCreateRequest{
+		Description: "test",
+		EquipmentID: &eqID,
+		RentEnd:     &endDate,
+		RentStart:   &startDate,
+	}
+	createdOrder, err := s.orderRepository.Create(ctx, data, s.user.ID, []int{s.equipments[0].ID})
+	require.NoError(t, err)
+	require.NoError(t, crtx.Commit())
+
+	tx, err := s.client.Tx(s.ctx)
+	require.NoError(t, err)
+	ctx = context.WithValue(s.ctx, middlewares.TxContextKey, tx)
+	found, err := s.orderRepository.Get(ctx, createdOrder.ID)
+	require.NoError(t, err)
+	require.Equal(t, createdOrder.ID, found.ID)
+	require.Equal(t, s.user.ID, found.Edges.Users.ID)
+
+	_, err = s.orderRepository.Get(ctx, createdOrder.ID+100)
+	require.True(t, ent.IsNotFound(err))
+	require.NoError(t, tx.Rollback())
+}
+
+func (s *OrderSuite) TestOrderRepository_Create_OnBehalfOfOrganization() {
+	t := s.T()
+	ctx := s.ctx
+	tx, err := s.client.Tx(ctx)
+	require.NoError(t, err)
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+
+	organization, err := tx.Organization.Create().SetName("shelter").Save(ctx)
+	require.NoError(t, err)
+	eqID := int64(1)
+	startDate := strfmt.DateTime(time.Now().UTC())
+	endDate := strfmt.DateTime(time.Now().UTC().Add(time.Hour * 24 * 5))
+	data := &models.OrderCreateRequest{
+		Description:    "test",
+		EquipmentID:    &eqID,
+		RentEnd:        &endDate,
+		RentStart:      &startDate,
+		OrganizationID: int64(organization.ID),
+	}
+	createdOrder, err := s.orderRepository.Create(ctx, data, s.user.ID, []int{s.equipments[0].ID})
+	require.NoError(t, err)
+	require.Equal(t, organization.ID, createdOrder.Edges.Organization.ID)
+
+	total, err := s.orderRepository.OrganizationOrdersTotal(ctx, organization.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	organizationID := organization.ID
+	orders, err := s.orderRepository.List(ctx, nil, domain.OrderFilter{
+		Filter: domain.Filter{
+			Limit:       10,
+			OrderBy:     utils.AscOrder,
+			OrderColumn: order.FieldID,
+		},
+		OrganizationID: &organizationID,
+	})
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	require.Equal(t, createdOrder.ID, orders[0].ID)
+	require.NoError(t, tx.Rollback())
+}
+
 func (s *OrderSuite) TestOrderRepository_Update_OK() {
 	t := s.T()
 	ctx := s.ctx
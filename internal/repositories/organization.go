@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/organization"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/organizationmember"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/user"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type organizationRepository struct {
+}
+
+func NewOrganizationRepository() domain.OrganizationRepository {
+	return &organizationRepository{}
+}
+
+func (r *organizationRepository) UserOrganizations(ctx context.Context, userID int) ([]*ent.Organization, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return organizationQuery(tx).
+		Where(organization.HasMembersWith(organizationmember.HasUserWith(user.ID(userID)))).
+		Order(ent.Asc(organization.FieldName)).
+		All(ctx)
+}
+
+func (r *organizationRepository) OrganizationByID(ctx context.Context, id int) (*ent.Organization, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return organizationQuery(tx).Where(organization.ID(id)).Only(ctx)
+}
+
+func (r *organizationRepository) CreateOrganization(ctx context.Context, name string, website *string,
+	ownerID int) (*ent.Organization, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	created, err := tx.Organization.Create().SetName(name).SetNillableWebsite(website).Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.OrganizationMember.Create().
+		SetOrganization(created).
+		SetUserID(ownerID).
+		SetRole(organizationmember.RoleOwner).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.OrganizationByID(ctx, created.ID)
+}
+
+// MemberRole returns the role of the user in the organization or the empty string if the user isn't its member.
+func (r *organizationRepository) MemberRole(ctx context.Context, id, userID int) (string, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	member, err := memberQuery(tx, id, userID).Only(ctx)
+	if ent.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return member.Role.String(), nil
+}
+
+// SetMember adds the user to the organization or changes the role of the member.
+// The organization always keeps at least one owner.
+func (r *organizationRepository) SetMember(ctx context.Context, id, userID int,
+	role string) (*ent.Organization, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	memberRole := organizationmember.Role(role)
+	if err = organizationmember.RoleValidator(memberRole); err != nil {
+		return nil, err
+	}
+	if _, err = tx.Organization.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	exists, err := tx.User.Query().Where(user.ID(userID), user.IsDeleted(false)).Exist(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrUnknownUser
+	}
+
+	member, err := memberQuery(tx, id, userID).Only(ctx)
+	switch {
+	case ent.IsNotFound(err):
+		err = tx.OrganizationMember.Create().
+			SetOrganizationID(id).
+			SetUserID(userID).
+			SetRole(memberRole).
+			Exec(ctx)
+	case err != nil:
+		return nil, err
+	default:
+		if err = checkOwnerLeft(ctx, tx, id, member, memberRole); err != nil {
+			return nil, err
+		}
+		err = tx.OrganizationMember.UpdateOne(member).SetRole(memberRole).Exec(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.OrganizationByID(ctx, id)
+}
+
+func (r *organizationRepository) RemoveMember(ctx context.Context, id, userID int) (*ent.Organization, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tx.Organization.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	member, err := memberQuery(tx, id, userID).Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, domain.ErrNotOrganizationMember
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = checkOwnerLeft(ctx, tx, id, member, ""); err != nil {
+		return nil, err
+	}
+	if err = tx.OrganizationMember.DeleteOne(member).Exec(ctx); err != nil {
+		return nil, err
+	}
+	return r.OrganizationByID(ctx, id)
+}
+
+// checkOwnerLeft returns ErrLastOrganizationOwner if the member is the only owner
+// and the new role of the member isn't the owner.
+func checkOwnerLeft(ctx context.Context, tx *ent.Tx, id int, member *ent.OrganizationMember,
+	newRole organizationmember.Role) error {
+	if member.Role != organizationmember.RoleOwner || newRole == organizationmember.RoleOwner {
+		return nil
+	}
+	owners, err := tx.OrganizationMember.Query().
+		Where(
+			organizationmember.HasOrganizationWith(organization.ID(id)),
+			organizationmember.RoleEQ(organizationmember.RoleOwner),
+		).
+		Count(ctx)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return domain.ErrLastOrganizationOwner
+	}
+	return nil
+}
+
+func memberQuery(tx *ent.Tx, id, userID int) *ent.OrganizationMemberQuery {
+	return tx.OrganizationMember.Query().
+		Where(
+			organizationmember.HasOrganizationWith(organization.ID(id)),
+			organizationmember.HasUserWith(user.ID(userID)),
+		)
+}
+
+func organizationQuery(tx *ent.Tx) *ent.OrganizationQuery {
+	return tx.Organization.Query().
+		WithMembers(func(q *ent.OrganizationMemberQuery) {
+			q.WithUser(func(q *ent.UserQuery) {
+				q.Select(user.FieldID, user.FieldLogin)
+			}).Order(ent.Asc(organizationmember.FieldID))
+		})
+}
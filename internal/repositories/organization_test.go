@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/enttest"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type OrganizationSuite struct {
+	suite.Suite
+	ctx        context.Context
+	client     *ent.Client
+	repository domain.OrganizationRepository
+	users      []*ent.User
+}
+
+func TestOrganizationSuite(t *testing.T) {
+	suite.Run(t, new(OrganizationSuite))
+}
+
+func (s *OrganizationSuite) SetupTest() {
+	t := s.T()
+	s.ctx = context.Background()
+	s.client = enttest.Open(t, "sqlite3", "file:organization?mode=memory&cache=shared&_fk=1")
+	s.repository = NewOrganizationRepository()
+
+	_, err := s.client.OrganizationMember.Delete().Exec(s.ctx)
+	require.NoError(t, err)
+	_, err = s.client.Organization.Delete().Exec(s.ctx)
+	require.NoError(t, err)
+	_, err = s.client.User.Delete().Exec(s.ctx)
+	require.NoError(t, err)
+
+	s.users = nil
+	for _, login := range []string{"owner", "staff", "outsider"} {
+		user, errCreate := s.client.User.Create().SetLogin(login).SetEmail(login + "@example.com").
+			SetPassword("password").Save(s.ctx)
+		require.NoError(t, errCreate)
+		s.users = append(s.users, user)
+	}
+}
+
+func (s *OrganizationSuite) TearDownSuite() {
+	s.client.Close()
+}
+
+func (s *OrganizationSuite) txContext() (context.Context, *ent.Tx) {
+	tx, err := s.client.Tx(s.ctx)
+	require.NoError(s.T(), err)
+	return context.WithValue(s.ctx, middlewares.TxContextKey, tx), tx
+}
+
+func (s *OrganizationSuite) TestOrganizationRepository_CreateOrganization() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	owner := s.users[0]
+	website := "https://shelter.example.com"
+
+	created, err := s.repository.CreateOrganization(ctx, "shelter", &website, owner.ID)
+	require.NoError(t, err)
+	require.Equal(t, "shelter", created.Name)
+	require.Equal(t, website, *created.Website)
+	require.Len(t, created.Edges.Members, 1)
+	require.Equal(t, owner.ID, created.Edges.Members[0].Edges.User.ID)
+	require.Equal(t, owner.Login, created.Edges.Members[0].Edges.User.Login)
+
+	role, err := s.repository.MemberRole(ctx, created.ID, owner.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.OrganizationRoleOwner, role)
+	role, err = s.repository.MemberRole(ctx, created.ID, s.users[2].ID)
+	require.NoError(t, err)
+	require.Empty(t, role)
+
+	organizations, err := s.repository.UserOrganizations(ctx, owner.ID)
+	require.NoError(t, err)
+	require.Len(t, organizations, 1)
+	organizations, err = s.repository.UserOrganizations(ctx, s.users[2].ID)
+	require.NoError(t, err)
+	require.Empty(t, organizations)
+	require.NoError(t, tx.Commit())
+}
+
+func (s *OrganizationSuite) TestOrganizationRepository_Members() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	owner, staff := s.users[0], s.users[1]
+
+	created, err := s.repository.CreateOrganization(ctx, "shelter", nil, owner.ID)
+	require.NoError(t, err)
+
+	updated, err := s.repository.SetMember(ctx, created.ID, staff.ID, domain.OrganizationRoleMember)
+	require.NoError(t, err)
+	require.Len(t, updated.Edges.Members, 2)
+	role, err := s.repository.MemberRole(ctx, created.ID, staff.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.OrganizationRoleMember, role)
+
+	_, err = s.repository.SetMember(ctx, created.ID, owner.ID, domain.OrganizationRoleMember)
+	require.ErrorIs(t, err, domain.ErrLastOrganizationOwner)
+	_, err = s.repository.RemoveMember(ctx, created.ID, owner.ID)
+	require.ErrorIs(t, err, domain.ErrLastOrganizationOwner)
+
+	_, err = s.repository.SetMember(ctx, created.ID, staff.ID, domain.OrganizationRoleOwner)
+	require.NoError(t, err)
+	updated, err = s.repository.RemoveMember(ctx, created.ID, owner.ID)
+	require.NoError(t, err)
+	require.Len(t, updated.Edges.Members, 1)
+	require.Equal(t, staff.ID, updated.Edges.Members[0].Edges.User.ID)
+
+	_, err = s.repository.RemoveMember(ctx, created.ID, owner.ID)
+	require.ErrorIs(t, err, domain.ErrNotOrganizationMember)
+	_, err = s.repository.SetMember(ctx, created.ID, owner.ID+100, domain.OrganizationRoleMember)
+	require.ErrorIs(t, err, domain.ErrUnknownUser)
+	_, err = s.repository.SetMember(ctx, created.ID+100, owner.ID, domain.OrganizationRoleMember)
+	require.True(t, ent.IsNotFound(err))
+	_, err = s.repository.SetMember(ctx, created.ID, owner.ID, "admin")
+	require.Error(t, err)
+	require.NoError(t, tx.Rollback())
+}
@@ -8,6 +8,7 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/passwordreset"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/user"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
 )
 
@@ -28,7 +29,7 @@ func (p *passwordResetRepository) CreateToken(ctx context.Context, token string,
 			return errDelete
 		}
 	}
-	_, err = tx.PasswordReset.Create().SetToken(token).SetTTL(ttl).SetUsersID(userID).Save(ctx)
+	_, err = tx.PasswordReset.Create().SetToken(utils.HashToken(token)).SetTTL(ttl).SetUsersID(userID).Save(ctx)
 	return err
 }
 
@@ -37,7 +38,7 @@ func (p *passwordResetRepository) GetToken(ctx context.Context, token string) (*
 	if err != nil {
 		return nil, err
 	}
-	return tx.PasswordReset.Query().Where(passwordreset.TokenEQ(token)).WithUsers().Only(ctx)
+	return tx.PasswordReset.Query().Where(passwordreset.TokenEQ(utils.HashToken(token))).WithUsers().Only(ctx)
 }
 
 func (p *passwordResetRepository) DeleteToken(ctx context.Context, token string) error {
@@ -45,8 +46,22 @@ func (p *passwordResetRepository) DeleteToken(ctx context.Context, token string)
 	if err != nil {
 		return err
 	}
-	_, err = tx.PasswordReset.Delete().Where(passwordreset.TokenEQ(token)).Exec(ctx)
-	return err
+	deleted, err := tx.PasswordReset.Delete().Where(passwordreset.TokenEQ(utils.HashToken(token))).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return &ent.NotFoundError{}
+	}
+	return nil
+}
+
+func (p *passwordResetRepository) DeleteExpiredTokens(ctx context.Context) (int, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return tx.PasswordReset.Delete().Where(passwordreset.TTLLT(time.Now())).Exec(ctx)
 }
 
 func NewPasswordResetRepository() domain.PasswordResetRepository {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/photo"
@@ -29,11 +30,16 @@ func (r *photoRepository) CreatePhoto(ctx context.Context, newPhoto *ent.Photo)
 	if err != nil {
 		return nil, err
 	}
-	p, err := tx.Photo.Create().
+	create := tx.Photo.Create().
 		SetID(newPhoto.ID).
-		SetFileName(newPhoto.FileName).
-		SetContent(newPhoto.Content).
-		Save(ctx)
+		SetFileName(newPhoto.FileName)
+	if newPhoto.MimeType != "" {
+		create.SetMimeType(newPhoto.MimeType)
+	}
+	if newPhoto.Etag != "" {
+		create.SetEtag(newPhoto.Etag)
+	}
+	p, err := create.Save(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -63,3 +69,25 @@ func (r *photoRepository) DeletePhotoByID(ctx context.Context, id string) error
 	}
 	return nil
 }
+
+func (r *photoRepository) PhotoInUse(ctx context.Context, id string) (bool, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	return tx.Photo.Query().
+		Where(photo.ID(id), photo.Or(photo.HasEquipmentPhotos(), photo.HasOrderStatuses())).
+		Exist(ctx)
+}
+
+func (r *photoRepository) OrphanedPhotoIDs(ctx context.Context, createdBefore time.Time) ([]string, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.Photo.Query().
+		Where(photo.CreatedAtLT(createdBefore), photo.Not(photo.HasEquipmentPhotos()),
+			photo.Not(photo.HasOrderStatuses())).
+		Order(ent.Asc(photo.FieldID)).
+		IDs(ctx)
+}
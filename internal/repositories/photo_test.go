@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -98,6 +99,30 @@ func (s *photoRepositorySuite) TestPhotoRepository_CreatePhoto_OK() {
 	require.NoError(t, tx.Commit())
 	require.Equal(t, id, createdPhoto.ID)
 	require.Equal(t, fileName, createdPhoto.FileName)
+	require.Equal(t, "image/jpeg", createdPhoto.MimeType)
+
+	_, err = s.client.Photo.Delete().Exec(s.ctx)
+	if err != nil {
+		t.Fatal()
+	}
+}
+
+func (s *photoRepositorySuite) TestPhotoRepository_CreatePhoto_MimeType() {
+	t := s.T()
+	newPhoto := &ent.Photo{
+		ID:       "somegenerateduuid",
+		FileName: "somegenerateduuid.png",
+		MimeType: "image/png",
+	}
+
+	ctx := s.ctx
+	tx, err := s.client.Tx(ctx)
+	require.NoError(t, err)
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+	createdPhoto, err := s.repository.CreatePhoto(ctx, newPhoto)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	require.Equal(t, "image/png", createdPhoto.MimeType)
 
 	_, err = s.client.Photo.Delete().Exec(s.ctx)
 	if err != nil {
@@ -194,3 +219,87 @@ func (s *photoRepositorySuite) TestPhotoRepository_DeletePhotoByID_OK() {
 		t.Fatal()
 	}
 }
+
+func (s *photoRepositorySuite) TestPhotoRepository_PhotoInUse() {
+	t := s.T()
+	used, err := s.client.Photo.Create().SetID("used").Save(s.ctx)
+	require.NoError(t, err)
+	_, err = s.client.Photo.Create().SetID("unused").Save(s.ctx)
+	require.NoError(t, err)
+	reported, err := s.client.Photo.Create().SetID("reported").Save(s.ctx)
+	require.NoError(t, err)
+	eq := s.client.Equipment.Create().SetName("equipment").SetTitle("equipment").SaveX(s.ctx)
+	s.client.EquipmentPhoto.Create().SetEquipment(eq).SetPhoto(used).SaveX(s.ctx)
+	s.createOrderStatusWithPhoto(reported)
+
+	ctx := s.ctx
+	tx, err := s.client.Tx(ctx)
+	require.NoError(t, err)
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+	inUse, err := s.repository.PhotoInUse(ctx, "used")
+	require.NoError(t, err)
+	require.True(t, inUse)
+	inUse, err = s.repository.PhotoInUse(ctx, "reported")
+	require.NoError(t, err)
+	require.True(t, inUse)
+	inUse, err = s.repository.PhotoInUse(ctx, "unused")
+	require.NoError(t, err)
+	require.False(t, inUse)
+	require.NoError(t, tx.Commit())
+
+	s.deleteOrderStatuses()
+	s.client.EquipmentPhoto.Delete().ExecX(s.ctx)
+	s.client.Equipment.Delete().ExecX(s.ctx)
+	_, err = s.client.Photo.Delete().Exec(s.ctx)
+	if err != nil {
+		t.Fatal()
+	}
+}
+
+func (s *photoRepositorySuite) TestPhotoRepository_OrphanedPhotoIDs() {
+	t := s.T()
+	old := time.Now().Add(-48 * time.Hour)
+	used, err := s.client.Photo.Create().SetID("used").SetCreatedAt(old).Save(s.ctx)
+	require.NoError(t, err)
+	_, err = s.client.Photo.Create().SetID("orphaned").SetCreatedAt(old).Save(s.ctx)
+	require.NoError(t, err)
+	_, err = s.client.Photo.Create().SetID("uploaded").Save(s.ctx)
+	require.NoError(t, err)
+	reported, err := s.client.Photo.Create().SetID("reported").SetCreatedAt(old).Save(s.ctx)
+	require.NoError(t, err)
+	eq := s.client.Equipment.Create().SetName("equipment").SetTitle("equipment").SaveX(s.ctx)
+	s.client.EquipmentPhoto.Create().SetEquipment(eq).SetPhoto(used).SaveX(s.ctx)
+	s.createOrderStatusWithPhoto(reported)
+
+	ctx := s.ctx
+	tx, err := s.client.Tx(ctx)
+	require.NoError(t, err)
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+	ids, err := s.repository.OrphanedPhotoIDs(ctx, time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	require.Equal(t, []string{"orphaned"}, ids)
+
+	s.deleteOrderStatuses()
+	s.client.EquipmentPhoto.Delete().ExecX(s.ctx)
+	s.client.Equipment.Delete().ExecX(s.ctx)
+	_, err = s.client.Photo.Delete().Exec(s.ctx)
+	if err != nil {
+		t.Fatal()
+	}
+}
+
+func (s *photoRepositorySuite) createOrderStatusWithPhoto(photo *ent.Photo) {
+	user := s.client.User.Create().SetLogin("operator").SetName("operator").
+		SetPassword("operator").SetEmail("operator@example.com").SaveX(s.ctx)
+	order := s.client.Order.Create().SetDescription("order").SetQuantity(1).
+		SetRentStart(time.Now()).SetRentEnd(time.Now()).SaveX(s.ctx)
+	s.client.OrderStatus.Create().SetComment("handed over").SetCurrentDate(time.Now()).
+		SetOrder(order).SetUsers(user).AddPhotos(photo).SaveX(s.ctx)
+}
+
+func (s *photoRepositorySuite) deleteOrderStatuses() {
+	s.client.OrderStatus.Delete().ExecX(s.ctx)
+	s.client.Order.Delete().ExecX(s.ctx)
+	s.client.User.Delete().ExecX(s.ctx)
+}
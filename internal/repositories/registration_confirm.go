@@ -8,6 +8,7 @@ import (
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/registrationconfirm"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/user"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
 )
 
@@ -28,7 +29,7 @@ func (rc *registrationConfirmRepository) CreateToken(ctx context.Context, token
 			return errDelete
 		}
 	}
-	_, err = tx.RegistrationConfirm.Create().SetToken(token).SetTTL(ttl).SetUsersID(userID).Save(ctx)
+	_, err = tx.RegistrationConfirm.Create().SetToken(utils.HashToken(token)).SetTTL(ttl).SetUsersID(userID).Save(ctx)
 	return err
 }
 
@@ -37,7 +38,7 @@ func (rc *registrationConfirmRepository) GetToken(ctx context.Context, token str
 	if err != nil {
 		return nil, err
 	}
-	return tx.RegistrationConfirm.Query().Where(registrationconfirm.TokenEQ(token)).WithUsers().Only(ctx)
+	return tx.RegistrationConfirm.Query().Where(registrationconfirm.TokenEQ(utils.HashToken(token))).WithUsers().Only(ctx)
 }
 
 func (rc *registrationConfirmRepository) DeleteToken(ctx context.Context, token string) error {
@@ -45,10 +46,18 @@ func (rc *registrationConfirmRepository) DeleteToken(ctx context.Context, token
 	if err != nil {
 		return err
 	}
-	_, err = tx.RegistrationConfirm.Delete().Where(registrationconfirm.TokenEQ(token)).Exec(ctx)
+	_, err = tx.RegistrationConfirm.Delete().Where(registrationconfirm.TokenEQ(utils.HashToken(token))).Exec(ctx)
 	return err
 }
 
+func (rc *registrationConfirmRepository) DeleteExpiredTokens(ctx context.Context) (int, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return tx.RegistrationConfirm.Delete().Where(registrationconfirm.TTLLT(time.Now())).Exec(ctx)
+}
+
 func NewRegistrationConfirmRepository() domain.RegistrationConfirmRepository {
 	return &registrationConfirmRepository{}
 }
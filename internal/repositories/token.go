@@ -2,8 +2,10 @@ package repositories
 
 import (
 	"context"
+
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/token"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/user"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
 )
@@ -55,3 +57,28 @@ func (t *tokenRepository) CreateTokens(ctx context.Context, ownerID int, accessT
 		Save(ctx)
 	return err
 }
+
+func (t *tokenRepository) DeleteTokensByUserID(ctx context.Context, userID int) error {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Token.Delete().Where(token.HasOwnerWith(user.IDEQ(userID))).Exec(ctx)
+	return err
+}
+
+func (t *tokenRepository) AccessTokenExists(ctx context.Context, accessToken string) (bool, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	return tx.Token.Query().Where(token.AccessTokenEQ(accessToken)).Exist(ctx)
+}
+
+func (t *tokenRepository) UserTokens(ctx context.Context, userID int) ([]*ent.Token, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.Token.Query().Where(token.HasOwnerWith(user.IDEQ(userID))).Order(ent.Asc(token.FieldID)).All(ctx)
+}
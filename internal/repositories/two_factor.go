@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/recoverycode"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/twofactorchallenge"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/user"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type twoFactorRepository struct {
+}
+
+func NewTwoFactorRepository() domain.TwoFactorRepository {
+	return &twoFactorRepository{}
+}
+
+func (r *twoFactorRepository) SetTOTPSecret(ctx context.Context, userID int, secret string) error {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = tx.User.UpdateOneID(userID).SetTotpSecret(secret).SetIsTotpEnabled(false).
+		ClearTotpLastStep().Save(ctx)
+	return err
+}
+
+func (r *twoFactorRepository) EnableTOTP(ctx context.Context, userID int, recoveryCodeHashes []string) error {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.User.UpdateOneID(userID).SetIsTotpEnabled(true).Save(ctx); err != nil {
+		return err
+	}
+	return replaceRecoveryCodes(ctx, tx, userID, recoveryCodeHashes)
+}
+
+func (r *twoFactorRepository) DisableTOTP(ctx context.Context, userID int) error {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.User.UpdateOneID(userID).ClearTotpSecret().SetIsTotpEnabled(false).
+		ClearTotpLastStep().Save(ctx); err != nil {
+		return err
+	}
+	_, err = tx.RecoveryCode.Delete().Where(recoverycode.HasUsersWith(user.ID(userID))).Exec(ctx)
+	return err
+}
+
+func (r *twoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID int, recoveryCodeHashes []string) error {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	return replaceRecoveryCodes(ctx, tx, userID, recoveryCodeHashes)
+}
+
+// UseRecoveryCode deletes the matching recovery code of the user. It returns false if there is no such code.
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID int, recoveryCodeHash string) (bool, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	deleted, err := tx.RecoveryCode.Delete().
+		Where(recoverycode.CodeHash(recoveryCodeHash), recoverycode.HasUsersWith(user.ID(userID))).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+func (r *twoFactorRepository) SetTOTPLastStep(ctx context.Context, userID int, step int64) error {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = tx.User.UpdateOneID(userID).SetTotpLastStep(step).Save(ctx)
+	return err
+}
+
+// CreateChallenge stores the challenge and deletes the expired ones.
+func (r *twoFactorRepository) CreateChallenge(ctx context.Context, userID int, jti string,
+	expiresAt time.Time) error {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = tx.TwoFactorChallenge.Delete().Where(twofactorchallenge.ExpiresAtLT(time.Now())).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = tx.TwoFactorChallenge.Create().SetJti(jti).SetExpiresAt(expiresAt).SetUsersID(userID).Save(ctx)
+	return err
+}
+
+func (r *twoFactorRepository) ChallengeByJTI(ctx context.Context, jti string) (*ent.TwoFactorChallenge, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.TwoFactorChallenge.Query().
+		Where(twofactorchallenge.Jti(jti), twofactorchallenge.ExpiresAtGT(time.Now())).
+		WithUsers().
+		Only(ctx)
+}
+
+func (r *twoFactorRepository) ConsumeChallenge(ctx context.Context, jti string) (bool, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	deleted, err := tx.TwoFactorChallenge.Delete().Where(twofactorchallenge.Jti(jti)).Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+func (r *twoFactorRepository) RecordChallengeFailure(ctx context.Context, client *ent.Client, jti string,
+	maxAttempts int) (err error) {
+	tx, err := client.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	_, err = tx.TwoFactorChallenge.Update().Where(twofactorchallenge.Jti(jti)).AddFailedAttempts(1).Save(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = tx.TwoFactorChallenge.Delete().
+		Where(twofactorchallenge.Jti(jti), twofactorchallenge.FailedAttemptsGTE(maxAttempts)).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx *ent.Tx, userID int, recoveryCodeHashes []string) error {
+	_, err := tx.RecoveryCode.Delete().Where(recoverycode.HasUsersWith(user.ID(userID))).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	builders := make([]*ent.RecoveryCodeCreate, len(recoveryCodeHashes))
+	for i, hash := range recoveryCodeHashes {
+		builders[i] = tx.RecoveryCode.Create().SetCodeHash(hash).SetUsersID(userID)
+	}
+	_, err = tx.RecoveryCode.CreateBulk(builders...).Save(ctx)
+	return err
+}
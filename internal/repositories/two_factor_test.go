@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/enttest"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type TwoFactorSuite struct {
+	suite.Suite
+	ctx        context.Context
+	client     *ent.Client
+	repository domain.TwoFactorRepository
+	user       *ent.User
+}
+
+func TestTwoFactorSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorSuite))
+}
+
+func (s *TwoFactorSuite) SetupTest() {
+	t := s.T()
+	s.ctx = context.Background()
+	s.client = enttest.Open(t, "sqlite3", "file:twofactor?mode=memory&cache=shared&_fk=1")
+	s.repository = NewTwoFactorRepository()
+
+	_, err := s.client.RecoveryCode.Delete().Exec(s.ctx)
+	require.NoError(t, err)
+	_, err = s.client.TwoFactorChallenge.Delete().Exec(s.ctx)
+	require.NoError(t, err)
+	_, err = s.client.User.Delete().Exec(s.ctx)
+	require.NoError(t, err)
+	s.user, err = s.client.User.Create().
+		SetLogin("login").SetEmail("email").SetPassword("password").
+		Save(s.ctx)
+	require.NoError(t, err)
+}
+
+func (s *TwoFactorSuite) TearDownSuite() {
+	s.client.Close()
+}
+
+func (s *TwoFactorSuite) txContext() (context.Context, *ent.Tx) {
+	tx, err := s.client.Tx(s.ctx)
+	require.NoError(s.T(), err)
+	return context.WithValue(s.ctx, middlewares.TxContextKey, tx), tx
+}
+
+func (s *TwoFactorSuite) TestTwoFactorRepository_EnableAndUseRecoveryCode() {
+	t := s.T()
+	ctx, tx := s.txContext()
+
+	require.NoError(t, s.repository.SetTOTPSecret(ctx, s.user.ID, "secret"))
+	require.NoError(t, s.repository.EnableTOTP(ctx, s.user.ID, []string{"hash1", "hash2"}))
+
+	user, err := tx.User.Get(ctx, s.user.ID)
+	require.NoError(t, err)
+	require.True(t, user.IsTotpEnabled)
+	require.Equal(t, "secret", *user.TotpSecret)
+
+	used, err := s.repository.UseRecoveryCode(ctx, s.user.ID, "hash1")
+	require.NoError(t, err)
+	require.True(t, used)
+
+	used, err = s.repository.UseRecoveryCode(ctx, s.user.ID, "hash1")
+	require.NoError(t, err)
+	require.False(t, used)
+
+	require.NoError(t, tx.Commit())
+}
+
+func (s *TwoFactorSuite) TestTwoFactorRepository_ReplaceRecoveryCodes() {
+	t := s.T()
+	ctx, tx := s.txContext()
+
+	require.NoError(t, s.repository.EnableTOTP(ctx, s.user.ID, []string{"old"}))
+	require.NoError(t, s.repository.ReplaceRecoveryCodes(ctx, s.user.ID, []string{"new1", "new2"}))
+
+	used, err := s.repository.UseRecoveryCode(ctx, s.user.ID, "old")
+	require.NoError(t, err)
+	require.False(t, used)
+
+	count, err := tx.RecoveryCode.Query().Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	require.NoError(t, tx.Commit())
+}
+
+func (s *TwoFactorSuite) TestTwoFactorRepository_DisableTOTP() {
+	t := s.T()
+	ctx, tx := s.txContext()
+
+	require.NoError(t, s.repository.SetTOTPSecret(ctx, s.user.ID, "secret"))
+	require.NoError(t, s.repository.EnableTOTP(ctx, s.user.ID, []string{"hash"}))
+	require.NoError(t, s.repository.SetTOTPLastStep(ctx, s.user.ID, 42))
+	require.NoError(t, s.repository.DisableTOTP(ctx, s.user.ID))
+
+	user, err := tx.User.Get(ctx, s.user.ID)
+	require.NoError(t, err)
+	require.False(t, user.IsTotpEnabled)
+	require.Nil(t, user.TotpSecret)
+	require.Nil(t, user.TotpLastStep)
+
+	count, err := tx.RecoveryCode.Query().Count(ctx)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	require.NoError(t, tx.Commit())
+}
+
+func (s *TwoFactorSuite) TestTwoFactorRepository_Challenge() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	require.NoError(t, s.repository.CreateChallenge(ctx, s.user.ID, "expired", time.Now().Add(-time.Minute)))
+	require.NoError(t, s.repository.CreateChallenge(ctx, s.user.ID, "jti", time.Now().Add(time.Minute)))
+
+	_, err := s.repository.ChallengeByJTI(ctx, "expired")
+	require.True(t, ent.IsNotFound(err))
+	challenge, err := s.repository.ChallengeByJTI(ctx, "jti")
+	require.NoError(t, err)
+	require.Equal(t, s.user.ID, challenge.Edges.Users.ID)
+
+	consumed, err := s.repository.ConsumeChallenge(ctx, "jti")
+	require.NoError(t, err)
+	require.True(t, consumed)
+	consumed, err = s.repository.ConsumeChallenge(ctx, "jti")
+	require.NoError(t, err)
+	require.False(t, consumed)
+	require.NoError(t, tx.Commit())
+
+	// the next challenge deletes the expired ones
+	ctx, tx = s.txContext()
+	require.NoError(t, s.repository.CreateChallenge(ctx, s.user.ID, "next", time.Now().Add(time.Minute)))
+	require.Equal(t, 1, tx.TwoFactorChallenge.Query().CountX(ctx))
+	require.NoError(t, tx.Commit())
+}
+
+func (s *TwoFactorSuite) TestTwoFactorRepository_RecordChallengeFailure() {
+	t := s.T()
+	ctx, tx := s.txContext()
+	require.NoError(t, s.repository.CreateChallenge(ctx, s.user.ID, "jti", time.Now().Add(time.Minute)))
+	require.NoError(t, tx.Commit())
+
+	require.NoError(t, s.repository.RecordChallengeFailure(s.ctx, s.client, "jti", 2))
+	challenge := s.client.TwoFactorChallenge.Query().OnlyX(s.ctx)
+	require.Equal(t, 1, challenge.FailedAttempts)
+
+	require.NoError(t, s.repository.RecordChallengeFailure(s.ctx, s.client, "jti", 2))
+	require.Zero(t, s.client.TwoFactorChallenge.Query().CountX(s.ctx))
+}
@@ -6,12 +6,22 @@ import (
 	"fmt"
 	"time"
 
+	"entgo.io/ent/dialect/sql"
+	"github.com/google/uuid"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/encryption"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
 
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/activearea"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/emailconfirm"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/organizationmember"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/passwordreset"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/recoverycode"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/registrationconfirm"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/role"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/token"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/user"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/swagger/models"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
@@ -32,20 +42,19 @@ var fieldsToOrderUsers = []string{
 type userRepository struct {
 }
 
-func (r *userRepository) UsersListTotal(ctx context.Context) (int, error) {
+func (r *userRepository) UsersListTotal(ctx context.Context, filter domain.UserFilter) (int, error) {
 	tx, err := middlewares.TxFromContext(ctx)
 	if err != nil {
 		return 0, err
 	}
-	return tx.User.Query().Where(user.IsDeleted(false)).Count(ctx)
+	return applyUserFilters(tx.User.Query(), filter).Count(ctx)
 }
 
-func (r *userRepository) UserList(ctx context.Context, limit, offset int,
-	orderBy, orderColumn string) ([]*ent.User, error) {
-	if !utils.IsValueInList(orderColumn, fieldsToOrderUsers) {
+func (r *userRepository) UserList(ctx context.Context, filter domain.UserFilter) ([]*ent.User, error) {
+	if !utils.IsValueInList(filter.OrderColumn, fieldsToOrderUsers) {
 		return nil, errors.New("wrong column to order by")
 	}
-	orderFunc, err := utils.GetOrderFunc(orderBy, orderColumn)
+	orderFunc, err := utils.GetOrderFunc(filter.OrderBy, filter.OrderColumn)
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +62,41 @@ func (r *userRepository) UserList(ctx context.Context, limit, offset int,
 	if err != nil {
 		return nil, err
 	}
-	return tx.User.Query().Where(user.IsDeleted(false)).WithRole().Order(orderFunc).Limit(limit).Offset(offset).All(ctx)
+	return applyUserFilters(tx.User.Query(), filter).
+		WithRole().Order(orderFunc).Limit(filter.Limit).Offset(filter.Offset).All(ctx)
+}
+
+func applyUserFilters(q *ent.UserQuery, filter domain.UserFilter) *ent.UserQuery {
+	q = q.Where(user.IsDeleted(filter.IsDeleted != nil && *filter.IsDeleted))
+	if filter.Login != nil {
+		q = q.Where(user.LoginContainsFold(*filter.Login))
+	}
+	if filter.Email != nil {
+		q = q.Where(user.EmailContainsFold(*filter.Email))
+	}
+	if filter.Search != nil {
+		q = q.Where(user.Or(
+			user.NameContainsFold(*filter.Search),
+			user.SurnameContainsFold(*filter.Search),
+			user.PhoneContainsFold(*filter.Search),
+		))
+	}
+	if filter.Role != nil {
+		q = q.Where(user.HasRoleWith(role.Slug(*filter.Role)))
+	}
+	if filter.ActiveAreaID != nil {
+		q = q.Where(user.HasActiveAreasWith(activearea.ID(*filter.ActiveAreaID)))
+	}
+	if filter.Type != nil {
+		q = q.Where(user.TypeEQ(user.Type(*filter.Type)))
+	}
+	if filter.IsReadonly != nil {
+		q = q.Where(user.IsReadonly(*filter.IsReadonly))
+	}
+	if filter.IsRegistrationConfirmed != nil {
+		q = q.Where(user.IsRegistrationConfirmed(*filter.IsRegistrationConfirmed))
+	}
+	return q
 }
 
 func (r *userRepository) UpdateUserByID(ctx context.Context, id int, patch *models.PatchUserRequest) error {
@@ -171,7 +214,12 @@ func (r *userRepository) GetUserByID(ctx context.Context, id int) (*ent.User, er
 	if err != nil {
 		return nil, err
 	}
-	return tx.User.Query().Where(user.ID(id)).WithGroups().WithRole().Only(ctx)
+	return tx.User.Query().Where(user.ID(id)).
+		WithGroups(func(q *ent.GroupQuery) {
+			q.WithPermissions()
+		}).
+		WithRole().
+		Only(ctx)
 }
 
 func (r *userRepository) SetUserRole(ctx context.Context, userId int, roleId int) error {
@@ -249,6 +297,61 @@ func (r *userRepository) CreateUser(ctx context.Context, data *models.UserRegist
 	return
 }
 
+// CreateOIDCUser creates the user with the default role for the identity provider account.
+// The password is random, the user can set their own one through the password reset.
+func (r *userRepository) CreateOIDCUser(ctx context.Context, data *domain.OIDCUser) (*ent.User, error) {
+	hashedPassword, err := utils.PasswordHash(uuid.New().String())
+	if err != nil {
+		return nil, fmt.Errorf("create user error, failed to generate password hash: %s", err)
+	}
+
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defaultRole, err := DefaultUserRole(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find default role, %w", err)
+	}
+	userCreate := tx.User.
+		Create().
+		SetLogin(data.Login).
+		SetEmail(data.Email).
+		SetPassword(hashedPassword).
+		SetOidcSubject(data.Subject).
+		SetIsRegistrationConfirmed(data.IsRegistrationConfirmed).
+		SetRole(defaultRole)
+	if data.Name != "" {
+		userCreate.SetName(data.Name)
+	}
+	return userCreate.Save(ctx)
+}
+
+func (r *userRepository) UserByOIDCSubject(ctx context.Context, subject string) (*ent.User, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.User.Query().Where(user.OidcSubjectEQ(subject)).Only(ctx)
+}
+
+// UserByEmail returns the not deleted user with the email, the emails are compared case-insensitively.
+func (r *userRepository) UserByEmail(ctx context.Context, email string) (*ent.User, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.User.Query().Where(user.EmailEqualFold(email), user.IsDeleted(false)).Only(ctx)
+}
+
+func (r *userRepository) SetOIDCSubject(ctx context.Context, id int, subject string) error {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	return tx.User.UpdateOneID(id).SetOidcSubject(subject).Exec(ctx)
+}
+
 func (r *userRepository) ConfirmRegistration(ctx context.Context, login string) error {
 	tx, err := middlewares.TxFromContext(ctx)
 	if err != nil {
@@ -279,12 +382,155 @@ func (r *userRepository) Delete(ctx context.Context, userId int) error {
 		return err
 	}
 
-	_, err = tx.User.UpdateOneID(userId).SetIsDeleted(true).Save(ctx)
+	deleted, err := tx.User.Get(ctx, userId)
 	if err != nil {
 		return err
 	}
+	// access tokens are checked against the storage, so deleting them signs the user out everywhere
+	if _, err = tx.Token.Delete().Where(token.HasOwnerWith(user.ID(userId))).Exec(ctx); err != nil {
+		return err
+	}
+	// deleting the deleted user again does not extend the retention period
+	if deleted.IsDeleted {
+		return nil
+	}
+	return tx.User.UpdateOneID(userId).
+		SetIsDeleted(true).
+		SetDeletedAt(time.Now()).
+		Exec(ctx)
+}
 
-	return nil
+func (r *userRepository) Restore(ctx context.Context, id int, deletedAfter time.Time) error {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	deletedUser, err := tx.User.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deletedUser.IsDeleted {
+		return domain.ErrUserNotDeleted
+	}
+	if deletedUser.PurgedAt != nil || deletedUser.DeletedAt == nil || deletedUser.DeletedAt.Before(deletedAfter) {
+		return domain.ErrRestorePeriodExpired
+	}
+	return tx.User.UpdateOne(deletedUser).SetIsDeleted(false).ClearDeletedAt().Exec(ctx)
+}
+
+func (r *userRepository) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int, error) {
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	ids, err := tx.User.Query().
+		Where(user.IsDeleted(true), user.PurgedAtIsNil(), user.DeletedAtLT(deletedBefore)).
+		IDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		if err = purgeUser(ctx, tx, id); err != nil {
+			return 0, err
+		}
+	}
+	return len(ids), nil
+}
+
+// purgedPassword isn't a bcrypt hash, so no password matches it.
+const purgedPassword = "-"
+
+// purgeUser replaces the personal data of the user with placeholders and removes everything
+// linked to the account except the orders and their statuses.
+func purgeUser(ctx context.Context, tx *ent.Tx, id int) error {
+	if _, err := tx.Token.Delete().Where(token.HasOwnerWith(user.ID(id))).Exec(ctx); err != nil {
+		return err
+	}
+	if _, err := tx.PasswordReset.Delete().Where(passwordreset.HasUsersWith(user.ID(id))).Exec(ctx); err != nil {
+		return err
+	}
+	_, err := tx.RegistrationConfirm.Delete().Where(registrationconfirm.HasUsersWith(user.ID(id))).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.EmailConfirm.Delete().Where(emailconfirm.HasUsersWith(user.ID(id))).Exec(ctx); err != nil {
+		return err
+	}
+	if _, err = tx.RecoveryCode.Delete().Where(recoverycode.HasUsersWith(user.ID(id))).Exec(ctx); err != nil {
+		return err
+	}
+	_, err = tx.OrganizationMember.Delete().Where(organizationmember.HasUserWith(user.ID(id))).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	placeholder := fmt.Sprintf("deleted_user_%d", id)
+	return tx.User.UpdateOneID(id).
+		SetLogin(placeholder).
+		SetEmail(placeholder + "@deleted.invalid").
+		SetPassword(purgedPassword).
+		ClearName().
+		ClearSurname().
+		ClearPatronymic().
+		ClearPassportSeries().
+		ClearPassportNumber().
+		ClearPassportAuthority().
+		ClearPassportIssueDate().
+		ClearPhone().
+		ClearOrgName().
+		ClearWebsite().
+		ClearVk().
+		ClearTotpSecret().
+		SetIsTotpEnabled(false).
+		ClearOidcSubject().
+		ClearGroups().
+		ClearActiveAreas().
+		SetPurgedAt(time.Now()).
+		Exec(ctx)
+}
+
+func (r *userRepository) EncryptPassportData(ctx context.Context, limit int) (int, error) {
+	keyring := encryption.Default()
+	if keyring == nil {
+		return 0, encryption.ErrNoKeyring
+	}
+	tx, err := middlewares.TxFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	notCurrent := func(column string) func(*sql.Selector) {
+		return func(s *sql.Selector) {
+			s.Where(sql.And(
+				sql.NotNull(s.C(column)),
+				sql.NEQ(s.C(column), ""),
+				sql.Not(sql.HasPrefix(s.C(column), keyring.CurrentPrefix())),
+			))
+		}
+	}
+	users, err := tx.User.Query().Where(user.Or(
+		notCurrent(user.FieldPassportSeries),
+		notCurrent(user.FieldPassportNumber),
+		notCurrent(user.FieldPassportAuthority),
+		notCurrent(user.FieldPassportIssueDate),
+	)).Order(ent.Asc(user.FieldID)).Limit(limit).All(ctx)
+	if err != nil {
+		return 0, err
+	}
+	// the values are decrypted on read and encrypted with the current key on write. All the columns are
+	// rewritten, the empty ones too: an encrypted empty value still matches the query until it is
+	// re-encrypted, and the user would come back in every batch.
+	for _, u := range users {
+		err = tx.User.UpdateOneID(u.ID).
+			SetPassportSeries(u.PassportSeries).
+			SetPassportNumber(u.PassportNumber).
+			SetPassportAuthority(u.PassportAuthority).
+			SetPassportIssueDate(u.PassportIssueDate).
+			Exec(ctx)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(users), nil
 }
 
 func (r *userRepository) SetIsReadonly(ctx context.Context, id int, isReadonly bool) error {
@@ -1,29 +1,47 @@
 package repositories
 
 import (
+	"bytes"
 	"context"
+	stdsql "database/sql"
 	"fmt"
 	"math"
 	"testing"
+	"time"
 
+	"entgo.io/ent/dialect/sql"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"golang.org/x/crypto/bcrypt"
 
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/encryption"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/enttest"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/user"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/middlewares"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
 )
 
 type UserSuite struct {
 	suite.Suite
-	ctx    context.Context
-	client *ent.Client
-	users  map[int]*ent.User
+	ctx     context.Context
+	client  *ent.Client
+	users   map[int]*ent.User
+	keyring *encryption.Keyring
+}
+
+// testKeyring returns the keyring encrypting with the current version, each version has its own key.
+func testKeyring(t *testing.T, current string, versions ...string) *encryption.Keyring {
+	keys := make(map[string][]byte)
+	for i, v := range versions {
+		keys[v] = bytes.Repeat([]byte{byte(i + 1)}, 32)
+	}
+	keyring, err := encryption.NewKeyring(current, keys)
+	require.NoError(t, err)
+	return keyring
 }
 
 func TestUserSuite(t *testing.T) {
@@ -31,6 +49,11 @@ func TestUserSuite(t *testing.T) {
 	suite.Run(t, s)
 }
 
+func (s *UserSuite) SetupSuite() {
+	s.keyring = testKeyring(s.T(), "1", "1")
+	encryption.SetDefault(s.keyring)
+}
+
 func (s *UserSuite) SetupTest() {
 	t := s.T()
 	s.ctx = context.Background()
@@ -68,6 +91,7 @@ func (s *UserSuite) SetupTest() {
 
 func (s *UserSuite) TearDownSuite() {
 	s.client.Close()
+	encryption.SetDefault(nil)
 }
 
 func (s *UserSuite) TestUserRepository_UsersListTotal() {
@@ -77,7 +101,7 @@ func (s *UserSuite) TestUserRepository_UsersListTotal() {
 	tx, err := s.client.Tx(ctx)
 	require.NoError(t, err)
 	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
-	totalUsers, err := repository.UsersListTotal(ctx)
+	totalUsers, err := repository.UsersListTotal(ctx, domain.UserFilter{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -105,7 +129,7 @@ func (s *UserSuite) TestUserRepository_UsersListTotal_NoDeletedUsersInList() {
 	require.NoError(t, err)
 
 	// Check
-	totalUsers, err := repo.UsersListTotal(ctx)
+	totalUsers, err := repo.UsersListTotal(ctx, domain.UserFilter{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -139,7 +163,7 @@ func (s *UserSuite) TestUserRepository_UsersList_NoDeletedUsersInList() {
 	offset := 0
 	orderBy := utils.AscOrder
 	orderColumn := user.FieldID
-	users, err := repo.UserList(ctx, limit, offset, orderBy, orderColumn)
+	users, err := repo.UserList(ctx, userListFilter(limit, offset, orderBy, orderColumn))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -160,7 +184,7 @@ func (s *UserSuite) TestUserRepository_UserList_EmptyOrderBy() {
 	tx, err := s.client.Tx(ctx)
 	require.NoError(t, err)
 	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
-	users, err := repository.UserList(ctx, limit, offset, orderBy, orderColumn)
+	users, err := repository.UserList(ctx, userListFilter(limit, offset, orderBy, orderColumn))
 	require.Error(t, err)
 	require.NoError(t, tx.Rollback())
 	require.Nil(t, users)
@@ -177,7 +201,7 @@ func (s *UserSuite) TestUserRepository_UserList_EmptyOrderColumn() {
 	tx, err := s.client.Tx(ctx)
 	require.NoError(t, err)
 	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
-	users, err := repository.UserList(ctx, limit, offset, orderBy, orderColumn)
+	users, err := repository.UserList(ctx, userListFilter(limit, offset, orderBy, orderColumn))
 	require.Error(t, err)
 	require.NoError(t, tx.Rollback())
 	require.Nil(t, users)
@@ -194,7 +218,7 @@ func (s *UserSuite) TestUserRepository_UserList_WrongOrderColumn() {
 	tx, err := s.client.Tx(ctx)
 	require.NoError(t, err)
 	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
-	users, err := repository.UserList(ctx, limit, offset, orderBy, orderColumn)
+	users, err := repository.UserList(ctx, userListFilter(limit, offset, orderBy, orderColumn))
 	require.Error(t, err)
 	require.NoError(t, tx.Rollback())
 	require.Nil(t, users)
@@ -211,7 +235,7 @@ func (s *UserSuite) TestUserRepository_UserList_OrderByIDDesc() {
 	tx, err := s.client.Tx(ctx)
 	require.NoError(t, err)
 	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
-	users, err := repository.UserList(ctx, limit, offset, orderBy, orderColumn)
+	users, err := repository.UserList(ctx, userListFilter(limit, offset, orderBy, orderColumn))
 	require.NoError(t, err)
 	require.NoError(t, tx.Commit())
 	require.Equal(t, len(s.users), len(users))
@@ -234,7 +258,7 @@ func (s *UserSuite) TestUserRepository_UserList_OrderByNameDesc() {
 	tx, err := s.client.Tx(ctx)
 	require.NoError(t, err)
 	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
-	users, err := repository.UserList(ctx, limit, offset, orderBy, orderColumn)
+	users, err := repository.UserList(ctx, userListFilter(limit, offset, orderBy, orderColumn))
 	require.NoError(t, err)
 	require.NoError(t, tx.Commit())
 	require.Equal(t, len(s.users), len(users))
@@ -257,7 +281,7 @@ func (s *UserSuite) TestUserRepository_UserList_OrderByIDAsc() {
 	tx, err := s.client.Tx(ctx)
 	require.NoError(t, err)
 	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
-	users, err := repository.UserList(ctx, limit, offset, orderBy, orderColumn)
+	users, err := repository.UserList(ctx, userListFilter(limit, offset, orderBy, orderColumn))
 	require.NoError(t, err)
 	require.NoError(t, tx.Commit())
 	require.Equal(t, len(s.users), len(users))
@@ -280,7 +304,7 @@ func (s *UserSuite) TestUserRepository_UserList_OrderByNameAsc() {
 	tx, err := s.client.Tx(ctx)
 	require.NoError(t, err)
 	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
-	users, err := repository.UserList(ctx, limit, offset, orderBy, orderColumn)
+	users, err := repository.UserList(ctx, userListFilter(limit, offset, orderBy, orderColumn))
 	require.NoError(t, err)
 	require.NoError(t, tx.Commit())
 	require.Equal(t, len(s.users), len(users))
@@ -303,7 +327,7 @@ func (s *UserSuite) TestUserRepository_UserList_Limit() {
 	tx, err := s.client.Tx(ctx)
 	require.NoError(t, err)
 	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
-	users, err := repository.UserList(ctx, limit, offset, orderBy, orderColumn)
+	users, err := repository.UserList(ctx, userListFilter(limit, offset, orderBy, orderColumn))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -322,7 +346,7 @@ func (s *UserSuite) TestUserRepository_UserList_Offset() {
 	tx, err := s.client.Tx(ctx)
 	require.NoError(t, err)
 	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
-	users, err := repository.UserList(ctx, limit, offset, orderBy, orderColumn)
+	users, err := repository.UserList(ctx, userListFilter(limit, offset, orderBy, orderColumn))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -429,6 +453,170 @@ func (s *UserSuite) TestUserRepository_DeleteUser_OK() {
 	assert.Equal(t, updatedUser.IsDeleted, true)
 }
 
+func (s *UserSuite) TestUserRepository_DeleteRestorePurge() {
+	t := s.T()
+	ctx := s.ctx
+	tx, err := s.client.Tx(ctx)
+	require.NoError(t, err)
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+	repository := NewUserRepository()
+
+	series, phone := "1234", "+79990001122"
+	deleted, err := tx.User.Create().SetLogin("to_delete").SetEmail("to_delete@example.com").
+		SetPassword("password").SetPassportSeries(series).SetPhone(phone).Save(ctx)
+	require.NoError(t, err)
+	_, err = tx.Token.Create().SetAccessToken("access").SetRefreshToken("refresh").SetOwner(deleted).Save(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, repository.Delete(ctx, deleted.ID))
+	tokens, err := deleted.QueryTokens().Count(ctx)
+	require.NoError(t, err)
+	require.Zero(t, tokens)
+	deletedAt := tx.User.GetX(ctx, deleted.ID).DeletedAt
+	require.NoError(t, repository.Delete(ctx, deleted.ID))
+	require.Equal(t, deletedAt, tx.User.GetX(ctx, deleted.ID).DeletedAt)
+	require.True(t, ent.IsNotFound(repository.Delete(ctx, deleted.ID+100)))
+
+	require.ErrorIs(t, repository.Restore(ctx, s.users[1].ID, time.Now().Add(-time.Hour)), domain.ErrUserNotDeleted)
+	require.ErrorIs(t, repository.Restore(ctx, deleted.ID, time.Now().Add(time.Hour)), domain.ErrRestorePeriodExpired)
+	require.NoError(t, repository.Restore(ctx, deleted.ID, time.Now().Add(-time.Hour)))
+	restored, err := tx.User.Get(ctx, deleted.ID)
+	require.NoError(t, err)
+	require.False(t, restored.IsDeleted)
+	require.Nil(t, restored.DeletedAt)
+
+	require.NoError(t, repository.Delete(ctx, deleted.ID))
+	purged, err := repository.PurgeDeletedUsers(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, purged)
+	purged, err = repository.PurgeDeletedUsers(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	require.Equal(t, 1, purged)
+
+	anonymised, err := tx.User.Get(ctx, deleted.ID)
+	require.NoError(t, err)
+	require.True(t, anonymised.IsDeleted)
+	require.NotNil(t, anonymised.PurgedAt)
+	require.NotEqual(t, "to_delete", anonymised.Login)
+	require.NotContains(t, anonymised.Email, "to_delete")
+	require.Empty(t, anonymised.PassportSeries)
+	require.Nil(t, anonymised.Phone)
+	require.ErrorIs(t, repository.Restore(ctx, deleted.ID, time.Now().Add(-time.Hour)),
+		domain.ErrRestorePeriodExpired)
+	require.NoError(t, tx.Rollback())
+}
+
+func (s *UserSuite) TestUserRepository_EncryptPassportData() {
+	t := s.T()
+	defer encryption.SetDefault(s.keyring)
+	ctx := s.ctx
+	repository := NewUserRepository()
+
+	// stored before the encryption was enabled, the value scanners refuse to write plaintext
+	issueDate := time.Date(2015, 3, 12, 0, 0, 0, 0, time.UTC)
+	legacy := s.client.User.Create().SetLogin("passport").SetEmail("passport@example.com").SetPassword("password").
+		SaveX(ctx)
+	db, err := stdsql.Open("sqlite3", "file:users?mode=memory&cache=shared&_fk=1")
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.ExecContext(ctx, "UPDATE users SET passport_number = ?, passport_issue_date = ? WHERE id = ?",
+		"567890", issueDate.Format(time.RFC3339Nano), legacy.ID)
+	require.NoError(t, err)
+
+	tx, err := s.client.Tx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+	encryptedWith := func(id int, keyring *encryption.Keyring, columns ...string) bool {
+		exists, errExist := tx.User.Query().Where(user.ID(id), func(sel *sql.Selector) {
+			for _, column := range columns {
+				sel.Where(sql.HasPrefix(sel.C(column), keyring.CurrentPrefix()))
+			}
+		}).Exist(ctx)
+		require.NoError(t, errExist)
+		return exists
+	}
+	allColumns := []string{user.FieldPassportSeries, user.FieldPassportNumber, user.FieldPassportAuthority,
+		user.FieldPassportIssueDate}
+
+	// the registration stores the empty passport data as well
+	empty, err := tx.User.Create().SetLogin("empty").SetEmail("empty@example.com").SetPassword("password").
+		SetPassportNumber("").SetPassportAuthority("").SetPassportIssueDate(time.Time{}).Save(ctx)
+	require.NoError(t, err)
+
+	encrypted, err := repository.EncryptPassportData(ctx, math.MaxInt)
+	require.NoError(t, err)
+	require.Equal(t, 1, encrypted)
+	require.True(t, encryptedWith(legacy.ID, s.keyring, allColumns...))
+	encrypted, err = repository.EncryptPassportData(ctx, math.MaxInt)
+	require.NoError(t, err)
+	require.Zero(t, encrypted)
+
+	second := testKeyring(t, "2", "1", "2")
+	encryption.SetDefault(second)
+	encrypted, err = repository.EncryptPassportData(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, encrypted)
+	encrypted, err = repository.EncryptPassportData(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, encrypted)
+	encrypted, err = repository.EncryptPassportData(ctx, 1)
+	require.NoError(t, err)
+	require.Zero(t, encrypted, "the users with the empty passport data are not selected again")
+	require.True(t, encryptedWith(legacy.ID, second, allColumns...))
+	require.True(t, encryptedWith(empty.ID, second, allColumns...))
+
+	found, err := tx.User.Get(ctx, legacy.ID)
+	require.NoError(t, err)
+	require.Equal(t, "567890", found.PassportNumber)
+	require.True(t, issueDate.Equal(found.PassportIssueDate))
+	found, err = tx.User.Get(ctx, empty.ID)
+	require.NoError(t, err)
+	require.Empty(t, found.PassportNumber)
+	require.True(t, found.PassportIssueDate.IsZero())
+}
+
+func (s *UserSuite) TestUserRepository_OIDCUser() {
+	t := s.T()
+	ctx := s.ctx
+	tx, err := s.client.Tx(ctx)
+	require.NoError(t, err)
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+	repo := NewUserRepository()
+	defaultRole, err := tx.Role.Create().SetName("user").SetSlug(defaultRoleSlug).Save(ctx)
+	require.NoError(t, err)
+
+	createdUser, err := repo.CreateOIDCUser(ctx, &domain.OIDCUser{
+		Subject:                 "subject",
+		Login:                   "oidc_login",
+		Email:                   "oidc@mail.com",
+		Name:                    "oidc",
+		IsRegistrationConfirmed: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "subject", *createdUser.OidcSubject)
+	require.Equal(t, "oidc", *createdUser.Name)
+	require.True(t, createdUser.IsRegistrationConfirmed)
+	createdUserRole, err := createdUser.QueryRole().Only(ctx)
+	require.NoError(t, err)
+	require.Equal(t, defaultRole.ID, createdUserRole.ID)
+
+	foundUser, err := repo.UserByOIDCSubject(ctx, "subject")
+	require.NoError(t, err)
+	require.Equal(t, createdUser.ID, foundUser.ID)
+
+	foundUser, err = repo.UserByEmail(ctx, "USER_1@mail.com")
+	require.NoError(t, err)
+	require.Equal(t, s.users[1].ID, foundUser.ID)
+
+	require.NoError(t, repo.SetOIDCSubject(ctx, foundUser.ID, "another subject"))
+	foundUser, err = repo.UserByOIDCSubject(ctx, "another subject")
+	require.NoError(t, err)
+	require.Equal(t, s.users[1].ID, foundUser.ID)
+
+	require.NoError(t, tx.Rollback())
+}
+
 func mapContainsUser(t *testing.T, eq *ent.User, m map[int]*ent.User) bool {
 	t.Helper()
 	for _, v := range m {
@@ -438,3 +626,69 @@ func mapContainsUser(t *testing.T, eq *ent.User, m map[int]*ent.User) bool {
 	}
 	return false
 }
+
+func (s *UserSuite) TestUserRepository_UserList_Filters() {
+	t := s.T()
+	ctx := s.ctx
+	tx, err := s.client.Tx(ctx)
+	require.NoError(t, err)
+	ctx = context.WithValue(ctx, middlewares.TxContextKey, tx)
+	repository := NewUserRepository()
+
+	filterRole, err := tx.Role.Create().SetName("filter role").SetSlug("filter_role").Save(ctx)
+	require.NoError(t, err)
+	area, err := tx.ActiveArea.Create().SetName("filter area").Save(ctx)
+	require.NoError(t, err)
+	found, err := tx.User.Create().
+		SetLogin("Searched_Login").
+		SetEmail("searched@example.com").
+		SetPassword("password").
+		SetSurname("Ivanova").
+		SetPhone("+79990001122").
+		SetType(user.TypeOrganization).
+		SetIsReadonly(true).
+		SetIsRegistrationConfirmed(true).
+		SetRole(filterRole).
+		AddActiveAreas(area).
+		Save(ctx)
+	require.NoError(t, err)
+	_, err = tx.User.Create().SetLogin("searched_deleted").SetEmail("deleted@example.com").
+		SetPassword("password").SetIsDeleted(true).Save(ctx)
+	require.NoError(t, err)
+
+	login, email, surname, phone := "searched_l", "SEARCHED@", "ivan", "0001122"
+	roleSlug, userType := filterRole.Slug, user.TypeOrganization.String()
+	areaID, isTrue := area.ID, true
+	for name, filter := range map[string]domain.UserFilter{
+		"login":     {Login: &login},
+		"email":     {Email: &email},
+		"surname":   {Search: &surname},
+		"phone":     {Search: &phone},
+		"role":      {Role: &roleSlug},
+		"area":      {ActiveAreaID: &areaID},
+		"type":      {Type: &userType},
+		"readonly":  {IsReadonly: &isTrue},
+		"confirmed": {IsRegistrationConfirmed: &isTrue},
+	} {
+		filter.Filter = domain.Filter{Limit: math.MaxInt, OrderBy: utils.AscOrder, OrderColumn: user.FieldID}
+		users, errList := repository.UserList(ctx, filter)
+		require.NoError(t, errList, name)
+		require.Len(t, users, 1, name)
+		require.Equal(t, found.ID, users[0].ID, name)
+		total, errTotal := repository.UsersListTotal(ctx, filter)
+		require.NoError(t, errTotal, name)
+		require.Equal(t, 1, total, name)
+	}
+
+	deletedLogin := "searched"
+	total, err := repository.UsersListTotal(ctx, domain.UserFilter{Login: &deletedLogin, IsDeleted: &isTrue})
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	require.NoError(t, tx.Rollback())
+}
+
+func userListFilter(limit, offset int, orderBy, orderColumn string) domain.UserFilter {
+	return domain.UserFilter{
+		Filter: domain.Filter{Limit: limit, Offset: offset, OrderBy: orderBy, OrderColumn: orderColumn},
+	}
+}
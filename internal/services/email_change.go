@@ -63,16 +63,15 @@ func (e *emailChange) SendEmailConfirmationLink(ctx context.Context, login, emai
 }
 
 func (e *emailChange) VerifyTokenAndChangeEmail(ctx context.Context, tokenToVerify string) error {
-	e.logger.Info("change email service: verify token and change email", zap.String("token", tokenToVerify))
+	e.logger.Info("change email service: verify token and change email")
 	token, err := e.GetToken(ctx, tokenToVerify)
 	if err != nil {
-		e.logger.Error("Error while getting token during changing email",
-			zap.String("token", tokenToVerify), zap.Error(err))
+		e.logger.Error("Error while getting token during changing email", zap.Error(err))
 		return err
 	}
 
 	if token.TTL.Before(time.Now()) {
-		e.logger.Error("Token is expired", zap.String("token", tokenToVerify))
+		e.logger.Error("Token is expired")
 		errDelete := e.DeleteToken(ctx, tokenToVerify)
 		if errDelete != nil {
 			return fmt.Errorf("error while deleting expired token: %w", errDelete)
@@ -96,9 +95,7 @@ func (e *emailChange) VerifyTokenAndChangeEmail(ctx context.Context, tokenToVeri
 
 	errDelete := e.DeleteToken(ctx, tokenToVerify)
 	if errDelete != nil {
-		e.logger.Warn("Error while deleting token during changing email",
-			zap.String("token", tokenToVerify), zap.Error(errDelete),
-		)
+		e.logger.Warn("Error while deleting token during changing email", zap.Error(errDelete))
 	}
 	return nil
 }
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+const (
+	oidcRandomBytes      = 32
+	oidcFallbackLoginLen = 16
+)
+
+type oidcService struct {
+	provider             domain.OIDCProvider
+	userRepository       domain.UserRepository
+	loginStateRepository domain.LoginStateRepository
+	stateTTL             time.Duration
+	logger               *zap.Logger
+}
+
+func NewOIDCService(provider domain.OIDCProvider, userRepository domain.UserRepository,
+	loginStateRepository domain.LoginStateRepository, stateTTL time.Duration, logger *zap.Logger) domain.OIDCService {
+	return &oidcService{
+		provider:             provider,
+		userRepository:       userRepository,
+		loginStateRepository: loginStateRepository,
+		stateTTL:             stateTTL,
+		logger:               logger,
+	}
+}
+
+// StartLogin saves the state, the nonce and the PKCE verifier of the new authorization request
+// and returns the URL the user has to be redirected to.
+func (s *oidcService) StartLogin(ctx context.Context) (string, error) {
+	state, err := randomURLSafeString()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomURLSafeString()
+	if err != nil {
+		return "", err
+	}
+	codeVerifier, err := randomURLSafeString()
+	if err != nil {
+		return "", err
+	}
+	err = s.loginStateRepository.CreateState(ctx, state, codeVerifier, nonce, time.Now().Add(s.stateTTL))
+	if err != nil {
+		return "", fmt.Errorf("can't save login state: %w", err)
+	}
+	return s.provider.AuthCodeURL(ctx, state, nonce, codeChallengeS256(codeVerifier))
+}
+
+// Authenticate redeems the authorization code and returns the user of the identity provider account.
+// The account is linked to the existing user with the same email only when both the provider
+// and the user have confirmed it, otherwise anybody could take over the account by registering the email.
+func (s *oidcService) Authenticate(ctx context.Context, code, state string) (*ent.User, error) {
+	loginState, err := s.loginStateRepository.ConsumeState(ctx, state)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: unknown state", domain.ErrOIDCLoginFailed)
+		}
+		return nil, err
+	}
+	if loginState.TTL.Before(time.Now()) {
+		return nil, fmt.Errorf("%w: state is expired", domain.ErrOIDCLoginFailed)
+	}
+
+	claims, err := s.provider.Exchange(ctx, code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepository.UserByOIDCSubject(ctx, claims.Subject)
+	if err == nil {
+		if user.IsDeleted {
+			return nil, fmt.Errorf("%w: user is deleted", domain.ErrOIDCLoginFailed)
+		}
+		return user, nil
+	}
+	if !ent.IsNotFound(err) {
+		return nil, err
+	}
+
+	if claims.Email == "" {
+		return nil, fmt.Errorf("%w: id_token has no email", domain.ErrOIDCLoginFailed)
+	}
+	user, err = s.userRepository.UserByEmail(ctx, claims.Email)
+	switch {
+	case err == nil:
+		return s.linkUser(ctx, user, claims)
+	case ent.IsNotSingular(err):
+		s.logger.Warn("several users have the email of the oidc account", zap.String("subject", claims.Subject))
+		return nil, domain.ErrOIDCAccountNotLinkable
+	case !ent.IsNotFound(err):
+		return nil, err
+	}
+
+	login, err := s.freeLogin(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	user, err = s.userRepository.CreateOIDCUser(ctx, &domain.OIDCUser{
+		Subject:                 claims.Subject,
+		Login:                   login,
+		Email:                   claims.Email,
+		Name:                    claims.Name,
+		IsRegistrationConfirmed: claims.EmailVerified,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't create oidc user: %w", err)
+	}
+	s.logger.Info("oidc service: user created", zap.String("login", user.Login))
+	return user, nil
+}
+
+func (s *oidcService) linkUser(ctx context.Context, user *ent.User, claims *domain.OIDCClaims) (*ent.User, error) {
+	if !claims.EmailVerified || !user.IsRegistrationConfirmed || user.OidcSubject != nil {
+		s.logger.Warn("oidc account can't be linked to the user", zap.String("login", user.Login),
+			zap.Bool("email verified", claims.EmailVerified),
+			zap.Bool("registration confirmed", user.IsRegistrationConfirmed))
+		return nil, domain.ErrOIDCAccountNotLinkable
+	}
+	if err := s.userRepository.SetOIDCSubject(ctx, user.ID, claims.Subject); err != nil {
+		return nil, fmt.Errorf("can't link oidc account: %w", err)
+	}
+	user.OidcSubject = &claims.Subject
+	s.logger.Info("oidc service: account linked", zap.String("login", user.Login))
+	return user, nil
+}
+
+// freeLogin returns the preferred username or the email if they are not used as a login yet,
+// otherwise the login derived from the subject.
+func (s *oidcService) freeLogin(ctx context.Context, claims *domain.OIDCClaims) (string, error) {
+	for _, login := range []string{claims.PreferredUsername, claims.Email} {
+		if login == "" {
+			continue
+		}
+		_, err := s.userRepository.UserByLogin(ctx, login)
+		if ent.IsNotFound(err) {
+			return login, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "oidc_" + utils.HashToken(claims.Subject)[:oidcFallbackLoginLen], nil
+}
+
+func randomURLSafeString() (string, error) {
+	b := make([]byte, oidcRandomBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func codeChallengeS256(codeVerifier string) string {
+	hash := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/mocks"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type OIDCServiceTestSuite struct {
+	suite.Suite
+	provider             *mocks.OIDCProvider
+	userRepository       *mocks.UserRepository
+	loginStateRepository *mocks.LoginStateRepository
+	service              domain.OIDCService
+	loginState           *ent.LoginState
+	claims               *domain.OIDCClaims
+}
+
+func TestOIDCServiceSuite(t *testing.T) {
+	suite.Run(t, new(OIDCServiceTestSuite))
+}
+
+func (s *OIDCServiceTestSuite) SetupTest() {
+	s.provider = &mocks.OIDCProvider{}
+	s.userRepository = &mocks.UserRepository{}
+	s.loginStateRepository = &mocks.LoginStateRepository{}
+	s.service = NewOIDCService(s.provider, s.userRepository, s.loginStateRepository, time.Minute, zap.NewNop())
+	s.loginState = &ent.LoginState{
+		TTL:          time.Now().Add(time.Minute),
+		CodeVerifier: "verifier",
+		Nonce:        "nonce",
+	}
+	s.claims = &domain.OIDCClaims{
+		Subject:           "subject",
+		Email:             "user@example.com",
+		EmailVerified:     true,
+		Name:              "User",
+		PreferredUsername: "user",
+	}
+}
+
+func (s *OIDCServiceTestSuite) expectExchange(ctx context.Context) {
+	s.loginStateRepository.On("ConsumeState", ctx, "state").Return(s.loginState, nil)
+	s.provider.On("Exchange", ctx, "code", "verifier", "nonce").Return(s.claims, nil)
+}
+
+func (s *OIDCServiceTestSuite) TestOIDC_StartLogin() {
+	t := s.T()
+	ctx := context.Background()
+	var state, nonce, verifier string
+	s.loginStateRepository.On("CreateState", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("string"),
+		mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) {
+			state, verifier, nonce = args.String(1), args.String(2), args.String(3)
+		}).Return(nil)
+	s.provider.On("AuthCodeURL", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("string"),
+		mock.AnythingOfType("string")).Return("https://idp.example.com/authorize", nil)
+
+	authURL, err := s.service.StartLogin(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "https://idp.example.com/authorize", authURL)
+	require.Len(t, verifier, 43)
+	s.provider.AssertCalled(t, "AuthCodeURL", ctx, state, nonce, codeChallengeS256(verifier))
+}
+
+func (s *OIDCServiceTestSuite) TestOIDC_Authenticate_UnknownState() {
+	t := s.T()
+	ctx := context.Background()
+	s.loginStateRepository.On("ConsumeState", ctx, "state").Return(nil, &ent.NotFoundError{})
+
+	_, err := s.service.Authenticate(ctx, "code", "state")
+	require.ErrorIs(t, err, domain.ErrOIDCLoginFailed)
+	s.provider.AssertNotCalled(t, "Exchange")
+}
+
+func (s *OIDCServiceTestSuite) TestOIDC_Authenticate_ExpiredState() {
+	t := s.T()
+	ctx := context.Background()
+	s.loginState.TTL = time.Now().Add(-time.Minute)
+	s.loginStateRepository.On("ConsumeState", ctx, "state").Return(s.loginState, nil)
+
+	_, err := s.service.Authenticate(ctx, "code", "state")
+	require.ErrorIs(t, err, domain.ErrOIDCLoginFailed)
+	s.provider.AssertNotCalled(t, "Exchange")
+}
+
+func (s *OIDCServiceTestSuite) TestOIDC_Authenticate_ExchangeErr() {
+	t := s.T()
+	ctx := context.Background()
+	s.loginStateRepository.On("ConsumeState", ctx, "state").Return(s.loginState, nil)
+	s.provider.On("Exchange", ctx, "code", "verifier", "nonce").Return(nil, errors.New("error"))
+
+	_, err := s.service.Authenticate(ctx, "code", "state")
+	require.Error(t, err)
+}
+
+func (s *OIDCServiceTestSuite) TestOIDC_Authenticate_LinkedUser() {
+	t := s.T()
+	ctx := context.Background()
+	s.expectExchange(ctx)
+	linkedUser := &ent.User{ID: 1, Login: "login", OidcSubject: &s.claims.Subject}
+	s.userRepository.On("UserByOIDCSubject", ctx, "subject").Return(linkedUser, nil)
+
+	user, err := s.service.Authenticate(ctx, "code", "state")
+	require.NoError(t, err)
+	require.Equal(t, linkedUser, user)
+}
+
+func (s *OIDCServiceTestSuite) TestOIDC_Authenticate_LinkedUserDeleted() {
+	t := s.T()
+	ctx := context.Background()
+	s.expectExchange(ctx)
+	s.userRepository.On("UserByOIDCSubject", ctx, "subject").
+		Return(&ent.User{ID: 1, Login: "login", IsDeleted: true}, nil)
+
+	_, err := s.service.Authenticate(ctx, "code", "state")
+	require.ErrorIs(t, err, domain.ErrOIDCLoginFailed)
+}
+
+func (s *OIDCServiceTestSuite) TestOIDC_Authenticate_LinkByVerifiedEmail() {
+	t := s.T()
+	ctx := context.Background()
+	s.expectExchange(ctx)
+	s.userRepository.On("UserByOIDCSubject", ctx, "subject").Return(nil, &ent.NotFoundError{})
+	s.userRepository.On("UserByEmail", ctx, "user@example.com").
+		Return(&ent.User{ID: 1, Login: "login", IsRegistrationConfirmed: true}, nil)
+	s.userRepository.On("SetOIDCSubject", ctx, 1, "subject").Return(nil)
+
+	user, err := s.service.Authenticate(ctx, "code", "state")
+	require.NoError(t, err)
+	require.Equal(t, 1, user.ID)
+	require.Equal(t, "subject", *user.OidcSubject)
+	s.userRepository.AssertExpectations(t)
+}
+
+func (s *OIDCServiceTestSuite) TestOIDC_Authenticate_NotLinkable() {
+	tests := []struct {
+		name          string
+		emailVerified bool
+		user          *ent.User
+	}{
+		{
+			name:          "email isn't verified by the provider",
+			emailVerified: false,
+			user:          &ent.User{ID: 1, IsRegistrationConfirmed: true},
+		},
+		{
+			name:          "registration isn't confirmed",
+			emailVerified: true,
+			user:          &ent.User{ID: 1},
+		},
+		{
+			name:          "user is linked to another account",
+			emailVerified: true,
+			user:          &ent.User{ID: 1, IsRegistrationConfirmed: true, OidcSubject: new(string)},
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.SetupTest()
+			t := s.T()
+			ctx := context.Background()
+			s.claims.EmailVerified = tt.emailVerified
+			s.expectExchange(ctx)
+			s.userRepository.On("UserByOIDCSubject", ctx, "subject").Return(nil, &ent.NotFoundError{})
+			s.userRepository.On("UserByEmail", ctx, "user@example.com").Return(tt.user, nil)
+
+			_, err := s.service.Authenticate(ctx, "code", "state")
+			require.ErrorIs(t, err, domain.ErrOIDCAccountNotLinkable)
+			s.userRepository.AssertNotCalled(t, "SetOIDCSubject")
+		})
+	}
+}
+
+func (s *OIDCServiceTestSuite) TestOIDC_Authenticate_CreateUser() {
+	t := s.T()
+	ctx := context.Background()
+	s.expectExchange(ctx)
+	s.userRepository.On("UserByOIDCSubject", ctx, "subject").Return(nil, &ent.NotFoundError{})
+	s.userRepository.On("UserByEmail", ctx, "user@example.com").Return(nil, &ent.NotFoundError{})
+	s.userRepository.On("UserByLogin", ctx, "user").Return(&ent.User{ID: 2}, nil)
+	s.userRepository.On("UserByLogin", ctx, "user@example.com").Return(nil, &ent.NotFoundError{})
+	createdUser := &ent.User{ID: 3, Login: "user@example.com"}
+	s.userRepository.On("CreateOIDCUser", ctx, &domain.OIDCUser{
+		Subject:                 "subject",
+		Login:                   "user@example.com",
+		Email:                   "user@example.com",
+		Name:                    "User",
+		IsRegistrationConfirmed: true,
+	}).Return(createdUser, nil)
+
+	user, err := s.service.Authenticate(ctx, "code", "state")
+	require.NoError(t, err)
+	require.Equal(t, createdUser, user)
+	s.userRepository.AssertExpectations(t)
+}
+
+func (s *OIDCServiceTestSuite) TestOIDC_Authenticate_NoEmail() {
+	t := s.T()
+	ctx := context.Background()
+	s.claims.Email = ""
+	s.expectExchange(ctx)
+	s.userRepository.On("UserByOIDCSubject", ctx, "subject").Return(nil, &ent.NotFoundError{})
+
+	_, err := s.service.Authenticate(ctx, "code", "state")
+	require.ErrorIs(t, err, domain.ErrOIDCLoginFailed)
+}
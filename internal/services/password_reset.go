@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
 )
 
@@ -16,21 +17,23 @@ type passwordReset struct {
 	domain.Sender
 	domain.UserRepository
 	domain.PasswordResetRepository
-	domain.PasswordGenerator
-	logger *zap.Logger
-	ttl    time.Duration
+	tokenRepository domain.TokenRepository
+	passwordPolicy  domain.PasswordPolicy
+	logger          *zap.Logger
+	ttl             time.Duration
 }
 
 func NewPasswordResetService(emailClient domain.Sender, userRepository domain.UserRepository,
-	passwordResetRepository domain.PasswordResetRepository, logger *zap.Logger, ttl time.Duration,
-	passwordGenerator domain.PasswordGenerator) domain.PasswordResetService {
+	passwordResetRepository domain.PasswordResetRepository, tokenRepository domain.TokenRepository,
+	logger *zap.Logger, ttl time.Duration, passwordPolicy domain.PasswordPolicy) domain.PasswordResetService {
 	return &passwordReset{
 		Sender:                  emailClient,
 		UserRepository:          userRepository,
 		PasswordResetRepository: passwordResetRepository,
+		tokenRepository:         tokenRepository,
 		logger:                  logger,
 		ttl:                     ttl,
-		PasswordGenerator:       passwordGenerator,
+		passwordPolicy:          passwordPolicy,
 	}
 }
 
@@ -42,6 +45,9 @@ func (p *passwordReset) SendResetPasswordLink(ctx context.Context, login string)
 		p.logger.Error("Error while getting user by login", zap.String("login", login), zap.Error(err))
 		return err
 	}
+	if user.IsDeleted {
+		return errors.New("user deleted, unable to send reset password link")
+	}
 	err = p.PasswordResetRepository.CreateToken(ctx, token, time.Now().Add(p.ttl), user.ID)
 	if err != nil {
 		p.logger.Error("Error while creating token", zap.String("login", login), zap.Error(err))
@@ -60,45 +66,70 @@ func (p *passwordReset) SendResetPasswordLink(ctx context.Context, login string)
 	return nil
 }
 
-func (p *passwordReset) VerifyTokenAndSendPassword(ctx context.Context, tokenToVerify string) error {
-	p.logger.Info("password reset service: verify token and send password", zap.String("token", tokenToVerify))
-	token, err := p.GetToken(ctx, tokenToVerify)
+func (p *passwordReset) VerifyToken(ctx context.Context, tokenToVerify string) error {
+	_, err := p.getValidToken(ctx, tokenToVerify)
+	return err
+}
+
+func (p *passwordReset) ResetPassword(ctx context.Context, tokenToVerify, newPassword string) error {
+	p.logger.Info("password reset service: reset password")
+	token, err := p.getValidToken(ctx, tokenToVerify)
 	if err != nil {
-		p.logger.Error("Error while getting token", zap.String("token", tokenToVerify), zap.Error(err))
 		return err
 	}
-	if token.TTL.Before(time.Now()) {
-		p.logger.Error("Token is expired", zap.String("token", tokenToVerify))
-		errDelete := p.DeleteToken(ctx, tokenToVerify)
-		if errDelete != nil {
-			return fmt.Errorf("error while deleting expired token: %w", errDelete)
-		}
-		return errors.New("token expired")
+	user := token.Edges.Users
+	if violations := p.passwordPolicy.Validate(newPassword, user.Login, user.Email); len(violations) > 0 {
+		return &domain.PasswordPolicyError{Violations: violations}
 	}
-	login := token.Edges.Users.Login
-	password, err := p.PasswordGenerator.NewPassword()
+	// the token is consumed first, so a concurrent request with the same token finds nothing to delete
+	err = p.DeleteToken(ctx, tokenToVerify)
 	if err != nil {
-		p.logger.Error("Error while generating password", zap.Error(err))
+		if ent.IsNotFound(err) {
+			return domain.ErrPasswordResetTokenInvalid
+		}
+		p.logger.Error("Error while deleting used token", zap.String("login", user.Login), zap.Error(err))
 		return err
 	}
-	err = p.ChangePasswordByLogin(ctx, login, password)
+	err = p.ChangePasswordByLogin(ctx, user.Login, newPassword)
 	if err != nil {
-		p.logger.Error("Error while changing password", zap.String("login", login), zap.Error(err))
+		p.logger.Error("Error while changing password", zap.String("login", user.Login), zap.Error(err))
 		return err
 	}
-	err = p.SendNewPassword(token.Edges.Users.Email, login, password)
+	err = p.tokenRepository.DeleteTokensByUserID(ctx, user.ID)
 	if err != nil {
-		p.logger.Error("Error while sending new password to email", zap.String("login", login), zap.Error(err))
+		p.logger.Error("Error while revoking sessions", zap.String("login", user.Login), zap.Error(err))
 		return err
 	}
-	errDelete := p.DeleteToken(ctx, tokenToVerify)
-	if errDelete != nil {
-		p.logger.Warn("Error while deleting token", zap.String("token", tokenToVerify), zap.Error(errDelete))
-	}
-	if !p.Sender.IsSendRequired() {
-		p.logger.Info("password reset service: verified token, password wasn't send, sending parameter is set to false and send email is not required")
-	} else {
-		p.logger.Info("password reset service: verified token and send password")
+	err = p.SendPasswordChanged(user.Email, user.Login)
+	if err != nil {
+		p.logger.Warn("Error while sending password changed notification", zap.String("login", user.Login),
+			zap.Error(err))
 	}
+	p.logger.Info("password reset service: password reset and sessions revoked", zap.String("login", user.Login))
 	return nil
 }
+
+// getValidToken looks up the token, expired tokens are removed and reported as invalid.
+func (p *passwordReset) getValidToken(ctx context.Context, tokenToVerify string) (*ent.PasswordReset, error) {
+	token, err := p.GetToken(ctx, tokenToVerify)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			p.logger.Warn("Password reset token not found")
+			return nil, domain.ErrPasswordResetTokenInvalid
+		}
+		p.logger.Error("Error while getting token", zap.Error(err))
+		return nil, err
+	}
+	if token.TTL.Before(time.Now()) {
+		p.logger.Warn("Password reset token is expired")
+		if errDelete := p.DeleteToken(ctx, tokenToVerify); errDelete != nil {
+			return nil, fmt.Errorf("error while deleting expired token: %w", errDelete)
+		}
+		return nil, domain.ErrPasswordResetTokenInvalid
+	}
+	if token.Edges.Users == nil || token.Edges.Users.IsDeleted {
+		p.logger.Warn("Password reset token has no active user")
+		return nil, domain.ErrPasswordResetTokenInvalid
+	}
+	return token, nil
+}
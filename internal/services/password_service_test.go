@@ -18,12 +18,13 @@ import (
 
 type PasswordResetTestSuite struct {
 	suite.Suite
-	logger            *zap.Logger
-	userRepository    *mocks.UserRepository
-	passwordRepo      *mocks.PasswordResetRepository
-	emailClient       *mocks.Sender
-	passwordGenerator *mocks.PasswordGenerator
-	passwordService   domain.PasswordResetService
+	logger          *zap.Logger
+	userRepository  *mocks.UserRepository
+	passwordRepo    *mocks.PasswordResetRepository
+	emailClient     *mocks.Sender
+	tokenRepository *mocks.TokenRepository
+	passwordPolicy  *mocks.PasswordPolicy
+	passwordService domain.PasswordResetService
 }
 
 func TestPasswordClientSuite(t *testing.T) {
@@ -35,10 +36,12 @@ func (s *PasswordResetTestSuite) SetupTest() {
 	s.userRepository = &mocks.UserRepository{}
 	s.passwordRepo = &mocks.PasswordResetRepository{}
 	s.emailClient = &mocks.Sender{}
-	s.passwordGenerator = &mocks.PasswordGenerator{}
+	s.tokenRepository = &mocks.TokenRepository{}
+	s.passwordPolicy = &mocks.PasswordPolicy{}
 	s.logger = zap.NewExample()
 	ttl := time.Hour
-	service := NewPasswordResetService(s.emailClient, s.userRepository, s.passwordRepo, s.logger, ttl, s.passwordGenerator)
+	service := NewPasswordResetService(s.emailClient, s.userRepository, s.passwordRepo, s.tokenRepository,
+		s.logger, ttl, s.passwordPolicy)
 	s.passwordService = service
 }
 
@@ -54,6 +57,18 @@ func (s *PasswordResetTestSuite) TestPasswordReset_SendResetPasswordLink_UserByL
 	s.userRepository.AssertExpectations(t)
 }
 
+func (s *PasswordResetTestSuite) TestPasswordReset_SendResetPasswordLink_DeletedUser() {
+	t := s.T()
+	ctx := context.Background()
+	login := "login"
+	s.userRepository.On("UserByLogin", ctx, login).
+		Return(&ent.User{ID: 1, Login: login, Email: "email", IsDeleted: true}, nil)
+	errReturn := s.passwordService.SendResetPasswordLink(ctx, login)
+	require.Error(t, errReturn)
+	s.userRepository.AssertExpectations(t)
+	s.passwordRepo.AssertExpectations(t)
+}
+
 func (s *PasswordResetTestSuite) TestPasswordReset_SendResetPasswordLink_CreateTokenErr() {
 	t := s.T()
 	ctx := context.Background()
@@ -114,187 +129,186 @@ func (s *PasswordResetTestSuite) TestPasswordReset_SendResetPasswordLink_OK() {
 	s.emailClient.AssertExpectations(t)
 }
 
-func (s *PasswordResetTestSuite) TestPasswordReset_VerifyTokenAndSendPassword_GetTokenErr() {
+func (s *PasswordResetTestSuite) validToken(token string) *ent.PasswordReset {
+	return &ent.PasswordReset{
+		TTL:   time.Now().Add(1 * time.Hour),
+		Token: token,
+		Edges: ent.PasswordResetEdges{
+			Users: &ent.User{ID: 1, Login: "login", Email: "email"},
+		},
+	}
+}
+
+func (s *PasswordResetTestSuite) TestPasswordReset_VerifyToken_GetTokenErr() {
 	t := s.T()
 	ctx := context.Background()
 	token := "token"
 	err := errors.New("error")
 	s.passwordRepo.On("GetToken", ctx, token).Return(nil, err)
-	errReturn := s.passwordService.VerifyTokenAndSendPassword(ctx, token)
+	errReturn := s.passwordService.VerifyToken(ctx, token)
 	require.Error(t, errReturn)
 	require.Equal(t, err, errReturn)
 	s.passwordRepo.AssertExpectations(t)
 }
 
-func (s *PasswordResetTestSuite) TestPasswordReset_VerifyTokenAndSendPassword_TokenExpired() {
+func (s *PasswordResetTestSuite) TestPasswordReset_VerifyToken_NotFound() {
 	t := s.T()
 	ctx := context.Background()
 	token := "token"
-	returnToken := &ent.PasswordReset{
-		TTL:   time.Now().Add(-1 * time.Hour),
-		Token: token,
-		Edges: ent.PasswordResetEdges{
-			Users: &ent.User{Login: "login"},
-		},
-	}
-	s.passwordRepo.On("GetToken", ctx, token).Return(returnToken, nil)
-	s.passwordRepo.On("DeleteToken", ctx, token).Return(nil)
-	errReturn := s.passwordService.VerifyTokenAndSendPassword(ctx, token)
-	require.Error(t, errReturn)
+	s.passwordRepo.On("GetToken", ctx, token).Return(nil, &ent.NotFoundError{})
+	errReturn := s.passwordService.VerifyToken(ctx, token)
+	require.ErrorIs(t, errReturn, domain.ErrPasswordResetTokenInvalid)
 	s.passwordRepo.AssertExpectations(t)
 }
 
-func (s *PasswordResetTestSuite) TestPasswordReset_VerifyTokenAndSendPassword_DeleteExpiredErr() {
+func (s *PasswordResetTestSuite) TestPasswordReset_VerifyToken_TokenExpired() {
 	t := s.T()
 	ctx := context.Background()
 	token := "token"
-	returnToken := &ent.PasswordReset{
-		TTL:   time.Now().Add(-1 * time.Hour),
-		Token: token,
-		Edges: ent.PasswordResetEdges{
-			Users: &ent.User{Login: "login"},
-		},
-	}
-	err := errors.New("error")
-	s.passwordRepo.On("GetToken", ctx, token).Return(returnToken, nil)
-	s.passwordRepo.On("DeleteToken", ctx, token).Return(err)
-	errReturn := s.passwordService.VerifyTokenAndSendPassword(ctx, token)
-	require.Error(t, errReturn)
+	returnToken := s.validToken(token)
+	returnToken.TTL = time.Now().Add(-1 * time.Hour)
+	s.passwordRepo.On("GetToken", ctx, returnToken.Token).Return(returnToken, nil)
+	s.passwordRepo.On("DeleteToken", ctx, returnToken.Token).Return(nil)
+	errReturn := s.passwordService.VerifyToken(ctx, token)
+	require.ErrorIs(t, errReturn, domain.ErrPasswordResetTokenInvalid)
 	s.passwordRepo.AssertExpectations(t)
 }
 
-func (s *PasswordResetTestSuite) TestPasswordReset_VerifyTokenAndSendPassword_PasswordGenErr() {
+func (s *PasswordResetTestSuite) TestPasswordReset_VerifyToken_DeleteExpiredErr() {
 	t := s.T()
 	ctx := context.Background()
 	token := "token"
-	returnToken := &ent.PasswordReset{
-		TTL:   time.Now().Add(1 * time.Hour),
-		Token: token,
-		Edges: ent.PasswordResetEdges{
-			Users: &ent.User{Login: "login"},
-		},
-	}
+	returnToken := s.validToken(token)
+	returnToken.TTL = time.Now().Add(-1 * time.Hour)
 	err := errors.New("error")
-	newPassword := "new password"
-	s.passwordRepo.On("GetToken", ctx, token).Return(returnToken, nil)
-	s.passwordGenerator.On("NewPassword").Return(newPassword, err)
+	s.passwordRepo.On("GetToken", ctx, returnToken.Token).Return(returnToken, nil)
+	s.passwordRepo.On("DeleteToken", ctx, returnToken.Token).Return(err)
+	errReturn := s.passwordService.VerifyToken(ctx, token)
+	require.ErrorIs(t, errReturn, err)
+	s.passwordRepo.AssertExpectations(t)
+}
 
-	errReturn := s.passwordService.VerifyTokenAndSendPassword(ctx, token)
-	require.Error(t, errReturn)
-	require.Equal(t, err, errReturn)
+func (s *PasswordResetTestSuite) TestPasswordReset_VerifyToken_DeletedUser() {
+	t := s.T()
+	ctx := context.Background()
+	token := "token"
+	returnToken := s.validToken(token)
+	returnToken.Edges.Users.IsDeleted = true
+	s.passwordRepo.On("GetToken", ctx, returnToken.Token).Return(returnToken, nil)
+	errReturn := s.passwordService.VerifyToken(ctx, token)
+	require.ErrorIs(t, errReturn, domain.ErrPasswordResetTokenInvalid)
 	s.passwordRepo.AssertExpectations(t)
-	s.userRepository.AssertExpectations(t)
-	s.emailClient.AssertExpectations(t)
+}
+
+func (s *PasswordResetTestSuite) TestPasswordReset_VerifyToken_OK() {
+	t := s.T()
+	ctx := context.Background()
+	token := "token"
+	returnToken := s.validToken(token)
+	s.passwordRepo.On("GetToken", ctx, returnToken.Token).Return(returnToken, nil)
+	errReturn := s.passwordService.VerifyToken(ctx, token)
+	require.NoError(t, errReturn)
 	s.passwordRepo.AssertExpectations(t)
 }
 
-func (s *PasswordResetTestSuite) TestPasswordReset_VerifyTokenAndSendPassword_ChangeTxErr() {
+func (s *PasswordResetTestSuite) TestPasswordReset_ResetPassword_PolicyViolated() {
 	t := s.T()
 	ctx := context.Background()
 	token := "token"
-	returnToken := &ent.PasswordReset{
-		TTL:   time.Now().Add(1 * time.Hour),
-		Token: token,
-		Edges: ent.PasswordResetEdges{
-			Users: &ent.User{Login: "login"},
-		},
-	}
-	err := errors.New("error")
-	newPassword := "new password"
-	s.passwordRepo.On("GetToken", ctx, token).Return(returnToken, nil)
-	s.userRepository.On("ChangePasswordByLogin", ctx, returnToken.Edges.Users.Login,
-		mock.AnythingOfType("string")).Return(err)
-	s.passwordGenerator.On("NewPassword").Return(newPassword, nil)
+	newPassword := "password"
+	returnToken := s.validToken(token)
+	user := returnToken.Edges.Users
+	violations := []string{"password is too common"}
+	s.passwordRepo.On("GetToken", ctx, returnToken.Token).Return(returnToken, nil)
+	s.passwordPolicy.On("Validate", newPassword, user.Login, user.Email).Return(violations)
 
-	errReturn := s.passwordService.VerifyTokenAndSendPassword(ctx, token)
-	require.Error(t, errReturn)
-	require.Equal(t, err, errReturn)
+	errReturn := s.passwordService.ResetPassword(ctx, token, newPassword)
+	var policyErr *domain.PasswordPolicyError
+	require.ErrorAs(t, errReturn, &policyErr)
+	require.Equal(t, violations, policyErr.Violations)
 	s.passwordRepo.AssertExpectations(t)
+	s.passwordPolicy.AssertExpectations(t)
 	s.userRepository.AssertExpectations(t)
-	s.emailClient.AssertExpectations(t)
+}
+
+func (s *PasswordResetTestSuite) TestPasswordReset_ResetPassword_TokenAlreadyUsed() {
+	t := s.T()
+	ctx := context.Background()
+	token := "token"
+	newPassword := "Str0ng-Passw"
+	returnToken := s.validToken(token)
+	user := returnToken.Edges.Users
+	s.passwordRepo.On("GetToken", ctx, returnToken.Token).Return(returnToken, nil)
+	s.passwordPolicy.On("Validate", newPassword, user.Login, user.Email).Return(nil)
+	s.passwordRepo.On("DeleteToken", ctx, returnToken.Token).Return(&ent.NotFoundError{})
+
+	errReturn := s.passwordService.ResetPassword(ctx, token, newPassword)
+	require.ErrorIs(t, errReturn, domain.ErrPasswordResetTokenInvalid)
 	s.passwordRepo.AssertExpectations(t)
+	s.userRepository.AssertExpectations(t)
+	s.tokenRepository.AssertExpectations(t)
 }
 
-func (s *PasswordResetTestSuite) TestPasswordReset_VerifyTokenAndSendPassword_SendEmailErr() {
+func (s *PasswordResetTestSuite) TestPasswordReset_ResetPassword_ChangePasswordErr() {
 	t := s.T()
 	ctx := context.Background()
 	token := "token"
-	returnToken := &ent.PasswordReset{
-		TTL:   time.Now().Add(1 * time.Hour),
-		Token: token,
-		Edges: ent.PasswordResetEdges{
-			Users: &ent.User{Login: "login"},
-		},
-	}
+	newPassword := "Str0ng-Passw"
+	returnToken := s.validToken(token)
+	user := returnToken.Edges.Users
 	err := errors.New("error")
-	newPassword := "new password"
-	s.passwordRepo.On("GetToken", ctx, token).Return(returnToken, nil)
-	s.passwordGenerator.On("NewPassword").Return(newPassword, nil)
-	s.userRepository.On("ChangePasswordByLogin", ctx, returnToken.Edges.Users.Login,
-		mock.AnythingOfType("string")).Return(nil)
-	s.emailClient.On("SendNewPassword", returnToken.Edges.Users.Email,
-		returnToken.Edges.Users.Login, newPassword).Return(err)
-	errReturn := s.passwordService.VerifyTokenAndSendPassword(ctx, token)
-	require.Error(t, errReturn)
+	s.passwordRepo.On("GetToken", ctx, returnToken.Token).Return(returnToken, nil)
+	s.passwordPolicy.On("Validate", newPassword, user.Login, user.Email).Return(nil)
+	s.passwordRepo.On("DeleteToken", ctx, returnToken.Token).Return(nil)
+	s.userRepository.On("ChangePasswordByLogin", ctx, user.Login, newPassword).Return(err)
+
+	errReturn := s.passwordService.ResetPassword(ctx, token, newPassword)
 	require.Equal(t, err, errReturn)
 	s.passwordRepo.AssertExpectations(t)
 	s.userRepository.AssertExpectations(t)
-	s.emailClient.AssertExpectations(t)
-	s.passwordRepo.AssertExpectations(t)
+	s.tokenRepository.AssertExpectations(t)
 }
 
-func (s *PasswordResetTestSuite) TestPasswordReset_VerifyTokenAndSendPassword_DeleteTokenErr() {
+func (s *PasswordResetTestSuite) TestPasswordReset_ResetPassword_RevokeSessionsErr() {
 	t := s.T()
 	ctx := context.Background()
 	token := "token"
-	returnToken := &ent.PasswordReset{
-		TTL:   time.Now().Add(1 * time.Hour),
-		Token: token,
-		Edges: ent.PasswordResetEdges{
-			Users: &ent.User{Login: "login"},
-		},
-	}
-	newPassword := "new password"
-	s.passwordRepo.On("GetToken", ctx, token).Return(returnToken, nil)
-	s.passwordGenerator.On("NewPassword").Return(newPassword, nil)
-	s.userRepository.On("ChangePasswordByLogin", ctx, returnToken.Edges.Users.Login,
-		mock.AnythingOfType("string")).Return(nil)
-	s.emailClient.On("SendNewPassword", returnToken.Edges.Users.Email,
-		returnToken.Edges.Users.Login, newPassword).Return(nil)
-	s.passwordRepo.On("DeleteToken", ctx, token).Return(errors.New("error"))
-	s.emailClient.On("IsSendRequired").Return(false)
-	errReturn := s.passwordService.VerifyTokenAndSendPassword(ctx, token)
-	require.NoError(t, errReturn)
+	newPassword := "Str0ng-Passw"
+	returnToken := s.validToken(token)
+	user := returnToken.Edges.Users
+	err := errors.New("error")
+	s.passwordRepo.On("GetToken", ctx, returnToken.Token).Return(returnToken, nil)
+	s.passwordPolicy.On("Validate", newPassword, user.Login, user.Email).Return(nil)
+	s.passwordRepo.On("DeleteToken", ctx, returnToken.Token).Return(nil)
+	s.userRepository.On("ChangePasswordByLogin", ctx, user.Login, newPassword).Return(nil)
+	s.tokenRepository.On("DeleteTokensByUserID", ctx, user.ID).Return(err)
+
+	errReturn := s.passwordService.ResetPassword(ctx, token, newPassword)
+	require.Equal(t, err, errReturn)
 	s.passwordRepo.AssertExpectations(t)
 	s.userRepository.AssertExpectations(t)
+	s.tokenRepository.AssertExpectations(t)
 	s.emailClient.AssertExpectations(t)
-	s.passwordRepo.AssertExpectations(t)
 }
 
-func (s *PasswordResetTestSuite) TestPasswordReset_VerifyTokenAndSendPassword_OK() {
+func (s *PasswordResetTestSuite) TestPasswordReset_ResetPassword_OK() {
 	t := s.T()
 	ctx := context.Background()
 	token := "token"
-	returnToken := &ent.PasswordReset{
-		TTL:   time.Now().Add(1 * time.Hour),
-		Token: token,
-		Edges: ent.PasswordResetEdges{
-			Users: &ent.User{Login: "login"},
-		},
-	}
-	newPassword := "new password"
-	s.passwordRepo.On("GetToken", ctx, token).Return(returnToken, nil)
-	s.passwordGenerator.On("NewPassword").Return(newPassword, nil)
-	s.userRepository.On("ChangePasswordByLogin", ctx, returnToken.Edges.Users.Login,
-		mock.AnythingOfType("string")).Return(nil)
-	s.emailClient.On("SendNewPassword", returnToken.Edges.Users.Email,
-		returnToken.Edges.Users.Login, newPassword).Return(nil)
-	s.passwordRepo.On("DeleteToken", ctx, token).Return(nil)
-	s.emailClient.On("IsSendRequired").Return(false)
-	errReturn := s.passwordService.VerifyTokenAndSendPassword(ctx, token)
+	newPassword := "Str0ng-Passw"
+	returnToken := s.validToken(token)
+	user := returnToken.Edges.Users
+	s.passwordRepo.On("GetToken", ctx, returnToken.Token).Return(returnToken, nil)
+	s.passwordPolicy.On("Validate", newPassword, user.Login, user.Email).Return(nil)
+	s.passwordRepo.On("DeleteToken", ctx, returnToken.Token).Return(nil)
+	s.userRepository.On("ChangePasswordByLogin", ctx, user.Login, newPassword).Return(nil)
+	s.tokenRepository.On("DeleteTokensByUserID", ctx, user.ID).Return(nil)
+	s.emailClient.On("SendPasswordChanged", user.Email, user.Login).Return(errors.New("error"))
+
+	errReturn := s.passwordService.ResetPassword(ctx, token, newPassword)
 	require.NoError(t, errReturn)
 	s.passwordRepo.AssertExpectations(t)
 	s.userRepository.AssertExpectations(t)
+	s.tokenRepository.AssertExpectations(t)
 	s.emailClient.AssertExpectations(t)
-	s.passwordRepo.AssertExpectations(t)
 }
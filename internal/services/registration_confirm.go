@@ -74,14 +74,14 @@ func (rc *registrationConfirm) SendConfirmationLink(ctx context.Context, login s
 }
 
 func (rc *registrationConfirm) VerifyConfirmationToken(ctx context.Context, tokenToVerify string) error {
-	rc.logger.Info("registration confirmation service: verify token", zap.String("token", tokenToVerify))
+	rc.logger.Info("registration confirmation service: verify token")
 	token, err := rc.GetToken(ctx, tokenToVerify)
 	if err != nil {
-		rc.logger.Error("Error while getting token", zap.String("token", tokenToVerify), zap.Error(err))
+		rc.logger.Error("Error while getting token", zap.Error(err))
 		return err
 	}
 	if token.TTL.Before(time.Now()) {
-		rc.logger.Error("Token is expired", zap.String("token", tokenToVerify))
+		rc.logger.Error("Token is expired")
 		errDelete := rc.DeleteToken(ctx, tokenToVerify)
 		if errDelete != nil {
 			return fmt.Errorf("error while deleting expired token: %w", errDelete)
@@ -97,7 +97,7 @@ func (rc *registrationConfirm) VerifyConfirmationToken(ctx context.Context, toke
 
 	errDelete := rc.DeleteToken(ctx, tokenToVerify)
 	if errDelete != nil {
-		rc.logger.Warn("Error while deleting token", zap.String("token", tokenToVerify), zap.Error(errDelete))
+		rc.logger.Warn("Error while deleting token", zap.Error(errDelete))
 	}
 	rc.logger.Info("registration confirmation service: verified token")
 	return nil
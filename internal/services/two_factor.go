@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+const (
+	// TokenPurposeClaim marks JWTs which must not be accepted as access or refresh tokens.
+	TokenPurposeClaim         = "purpose"
+	TwoFactorChallengePurpose = "2fa_challenge"
+	ChallengeIDTokenClaim     = "jti"
+)
+
+type twoFactorService struct {
+	client               *ent.Client
+	userRepository       domain.UserRepository
+	twoFactorRepository  domain.TwoFactorRepository
+	jwtSecret            string
+	issuer               string
+	challengeTTL         time.Duration
+	maxChallengeAttempts int
+	recoveryCodesCount   int
+	requiredForRoles     []string
+	logger               *zap.Logger
+}
+
+// NewTwoFactorService creates the service. The client is used to record failed challenge attempts
+// in a separate transaction, so they are not rolled back together with the failed request.
+func NewTwoFactorService(client *ent.Client, userRepository domain.UserRepository,
+	twoFactorRepository domain.TwoFactorRepository, jwtSecret, issuer string, challengeTTL time.Duration,
+	maxChallengeAttempts, recoveryCodesCount int, requiredForRoles []string,
+	logger *zap.Logger) domain.TwoFactorService {
+	return &twoFactorService{
+		client:               client,
+		userRepository:       userRepository,
+		twoFactorRepository:  twoFactorRepository,
+		jwtSecret:            jwtSecret,
+		issuer:               issuer,
+		challengeTTL:         challengeTTL,
+		maxChallengeAttempts: maxChallengeAttempts,
+		recoveryCodesCount:   recoveryCodesCount,
+		requiredForRoles:     requiredForRoles,
+		logger:               logger,
+	}
+}
+
+// IsRequired reports whether users with the role are not allowed to work without the second factor.
+func (s *twoFactorService) IsRequired(roleSlug string) bool {
+	return utils.IsValueInList(roleSlug, s.requiredForRoles)
+}
+
+// Enroll generates a new TOTP secret for the user. The secret is not used for login until Activate is called.
+func (s *twoFactorService) Enroll(ctx context.Context, userID int) (*domain.TwoFactorEnrollment, error) {
+	user, err := s.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsTotpEnabled {
+		return nil, domain.ErrTwoFactorEnabled
+	}
+	secret, err := utils.NewTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err = s.twoFactorRepository.SetTOTPSecret(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+	s.logger.Info("two-factor authentication enrolment started", zap.Int("userID", userID))
+	return &domain.TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(s.issuer, user.Login, secret),
+	}, nil
+}
+
+// Activate confirms the enrolment with a code from the authenticator app and returns new recovery codes.
+func (s *twoFactorService) Activate(ctx context.Context, userID int, code string) ([]string, error) {
+	user, err := s.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsTotpEnabled {
+		return nil, domain.ErrTwoFactorEnabled
+	}
+	if user.TotpSecret == nil {
+		return nil, domain.ErrTwoFactorNotEnrolled
+	}
+	if err = s.acceptTOTPCode(ctx, user, code); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := s.newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err = s.twoFactorRepository.EnableTOTP(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	s.logger.Info("two-factor authentication enabled", zap.Int("userID", userID))
+	return codes, nil
+}
+
+func (s *twoFactorService) Disable(ctx context.Context, userID int, code string) error {
+	user, err := s.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.IsTotpEnabled {
+		return domain.ErrTwoFactorNotEnrolled
+	}
+	if user.Edges.Role != nil && s.IsRequired(user.Edges.Role.Slug) {
+		return domain.ErrTwoFactorMandatory
+	}
+	if err = s.verifyCode(ctx, user, code); err != nil {
+		return err
+	}
+	if err = s.twoFactorRepository.DisableTOTP(ctx, userID); err != nil {
+		return err
+	}
+	s.logger.Info("two-factor authentication disabled", zap.Int("userID", userID))
+	return nil
+}
+
+func (s *twoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID int, code string) ([]string, error) {
+	user, err := s.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsTotpEnabled {
+		return nil, domain.ErrTwoFactorNotEnrolled
+	}
+	if err = s.acceptTOTPCode(ctx, user, code); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := s.newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err = s.twoFactorRepository.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// IssueChallenge returns a short-lived token proving that the first authentication step was passed.
+// It must be called only after the password has been verified. The token can be used for one successful login.
+func (s *twoFactorService) IssueChallenge(ctx context.Context, login string) (string, error) {
+	user, err := s.userRepository.GetUserByLogin(ctx, login)
+	if err != nil {
+		return "", err
+	}
+	jti := uuid.New().String()
+	expiresAt := time.Now().Add(s.challengeTTL)
+	if err = s.twoFactorRepository.CreateChallenge(ctx, user.ID, jti, expiresAt); err != nil {
+		return "", err
+	}
+	token := jwt.New(jwt.SigningMethodHS256)
+	claims := token.Claims.(jwt.MapClaims)
+	claims[UserIDTokenClaim] = user.ID
+	claims[ExpireAtTokenClaim] = expiresAt.Unix()
+	claims[TokenPurposeClaim] = TwoFactorChallengePurpose
+	claims[ChallengeIDTokenClaim] = jti
+
+	return token.SignedString([]byte(s.jwtSecret))
+}
+
+// VerifyChallenge checks the challenge token and the TOTP or recovery code. It returns ID of the authenticated user.
+// The challenge is consumed on success and invalidated after maxChallengeAttempts wrong codes.
+func (s *twoFactorService) VerifyChallenge(ctx context.Context, challengeToken, code string) (int, error) {
+	claims := jwt.MapClaims{}
+	parsed, err := jwt.ParseWithClaims(challengeToken, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("error decoding token")
+		}
+		return []byte(s.jwtSecret), nil
+	})
+	if err != nil || !parsed.Valid {
+		return 0, domain.ErrInvalidTwoFactorCode
+	}
+	if claims[TokenPurposeClaim] != TwoFactorChallengePurpose {
+		return 0, domain.ErrInvalidTwoFactorCode
+	}
+	jti, ok := claims[ChallengeIDTokenClaim].(string)
+	if !ok {
+		return 0, domain.ErrInvalidTwoFactorCode
+	}
+	challenge, err := s.twoFactorRepository.ChallengeByJTI(ctx, jti)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return 0, domain.ErrInvalidTwoFactorCode
+		}
+		return 0, err
+	}
+	id, ok := claims[UserIDTokenClaim].(float64)
+	if !ok || challenge.Edges.Users == nil || challenge.Edges.Users.ID != int(id) {
+		return 0, domain.ErrInvalidTwoFactorCode
+	}
+	userID := int(id)
+
+	user, err := s.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if user.IsDeleted || !user.IsTotpEnabled || user.TotpSecret == nil {
+		return 0, domain.ErrInvalidTwoFactorCode
+	}
+	if err = s.verifyCode(ctx, user, code); err != nil {
+		if errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+			errRecord := s.twoFactorRepository.RecordChallengeFailure(ctx, s.client, jti, s.maxChallengeAttempts)
+			if errRecord != nil {
+				return 0, errRecord
+			}
+		}
+		return 0, err
+	}
+	consumed, err := s.twoFactorRepository.ConsumeChallenge(ctx, jti)
+	if err != nil {
+		return 0, err
+	}
+	if !consumed {
+		return 0, domain.ErrInvalidTwoFactorCode
+	}
+	return userID, nil
+}
+
+// verifyCode accepts either a TOTP code or one of the unused recovery codes. A recovery code is consumed.
+func (s *twoFactorService) verifyCode(ctx context.Context, user *ent.User, code string) error {
+	err := s.acceptTOTPCode(ctx, user, code)
+	if !errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+		return err
+	}
+	used, err := s.twoFactorRepository.UseRecoveryCode(ctx, user.ID,
+		utils.HashToken(utils.NormalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	s.logger.Info("recovery code used", zap.Int("userID", user.ID))
+	return nil
+}
+
+// acceptTOTPCode checks the TOTP code and remembers its time step. A code of the already accepted
+// or an earlier time step is rejected, so a code can not be replayed while it is still valid.
+func (s *twoFactorService) acceptTOTPCode(ctx context.Context, user *ent.User, code string) error {
+	if user.TotpSecret == nil {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	step, ok := utils.MatchTOTPCode(*user.TotpSecret, code, time.Now())
+	if !ok || (user.TotpLastStep != nil && step <= *user.TotpLastStep) {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	return s.twoFactorRepository.SetTOTPLastStep(ctx, user.ID, step)
+}
+
+func (s *twoFactorService) newRecoveryCodes() ([]string, []string, error) {
+	codes, err := utils.NewRecoveryCodes(s.recoveryCodesCount)
+	if err != nil {
+		return nil, nil, err
+	}
+	hashes := make([]string, len(codes))
+	for i, c := range codes {
+		hashes[i] = utils.HashToken(c)
+	}
+	return codes, hashes, nil
+}
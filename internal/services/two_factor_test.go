@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/mocks"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/roles"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type TwoFactorServiceTestSuite struct {
+	suite.Suite
+	userRepository      *mocks.UserRepository
+	twoFactorRepository *mocks.TwoFactorRepository
+	service             domain.TwoFactorService
+	secret              string
+}
+
+func TestTwoFactorServiceSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorServiceTestSuite))
+}
+
+func (s *TwoFactorServiceTestSuite) SetupTest() {
+	s.userRepository = &mocks.UserRepository{}
+	s.twoFactorRepository = &mocks.TwoFactorRepository{}
+	s.service = NewTwoFactorService(nil, s.userRepository, s.twoFactorRepository, "secret", "CSR",
+		time.Minute, 5, 3, []string{roles.Admin}, zap.NewNop())
+	secret, err := utils.NewTOTPSecret()
+	require.NoError(s.T(), err)
+	s.secret = secret
+}
+
+func (s *TwoFactorServiceTestSuite) currentCode() string {
+	code, err := utils.TOTPCode(s.secret, time.Now())
+	require.NoError(s.T(), err)
+	return code
+}
+
+// issueChallenge returns the challenge token and its stored jti.
+func (s *TwoFactorServiceTestSuite) issueChallenge(ctx context.Context, user *ent.User) (string, string) {
+	var jti string
+	s.userRepository.On("GetUserByLogin", ctx, user.Login).Return(user, nil)
+	s.twoFactorRepository.On("CreateChallenge", ctx, user.ID, mock.AnythingOfType("string"),
+		mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) {
+			jti = args.String(2)
+		}).
+		Return(nil)
+	challenge, err := s.service.IssueChallenge(ctx, user.Login)
+	require.NoError(s.T(), err)
+	require.NotEmpty(s.T(), jti)
+	s.twoFactorRepository.On("ChallengeByJTI", ctx, jti).Return(&ent.TwoFactorChallenge{
+		Jti:   jti,
+		Edges: ent.TwoFactorChallengeEdges{Users: user},
+	}, nil)
+	return challenge, jti
+}
+
+func (s *TwoFactorServiceTestSuite) enabledUser(roleSlug string) *ent.User {
+	return &ent.User{
+		ID:            1,
+		Login:         "login",
+		TotpSecret:    &s.secret,
+		IsTotpEnabled: true,
+		Edges:         ent.UserEdges{Role: &ent.Role{Slug: roleSlug}},
+	}
+}
+
+func (s *TwoFactorServiceTestSuite) TestTwoFactor_IsRequired() {
+	t := s.T()
+	require.True(t, s.service.IsRequired(roles.Admin))
+	require.False(t, s.service.IsRequired(roles.User))
+}
+
+func (s *TwoFactorServiceTestSuite) TestTwoFactor_Enroll_AlreadyEnabled() {
+	t := s.T()
+	ctx := context.Background()
+	s.userRepository.On("GetUserByID", ctx, 1).Return(s.enabledUser(roles.User), nil)
+
+	_, err := s.service.Enroll(ctx, 1)
+	require.ErrorIs(t, err, domain.ErrTwoFactorEnabled)
+	s.userRepository.AssertExpectations(t)
+}
+
+func (s *TwoFactorServiceTestSuite) TestTwoFactor_Enroll_OK() {
+	t := s.T()
+	ctx := context.Background()
+	s.userRepository.On("GetUserByID", ctx, 1).Return(&ent.User{ID: 1, Login: "login"}, nil)
+	s.twoFactorRepository.On("SetTOTPSecret", ctx, 1, mock.AnythingOfType("string")).Return(nil)
+
+	enrollment, err := s.service.Enroll(ctx, 1)
+	require.NoError(t, err)
+	require.NotEmpty(t, enrollment.Secret)
+	require.Contains(t, enrollment.ProvisioningURI, "otpauth://totp/CSR:login")
+	require.Contains(t, enrollment.ProvisioningURI, enrollment.Secret)
+	s.userRepository.AssertExpectations(t)
+	s.twoFactorRepository.AssertExpectations(t)
+}
+
+func (s *TwoFactorServiceTestSuite) TestTwoFactor_Activate_NotEnrolled() {
+	t := s.T()
+	ctx := context.Background()
+	s.userRepository.On("GetUserByID", ctx, 1).Return(&ent.User{ID: 1}, nil)
+
+	_, err := s.service.Activate(ctx, 1, "123456")
+	require.ErrorIs(t, err, domain.ErrTwoFactorNotEnrolled)
+	s.userRepository.AssertExpectations(t)
+}
+
+func (s *TwoFactorServiceTestSuite) TestTwoFactor_Activate_InvalidCode() {
+	t := s.T()
+	ctx := context.Background()
+	s.userRepository.On("GetUserByID", ctx, 1).Return(&ent.User{ID: 1, TotpSecret: &s.secret}, nil)
+
+	_, err := s.service.Activate(ctx, 1, "abcdef")
+	require.ErrorIs(t, err, domain.ErrInvalidTwoFactorCode)
+	s.userRepository.AssertExpectations(t)
+	s.twoFactorRepository.AssertExpectations(t)
+}
+
+func (s *TwoFactorServiceTestSuite) TestTwoFactor_Activate_OK() {
+	t := s.T()
+	ctx := context.Background()
+	s.userRepository.On("GetUserByID", ctx, 1).Return(&ent.User{ID: 1, TotpSecret: &s.secret}, nil)
+	s.twoFactorRepository.On("SetTOTPLastStep", ctx, 1, utils.TOTPStep(time.Now())).Return(nil)
+	s.twoFactorRepository.On("EnableTOTP", ctx, 1, mock.AnythingOfType("[]string")).Return(nil)
+
+	codes, err := s.service.Activate(ctx, 1, s.currentCode())
+	require.NoError(t, err)
+	require.Len(t, codes, 3)
+
+	hashes := s.twoFactorRepository.Calls[1].Arguments.Get(2).([]string)
+	for i, code := range codes {
+		require.Equal(t, utils.HashToken(code), hashes[i])
+	}
+	s.userRepository.AssertExpectations(t)
+	s.twoFactorRepository.AssertExpectations(t)
+}
+
+func (s *TwoFactorServiceTestSuite) TestTwoFactor_Disable_Mandatory() {
+	t := s.T()
+	ctx := context.Background()
+	s.userRepository.On("GetUserByID", ctx, 1).Return(s.enabledUser(roles.Admin), nil)
+
+	err := s.service.Disable(ctx, 1, s.currentCode())
+	require.ErrorIs(t, err, domain.ErrTwoFactorMandatory)
+	s.userRepository.AssertExpectations(t)
+	s.twoFactorRepository.AssertExpectations(t)
+}
+
+func (s *TwoFactorServiceTestSuite) TestTwoFactor_Disable_OK() {
+	t := s.T()
+	ctx := context.Background()
+	s.userRepository.On("GetUserByID", ctx, 1).Return(s.enabledUser(roles.User), nil)
+	s.twoFactorRepository.On("SetTOTPLastStep", ctx, 1, utils.TOTPStep(time.Now())).Return(nil)
+	s.twoFactorRepository.On("DisableTOTP", ctx, 1).Return(nil)
+
+	err := s.service.Disable(ctx, 1, s.currentCode())
+	require.NoError(t, err)
+	s.userRepository.AssertExpectations(t)
+	s.twoFactorRepository.AssertExpectations(t)
+}
+
+func (s *TwoFactorServiceTestSuite) TestTwoFactor_Challenge_TOTPCode() {
+	t := s.T()
+	ctx := context.Background()
+	user := s.enabledUser(roles.Admin)
+	challenge, jti := s.issueChallenge(ctx, user)
+	s.userRepository.On("GetUserByID", ctx, user.ID).Return(user, nil)
+	s.twoFactorRepository.On("SetTOTPLastStep", ctx, user.ID, utils.TOTPStep(time.Now())).Return(nil)
+	s.twoFactorRepository.On("ConsumeChallenge", ctx, jti).Return(true, nil)
+
+	userID, err := s.service.VerifyChallenge(ctx, challenge, s.currentCode())
+	require.NoError(t, err)
+	require.Equal(t, user.ID, userID)
+	s.userRepository.AssertExpectations(t)
+	s.twoFactorRepository.AssertExpectations(t)
+}
+
+func (s *TwoFactorServiceTestSuite) TestTwoFactor_Challenge_RecoveryCode() {
+	t := s.T()
+	ctx := context.Background()
+	user := s.enabledUser(roles.Admin)
+	recoveryCode := "abcde-12345"
+	challenge, jti := s.issueChallenge(ctx, user)
+	s.userRepository.On("GetUserByID", ctx, user.ID).Return(user, nil)
+	s.twoFactorRepository.On("UseRecoveryCode", ctx, user.ID, utils.HashToken(recoveryCode)).Return(true, nil)
+	s.twoFactorRepository.On("ConsumeChallenge", ctx, jti).Return(true, nil)
+
+	userID, err := s.service.VerifyChallenge(ctx, challenge, " ABCDE-12345 ")
+	require.NoError(t, err)
+	require.Equal(t, user.ID, userID)
+	s.userRepository.AssertExpectations(t)
+	s.twoFactorRepository.AssertExpectations(t)
+}
+
+func (s *TwoFactorServiceTestSuite) TestTwoFactor_Challenge_InvalidCode() {
+	t := s.T()
+	ctx := context.Background()
+	user := s.enabledUser(roles.Admin)
+	challenge, jti := s.issueChallenge(ctx, user)
+	s.userRepository.On("GetUserByID", ctx, user.ID).Return(user, nil)
+	s.twoFactorRepository.On("UseRecoveryCode", ctx, user.ID, mock.AnythingOfType("string")).Return(false, nil)
+	s.twoFactorRepository.On("RecordChallengeFailure", ctx, (*ent.Client)(nil), jti, 5).Return(nil)
+
+	_, err := s.service.VerifyChallenge(ctx, challenge, "wrong")
+	require.ErrorIs(t, err, domain.ErrInvalidTwoFactorCode)
+	s.userRepository.AssertExpectations(t)
+	s.twoFactorRepository.AssertExpectations(t)
+}
+
+func (s *TwoFactorServiceTestSuite) TestTwoFactor_Challenge_ReplayedCode() {
+	t := s.T()
+	ctx := context.Background()
+	user := s.enabledUser(roles.Admin)
+	lastStep := utils.TOTPStep(time.Now())
+	user.TotpLastStep = &lastStep
+	challenge, jti := s.issueChallenge(ctx, user)
+	s.userRepository.On("GetUserByID", ctx, user.ID).Return(user, nil)
+	s.twoFactorRepository.On("UseRecoveryCode", ctx, user.ID, mock.AnythingOfType("string")).Return(false, nil)
+	s.twoFactorRepository.On("RecordChallengeFailure", ctx, (*ent.Client)(nil), jti, 5).Return(nil)
+
+	_, err := s.service.VerifyChallenge(ctx, challenge, s.currentCode())
+	require.ErrorIs(t, err, domain.ErrInvalidTwoFactorCode)
+	s.userRepository.AssertExpectations(t)
+	s.twoFactorRepository.AssertExpectations(t)
+}
+
+func (s *TwoFactorServiceTestSuite) TestTwoFactor_Challenge_Consumed() {
+	t := s.T()
+	ctx := context.Background()
+	user := s.enabledUser(roles.Admin)
+	s.userRepository.On("GetUserByLogin", ctx, user.Login).Return(user, nil)
+	s.twoFactorRepository.On("CreateChallenge", ctx, user.ID, mock.AnythingOfType("string"),
+		mock.AnythingOfType("time.Time")).Return(nil)
+	s.twoFactorRepository.On("ChallengeByJTI", ctx, mock.AnythingOfType("string")).
+		Return(nil, &ent.NotFoundError{})
+
+	challenge, err := s.service.IssueChallenge(ctx, user.Login)
+	require.NoError(t, err)
+
+	_, err = s.service.VerifyChallenge(ctx, challenge, s.currentCode())
+	require.ErrorIs(t, err, domain.ErrInvalidTwoFactorCode)
+	s.userRepository.AssertExpectations(t)
+	s.twoFactorRepository.AssertExpectations(t)
+}
+
+func (s *TwoFactorServiceTestSuite) TestTwoFactor_Challenge_AccessTokenIsNotAccepted() {
+	t := s.T()
+	ctx := context.Background()
+	user := s.enabledUser(roles.Admin)
+	accessToken, err := generateJWT(user, "secret")
+	require.NoError(t, err)
+
+	_, err = s.service.VerifyChallenge(ctx, accessToken, s.currentCode())
+	require.ErrorIs(t, err, domain.ErrInvalidTwoFactorCode)
+	s.userRepository.AssertExpectations(t)
+}
@@ -50,6 +50,9 @@ func (s *tokenManager) RefreshToken(ctx context.Context, token string) (string,
 	}
 
 	if refreshToken.Valid {
+		if _, ok := claims[TokenPurposeClaim]; ok {
+			return "", "", false, errors.New("token is not a refresh token")
+		}
 		if refreshToken.Raw != token {
 			return "", "", false, errors.New("refresh token is invalid")
 		}
@@ -124,6 +127,32 @@ func (s *tokenManager) GenerateTokens(ctx context.Context, login, password strin
 		return "", "", false, err
 	}
 
+	if user.IsTotpEnabled {
+		return "", "", false, domain.ErrTwoFactorRequired
+	}
+
+	return s.createTokens(ctx, user)
+}
+
+// GenerateTokensByUserID generates tokens for the user who has already been authenticated,
+// e.g. after the second authentication step.
+func (s *tokenManager) GenerateTokensByUserID(ctx context.Context, userID int) (string, string, bool, error) {
+	user, err := s.userRepository.GetUserByID(ctx, userID)
+	if ent.IsNotFound(err) {
+		return "", "", false, err
+	}
+	if err != nil {
+		return "", "", true, err
+	}
+
+	if user.IsDeleted {
+		return "", "", false, errors.New("user deleted, unable to generate token")
+	}
+
+	return s.createTokens(ctx, user)
+}
+
+func (s *tokenManager) createTokens(ctx context.Context, user *ent.User) (string, string, bool, error) {
 	accessToken, err := generateJWT(user, s.jwtSecret)
 	if err != nil {
 		return "", "", true, err
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent/order"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/utils"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type userExportService struct {
+	userRepository        domain.UserRepository
+	activeAreaRepository  domain.ActiveAreaRepository
+	orderRepository       domain.OrderRepository
+	orderStatusRepository domain.OrderStatusRepository
+	tokenRepository       domain.TokenRepository
+}
+
+func NewUserExportService(userRepository domain.UserRepository, activeAreaRepository domain.ActiveAreaRepository,
+	orderRepository domain.OrderRepository, orderStatusRepository domain.OrderStatusRepository,
+	tokenRepository domain.TokenRepository) domain.UserExportService {
+	return &userExportService{
+		userRepository:        userRepository,
+		activeAreaRepository:  activeAreaRepository,
+		orderRepository:       orderRepository,
+		orderStatusRepository: orderStatusRepository,
+		tokenRepository:       tokenRepository,
+	}
+}
+
+func (s *userExportService) Export(ctx context.Context, userID int) (*domain.UserExport, error) {
+	user, err := s.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	areas, err := s.activeAreaRepository.UserActiveAreas(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := s.exportOrders(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := s.tokenRepository.UserTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &domain.UserExport{
+		Profile:     exportProfile(user),
+		ActiveAreas: make([]string, len(areas)),
+		Orders:      orders,
+		Sessions:    make([]domain.UserExportSession, 0, len(tokens)),
+	}
+	for i, area := range areas {
+		export.ActiveAreas[i] = area.Name
+	}
+	now := time.Now()
+	for _, t := range tokens {
+		expiresAt := tokenExpiration(t.RefreshToken)
+		// expired tokens may still be stored, they are not active sessions
+		if expiresAt != nil && expiresAt.Before(now) {
+			continue
+		}
+		export.Sessions = append(export.Sessions, domain.UserExportSession{ID: t.ID, ExpiresAt: expiresAt})
+	}
+	return export, nil
+}
+
+func (s *userExportService) exportOrders(ctx context.Context, userID int) ([]domain.UserExportOrder, error) {
+	orders, err := s.orderRepository.List(ctx, &userID, domain.OrderFilter{
+		Filter: domain.Filter{Limit: math.MaxInt, OrderBy: utils.AscOrder, OrderColumn: order.FieldID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]domain.UserExportOrder, len(orders))
+	for i, o := range orders {
+		statuses, errHistory := s.orderStatusRepository.StatusHistory(ctx, o.ID)
+		if errHistory != nil {
+			return nil, errHistory
+		}
+		sort.Slice(statuses, func(a, b int) bool {
+			return statuses[a].CurrentDate.Before(statuses[b].CurrentDate)
+		})
+		result[i] = domain.UserExportOrder{
+			ID:          o.ID,
+			Description: o.Description,
+			Quantity:    o.Quantity,
+			RentStart:   o.RentStart,
+			RentEnd:     o.RentEnd,
+			CreatedAt:   o.CreatedAt,
+			Equipment:   make([]string, len(o.Edges.Equipments)),
+			Statuses:    make([]domain.UserExportOrderStatus, len(statuses)),
+		}
+		for j, eq := range o.Edges.Equipments {
+			result[i].Equipment[j] = eq.Title
+		}
+		for j, status := range statuses {
+			result[i].Statuses[j] = domain.UserExportOrderStatus{Comment: status.Comment, Date: status.CurrentDate}
+			if status.Edges.OrderStatusName != nil {
+				result[i].Statuses[j].Status = status.Edges.OrderStatusName.Status
+			}
+		}
+	}
+	return result, nil
+}
+
+func exportProfile(user *ent.User) domain.UserExportProfile {
+	profile := domain.UserExportProfile{
+		ID:                      user.ID,
+		Login:                   user.Login,
+		Email:                   user.Email,
+		Name:                    user.Name,
+		Surname:                 user.Surname,
+		Patronymic:              user.Patronymic,
+		PassportSeries:          utils.PointerOrNil(user.PassportSeries),
+		PassportNumber:          utils.PointerOrNil(user.PassportNumber),
+		PassportAuthority:       utils.PointerOrNil(user.PassportAuthority),
+		Phone:                   user.Phone,
+		Type:                    user.Type.String(),
+		OrgName:                 user.OrgName,
+		Website:                 user.Website,
+		Vk:                      user.Vk,
+		IsReadonly:              user.IsReadonly,
+		IsRegistrationConfirmed: user.IsRegistrationConfirmed,
+		IsTotpEnabled:           user.IsTotpEnabled,
+	}
+	if !user.PassportIssueDate.IsZero() {
+		issueDate := user.PassportIssueDate
+		profile.PassportIssueDate = &issueDate
+	}
+	if user.Edges.Role != nil {
+		profile.Role = user.Edges.Role.Slug
+	}
+	return profile
+}
+
+// tokenExpiration reads the expiration of the token issued by the token manager. The signature is not
+// checked, the token comes from the database and is not exported itself.
+func tokenExpiration(token string) *time.Time {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return nil
+	}
+	exp, ok := claims[ExpireAtTokenClaim].(float64)
+	if !ok {
+		return nil
+	}
+	expiresAt := time.Unix(int64(exp), 0).UTC()
+	return &expiresAt
+}
+
+func (s *userExportService) WriteZIP(w io.Writer, export *domain.UserExport) error {
+	archive := zip.NewWriter(w)
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"active_areas.json", export.ActiveAreas},
+		{"orders.json", export.Orders},
+		{"sessions.json", export.Sessions},
+	}
+	for _, file := range files {
+		fw, err := archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(fw)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(file.data); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
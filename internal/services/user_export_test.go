@@ -0,0 +1,147 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/mocks"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+type UserExportServiceTestSuite struct {
+	suite.Suite
+	userRepository        *mocks.UserRepository
+	activeAreaRepository  *mocks.ActiveAreaRepository
+	orderRepository       *mocks.OrderRepository
+	orderStatusRepository *mocks.OrderStatusRepository
+	tokenRepository       *mocks.TokenRepository
+	service               domain.UserExportService
+}
+
+func TestUserExportServiceSuite(t *testing.T) {
+	suite.Run(t, new(UserExportServiceTestSuite))
+}
+
+func (s *UserExportServiceTestSuite) SetupTest() {
+	s.userRepository = &mocks.UserRepository{}
+	s.activeAreaRepository = &mocks.ActiveAreaRepository{}
+	s.orderRepository = &mocks.OrderRepository{}
+	s.orderStatusRepository = &mocks.OrderStatusRepository{}
+	s.tokenRepository = &mocks.TokenRepository{}
+	s.service = NewUserExportService(s.userRepository, s.activeAreaRepository, s.orderRepository,
+		s.orderStatusRepository, s.tokenRepository)
+}
+
+func (s *UserExportServiceTestSuite) TearDownTest() {
+	s.userRepository.AssertExpectations(s.T())
+	s.activeAreaRepository.AssertExpectations(s.T())
+	s.orderRepository.AssertExpectations(s.T())
+	s.orderStatusRepository.AssertExpectations(s.T())
+	s.tokenRepository.AssertExpectations(s.T())
+}
+
+func (s *UserExportServiceTestSuite) TestUserExport_Export() {
+	t := s.T()
+	ctx := context.Background()
+	name := "Ivan"
+	user := &ent.User{
+		ID: 1, Login: "login", Email: "user@example.com", Name: &name, Password: "hash", Type: "person",
+		Edges: ent.UserEdges{Role: &ent.Role{Slug: "user"}},
+	}
+	refreshToken, err := generateRefreshToken(user, "secret")
+	require.NoError(t, err)
+	now := time.Now()
+	statuses := []*ent.OrderStatus{
+		{Comment: "approved", CurrentDate: now, Edges: ent.OrderStatusEdges{
+			OrderStatusName: &ent.OrderStatusName{Status: domain.OrderStatusApproved}}},
+		{Comment: "Order created", CurrentDate: now.Add(-time.Hour), Edges: ent.OrderStatusEdges{
+			OrderStatusName: &ent.OrderStatusName{Status: domain.OrderStatusInReview}}},
+	}
+	s.userRepository.On("GetUserByID", ctx, user.ID).Return(user, nil)
+	s.activeAreaRepository.On("UserActiveAreas", ctx, user.ID).
+		Return([]*ent.ActiveArea{{ID: 1, Name: "area"}}, nil)
+	s.orderRepository.On("List", ctx, &user.ID, mock.Anything).Return([]*ent.Order{{
+		ID: 5, Description: "order", Quantity: 1,
+		Edges: ent.OrderEdges{Equipments: []*ent.Equipment{{Title: "tent"}}},
+	}}, nil)
+	s.orderStatusRepository.On("StatusHistory", ctx, 5).Return(statuses, nil)
+	expiredToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		ExpireAtTokenClaim: now.Add(-time.Hour).Unix(),
+	}).SignedString([]byte("secret"))
+	require.NoError(t, err)
+	s.tokenRepository.On("UserTokens", ctx, user.ID).Return([]*ent.Token{
+		{ID: 2, AccessToken: "expired", RefreshToken: expiredToken},
+		{ID: 3, AccessToken: "access", RefreshToken: refreshToken},
+	}, nil)
+
+	export, err := s.service.Export(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, "login", export.Profile.Login)
+	require.Equal(t, &name, export.Profile.Name)
+	require.Equal(t, "user", export.Profile.Role)
+	require.Nil(t, export.Profile.PassportIssueDate)
+	require.Equal(t, []string{"area"}, export.ActiveAreas)
+	require.Len(t, export.Orders, 1)
+	require.Equal(t, []string{"tent"}, export.Orders[0].Equipment)
+	require.Len(t, export.Orders[0].Statuses, 2)
+	require.Equal(t, domain.OrderStatusInReview, export.Orders[0].Statuses[0].Status)
+	require.Equal(t, domain.OrderStatusApproved, export.Orders[0].Statuses[1].Status)
+	require.Len(t, export.Sessions, 1)
+	require.Equal(t, 3, export.Sessions[0].ID)
+	require.NotNil(t, export.Sessions[0].ExpiresAt)
+	require.True(t, export.Sessions[0].ExpiresAt.After(now))
+
+	data, err := json.Marshal(export)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), refreshToken)
+	require.NotContains(t, string(data), "hash")
+}
+
+func (s *UserExportServiceTestSuite) TestUserExport_Export_UserError() {
+	t := s.T()
+	ctx := context.Background()
+	s.userRepository.On("GetUserByID", ctx, 1).Return(nil, &ent.NotFoundError{})
+
+	_, err := s.service.Export(ctx, 1)
+	require.True(t, ent.IsNotFound(err))
+}
+
+func (s *UserExportServiceTestSuite) TestUserExport_WriteZIP() {
+	t := s.T()
+	export := &domain.UserExport{
+		Profile:     domain.UserExportProfile{ID: 1, Login: "login"},
+		ActiveAreas: []string{"area"},
+		Orders:      []domain.UserExportOrder{},
+		Sessions:    []domain.UserExportSession{},
+	}
+	buf := &bytes.Buffer{}
+	require.NoError(t, s.service.WriteZIP(buf, export))
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	names := make([]string, len(archive.File))
+	for i, f := range archive.File {
+		names[i] = f.Name
+	}
+	require.Equal(t, []string{"profile.json", "active_areas.json", "orders.json", "sessions.json"}, names)
+
+	f, err := archive.File[0].Open()
+	require.NoError(t, err)
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	profile := domain.UserExportProfile{}
+	require.NoError(t, json.Unmarshal(data, &profile))
+	require.Equal(t, export.Profile, profile)
+}
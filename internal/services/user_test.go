@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -132,6 +134,70 @@ func (s *UserServiceTestSuite) TestUserService_GenerateAccessToken_DeletedUserEr
 	s.tokenRepository.AssertExpectations(t)
 }
 
+func (s *UserServiceTestSuite) TestUserService_GenerateAccessToken_TwoFactorRequired() {
+	t := s.T()
+	login := "login"
+	password := "password"
+	ctx := context.Background()
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &ent.User{
+		ID:            1,
+		Login:         login,
+		Password:      string(hashedPassword),
+		IsTotpEnabled: true,
+	}
+
+	s.userRepository.On("GetUserByLogin", ctx, login).Return(user, nil)
+	accessToken, refreshToken, isInternalErr, errGen := s.userService.GenerateTokens(ctx, login, password)
+	require.ErrorIs(t, errGen, domain.ErrTwoFactorRequired)
+	require.Empty(t, accessToken)
+	require.Empty(t, refreshToken)
+	require.False(t, isInternalErr)
+	s.userRepository.AssertExpectations(t)
+	s.tokenRepository.AssertExpectations(t)
+}
+
+func (s *UserServiceTestSuite) TestUserService_GenerateTokensByUserID_OK() {
+	t := s.T()
+	ctx := context.Background()
+	user := &ent.User{ID: 1, Login: "login"}
+
+	s.userRepository.On("GetUserByID", ctx, user.ID).Return(user, nil)
+	s.tokenRepository.On("CreateTokens", ctx, user.ID, mock.AnythingOfType("string"),
+		mock.AnythingOfType("string")).Return(nil)
+	accessToken, refreshToken, isInternalErr, err := s.userService.GenerateTokensByUserID(ctx, user.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, accessToken)
+	require.NotEmpty(t, refreshToken)
+	require.False(t, isInternalErr)
+	s.userRepository.AssertExpectations(t)
+	s.tokenRepository.AssertExpectations(t)
+}
+
+func (s *UserServiceTestSuite) TestUserService_RefreshToken_ChallengeTokenIsNotAccepted() {
+	t := s.T()
+	ctx := context.Background()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		UserIDTokenClaim:   1,
+		ExpireAtTokenClaim: time.Now().Add(time.Minute).Unix(),
+		TokenPurposeClaim:  TwoFactorChallengePurpose,
+	})
+	challenge, err := token.SignedString([]byte(s.jwtSecret))
+	require.NoError(t, err)
+
+	accessToken, refreshToken, isInternalErr, err := s.userService.RefreshToken(ctx, challenge)
+	require.Error(t, err)
+	require.Empty(t, accessToken)
+	require.Empty(t, refreshToken)
+	require.False(t, isInternalErr)
+	s.userRepository.AssertExpectations(t)
+	s.tokenRepository.AssertExpectations(t)
+}
+
 func (s *UserServiceTestSuite) TestUserService_GenerateAccessToken_TokenRepoErr() {
 	t := s.T()
 	login := "login"
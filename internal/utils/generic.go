@@ -8,3 +8,22 @@ func IsValueInList[T comparable](needle T, in []T) bool {
 	}
 	return false
 }
+
+func Unique[T comparable](in []T) []T {
+	result := make([]T, 0, len(in))
+	for _, v := range in {
+		if !IsValueInList(v, result) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// PointerOrNil returns nil for the zero value, it maps the optional columns stored without NULL.
+func PointerOrNil[T comparable](v T) *T {
+	var zero T
+	if v == zero {
+		return nil
+	}
+	return &v
+}
@@ -4,14 +4,21 @@ import (
 	"crypto/rand"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
 )
 
 const (
-	MinPasswordLen                    = 8
-	MaxPasswordLen                    = 32
-	AllowedRandomResetPasswordSymbols = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	MinPasswordLen = 8
+	MaxPasswordLen = 32
+
+	lowercaseSymbols = "abcdefghijklmnopqrstuvwxyz"
+	uppercaseSymbols = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitSymbols     = "0123456789"
+	specialSymbols   = "!#$%&*+-=?@_"
+
+	AllowedRandomResetPasswordSymbols = lowercaseSymbols + uppercaseSymbols + digitSymbols + specialSymbols
 )
 
 type passwordGenerator struct {
@@ -28,8 +35,19 @@ func NewPasswordGenerator(length int) (domain.PasswordGenerator, error) {
 	return &passwordGenerator{length: length}, nil
 }
 
+// NewPassword generates a password containing at least one symbol of every class,
+// so it satisfies the password policy whatever character classes it requires.
 func (p passwordGenerator) NewPassword() (string, error) {
-	return generateRandomString(p.length, AllowedRandomResetPasswordSymbols)
+	for {
+		password, err := generateRandomString(p.length, AllowedRandomResetPasswordSymbols)
+		if err != nil {
+			return "", err
+		}
+		if strings.ContainsAny(password, lowercaseSymbols) && strings.ContainsAny(password, uppercaseSymbols) &&
+			strings.ContainsAny(password, digitSymbols) && strings.ContainsAny(password, specialSymbols) {
+			return password, nil
+		}
+	}
 }
 
 func generateRandomString(n int, symbols string) (string, error) {
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -32,3 +33,16 @@ func TestPasswordGenerator_Generate(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, password, length)
 }
+
+func TestPasswordGenerator_GenerateAllSymbolClasses(t *testing.T) {
+	generator, err := NewPasswordGenerator(MinPasswordLen)
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		password, err := generator.NewPassword()
+		require.NoError(t, err)
+		require.True(t, strings.ContainsAny(password, lowercaseSymbols))
+		require.True(t, strings.ContainsAny(password, uppercaseSymbols))
+		require.True(t, strings.ContainsAny(password, digitSymbols))
+		require.True(t, strings.ContainsAny(password, specialSymbols))
+	}
+}
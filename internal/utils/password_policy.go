@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/pkg/domain"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsList string
+
+type PasswordPolicyRules struct {
+	MinLength        int
+	RequireLowercase bool
+	RequireUppercase bool
+	RequireDigit     bool
+	RequireSpecial   bool
+	ForbidUserData   bool
+	ForbidCommon     bool
+}
+
+type passwordPolicy struct {
+	rules           PasswordPolicyRules
+	commonPasswords map[string]struct{}
+}
+
+func NewPasswordPolicy(rules PasswordPolicyRules) domain.PasswordPolicy {
+	p := &passwordPolicy{rules: rules}
+	if rules.ForbidCommon {
+		p.commonPasswords = make(map[string]struct{})
+		scanner := bufio.NewScanner(strings.NewReader(commonPasswordsList))
+		for scanner.Scan() {
+			if word := strings.TrimSpace(scanner.Text()); word != "" {
+				p.commonPasswords[strings.ToLower(word)] = struct{}{}
+			}
+		}
+	}
+	return p
+}
+
+// Validate returns the messages of all violated rules, an empty result means the password is acceptable.
+func (p *passwordPolicy) Validate(password string, userData ...string) []string {
+	var violations []string
+	if len([]rune(password)) < p.rules.MinLength {
+		violations = append(violations, fmt.Sprintf(messages.ErrPasswordTooShort, p.rules.MinLength))
+	}
+
+	var hasLower, hasUpper, hasDigit, hasSpecial bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSpecial = true
+		}
+	}
+	if p.rules.RequireLowercase && !hasLower {
+		violations = append(violations, messages.ErrPasswordNoLowercase)
+	}
+	if p.rules.RequireUppercase && !hasUpper {
+		violations = append(violations, messages.ErrPasswordNoUppercase)
+	}
+	if p.rules.RequireDigit && !hasDigit {
+		violations = append(violations, messages.ErrPasswordNoDigit)
+	}
+	if p.rules.RequireSpecial && !hasSpecial {
+		violations = append(violations, messages.ErrPasswordNoSpecial)
+	}
+
+	if p.rules.ForbidUserData {
+		for _, data := range userData {
+			if data != "" && strings.EqualFold(password, data) {
+				violations = append(violations, messages.ErrPasswordEqualUserData)
+				break
+			}
+		}
+	}
+
+	if p.rules.ForbidCommon {
+		if _, ok := p.commonPasswords[strings.ToLower(password)]; ok {
+			violations = append(violations, messages.ErrPasswordTooCommon)
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/messages"
+)
+
+var testPasswordPolicyRules = PasswordPolicyRules{
+	MinLength:        8,
+	RequireLowercase: true,
+	RequireUppercase: true,
+	RequireDigit:     true,
+	RequireSpecial:   true,
+	ForbidUserData:   true,
+	ForbidCommon:     true,
+}
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	policy := NewPasswordPolicy(testPasswordPolicyRules)
+	tests := []struct {
+		name     string
+		password string
+		userData []string
+		expected []string
+	}{
+		{
+			name:     "valid",
+			password: "Str0ng-Passw",
+			userData: []string{"login", "login@example.com"},
+		},
+		{
+			name:     "all rules violated at once",
+			password: "",
+			expected: []string{
+				fmt.Sprintf(messages.ErrPasswordTooShort, 8),
+				messages.ErrPasswordNoLowercase,
+				messages.ErrPasswordNoUppercase,
+				messages.ErrPasswordNoDigit,
+				messages.ErrPasswordNoSpecial,
+			},
+		},
+		{
+			name:     "missing character classes",
+			password: "abcdefghij",
+			expected: []string{
+				messages.ErrPasswordNoUppercase,
+				messages.ErrPasswordNoDigit,
+				messages.ErrPasswordNoSpecial,
+			},
+		},
+		{
+			name:     "equal to login ignoring case",
+			password: "Us3r-Login",
+			userData: []string{"us3r-login", "user@example.com"},
+			expected: []string{messages.ErrPasswordEqualUserData},
+		},
+		{
+			name:     "common password",
+			password: "P@ssw0rd",
+			expected: []string{messages.ErrPasswordTooCommon},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, policy.Validate(tt.password, tt.userData...))
+		})
+	}
+}
+
+func TestPasswordPolicy_ValidateDisabledRules(t *testing.T) {
+	policy := NewPasswordPolicy(PasswordPolicyRules{MinLength: 6})
+	require.Empty(t, policy.Validate("password", "password"))
+	require.Equal(t, []string{fmt.Sprintf(messages.ErrPasswordTooShort, 6)}, policy.Validate("pass"))
+}
@@ -12,3 +12,8 @@ func TestGetParamInt(t *testing.T) {
 
 	require.Equal(t, 6, GetValueByPointerOrDefaultValue(nil, 6))
 }
+
+func TestPointerOrNil(t *testing.T) {
+	require.Equal(t, "value", *PointerOrNil("value"))
+	require.Nil(t, PointerOrNil(""))
+}
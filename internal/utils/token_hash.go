@@ -0,0 +1,14 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HashToken returns hex encoded SHA-256 of a high-entropy secret (token, recovery code).
+// It must not be used for user chosen passwords, use PasswordHash for them.
+// The registration confirmation, email change and password reset tokens sent to users are stored only as this hash.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// base32 of the RFC 6238 SHA1 test secret "12345678901234567890"
+const rfcTestSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode_RFCVectors(t *testing.T) {
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, expected := range vectors {
+		code, err := TOTPCode(rfcTestSecret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, expected, code, "time %d", unix)
+	}
+}
+
+func TestTOTPCode_InvalidSecret(t *testing.T) {
+	_, err := TOTPCode("not base32!", time.Now())
+	require.Error(t, err)
+}
+
+func TestValidateTOTPCode(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	require.True(t, ValidateTOTPCode(rfcTestSecret, "081804", now))
+	require.True(t, ValidateTOTPCode(rfcTestSecret, "081804", now.Add(TOTPPeriod)))
+	require.False(t, ValidateTOTPCode(rfcTestSecret, "081804", now.Add(3*TOTPPeriod)))
+	require.False(t, ValidateTOTPCode(rfcTestSecret, "000000", now))
+	require.False(t, ValidateTOTPCode(rfcTestSecret, "81804", now))
+}
+
+func TestMatchTOTPCode(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	step, ok := MatchTOTPCode(rfcTestSecret, "081804", now)
+	require.True(t, ok)
+	require.Equal(t, TOTPStep(now), step)
+
+	step, ok = MatchTOTPCode(rfcTestSecret, "081804", now.Add(TOTPPeriod))
+	require.True(t, ok)
+	require.Equal(t, TOTPStep(now), step)
+
+	_, ok = MatchTOTPCode(rfcTestSecret, "081804", now.Add(3*TOTPPeriod))
+	require.False(t, ok)
+}
+
+func TestNewTOTPSecret(t *testing.T) {
+	secret, err := NewTOTPSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 32)
+
+	code, err := TOTPCode(secret, time.Now())
+	require.NoError(t, err)
+	require.True(t, ValidateTOTPCode(secret, code, time.Now()))
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := TOTPProvisioningURI("CSR", "admin", rfcTestSecret)
+	require.True(t, strings.HasPrefix(uri, "otpauth://totp/CSR:admin?"))
+	require.Contains(t, uri, "secret="+rfcTestSecret)
+	require.Contains(t, uri, "issuer=CSR")
+}
+
+func TestNewRecoveryCodes(t *testing.T) {
+	codes, err := NewRecoveryCodes(10)
+	require.NoError(t, err)
+	require.Len(t, codes, 10)
+	for _, code := range codes {
+		require.Len(t, code, 2*recoveryCodePartLen+1)
+		require.Equal(t, code, NormalizeRecoveryCode(" "+strings.ToUpper(code)+" "))
+	}
+}
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore keeps the binary content like the photos outside of their rows, the key is unique within the store.
+type BlobStore interface {
+	Put(ctx context.Context, key string, content io.Reader, size int64) error
+	// Get returns ErrBlobNotFound if there is no content for the key, the caller closes the reader. The reader
+	// seeks without reading the content up to the offset, so the ranges of large content are served cheaply.
+	Get(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	// Delete does nothing if there is no content for the key.
+	Delete(ctx context.Context, key string) error
+}
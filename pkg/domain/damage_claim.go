@@ -0,0 +1,20 @@
+package domain
+
+import "errors"
+
+// Statuses of the damage claim, the claim is open until the compensation is paid or waived.
+const (
+	DamageClaimStatusOpen   = "open"
+	DamageClaimStatusPaid   = "paid"
+	DamageClaimStatusWaived = "waived"
+)
+
+var (
+	ErrEquipmentNotInOrder    = errors.New("equipment is not in the order")
+	ErrDamageClaimExists      = errors.New("damage claim for the equipment of the order already exists")
+	ErrDamageClaimNotOpen     = errors.New("damage claim is already paid or waived")
+	ErrDamageClaimOrderActive = errors.New("equipment of the order is not returned yet")
+	// ErrEquipmentHasDamageClaims refuses to delete the equipment, its claims are the record of the damage
+	// and the compensation.
+	ErrEquipmentHasDamageClaims = errors.New("equipment has damage claims")
+)
@@ -2,7 +2,7 @@ package domain
 
 type Sender interface {
 	SendResetLink(email string, userName string, token string) error
-	SendNewPassword(email string, userName string, password string) error
+	SendPasswordChanged(email string, userName string) error
 	SendRegistrationConfirmLink(email string, userName string, token string) error
 	IsSendRequired() bool
 	SendEmailConfirmationLink(email string, userName string, token string) error
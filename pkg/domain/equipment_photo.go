@@ -0,0 +1,8 @@
+package domain
+
+import "errors"
+
+var (
+	ErrEquipmentPhotoNotFound  = errors.New("photo not found")
+	ErrEquipmentPhotosMismatch = errors.New("photos don't match the gallery of the equipment")
+)
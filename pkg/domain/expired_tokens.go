@@ -0,0 +1,11 @@
+package domain
+
+import (
+	"context"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+)
+
+type ExpiredTokensCleanup interface {
+	Cleanup(ctx context.Context, cln *ent.Client) error
+}
@@ -0,0 +1,8 @@
+package domain
+
+import "errors"
+
+var (
+	ErrUnknownPermission = errors.New("unknown permission")
+	ErrUnknownUser       = errors.New("unknown user")
+)
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+)
+
+var (
+	ErrMaintenancePlanTarget   = errors.New("maintenance plan must have either equipment or category")
+	ErrMaintenancePlanInterval = errors.New("maintenance plan must have the number of rentals or days")
+)
+
+// MaintenanceScheduler makes the equipment not available for the maintenance which is due by its plans.
+type MaintenanceScheduler interface {
+	Schedule(ctx context.Context, cln *ent.Client) error
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+)
+
+var (
+	// ErrOIDCLoginFailed is returned when the login state, the authorization code or the ID token is invalid.
+	ErrOIDCLoginFailed = errors.New("oidc login failed")
+	// ErrOIDCAccountNotLinkable is returned when the identity provider account can't be linked
+	// to the existing user with the same email.
+	ErrOIDCAccountNotLinkable = errors.New("oidc account can't be linked to the existing user")
+)
+
+// OIDCClaims are the claims of a verified ID token.
+type OIDCClaims struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+type OIDCProvider interface {
+	// AuthCodeURL returns the URL of the identity provider authorization endpoint.
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange redeems the authorization code and returns the claims of the verified ID token.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*OIDCClaims, error)
+}
+
+type OIDCService interface {
+	StartLogin(ctx context.Context) (string, error)
+	Authenticate(ctx context.Context, code, state string) (*ent.User, error)
+}
@@ -1,5 +1,9 @@
 package domain
 
+import "errors"
+
+var ErrOrderStatusPhotoNotFound = errors.New("photo of the order status is not found")
+
 var (
 	OrderStatusInReview   = "in review"
 	OrderStatusApproved   = "approved"
@@ -0,0 +1,14 @@
+package domain
+
+import "errors"
+
+// Roles of the members of the organization.
+const (
+	OrganizationRoleOwner  = "owner"
+	OrganizationRoleMember = "member"
+)
+
+var (
+	ErrNotOrganizationMember = errors.New("user is not a member of the organization")
+	ErrLastOrganizationOwner = errors.New("organization must have at least one owner")
+)
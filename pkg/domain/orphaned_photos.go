@@ -0,0 +1,13 @@
+package domain
+
+import (
+	"context"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+)
+
+// OrphanedPhotosCleanup deletes the photos which are in no gallery and no order status report longer than
+// the grace period.
+type OrphanedPhotosCleanup interface {
+	Cleanup(ctx context.Context, cln *ent.Client) error
+}
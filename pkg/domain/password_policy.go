@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrPasswordPolicyViolated = errors.New("password does not meet the password policy")
+
+// PasswordPolicy checks a password against the configured rules and returns every rule it violates.
+// userData holds values the password must not be equal to, e.g. the login and the email of the user.
+type PasswordPolicy interface {
+	Validate(password string, userData ...string) []string
+}
+
+// PasswordPolicyError carries all rules violated by a password.
+type PasswordPolicyError struct {
+	Violations []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return ErrPasswordPolicyViolated.Error() + ": " + strings.Join(e.Violations, "; ")
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrPasswordPolicyViolated
+}
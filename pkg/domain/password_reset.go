@@ -1,10 +1,18 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+var ErrPasswordResetTokenInvalid = errors.New("password reset token is invalid or expired")
 
 type PasswordResetService interface {
 	SendResetPasswordLink(ctx context.Context, login string) error
-	VerifyTokenAndSendPassword(ctx context.Context, tokenToVerify string) error
+	// VerifyToken checks that the reset token exists and is not expired without consuming it.
+	VerifyToken(ctx context.Context, tokenToVerify string) error
+	// ResetPassword sets the password chosen by the user, consumes the token and revokes all sessions of the user.
+	ResetPassword(ctx context.Context, tokenToVerify, newPassword string) error
 }
 
 type RegistrationConfirmService interface {
@@ -15,6 +23,7 @@ type RegistrationConfirmService interface {
 
 type TokenManager interface {
 	GenerateTokens(ctx context.Context, login, password string) (string, string, bool, error)
+	GenerateTokensByUserID(ctx context.Context, userID int) (string, string, bool, error)
 	RefreshToken(ctx context.Context, token string) (string, string, bool, error)
 	DeleteTokenPair(ctx context.Context, token string) error
 }
@@ -11,6 +11,7 @@ import (
 type ActiveAreaRepository interface {
 	AllActiveAreas(ctx context.Context, limit, offset int, orderBy, orderColumn string) ([]*ent.ActiveArea, error)
 	TotalActiveAreas(ctx context.Context) (int, error)
+	UserActiveAreas(ctx context.Context, userID int) ([]*ent.ActiveArea, error)
 }
 
 type Filter struct {
@@ -25,8 +26,29 @@ type CategoryFilter struct {
 
 type OrderFilter struct {
 	Filter
-	Status      *string
+	Status         *string
+	EquipmentID    *int
+	OrganizationID *int
+}
+
+// DamageClaimFilter narrows the list of damage claims, nil fields aren't checked.
+type DamageClaimFilter struct {
+	OrderID     *int
 	EquipmentID *int
+	Status      *string
+}
+
+// UserFilter narrows the list of users. Login, Email and Search match substrings ignoring the case,
+// Search looks in the name, surname and phone. Deleted users are listed only if IsDeleted is set.
+type UserFilter struct {
+	Filter
+	Login, Email, Search    *string
+	Role                    *string
+	ActiveAreaID            *int
+	Type                    *string
+	IsReadonly              *bool
+	IsRegistrationConfirmed *bool
+	IsDeleted               *bool
 }
 
 type CategoryRepository interface {
@@ -38,13 +60,23 @@ type CategoryRepository interface {
 	UpdateCategory(ctx context.Context, id int, update models.UpdateCategoryRequest) (*ent.Category, error)
 }
 
+type DamageClaimRepository interface {
+	// CreateDamageClaim claims the damage of the equipment returned by the closed order,
+	// the amount is the compensation cost of the equipment if it is nil.
+	CreateDamageClaim(ctx context.Context, orderID, equipmentID int, amount *int64,
+		notes string) (*ent.DamageClaim, error)
+	DamageClaimByID(ctx context.Context, id int) (*ent.DamageClaim, error)
+	DamageClaims(ctx context.Context, filter DamageClaimFilter) ([]*ent.DamageClaim, error)
+	// UpdateDamageClaim changes the open claim, paid and waived claims are final.
+	UpdateDamageClaim(ctx context.Context, id int, update models.DamageClaimUpdate) (*ent.DamageClaim, error)
+}
+
 type EquipmentRepository interface {
 	EquipmentsByFilter(ctx context.Context, filter models.EquipmentFilter, limit, offset int,
 		orderBy, orderColumn string) ([]*ent.Equipment, error)
 	CreateEquipment(ctx context.Context, eq models.Equipment, status *ent.EquipmentStatusName) (*ent.Equipment, error)
 	EquipmentByID(ctx context.Context, id int) (*ent.Equipment, error)
 	DeleteEquipmentByID(ctx context.Context, id int) error
-	DeleteEquipmentPhoto(ctx context.Context, id string) error
 	AllEquipments(ctx context.Context, limit, offset int, orderBy, orderColumn string) ([]*ent.Equipment, error)
 	UpdateEquipmentByID(ctx context.Context, id int, eq *models.Equipment) (*ent.Equipment, error)
 	AllEquipmentsTotal(ctx context.Context) (int, error)
@@ -55,8 +87,20 @@ type EquipmentRepository interface {
 	UnblockAllExpiredEquipment(ctx context.Context, client *ent.Client) (int, error)
 }
 
+// EquipmentPhotoRepository manages the gallery of the equipment. The photos are returned in the gallery order,
+// the gallery always has one cover photo unless it is empty.
+type EquipmentPhotoRepository interface {
+	EquipmentPhotos(ctx context.Context, equipmentID int) ([]*ent.EquipmentPhoto, error)
+	AddEquipmentPhoto(ctx context.Context, equipmentID int, photoID string, cover bool) ([]*ent.EquipmentPhoto, error)
+	ReorderEquipmentPhotos(ctx context.Context, equipmentID int, photoIDs []string) ([]*ent.EquipmentPhoto, error)
+	SetEquipmentCoverPhoto(ctx context.Context, equipmentID int, photoID string) ([]*ent.EquipmentPhoto, error)
+	RemoveEquipmentPhoto(ctx context.Context, equipmentID int, photoID string) ([]*ent.EquipmentPhoto, error)
+}
+
 type EquipmentStatusRepository interface {
 	Create(ctx context.Context, data *models.NewEquipmentStatus) (*ent.EquipmentStatus, error)
+	CreateNotAvailable(ctx context.Context, equipmentID int, startDate, endDate time.Time) (*ent.EquipmentStatus, error)
+	GetActiveOrdersByEquipmentInPeriod(ctx context.Context, equipmentID int, startDate, endDate time.Time) ([]*ent.Order, error)
 	GetEquipmentsStatusesByOrder(ctx context.Context, orderID int) ([]*ent.EquipmentStatus, error)
 	HasStatusByPeriod(ctx context.Context, status string, eqID int, startDate, endDate time.Time) (bool, error)
 	Update(ctx context.Context, data *models.EquipmentStatus) (*ent.EquipmentStatus, error)
@@ -74,10 +118,55 @@ type EquipmentStatusNameRepository interface {
 	Delete(ctx context.Context, id int) (*ent.EquipmentStatusName, error)
 }
 
+type GroupRepository interface {
+	AllPermissions(ctx context.Context) ([]*ent.Permission, error)
+	AllGroups(ctx context.Context) ([]*ent.Group, error)
+	GroupByID(ctx context.Context, id int) (*ent.Group, error)
+	CreateGroup(ctx context.Context, name, description string) (*ent.Group, error)
+	UpdateGroup(ctx context.Context, id int, name, description string) (*ent.Group, error)
+	DeleteGroup(ctx context.Context, id int) error
+	SetGroupPermissions(ctx context.Context, id int, permissionNames []string) (*ent.Group, error)
+	AddGroupUsers(ctx context.Context, id int, userIDs []int) (*ent.Group, error)
+	RemoveGroupUser(ctx context.Context, id int, userID int) (*ent.Group, error)
+}
+
+type OrganizationRepository interface {
+	UserOrganizations(ctx context.Context, userID int) ([]*ent.Organization, error)
+	OrganizationByID(ctx context.Context, id int) (*ent.Organization, error)
+	CreateOrganization(ctx context.Context, name string, website *string, ownerID int) (*ent.Organization, error)
+	MemberRole(ctx context.Context, id, userID int) (string, error)
+	SetMember(ctx context.Context, id, userID int, role string) (*ent.Organization, error)
+	RemoveMember(ctx context.Context, id, userID int) (*ent.Organization, error)
+}
+
+type LoginStateRepository interface {
+	CreateState(ctx context.Context, state, codeVerifier, nonce string, ttl time.Time) error
+	ConsumeState(ctx context.Context, state string) (*ent.LoginState, error)
+	DeleteExpiredTokens(ctx context.Context) (int, error)
+}
+
+type MaintenancePlanRepository interface {
+	CreateMaintenancePlan(ctx context.Context, plan models.NewMaintenancePlan) (*ent.MaintenancePlan, error)
+	MaintenancePlans(ctx context.Context) ([]*ent.MaintenancePlan, error)
+	// DeleteMaintenancePlan deletes the plan with its windows and cancels the maintenance which hasn't started.
+	DeleteMaintenancePlan(ctx context.Context, id int) error
+	// LastMaintenance returns the latest window of the plan for the equipment, nil if there was none.
+	LastMaintenance(ctx context.Context, planID, equipmentID int) (*ent.MaintenanceWindow, error)
+	// RentalsSince counts the closed orders of the equipment which ended after the time.
+	RentalsSince(ctx context.Context, equipmentID int, since time.Time) (int, error)
+	// ScheduleMaintenance makes the equipment not available during the window of the plan.
+	ScheduleMaintenance(ctx context.Context, planID, equipmentID int,
+		start, end time.Time) (*ent.MaintenanceWindow, error)
+	// UpcomingMaintenance returns the windows which end after the time, of the equipment if it is set.
+	UpcomingMaintenance(ctx context.Context, from time.Time, equipmentID *int) ([]*ent.MaintenanceWindow, error)
+}
+
 type OrderRepository interface {
 	List(ctx context.Context, ownerId *int, filter OrderFilter) ([]*ent.Order, error)
 	OrdersTotal(ctx context.Context, ownerId *int) (int, error)
 	Create(ctx context.Context, data *models.OrderCreateRequest, ownerId int, equipmentIDs []int) (*ent.Order, error)
+	Get(ctx context.Context, id int) (*ent.Order, error)
+	OrganizationOrdersTotal(ctx context.Context, organizationID int) (int, error)
 	Update(ctx context.Context, id int, data *models.OrderUpdateRequest, ownerId int) (*ent.Order, error)
 }
 
@@ -105,12 +194,14 @@ type PasswordResetRepository interface {
 	CreateToken(ctx context.Context, token string, ttl time.Time, userID int) error
 	GetToken(ctx context.Context, token string) (*ent.PasswordReset, error)
 	DeleteToken(ctx context.Context, token string) error
+	DeleteExpiredTokens(ctx context.Context) (int, error)
 }
 
 type EmailConfirmRepository interface {
 	CreateToken(ctx context.Context, token string, ttl time.Time, userID int, email string) error
 	GetToken(ctx context.Context, token string) (*ent.EmailConfirm, error)
 	DeleteToken(ctx context.Context, token string) error
+	DeleteExpiredTokens(ctx context.Context) (int, error)
 }
 
 type PetKindRepository interface {
@@ -133,12 +224,17 @@ type PhotoRepository interface {
 	CreatePhoto(ctx context.Context, p *ent.Photo) (*ent.Photo, error)
 	PhotoByID(ctx context.Context, id string) (*ent.Photo, error)
 	DeletePhotoByID(ctx context.Context, id string) error
+	// PhotoInUse reports whether the photo is in the gallery of some equipment or in the report of an order status.
+	PhotoInUse(ctx context.Context, id string) (bool, error)
+	// OrphanedPhotoIDs returns the photos created before the time which are in no gallery and no report.
+	OrphanedPhotoIDs(ctx context.Context, createdBefore time.Time) ([]string, error)
 }
 
 type RegistrationConfirmRepository interface {
 	CreateToken(ctx context.Context, token string, ttl time.Time, userID int) error
 	GetToken(ctx context.Context, token string) (*ent.RegistrationConfirm, error)
 	DeleteToken(ctx context.Context, token string) error
+	DeleteExpiredTokens(ctx context.Context) (int, error)
 }
 
 type RoleRepository interface {
@@ -156,6 +252,26 @@ type TokenRepository interface {
 	CreateTokens(ctx context.Context, ownerID int, accessToken, refreshToken string) error
 	DeleteTokensByRefreshToken(ctx context.Context, refreshToken string) error
 	UpdateAccessToken(ctx context.Context, accessToken, refreshToken string) error
+	DeleteTokensByUserID(ctx context.Context, userID int) error
+	AccessTokenExists(ctx context.Context, accessToken string) (bool, error)
+	UserTokens(ctx context.Context, userID int) ([]*ent.Token, error)
+}
+
+type TwoFactorRepository interface {
+	SetTOTPSecret(ctx context.Context, userID int, secret string) error
+	EnableTOTP(ctx context.Context, userID int, recoveryCodeHashes []string) error
+	DisableTOTP(ctx context.Context, userID int) error
+	ReplaceRecoveryCodes(ctx context.Context, userID int, recoveryCodeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID int, recoveryCodeHash string) (bool, error)
+	// SetTOTPLastStep remembers the time step of the accepted TOTP code so the code can not be replayed.
+	SetTOTPLastStep(ctx context.Context, userID int, step int64) error
+	CreateChallenge(ctx context.Context, userID int, jti string, expiresAt time.Time) error
+	ChallengeByJTI(ctx context.Context, jti string) (*ent.TwoFactorChallenge, error)
+	// ConsumeChallenge deletes the challenge, it returns false if the challenge has already been consumed.
+	ConsumeChallenge(ctx context.Context, jti string) (bool, error)
+	// RecordChallengeFailure counts the failed attempt and deletes the challenge after maxAttempts failures.
+	// It runs in its own transaction, so the failure is kept when the transaction of the request is rolled back.
+	RecordChallengeFailure(ctx context.Context, client *ent.Client, jti string, maxAttempts int) error
 }
 
 type UserRepository interface {
@@ -168,10 +284,30 @@ type UserRepository interface {
 	GetUserByLogin(ctx context.Context, login string) (*ent.User, error)
 	GetUserByID(ctx context.Context, id int) (*ent.User, error)
 	UpdateUserByID(ctx context.Context, id int, patch *models.PatchUserRequest) error
-	UserList(ctx context.Context, limit, offset int, orderBy, orderColumn string) ([]*ent.User, error)
+	UserList(ctx context.Context, filter UserFilter) ([]*ent.User, error)
 	Delete(ctx context.Context, userId int) error
-	UsersListTotal(ctx context.Context) (int, error)
+	// Restore returns the user deleted after deletedAfter and not purged yet.
+	Restore(ctx context.Context, id int, deletedAfter time.Time) error
+	// PurgeDeletedUsers anonymises the users deleted before deletedBefore and returns their number.
+	PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int, error)
+	// EncryptPassportData rewrites the passport data of up to limit users which is not encrypted with the current
+	// key and returns the number of users updated.
+	EncryptPassportData(ctx context.Context, limit int) (int, error)
+	UsersListTotal(ctx context.Context, filter UserFilter) (int, error)
 	ConfirmRegistration(ctx context.Context, login string) error
 	UnConfirmRegistration(ctx context.Context, login string) error
 	SetIsReadonly(ctx context.Context, id int, isReadonly bool) error
+	UserByOIDCSubject(ctx context.Context, subject string) (*ent.User, error)
+	UserByEmail(ctx context.Context, email string) (*ent.User, error)
+	SetOIDCSubject(ctx context.Context, id int, subject string) error
+	CreateOIDCUser(ctx context.Context, data *OIDCUser) (*ent.User, error)
+}
+
+// OIDCUser is the data of the user created on the first login through the identity provider.
+type OIDCUser struct {
+	Subject                 string
+	Login                   string
+	Email                   string
+	Name                    string
+	IsRegistrationConfirmed bool
 }
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrTwoFactorRequired is returned by TokenManager.GenerateTokens when the password is correct,
+	// but the user has to pass the second authentication step.
+	ErrTwoFactorRequired    = errors.New("two-factor authentication required")
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled = errors.New("two-factor authentication is not enrolled")
+	ErrTwoFactorMandatory   = errors.New("two-factor authentication is mandatory for the role")
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor authentication code")
+)
+
+type TwoFactorEnrollment struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+type TwoFactorService interface {
+	Enroll(ctx context.Context, userID int) (*TwoFactorEnrollment, error)
+	Activate(ctx context.Context, userID int, code string) ([]string, error)
+	Disable(ctx context.Context, userID int, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID int, code string) ([]string, error)
+	IssueChallenge(ctx context.Context, login string) (string, error)
+	VerifyChallenge(ctx context.Context, challengeToken, code string) (int, error)
+	IsRequired(roleSlug string) bool
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"git.epam.com/epm-lstr/epm-lstr-lc/be/internal/generated/ent"
+)
+
+var (
+	ErrUserNotDeleted       = errors.New("user is not deleted")
+	ErrRestorePeriodExpired = errors.New("retention period of the deleted user is over")
+)
+
+// DeletedUsersPurge anonymises the personal data of the users deleted more than the retention period ago.
+type DeletedUsersPurge interface {
+	Purge(ctx context.Context, cln *ent.Client) error
+}
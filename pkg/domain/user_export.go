@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Formats of the personal data export.
+const (
+	UserExportFormatJSON = "json"
+	UserExportFormatZIP  = "zip"
+)
+
+// UserExport is everything stored about the user. Secrets like the password hash, the TOTP secret
+// and the tokens themselves are never exported.
+type UserExport struct {
+	Profile     UserExportProfile   `json:"profile"`
+	ActiveAreas []string            `json:"active_areas"`
+	Orders      []UserExportOrder   `json:"orders"`
+	Sessions    []UserExportSession `json:"sessions"`
+}
+
+type UserExportProfile struct {
+	ID                      int        `json:"id"`
+	Login                   string     `json:"login"`
+	Email                   string     `json:"email"`
+	Name                    *string    `json:"name"`
+	Surname                 *string    `json:"surname"`
+	Patronymic              *string    `json:"patronymic"`
+	PassportSeries          *string    `json:"passport_series"`
+	PassportNumber          *string    `json:"passport_number"`
+	PassportAuthority       *string    `json:"passport_authority"`
+	PassportIssueDate       *time.Time `json:"passport_issue_date"`
+	Phone                   *string    `json:"phone"`
+	Type                    string     `json:"type"`
+	OrgName                 *string    `json:"org_name"`
+	Website                 *string    `json:"website"`
+	Vk                      *string    `json:"vk"`
+	Role                    string     `json:"role"`
+	IsReadonly              bool       `json:"is_readonly"`
+	IsRegistrationConfirmed bool       `json:"is_registration_confirmed"`
+	IsTotpEnabled           bool       `json:"is_totp_enabled"`
+}
+
+type UserExportOrder struct {
+	ID          int                     `json:"id"`
+	Description string                  `json:"description"`
+	Quantity    int                     `json:"quantity"`
+	RentStart   time.Time               `json:"rent_start"`
+	RentEnd     time.Time               `json:"rent_end"`
+	CreatedAt   time.Time               `json:"created_at"`
+	Equipment   []string                `json:"equipment"`
+	Statuses    []UserExportOrderStatus `json:"statuses"`
+}
+
+type UserExportOrderStatus struct {
+	Status  string    `json:"status"`
+	Comment string    `json:"comment"`
+	Date    time.Time `json:"date"`
+}
+
+// UserExportSession is the login session, ExpiresAt is the expiration of its refresh token.
+type UserExportSession struct {
+	ID        int        `json:"id"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type UserExportService interface {
+	Export(ctx context.Context, userID int) (*UserExport, error)
+	// WriteZIP writes the export as the archive with a JSON file for each section.
+	WriteZIP(w io.Writer, export *UserExport) error
+}